@@ -0,0 +1,539 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/rekki/blackrock/pkg/auth"
+	spec "github.com/rekki/blackrock/pkg/blackrock_io"
+	"github.com/rekki/blackrock/pkg/certs"
+	"github.com/rekki/blackrock/pkg/deadletter"
+	"github.com/rekki/blackrock/pkg/depths"
+	"github.com/rekki/blackrock/pkg/index"
+	. "github.com/rekki/blackrock/pkg/logger"
+	pen "github.com/rekki/go-pen"
+	"google.golang.org/grpc"
+)
+
+type target struct {
+	remote      *string
+	apiKey      *string
+	tenant      *string
+	tls         *certs.Flags
+	root        *string
+	segmentStep *int
+	logLevel    *int
+}
+
+func addTargetFlags(fs *flag.FlagSet) *target {
+	return &target{
+		remote:      fs.String("search-grpc", ":8002", "connect to search grpc, paths are on the search host"),
+		apiKey:      fs.String("api-key", "", "api key sent to search, backup, restore and reindex need the admin scope"),
+		tenant:      fs.String("tenant", "", "tenant when search runs with -tenants, with -root use root/<tenant> instead"),
+		tls:         certs.AddFlags(fs),
+		root:        fs.String("root", "", "work directly on root directory (the search must be stopped), instead of connecting to search grpc"),
+		segmentStep: fs.Int("segment-step", 3600, "segment step, used only with -root"),
+		logLevel:    fs.Int("log-level", 0, "log level"),
+	}
+}
+
+func (t *target) offline() bool {
+	return *t.root != ""
+}
+
+func (t *target) index() *index.SearchIndex {
+	return index.NewSearchIndex(*t.root, 100, int64(*t.segmentStep), false, map[string]bool{})
+}
+
+func (t *target) client() (spec.SearchClient, *grpc.ClientConn, error) {
+	opts, err := t.tls.DialOptions()
+	if err != nil {
+		return nil, nil, err
+	}
+	if *t.apiKey != "" {
+		opts = append(opts, auth.Credentials(*t.apiKey))
+	}
+	if *t.tenant != "" {
+		opts = append(opts, auth.Tenant(*t.tenant))
+	}
+	conn, err := grpc.Dial(*t.remote, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return spec.NewSearchClient(conn), conn, nil
+}
+
+func backup(args []string) error {
+	fs := flag.NewFlagSet("backup", flag.ExitOnError)
+	t := addTargetFlags(fs)
+	var out = fs.String("out", "", "destination directory, or tarball if it ends with .tar, .tar.gz or .tgz, relative to -backup-root of search unless -root is used")
+	var from = fs.Uint("from", 0, "include segments from this second, 0 means all")
+	var to = fs.Uint("to", 0, "include segments up to this second, 0 means all")
+	_ = fs.Parse(args)
+	LogInit(*t.logLevel)
+
+	if *out == "" {
+		return errors.New("missing -out")
+	}
+
+	var (
+		manifest *spec.SnapshotManifest
+		err      error
+	)
+	if t.offline() {
+		si := t.index()
+		defer si.Close()
+		manifest, err = si.Snapshot(*out, uint32(*from), uint32(*to))
+	} else {
+		var (
+			client spec.SearchClient
+			conn   *grpc.ClientConn
+		)
+		client, conn, err = t.client()
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		manifest, err = client.SayBackup(context.Background(), &spec.BackupRequest{Destination: *out, FromSecond: uint32(*from), ToSecond: uint32(*to)})
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(depths.DumpObj(manifest))
+	return nil
+}
+
+func restore(args []string) error {
+	fs := flag.NewFlagSet("restore", flag.ExitOnError)
+	t := addTargetFlags(fs)
+	var in = fs.String("in", "", "snapshot directory or tarball, relative to -backup-root of search unless -root is used")
+	_ = fs.Parse(args)
+	LogInit(*t.logLevel)
+
+	if *in == "" {
+		return errors.New("missing -in")
+	}
+
+	var (
+		manifest *spec.SnapshotManifest
+		err      error
+	)
+	if t.offline() {
+		si := t.index()
+		defer si.Close()
+		manifest, err = si.Restore(*in)
+	} else {
+		var (
+			client spec.SearchClient
+			conn   *grpc.ClientConn
+		)
+		client, conn, err = t.client()
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		manifest, err = client.SayRestore(context.Background(), &spec.RestoreRequest{Source: *in})
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(depths.DumpObj(manifest))
+	return nil
+}
+
+func fsck(args []string) error {
+	fs := flag.NewFlagSet("fsck", flag.ExitOnError)
+	t := addTargetFlags(fs)
+	var from = fs.Uint("from", 0, "check segments from this second, 0 means all")
+	var to = fs.Uint("to", 0, "check segments up to this second, 0 means all")
+	var repair = fs.Bool("repair", false, "rebuild the inverted index from main.bin for the broken segments")
+	_ = fs.Parse(args)
+	LogInit(*t.logLevel)
+
+	if !t.offline() {
+		return errors.New("fsck works only with -root")
+	}
+
+	si := t.index()
+	defer si.Close()
+
+	checks, err := si.Check(uint32(*from), uint32(*to))
+	if err != nil {
+		return err
+	}
+
+	broken := 0
+	for _, c := range checks {
+		if c.Ok() {
+			continue
+		}
+		fmt.Println(depths.DumpObjNoIndent(c))
+		if !*repair {
+			broken++
+			continue
+		}
+
+		err := si.RebuildInverted(c.Id)
+		if err != nil {
+			return err
+		}
+		Log.Infof("rebuilt inverted index for segment %s", c.Id)
+	}
+
+	Log.Infof("checked %d segments, %d broken", len(checks), broken)
+	if broken > 0 {
+		return fmt.Errorf("%d broken segments, run with -repair to rebuild the inverted index", broken)
+	}
+	return nil
+}
+
+func reindex(args []string) error {
+	fs := flag.NewFlagSet("reindex", flag.ExitOnError)
+	t := addTargetFlags(fs)
+	var from = fs.Uint("from", 0, "reindex segments from this second, 0 means all")
+	var to = fs.Uint("to", 0, "reindex segments up to this second, 0 means all")
+	var pwhitelist = fs.String("whitelist", "", "csv list of indexable search terms, nothing means all")
+	var status = fs.Bool("status", false, "only print the status of the running reindex")
+	var wait = fs.Bool("wait", false, "wait for the reindex to finish")
+	_ = fs.Parse(args)
+	LogInit(*t.logLevel)
+
+	whitelist := []string{}
+	for _, v := range strings.Split(*pwhitelist, ",") {
+		if len(v) > 0 {
+			whitelist = append(whitelist, v)
+		}
+	}
+
+	if t.offline() {
+		si := t.index()
+		defer si.Close()
+
+		wm := map[string]bool{}
+		for _, v := range whitelist {
+			wm[v] = true
+		}
+		err := si.Reindex(uint32(*from), uint32(*to), wm)
+		fmt.Println(depths.DumpObj(si.ReindexStatus()))
+		return err
+	}
+
+	client, conn, err := t.client()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	req := &spec.ReindexRequest{FromSecond: uint32(*from), ToSecond: uint32(*to), Whitelist: whitelist, StatusOnly: *status}
+	for {
+		s, err := client.SayReindex(context.Background(), req)
+		if err != nil {
+			return err
+		}
+
+		if !*wait || !s.Running {
+			fmt.Println(depths.DumpObj(s))
+			if s.Error != "" {
+				return errors.New(s.Error)
+			}
+			return nil
+		}
+
+		Log.Infof("reindexed %d out of %d segments", s.SegmentsDone, s.SegmentsTotal)
+		req = &spec.ReindexRequest{StatusOnly: true}
+		time.Sleep(1 * time.Second)
+	}
+}
+
+func stats(args []string) error {
+	fs := flag.NewFlagSet("stats", flag.ExitOnError)
+	t := addTargetFlags(fs)
+	var from = fs.Uint("from", 0, "segments from this second, 0 means all")
+	var to = fs.Uint("to", 0, "segments up to this second, 0 means all")
+	var top = fs.Uint("top-event-types", 10, "number of event types per segment")
+	_ = fs.Parse(args)
+	LogInit(*t.logLevel)
+
+	if t.offline() {
+		si := t.index()
+		defer si.Close()
+
+		fmt.Println(depths.DumpObj(si.Stats(uint32(*from), uint32(*to), int(*top))))
+		return nil
+	}
+
+	client, conn, err := t.client()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	s, err := client.SayStats(context.Background(), &spec.StatsRequest{FromSecond: uint32(*from), ToSecond: uint32(*to), TopEventTypes: uint32(*top)})
+	if err != nil {
+		return err
+	}
+	fmt.Println(depths.DumpObj(s))
+	return nil
+}
+
+func replay(args []string) error {
+	fs := flag.NewFlagSet("replay", flag.ExitOnError)
+	t := addTargetFlags(fs)
+	var from = fs.String("from", "", "dead letters to replay, file:<path> or kafka:<topic>, a file resumes from <path>.replayed, a topic from <progress>/<topic>.<partition>.replayed")
+	var progressDir = fs.String("progress", ".", "directory with the offsets a kafka:<topic> replay resumes from")
+	var failed = fs.String("failed", "", "where to write the dead letters that still fail, file:<path> or kafka:<topic>, nothing means they are only logged")
+	var kafkaServers = fs.String("kafka", "localhost:9092", "comma separated list of kafka servers")
+	var batchSize = fs.Int("batch-size", 500, "max events pushed to search at once")
+	var dryRun = fs.Bool("dry-run", false, "only check which dead letters would be replayed")
+	var restart = fs.Bool("restart", false, "ignore the .replayed offsets and replay the dead letters from the start")
+	_ = fs.Parse(args)
+	LogInit(*t.logLevel)
+
+	if *from == "" {
+		return errors.New("missing -from")
+	}
+
+	// how far the dead letters were replayed, stored after every push so
+	// running replay again resumes instead of pushing everything again, a
+	// topic has one offset per partition, a file only one
+	progress := map[int]*pen.OffsetWriter{}
+	done := map[int]int64{}
+	defer func() {
+		for _, p := range progress {
+			p.Close()
+		}
+	}()
+	resume := func(fn string, key int) (int64, error) {
+		if *dryRun {
+			return 0, nil
+		}
+		p, err := pen.NewOffsetWriter(fn)
+		if err != nil {
+			return 0, err
+		}
+		progress[key] = p
+		if !*restart {
+			done[key] = p.ReadOrDefault(0)
+		}
+		if done[key] > 0 {
+			Log.Infof("resuming %s from offset %d", fn, done[key])
+		}
+		return done[key], nil
+	}
+
+	var sink deadletter.Sink
+	if *failed != "" {
+		var err error
+		sink, err = deadletter.NewSink(*failed, *kafkaServers)
+		if err != nil {
+			return err
+		}
+		defer sink.Close()
+	}
+
+	var push func([]*spec.Envelope) error
+	if t.offline() {
+		si := t.index()
+		defer si.Close()
+
+		push = func(batch []*spec.Envelope) error {
+			for _, envelope := range batch {
+				err := si.Ingest(envelope)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	} else {
+		client, conn, err := t.client()
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		push = func(batch []*spec.Envelope) error {
+			stream, err := client.SayPush(context.Background())
+			if err != nil {
+				return err
+			}
+			for _, envelope := range batch {
+				err = stream.Send(envelope)
+				if err != nil {
+					return err
+				}
+			}
+			_, err = stream.CloseAndRecv()
+			return err
+		}
+	}
+
+	replayed, stillFailing := 0, 0
+	batch := []*spec.Envelope{}
+	flush := func() error {
+		if *dryRun {
+			batch = batch[:0]
+			return nil
+		}
+		if len(batch) > 0 {
+			err := push(batch)
+			batch = batch[:0]
+			if err != nil {
+				return err
+			}
+		}
+		for key, p := range progress {
+			err := p.SetOffset(done[key])
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	cb := func(d *spec.DeadLetter) error {
+		envelope := &spec.Envelope{}
+		err := proto.Unmarshal(d.Value, envelope)
+		if err == nil {
+			err = spec.ValidateEnvelope(envelope)
+		}
+		if err != nil {
+			stillFailing++
+			Log.Warnf("still failing, partition: %d, offset: %d, error: %s", d.Partition, d.Offset, err.Error())
+			if sink != nil && !*dryRun {
+				d.Reason = err.Error()
+				d.CreatedAtNs = 0
+				return sink.Write(d)
+			}
+			return nil
+		}
+
+		envelope.Metadata.Id = uint64(d.Partition)<<56 | uint64(d.Offset)
+		batch = append(batch, envelope)
+		replayed++
+		if len(batch) >= *batchSize {
+			return flush()
+		}
+		return nil
+	}
+
+	var err error
+	switch {
+	case strings.HasPrefix(*from, "file:"):
+		fn := strings.TrimPrefix(*from, "file:")
+		var start int64
+		start, err = resume(fn+".replayed", 0)
+		if err != nil {
+			return err
+		}
+		err = deadletter.ReadFileFrom(fn, uint32(start), func(d *spec.DeadLetter, next uint32) error {
+			done[0] = int64(next)
+			return cb(d)
+		})
+	case strings.HasPrefix(*from, "kafka:"):
+		topic := strings.TrimPrefix(*from, "kafka:")
+		err = deadletter.ReadTopicFrom(*kafkaServers, topic, deadletter.DefaultIdle, func(partition int) (int64, error) {
+			return resume(path.Join(*progressDir, fmt.Sprintf("%s.%d.replayed", topic, partition)), partition)
+		}, func(d *spec.DeadLetter, partition int, next int64) error {
+			done[partition] = next
+			return cb(d)
+		})
+	default:
+		err = deadletter.Read(*from, *kafkaServers, cb)
+	}
+	if err != nil {
+		return err
+	}
+
+	err = flush()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("replayed: %d, still failing: %d\n", replayed, stillFailing)
+	return nil
+}
+
+func signPixel(args []string) error {
+	fs := flag.NewFlagSet("sign-pixel", flag.ExitOnError)
+	var secretFile = fs.String("secret-file", "", "same file as orgrim -pixel-secret-file")
+	var path = fs.String("path", "", "pixel path, e.g. /png/click/user/5/campaign:x")
+	var ttl = fs.Duration("ttl", 0, "how long the url is valid, 0 means forever")
+	_ = fs.Parse(args)
+
+	if *secretFile == "" || !strings.HasPrefix(*path, "/png/") {
+		return errors.New("-secret-file and -path /png/... are required")
+	}
+	data, err := ioutil.ReadFile(*secretFile)
+	if err != nil {
+		return err
+	}
+	secret := bytes.TrimSpace(data)
+	if len(secret) == 0 {
+		return errors.New("empty pixel secret")
+	}
+
+	exp := int64(0)
+	query := ""
+	if *ttl > 0 {
+		exp = time.Now().Add(*ttl).Unix()
+		query = fmt.Sprintf("&exp=%d", exp)
+	}
+	fmt.Printf("%s?sig=%s%s\n", *path, auth.SignPixel(secret, *path, exp), query)
+	return nil
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: %s <command> [flags]\n\ncommands:\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "  backup   write a consistent snapshot of the segments\n")
+	fmt.Fprintf(os.Stderr, "  restore  validate a snapshot and load it into the index\n")
+	fmt.Fprintf(os.Stderr, "  fsck     check the forward and inverted index of the segments\n")
+	fmt.Fprintf(os.Stderr, "  reindex  rebuild the inverted index with a new whitelist\n")
+	fmt.Fprintf(os.Stderr, "  stats    print per segment statistics\n")
+	fmt.Fprintf(os.Stderr, "  replay   push the consumer dead letters again\n")
+	fmt.Fprintf(os.Stderr, "  sign-pixel  print a signed /png url for orgrim -pixel-secret-file\n")
+	fmt.Fprintf(os.Stderr, "\nrun '%s <command> -h' for the command flags\n", os.Args[0])
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "backup":
+		err = backup(os.Args[2:])
+	case "restore":
+		err = restore(os.Args[2:])
+	case "fsck":
+		err = fsck(os.Args[2:])
+	case "reindex":
+		err = reindex(os.Args[2:])
+	case "stats":
+		err = stats(os.Args[2:])
+	case "replay":
+		err = replay(os.Args[2:])
+	case "sign-pixel":
+		err = signPixel(os.Args[2:])
+	default:
+		usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		Log.Fatal(err)
+	}
+}
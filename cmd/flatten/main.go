@@ -1,8 +1,11 @@
 package main
 
 import (
+	"compress/gzip"
 	"context"
 	"flag"
+	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 
@@ -11,7 +14,10 @@ import (
 	"github.com/gogo/protobuf/jsonpb"
 	"github.com/gogo/protobuf/proto"
 	"github.com/oschwald/geoip2-golang"
+	"github.com/rekki/blackrock/pkg/auth"
 	spec "github.com/rekki/blackrock/pkg/blackrock_io"
+	"github.com/rekki/blackrock/pkg/certs"
+	"github.com/rekki/blackrock/pkg/logger"
 	log "github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
 
@@ -36,14 +42,94 @@ func UnmarshalAndClose(c *gin.Context, into proto.Message) error {
 
 	return err
 }
+
+// requireKey rejects the requests without a valid ingest key, nil keys
+// means no auth
+func requireKey(keys *auth.Keys) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if keys == nil {
+			return
+		}
+
+		key, err := keys.FromHTTP(c.Request)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		if !key.Can(auth.ScopeIngest) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "key " + key.Name + " can not " + auth.ScopeIngest})
+			return
+		}
+		c.Set("key", key)
+	}
+}
+
+// allowed checks the event_type against the key of the request
+func allowed(c *gin.Context, envelope *spec.Envelope) error {
+	key, ok := c.Get("key")
+	if !ok {
+		return nil
+	}
+	return key.(*auth.Key).CheckEnvelope(envelope)
+}
+
 func main() {
 	var bind = flag.String("bind", ":9001", "bind to")
+	var debugHttp = flag.String("debug-http", "localhost:6062", "bind to for /debug/vars with pixel_dropped and schema_rejections, keep it private, it shows the flags")
 	var remote = flag.String("producer-grpc", ":8001", "connect to producer grpc")
 	var geoipFile = flag.String("geoip", "", "path to https://dev.maxmind.com/geoip/geoip2/geolite2/ file")
+	var schemas = flag.String("schemas", "", "json file with the event_type schemas")
+	var logLevel = flag.Int("log-level", 0, "log level of the schema violations in dry run")
+	var flattenRules = flag.String("flatten-rules", "", "json file with the rules for /push/flatten per event_type, nothing means the default _id, _ids and _code expansion")
+	var maxBatch = flag.Int("max-batch", 1000, "max frames in one /push/batch request")
+	var maxBatchBytes = flag.Int64("max-batch-bytes", 16*1024*1024, "max bytes of one /push/batch request, before and after gzip")
+	var authKeys = flag.String("auth-keys", "", "json file with the api keys allowed to use /push, reloaded when it changes, nothing means no auth")
+	var apiKey = flag.String("api-key", "", "api key sent to the producer, it needs the ingest scope")
+	var pixelSecret = flag.String("pixel-secret-file", "", "file with the secret of the signed /png urls, they need ?sig=<hex hmac-sha256 of the path> and optionally &exp=<unix second>, see 'blackrock sign-pixel', nothing means no signature")
+	var pixelEventTypes = flag.String("pixel-event-types", "", "comma separated event types allowed on /png, nothing means all")
+	var pixelIPRate = flag.Float64("pixel-ip-rate", 0, "/png events per second per client ip, 0 means no limit")
+	var pixelIPBurst = flag.Int("pixel-ip-burst", 20, "/png burst per client ip")
+	var pixelTrustedProxies = flag.String("pixel-trusted-proxies", "", "comma separated ips or cidrs of the load balancers in front of orgrim, only their x-forwarded-for is used for -pixel-ip-rate, nothing means the ip of the connection")
+	var pixelForeignRate = flag.Float64("pixel-foreign-id-rate", 0, "/png events per second per foreign_type and foreign_id, 0 means no limit")
+	var pixelForeignBurst = flag.Int("pixel-foreign-id-burst", 20, "/png burst per foreign_type and foreign_id")
+	var pixelDropBots = flag.Bool("pixel-drop-bots", false, "drop the /png events with ua_is_bot:true")
+	tlsFlags := certs.AddFlags(flag.CommandLine)
 	flag.Parse()
 
-	var geoip *geoip2.Reader
+	logger.LogInit(*logLevel)
+
+	var schemaRegistry *spec.Schemas
 	var err error
+	if *schemas != "" {
+		schemaRegistry, err = spec.LoadSchemas(*schemas)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	var flattenConfig *spec.FlattenConfig
+	if *flattenRules != "" {
+		flattenConfig, err = spec.LoadFlattenConfig(*flattenRules)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	var keys *auth.Keys
+	if *authKeys != "" {
+		keys, err = auth.LoadKeys(*authKeys)
+		if err != nil {
+			log.Fatal(err)
+		}
+		go keys.Watch(context.Background(), 10*time.Second)
+	}
+
+	guard, err := newPixelGuard(*pixelSecret, *pixelEventTypes, *pixelIPRate, *pixelIPBurst, *pixelForeignRate, *pixelForeignBurst, *pixelDropBots, *pixelTrustedProxies)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var geoip *geoip2.Reader
 	if *geoipFile != "" {
 		geoip, err = geoip2.Open(*geoipFile)
 		if err != nil {
@@ -52,17 +138,32 @@ func main() {
 		defer geoip.Close()
 	}
 
+	go func() {
+		log.Info(http.ListenAndServe(*debugHttp, http.DefaultServeMux))
+	}()
+
 	r := gin.Default()
 	r.Use(gin.Recovery())
-	r.Use(cors.Default())
+	corsConfig := cors.DefaultConfig()
+	corsConfig.AllowAllOrigins = true
+	corsConfig.AddAllowHeaders("Authorization", "X-Api-Key")
+	r.Use(cors.New(corsConfig))
 
-	conn, err := grpc.Dial(*remote, grpc.WithInsecure())
+	dialOptions, err := tlsFlags.DialOptions()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *apiKey != "" {
+		dialOptions = append(dialOptions, auth.Credentials(*apiKey))
+	}
+	conn, err := grpc.Dial(*remote, dialOptions...)
 	if err != nil {
 		log.Fatal(err)
 	}
 	defer conn.Close()
 
 	enqueue := spec.NewEnqueueClient(conn)
+	pipeline := spec.NewPipeline(geoip, schemaRegistry)
 
 	r.GET("/health", func(c *gin.Context) {
 		_, err := enqueue.SayHealth(context.Background(), &spec.HealthRequest{})
@@ -95,14 +196,13 @@ func main() {
 			}
 			envelope.Metadata.Search = append(envelope.Metadata.Search, spec.KV{Key: kv[0], Value: kv[1]})
 		}
-		err = spec.Decorate(geoip, c.Request, envelope)
-		if err != nil {
-			log.Warnf("[orgrim] failed to decorate, err: %s", err.Error())
+		if !guard.allow(c, envelope.Metadata) {
+			return
 		}
-		err = spec.ValidateEnvelope(envelope)
+		err = pipeline.Process(envelope, c.Request)
 		if err != nil {
 			log.Warnf("[orgrim] invalid input, err: %s", err.Error())
-		} else {
+		} else if !guard.isBot(envelope.Metadata) {
 			stream, err := enqueue.SayPush(context.Background())
 			if err != nil {
 				log.Warnf("[orgrim] error sending message, metadata %v, err: %s", envelope.Metadata, err.Error())
@@ -124,7 +224,9 @@ func main() {
 		c.Data(200, "image/png", []byte{137, 80, 78, 71, 13, 10, 26, 10, 0, 0, 0, 13, 73, 72, 68, 82, 0, 0, 0, 1, 0, 0, 0, 1, 8, 6, 0, 0, 0, 31, 21, 196, 137, 0, 0, 0, 9, 112, 72, 89, 115, 0, 0, 11, 19, 0, 0, 11, 19, 1, 0, 154, 156, 24, 0, 0, 0, 1, 115, 82, 71, 66, 0, 174, 206, 28, 233, 0, 0, 0, 4, 103, 65, 77, 65, 0, 0, 177, 143, 11, 252, 97, 5, 0, 0, 0, 16, 73, 68, 65, 84, 120, 1, 1, 5, 0, 250, 255, 0, 0, 0, 0, 0, 0, 5, 0, 1, 100, 120, 149, 56, 0, 0, 0, 0, 73, 69, 78, 68, 174, 66, 96, 130})
 	})
 
-	r.POST("/push/envelope", func(c *gin.Context) {
+	push := r.Group("/push", requireKey(keys))
+
+	push.POST("/envelope", func(c *gin.Context) {
 		var envelope spec.Envelope
 		err := UnmarshalAndClose(c, &envelope)
 		if err != nil {
@@ -132,6 +234,15 @@ func main() {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
+		err = pipeline.Process(&envelope, nil)
+		if err == nil {
+			err = allowed(c, &envelope)
+		}
+		if err != nil {
+			log.Warnf("[orgrim] invalid input, err: %s", err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 
 		stream, err := enqueue.SayPush(context.Background())
 		if err != nil {
@@ -148,29 +259,38 @@ func main() {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
 		}
-		_, err = stream.CloseAndRecv()
+		res, err := stream.CloseAndRecv()
 		if err != nil {
 			log.Warnf("[orgrim] error sending message, metadata %v, err: %s", envelope.Metadata, err.Error())
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
 		}
+		if len(res.Rejections) > 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": res.Rejections[0].Reason})
+			return
+		}
 
 		c.JSON(200, gin.H{"success": true})
 	})
 
-	r.POST("/push/flatten", func(c *gin.Context) {
+	push.POST("/flatten", func(c *gin.Context) {
 		body := c.Request.Body
 		defer body.Close()
 
-		converted, err := spec.DecodeAndFlatten(body)
+		converted, err := spec.DecodeAndFlatten(body, flattenConfig)
 		if err != nil {
 			log.Warnf("[orgrim] invalid input, err: %s", err.Error())
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
-		err = spec.Decorate(geoip, c.Request, converted)
+		err = pipeline.Process(converted, c.Request)
+		if err == nil {
+			err = allowed(c, converted)
+		}
 		if err != nil {
-			log.Warnf("[orgrim] failed to decorate, err: %s", err.Error())
+			log.Warnf("[orgrim] invalid input, err: %s", err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
 		}
 
 		stream, err := enqueue.SayPush(context.Background())
@@ -186,14 +306,105 @@ func main() {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
 		}
-		_, err = stream.CloseAndRecv()
+		res, err := stream.CloseAndRecv()
 		if err != nil {
 			log.Warnf("[orgrim] error sending message, metadata %v, err: %s", converted.Metadata, err.Error())
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
 		}
+		if len(res.Rejections) > 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": res.Rejections[0].Reason})
+			return
+		}
 
 		c.JSON(200, gin.H{"success": true})
 	})
-	log.Panic(r.Run(*bind))
+	// json array or one frame per line, results are in the order of the
+	// frames, empty lines are ignored
+	push.POST("/batch", func(c *gin.Context) {
+		body := http.MaxBytesReader(c.Writer, c.Request.Body, *maxBatchBytes)
+		defer body.Close()
+
+		reader := io.Reader(body)
+		var inflated *io.LimitedReader
+		if c.Request.Header.Get("content-encoding") == "gzip" {
+			gz, err := gzip.NewReader(body)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			defer gz.Close()
+			// a small body can inflate to a lot
+			inflated = &io.LimitedReader{R: gz, N: *maxBatchBytes + 1}
+			reader = inflated
+		}
+
+		results := []gin.H{}
+		batch := []*spec.Envelope{}
+		positions := []int{}
+		err := spec.DecodeFrames(reader, *maxBatch, func(frame *spec.JsonFrame, err error) error {
+			var converted *spec.Envelope
+			if err == nil {
+				converted, err = spec.FlattenFrame(frame, flattenConfig)
+			}
+			if err == nil {
+				err = pipeline.Process(converted, c.Request)
+			}
+			if err == nil {
+				err = allowed(c, converted)
+			}
+			if err != nil {
+				results = append(results, gin.H{"success": false, "error": err.Error()})
+				return nil
+			}
+
+			results = append(results, gin.H{"success": true})
+			positions = append(positions, len(results)-1)
+			batch = append(batch, converted)
+			return nil
+		})
+		if inflated != nil && inflated.N == 0 {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("batch is larger than %d bytes after gzip", *maxBatchBytes)})
+			return
+		}
+		if err != nil {
+			log.Warnf("[orgrim] invalid batch, err: %s", err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if len(batch) > 0 {
+			stream, err := enqueue.SayPush(context.Background())
+			if err != nil {
+				log.Warnf("[orgrim] error sending batch of %d, err: %s", len(batch), err.Error())
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+			for _, envelope := range batch {
+				err = stream.Send(envelope)
+				if err != nil {
+					_, _ = stream.CloseAndRecv() // close anyway
+
+					log.Warnf("[orgrim] error sending batch of %d, err: %s", len(batch), err.Error())
+					c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+					return
+				}
+			}
+			res, err := stream.CloseAndRecv()
+			if err != nil {
+				log.Warnf("[orgrim] error sending batch of %d, err: %s", len(batch), err.Error())
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+			for _, rejection := range res.Rejections {
+				if int(rejection.Index) < len(positions) {
+					results[positions[rejection.Index]] = gin.H{"success": false, "error": rejection.Reason}
+				}
+			}
+		}
+
+		c.JSON(200, gin.H{"results": results})
+	})
+
+	log.Panic(tlsFlags.ListenAndServe(*bind, r))
 }
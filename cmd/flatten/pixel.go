@@ -0,0 +1,182 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"expvar"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rekki/blackrock/pkg/auth"
+	spec "github.com/rekki/blackrock/pkg/blackrock_io"
+	"github.com/rekki/blackrock/pkg/ratelimit"
+)
+
+var errEmptySecret = errors.New("empty pixel secret")
+
+var pixelDropped = expvar.NewMap("pixel_dropped")
+
+// pixelGuard protects /png, every check is off when its flag is not set
+type pixelGuard struct {
+	secret     []byte
+	eventTypes map[string]bool
+	perIP      *ratelimit.Limiter
+	perForeign *ratelimit.Limiter
+	dropBots   bool
+	trusted    []*net.IPNet
+}
+
+func readSecret(filename string) ([]byte, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, errEmptySecret
+	}
+	return data, nil
+}
+
+func parseTrusted(s string) ([]*net.IPNet, error) {
+	out := []*net.IPNet{}
+	for _, p := range strings.Split(s, ",") {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		if !strings.Contains(p, "/") {
+			if strings.Contains(p, ":") {
+				p += "/128"
+			} else {
+				p += "/32"
+			}
+		}
+		_, n, err := net.ParseCIDR(p)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, n)
+	}
+	return out, nil
+}
+
+func newPixelGuard(secretFile string, eventTypes string, ipRate float64, ipBurst int, foreignRate float64, foreignBurst int, dropBots bool, trustedProxies string) (*pixelGuard, error) {
+	g := &pixelGuard{dropBots: dropBots}
+	trusted, err := parseTrusted(trustedProxies)
+	if err != nil {
+		return nil, err
+	}
+	g.trusted = trusted
+	if secretFile != "" {
+		secret, err := readSecret(secretFile)
+		if err != nil {
+			return nil, err
+		}
+		g.secret = secret
+	}
+	if eventTypes != "" {
+		g.eventTypes = map[string]bool{}
+		for _, t := range strings.Split(eventTypes, ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				g.eventTypes[t] = true
+			}
+		}
+	}
+	if ipRate > 0 {
+		g.perIP = ratelimit.NewLimiter(ipRate, ipBurst, 100000)
+	}
+	if foreignRate > 0 {
+		g.perForeign = ratelimit.NewLimiter(foreignRate, foreignBurst, 100000)
+	}
+	return g, nil
+}
+
+func (g *pixelGuard) isTrusted(ip string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, n := range g.trusted {
+		if n.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
+// clientIP is the ip the rate limit is keyed on, x-forwarded-for is only
+// read when the connection comes from a trusted proxy, and then the last
+// entry that is not a trusted proxy is the client, anything before it was
+// sent by the client and can be anything
+func (g *pixelGuard) clientIP(r *http.Request) string {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+	if !g.isTrusted(ip) {
+		return ip
+	}
+
+	forwarded := []string{}
+	for _, h := range r.Header["X-Forwarded-For"] {
+		forwarded = append(forwarded, strings.Split(h, ",")...)
+	}
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(forwarded[i])
+		if hop == "" {
+			continue
+		}
+		if !g.isTrusted(hop) {
+			return hop
+		}
+		ip = hop
+	}
+	return ip
+}
+
+func (g *pixelGuard) drop(c *gin.Context, code int, reason string) bool {
+	pixelDropped.Add(reason, 1)
+	c.AbortWithStatus(code)
+	return false
+}
+
+// allow checks the request before the envelope is processed, it aborts
+// with 403 or 429 and returns false when the pixel must be dropped
+func (g *pixelGuard) allow(c *gin.Context, m *spec.Metadata) bool {
+	if g.secret != nil {
+		err := auth.VerifyPixel(g.secret, c.Request.URL.Path, c.Query("exp"), c.Query("sig"), time.Now())
+		if err != nil {
+			return g.drop(c, http.StatusForbidden, "signature")
+		}
+	}
+	if g.eventTypes != nil && !g.eventTypes[m.EventType] {
+		return g.drop(c, http.StatusForbidden, "event_type")
+	}
+	if g.perIP != nil && !g.perIP.Allow(g.clientIP(c.Request)) {
+		return g.drop(c, http.StatusTooManyRequests, "ip")
+	}
+	if g.perForeign != nil && !g.perForeign.Allow(m.ForeignType+"\x00"+m.ForeignId) {
+		return g.drop(c, http.StatusTooManyRequests, "foreign_id")
+	}
+	return true
+}
+
+// isBot is checked after the pipeline decorated the envelope, the bots
+// still get the png so they don't retry
+func (g *pixelGuard) isBot(m *spec.Metadata) bool {
+	if !g.dropBots {
+		return false
+	}
+	for _, kv := range m.Search {
+		if kv.Key == "ua_is_bot" && kv.Value == "true" {
+			pixelDropped.Add("bot", 1)
+			return true
+		}
+	}
+	return false
+}
@@ -3,128 +3,306 @@ package main
 import (
 	"context"
 	"errors"
+	"expvar"
 	"fmt"
 	"path"
-	"strings"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/gofrs/flock"
 	"github.com/gogo/protobuf/proto"
 	spec "github.com/rekki/blackrock/pkg/blackrock_io"
-	"github.com/rekki/blackrock/pkg/depths"
+	"github.com/rekki/blackrock/pkg/deadletter"
 	"github.com/rekki/blackrock/pkg/logger"
+	"github.com/rekki/blackrock/pkg/queue"
 	pen "github.com/rekki/go-pen"
-	"github.com/segmentio/kafka-go"
 )
 
-func consumeEvents(si spec.SearchClient, root string, pr *PartitionReader) error {
+var errBatchAborted = errors.New("shutting down before the batch was acknowledged")
+
+var partitionLag = expvar.NewMap("partition_lag")
+
+type batchConfig struct {
+	size         int
+	wait         time.Duration
+	maxBackoff   time.Duration
+	drainTimeout time.Duration
+	schemas      *spec.Schemas
+}
+
+type messageFetcher interface {
+	FetchMessage(ctx context.Context) (queue.Message, error)
+}
+
+// partitionLags keeps the subscription of every partition for reportLag
+type partitionLags struct {
+	subscriptions map[int]queue.Subscription
+	sync.Mutex
+}
+
+func (p *partitionLags) set(partition int, s queue.Subscription) {
+	p.Lock()
+	defer p.Unlock()
+	p.subscriptions[partition] = s
+}
+
+func (p *partitionLags) lags() map[string]int64 {
+	p.Lock()
+	defer p.Unlock()
+	out := map[string]int64{}
+	for partition, s := range p.subscriptions {
+		out[strconv.Itoa(partition)] = s.Lag()
+	}
+	return out
+}
+
+func consumeEvents(ctx context.Context, si spec.SearchClient, root string, q queue.Queue, partition int, cfg batchConfig, deadLetters deadletter.Sink, lags *partitionLags) error {
 	l := logger.Log
-	fileLock := flock.New(path.Join(root, fmt.Sprintf("partition_%d.lock", pr.Partition.ID)))
+	fileLock := flock.New(path.Join(root, fmt.Sprintf("partition_%d.lock", partition)))
 	err := fileLock.Lock()
 	if err != nil {
 		return err
 	}
 	defer fileLock.Close() // also unlocks
 
-	ow, err := pen.NewOffsetWriter(path.Join(root, fmt.Sprintf("partition_%d.offset", pr.Partition.ID)))
+	ow, err := pen.NewOffsetWriter(path.Join(root, fmt.Sprintf("partition_%d.offset", partition)))
 	if err != nil {
 		return err
 	}
 	defer ow.Close() // close also syncs
 
-	offset := ow.ReadOrDefault(kafka.FirstOffset)
+	offset := ow.ReadOrDefault(queue.FirstOffset)
 
-	if offset != kafka.FirstOffset {
-		offset++ // start from the next one, as we already stored the current one
+	// the offsets are also kept in the index and replicated with it, so
+	// after a follower is promoted we continue from what it has
+	stats, err := si.SayStats(ctx, &spec.StatsRequest{TopEventTypes: 1})
+	if err != nil {
+		return err
+	}
+	if indexed, ok := stats.PartitionOffsets[int32(partition)]; ok && (offset == queue.FirstOffset || indexed > offset) {
+		l.Infof("partition: %d, index is ahead at offset: %d, local offset: %d", partition, indexed, offset)
+		offset = indexed
 	}
-	err = pr.Reader.SetOffset(offset)
+
+	// starts from the next one, as we already stored the current one
+	sub, err := q.Subscribe(partition, offset)
 	if err != nil {
 		return err
 	}
+	defer sub.Close()
+	lags.set(partition, sub)
 
-	l.Infof("starting partition: %d at offset: %d", pr.Partition.ID, offset)
-	ctx := context.Background()
+	l.Infof("starting partition: %d after offset: %d", partition, offset)
+	return consumeBatches(ctx, si, sub, cfg, deadLetters, func(last []queue.Message) error {
+		// only our partition
+		return ow.SetOffset(last[0].Offset)
+	})
+}
+
+// consumeBatches pushes the messages in batches of cfg.size, or whatever
+// arrived within cfg.wait after the first message of the batch, commit is
+// called with the offset of the last message only after search acknowledged
+// the batch. When ctx is done the current batch is still pushed, we give it
+// cfg.drainTimeout.
+//
+// A batch that failed in the middle is pushed again, so events can be
+// ingested twice, never lost. Messages that can not be decoded or are not
+// valid go to the dead letters.
+func consumeBatches(ctx context.Context, si spec.SearchClient, r messageFetcher, cfg batchConfig, deadLetters deadletter.Sink, commit func([]queue.Message) error) error {
+	pushCtx, cancel := drainContext(ctx, cfg.drainTimeout)
+	defer cancel()
 
 	for {
-		m, err := pr.Reader.FetchMessage(ctx)
-		if err != nil {
-			return err
-		}
+		batch, last, fetchErr := fetchBatch(ctx, r, cfg, deadLetters)
+		if len(last) > 0 {
+			if len(batch) > 0 {
+				err := pushWithRetry(pushCtx, si, batch, cfg.maxBackoff)
+				if err != nil {
+					return err
+				}
+			}
 
-		envelope := spec.Envelope{}
-		err = proto.Unmarshal(m.Value, &envelope)
-		if err != nil {
-			l.Warnf("failed to unmarshal, data: %s, error: %s", string(m.Value), err.Error())
-			continue
+			err := commit(last)
+			if err != nil {
+				return err
+			}
+			for _, m := range last {
+				logger.Log.Infof("consumed events at partition: %d, up to offset: %d", m.Partition, m.Offset)
+			}
 		}
 
-		if envelope.Metadata != nil {
-			envelope.Metadata.Id = uint64(m.Partition)<<56 | uint64(m.Offset)
+		if fetchErr != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fetchErr
 		}
+	}
+}
+
+// fetchBatch returns the valid envelopes and the last message of every
+// partition in the batch, the rest is written to the dead letters, but
+// still counts for the offset
+func fetchBatch(ctx context.Context, r messageFetcher, cfg batchConfig, deadLetters deadletter.Sink) ([]*spec.Envelope, []queue.Message, error) {
+	batch := []*spec.Envelope{}
+	last := []queue.Message{}
 
-		// FIXME: use one stream complicates because message can be in the wire, but it is not guaranteed to be written
-		// so we do one by one
-		stream, err := si.SayPush(context.Background())
+	fetchCtx := ctx
+	for n := 0; n < cfg.size; n++ {
+		m, err := r.FetchMessage(fetchCtx)
 		if err != nil {
-			panic(err)
+			if fetchCtx != ctx && ctx.Err() == nil && fetchCtx.Err() != nil {
+				// the batch is old enough
+				return batch, last, nil
+			}
+			return batch, last, err
 		}
 
-		err = stream.Send(&envelope)
-		if err != nil {
-			panic(err)
+		if len(last) == 0 {
+			var cancel context.CancelFunc
+			fetchCtx, cancel = context.WithTimeout(ctx, cfg.wait)
+			defer cancel()
 		}
+		last = setLast(last, m)
 
-		_, err = stream.CloseAndRecv()
+		envelope := &spec.Envelope{}
+		err = proto.Unmarshal(m.Value, envelope)
+		if err == nil {
+			// the same checks search does, without changing the
+			// envelope, one bad event would fail the whole batch
+			err = spec.ValidateEnvelope(envelope)
+		}
+		if err == nil {
+			err = cfg.schemas.Check(envelope)
+		}
 		if err != nil {
-			panic(err)
+			logger.Log.Warnf("dead letter at partition: %d, offset: %d, error: %s", m.Partition, m.Offset, err.Error())
+			derr := deadLetters.Write(&spec.DeadLetter{Value: m.Value, Topic: m.Topic, Partition: int32(m.Partition), Offset: m.Offset, Reason: err.Error()})
+			if derr != nil {
+				return batch, last, derr
+			}
+			continue
 		}
 
-		l.Infof("consumed event at partition: %d, offset: %d", m.Partition, m.Offset)
-		err = ow.SetOffset(m.Offset)
+		envelope.Metadata.Id = uint64(m.Partition)<<56 | uint64(m.Offset)
+		batch = append(batch, envelope)
+	}
+	return batch, last, nil
+}
+
+func setLast(last []queue.Message, m queue.Message) []queue.Message {
+	for i := range last {
+		if last[i].Partition == m.Partition {
+			last[i] = m
+			return last
+		}
+	}
+	return append(last, m)
+}
+
+func pushBatch(ctx context.Context, si spec.SearchClient, batch []*spec.Envelope) error {
+	stream, err := si.SayPush(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, envelope := range batch {
+		err = stream.Send(envelope)
 		if err != nil {
-			panic(err)
+			return err
 		}
 	}
+
+	// search replies only after everything is ingested
+	_, err = stream.CloseAndRecv()
+	return err
 }
 
-func ReadPartitions(brokers string, topic string) ([]kafka.Partition, error) {
-	for _, b := range depths.ShuffledStrings(strings.Split(brokers, ",")) {
-		conn, err := kafka.Dial("tcp", b)
+func pushWithRetry(ctx context.Context, si spec.SearchClient, batch []*spec.Envelope, maxBackoff time.Duration) error {
+	backoff := 100 * time.Millisecond
+	for {
+		err := pushBatch(ctx, si, batch)
 		if err == nil {
-			p, err := conn.ReadPartitions(topic)
-			conn.Close()
-			if err == nil {
-				return p, nil
-			}
+			return nil
+		}
+
+		if ctx.Err() != nil {
+			return errBatchAborted
+		}
+
+		logger.Log.Warnf("failed to push %d events, retrying in %s, err: %s", len(batch), backoff, err.Error())
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return errBatchAborted
+		}
+
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
 		}
 	}
-	return nil, errors.New("failed to get partitions, assuming we cant reach kafka")
 }
 
-type PartitionReader struct {
-	Reader    *kafka.Reader
-	Partition kafka.Partition
+// drainContext is done timeout after ctx is done, so the last batch has a
+// chance to be pushed
+func drainContext(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	drain, cancel := context.WithCancel(context.Background())
+	go func() {
+		select {
+		case <-ctx.Done():
+			select {
+			case <-time.After(timeout):
+				cancel()
+			case <-drain.Done():
+			}
+		case <-drain.Done():
+		}
+	}()
+	return drain, cancel
 }
 
-func consumeKafka(si spec.SearchClient, root, dataTopic, kafkaServers string) error {
-	partitions, err := ReadPartitions(kafkaServers, dataTopic)
+func consumePartitions(ctx context.Context, si spec.SearchClient, root string, q queue.Queue, cfg batchConfig, lagEvery time.Duration, deadLetters deadletter.Sink) error {
+	partitions, err := q.Partitions()
 	if err != nil {
 		return err
 	}
 
-	brokers := strings.Split(kafkaServers, ",")
-	readers := []*PartitionReader{}
-	for _, p := range partitions {
-		rd := kafka.NewReader(kafka.ReaderConfig{
-			Brokers:   brokers,
-			Topic:     dataTopic,
-			MaxWait:   1 * time.Second,
-			Partition: p.ID,
-		})
-		readers = append(readers, &PartitionReader{rd, p})
+	lags := &partitionLags{subscriptions: map[int]queue.Subscription{}}
+	go reportLag(ctx, lagEvery, lags.lags)
+
+	err = consumeEventsFromAllPartitions(ctx, si, root, q, partitions, cfg, deadLetters, lags)
+	if err != nil {
+		logger.Log.Warnf("error consuming events: %s", err.Error())
+		return err
 	}
+	return nil
+}
+
+// consumeGroup joins the consumer group, kafka assigns the partitions and
+// rebalances them when consumers or partitions come and go. The offsets are
+// committed to kafka after every acknowledged batch, so there are no local
+// offsets and the partition offsets in the index are not used.
+func consumeGroup(ctx context.Context, si spec.SearchClient, k *queue.Kafka, group string, cfg batchConfig, lagEvery time.Duration, deadLetters deadletter.Sink) error {
+	g := k.SubscribeGroup(group)
+	defer g.Close()
 
-	err = consumeEventsFromAllPartitions(si, root, readers)
+	// per partition lag is not available in group mode
+	go reportLag(ctx, lagEvery, func() map[string]int64 {
+		return map[string]int64{group: g.Lag()}
+	})
+
+	logger.Log.Infof("joining group: %s", group)
+	err := consumeBatches(ctx, si, g, cfg, deadLetters, func(last []queue.Message) error {
+		// not ctx, the drained batch is committed too
+		err := g.Commit(context.Background(), last...)
+		if err != nil {
+			// most likely rebalanced, the new owner gets the batch again
+			logger.Log.Warnf("failed to commit, err: %s", err.Error())
+		}
+		return nil
+	})
 	if err != nil {
 		logger.Log.Warnf("error consuming events: %s", err.Error())
 		return err
@@ -132,24 +310,44 @@ func consumeKafka(si spec.SearchClient, root, dataTopic, kafkaServers string) er
 	return nil
 }
 
-func consumeEventsFromAllPartitions(si spec.SearchClient, root string, pr []*PartitionReader) error {
-	errChan := make(chan error)
+func reportLag(ctx context.Context, every time.Duration, lags func() map[string]int64) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(every):
+		}
+
+		for k, lag := range lags() {
+			v := &expvar.Int{}
+			v.Set(lag)
+			partitionLag.Set(k, v)
+			logger.Log.Infof("partition: %s, lag: %d", k, lag)
+		}
+	}
+}
 
-	for _, p := range pr {
-		go func(p *PartitionReader) {
-			errChan <- consumeEvents(si, root, p)
+// consumeEventsFromAllPartitions stops all partitions when one fails, every
+// partition drains its current batch before returning
+func consumeEventsFromAllPartitions(ctx context.Context, si spec.SearchClient, root string, q queue.Queue, partitions []int, cfg batchConfig, deadLetters deadletter.Sink, lags *partitionLags) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	errChan := make(chan error)
+	for _, p := range partitions {
+		go func(p int) {
+			errChan <- consumeEvents(ctx, si, root, q, p, cfg, deadLetters, lags)
 		}(p)
 	}
 
 	var lastError error
-	for range pr {
+	for range partitions {
 		err := <-errChan
-		lastError = err
-		logger.Log.Warnf("received error: %s", err)
-		for _, p := range pr {
-			p.Reader.Close()
+		if err != nil {
+			lastError = err
+			logger.Log.Warnf("received error: %s", err)
+			cancel()
 		}
 	}
-
 	return lastError
 }
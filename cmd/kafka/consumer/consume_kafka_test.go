@@ -0,0 +1,394 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/rekki/blackrock/pkg/auth"
+	spec "github.com/rekki/blackrock/pkg/blackrock_io"
+	"github.com/rekki/blackrock/pkg/logger"
+	"github.com/rekki/blackrock/pkg/queue"
+	"google.golang.org/grpc"
+)
+
+func init() {
+	logger.LogInit(3)
+}
+
+type fakeFetcher struct {
+	messages chan queue.Message
+}
+
+func (f *fakeFetcher) FetchMessage(ctx context.Context) (queue.Message, error) {
+	select {
+	case m := <-f.messages:
+		return m, nil
+	case <-ctx.Done():
+		return queue.Message{}, ctx.Err()
+	}
+}
+
+type fakeSearch struct {
+	spec.SearchClient
+	failures int
+	batches  [][]*spec.Envelope
+	sync.Mutex
+}
+
+func (f *fakeSearch) SayPush(ctx context.Context, opts ...grpc.CallOption) (spec.Search_SayPushClient, error) {
+	return &fakePushStream{search: f}, nil
+}
+
+type fakePushStream struct {
+	grpc.ClientStream
+	search *fakeSearch
+	batch  []*spec.Envelope
+}
+
+func (s *fakePushStream) Send(e *spec.Envelope) error {
+	s.batch = append(s.batch, e)
+	return nil
+}
+
+func (s *fakePushStream) CloseAndRecv() (*spec.Success, error) {
+	s.search.Lock()
+	defer s.search.Unlock()
+	if s.search.failures != 0 {
+		s.search.failures--
+		return nil, errors.New("search is down")
+	}
+	s.search.batches = append(s.search.batches, s.batch)
+	return &spec.Success{Success: true}, nil
+}
+
+func (f *fakeSearch) SayStats(ctx context.Context, in *spec.StatsRequest, opts ...grpc.CallOption) (*spec.StatsResponse, error) {
+	return &spec.StatsResponse{}, nil
+}
+
+func (f *fakeSearch) pushed() [][]*spec.Envelope {
+	f.Lock()
+	defer f.Unlock()
+	return f.batches
+}
+
+type memorySink struct {
+	letters []*spec.DeadLetter
+}
+
+func (s *memorySink) Write(d *spec.DeadLetter) error {
+	s.letters = append(s.letters, d)
+	return nil
+}
+
+func (s *memorySink) Close() error {
+	return nil
+}
+
+func newFakeFetcher(t *testing.T, n int) *fakeFetcher {
+	f := &fakeFetcher{messages: make(chan queue.Message, n)}
+	for i := 0; i < n; i++ {
+		value := []byte("garbage")
+		if i != 3 {
+			meta := &spec.Metadata{EventType: "click", ForeignType: "user", ForeignId: "a"}
+			if i == 7 {
+				meta.ForeignId = ""
+			}
+			var err error
+			value, err = proto.Marshal(&spec.Envelope{Metadata: meta})
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		f.messages <- queue.Message{Partition: 2, Offset: int64(i), Value: value}
+	}
+	return f
+}
+
+func TestConsumeBatches(t *testing.T) {
+	fetcher := newFakeFetcher(t, 25)
+	search := &fakeSearch{failures: 2}
+	cfg := batchConfig{size: 10, wait: 50 * time.Millisecond, maxBackoff: time.Millisecond, drainTimeout: time.Second}
+
+	deadLetters := &memorySink{}
+	ctx, cancel := context.WithCancel(context.Background())
+	var lock sync.Mutex
+	commits := []int64{}
+	done := make(chan error)
+	go func() {
+		done <- consumeBatches(ctx, search, fetcher, cfg, deadLetters, func(last []queue.Message) error {
+			lock.Lock()
+			defer lock.Unlock()
+			if n := len(search.pushed()); n != len(commits)+1 {
+				t.Errorf("commit before the batch was acknowledged, batches: %d commits: %d", n, len(commits))
+			}
+			if len(last) != 1 {
+				t.Errorf("expected one partition got %d", len(last))
+			}
+			commits = append(commits, last[0].Offset)
+			return nil
+		})
+	}()
+
+	time.Sleep(500 * time.Millisecond)
+	cancel()
+	err := <-done
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	batches := search.pushed()
+	if len(batches) != 3 || len(batches[0]) != 8 || len(batches[1]) != 10 || len(batches[2]) != 5 {
+		t.Fatalf("unexpected batches %d", len(batches))
+	}
+	if batches[1][0].Metadata.Id != 2<<56|10 {
+		t.Fatalf("unexpected id %d", batches[1][0].Metadata.Id)
+	}
+	if len(commits) != 3 || commits[0] != 9 || commits[1] != 19 || commits[2] != 24 {
+		t.Fatalf("unexpected commits %v", commits)
+	}
+
+	if len(deadLetters.letters) != 2 {
+		t.Fatalf("expected 2 dead letters got %d", len(deadLetters.letters))
+	}
+	d := deadLetters.letters[1]
+	if d.Partition != 2 || d.Offset != 7 || d.Reason == "" || len(d.Value) == 0 {
+		t.Fatalf("unexpected dead letter %+v", d)
+	}
+}
+
+func TestFetchBatchPartitions(t *testing.T) {
+	fetcher := &fakeFetcher{messages: make(chan queue.Message, 10)}
+	for i := 0; i < 10; i++ {
+		value, err := proto.Marshal(&spec.Envelope{Metadata: &spec.Metadata{EventType: "click", ForeignType: "user", ForeignId: "a"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		fetcher.messages <- queue.Message{Partition: i % 3, Offset: int64(100 + i), Value: value}
+	}
+
+	batch, last, err := fetchBatch(context.Background(), fetcher, batchConfig{size: 10, wait: time.Second}, &memorySink{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(batch) != 10 || len(last) != 3 {
+		t.Fatalf("unexpected batch %d %d", len(batch), len(last))
+	}
+	for i, offset := range []int64{109, 107, 108} {
+		if last[i].Partition != i || last[i].Offset != offset {
+			t.Fatalf("unexpected last %d: %d", last[i].Partition, last[i].Offset)
+		}
+	}
+}
+
+func TestConsumeBatchesDrain(t *testing.T) {
+	// the batch is pushed even if we are asked to stop while waiting for it
+	// to fill up
+	fetcher := newFakeFetcher(t, 5)
+	search := &fakeSearch{}
+	cfg := batchConfig{size: 10, wait: time.Hour, maxBackoff: time.Millisecond, drainTimeout: time.Second}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	committed := int64(-1)
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		cancel()
+	}()
+	err := consumeBatches(ctx, search, fetcher, cfg, &memorySink{}, func(last []queue.Message) error {
+		committed = last[0].Offset
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if committed != 4 || len(search.pushed()) != 1 {
+		t.Fatalf("expected the batch to be drained, committed: %d", committed)
+	}
+
+	// search is down, we give up after the drain timeout without committing
+	fetcher = newFakeFetcher(t, 5)
+	search = &fakeSearch{failures: 1000000}
+	cfg.drainTimeout = 100 * time.Millisecond
+	ctx, cancel = context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		cancel()
+	}()
+	committed = -1
+	err = consumeBatches(ctx, search, fetcher, cfg, &memorySink{}, func(last []queue.Message) error {
+		committed = last[0].Offset
+		return nil
+	})
+	if err != errBatchAborted || committed != -1 {
+		t.Fatalf("expected aborted batch, got %v committed: %d", err, committed)
+	}
+}
+
+func publishEvents(t *testing.T, q queue.Queue, n int) {
+	for i := 0; i < n; i++ {
+		value, err := proto.Marshal(&spec.Envelope{Metadata: &spec.Metadata{EventType: "click", ForeignType: "user", ForeignId: "a"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = q.Publish(context.Background(), value)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestConsumePartitionsFileQueue(t *testing.T) {
+	dir, err := ioutil.TempDir("", "consumer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	q, err := queue.Open("file:"+path.Join(dir, "queue"), "", "blackrock-data")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer q.Close()
+
+	cfg := batchConfig{size: 10, wait: 10 * time.Millisecond, maxBackoff: time.Millisecond, drainTimeout: time.Second}
+	consume := func(search *fakeSearch, expected int) {
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error)
+		go func() {
+			done <- consumePartitions(ctx, search, dir, q, cfg, time.Hour, &memorySink{})
+		}()
+
+		for i := 0; i < 100; i++ {
+			n := 0
+			for _, b := range search.pushed() {
+				n += len(b)
+			}
+			if n == expected {
+				break
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+		err := <-done
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		n := 0
+		for _, b := range search.pushed() {
+			n += len(b)
+		}
+		if n != expected {
+			t.Fatalf("expected %d events got %d", expected, n)
+		}
+	}
+
+	publishEvents(t, q, 25)
+	consume(&fakeSearch{}, 25)
+
+	// continues after the stored offset
+	publishEvents(t, q, 5)
+	search := &fakeSearch{}
+	consume(search, 5)
+	if search.pushed()[0][0].Metadata.Id>>56 != 0 {
+		t.Fatalf("unexpected id %d", search.pushed()[0][0].Metadata.Id)
+	}
+}
+
+type ingestOnlySearch struct {
+	spec.UnimplementedSearchServer
+	pushed int
+	sync.Mutex
+}
+
+func (s *ingestOnlySearch) SayPush(stream spec.Search_SayPushServer) error {
+	for {
+		_, err := stream.Recv()
+		if err == io.EOF {
+			return stream.SendAndClose(&spec.Success{Success: true})
+		}
+		if err != nil {
+			return err
+		}
+		s.Lock()
+		s.pushed++
+		s.Unlock()
+	}
+}
+
+func (s *ingestOnlySearch) SayStats(ctx context.Context, in *spec.StatsRequest) (*spec.StatsResponse, error) {
+	return &spec.StatsResponse{Docs: 1, PartitionOffsets: map[int32]int64{}}, nil
+}
+
+func TestConsumeIngestKey(t *testing.T) {
+	dir, err := ioutil.TempDir("", "consumer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	err = ioutil.WriteFile(path.Join(dir, "keys.json"), []byte(`{"keys": [{"key": "consumer", "scopes": ["ingest"]}]}`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys, err := auth.LoadKeys(path.Join(dir, "keys.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	grpcServer := grpc.NewServer(logger.AddLogging([]grpc.ServerOption{}, keys.Interceptor())...)
+	search := &ingestOnlySearch{}
+	spec.RegisterSearchServer(grpcServer, search)
+	go grpcServer.Serve(lis)
+	defer grpcServer.Stop()
+
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithInsecure(), auth.Credentials("consumer"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	q, err := queue.Open("file:"+path.Join(dir, "queue"), "", "blackrock-data")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer q.Close()
+	publishEvents(t, q, 5)
+
+	cfg := batchConfig{size: 10, wait: 10 * time.Millisecond, maxBackoff: time.Millisecond, drainTimeout: time.Second}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- consumePartitions(ctx, spec.NewSearchClient(conn), dir, q, cfg, time.Hour, &memorySink{})
+	}()
+
+	pushed := func() int {
+		search.Lock()
+		defer search.Unlock()
+		return search.pushed
+	}
+	for i := 0; i < 100 && pushed() < 5; i++ {
+		time.Sleep(20 * time.Millisecond)
+	}
+	cancel()
+	err = <-done
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := pushed(); n != 5 {
+		t.Fatalf("expected 5 events got %d", n)
+	}
+}
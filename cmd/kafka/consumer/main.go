@@ -3,11 +3,19 @@ package main
 import (
 	"context"
 	"flag"
+	"net/http"
 	"os"
+	"os/signal"
+	"path"
+	"syscall"
 	"time"
 
+	"github.com/rekki/blackrock/pkg/auth"
 	spec "github.com/rekki/blackrock/pkg/blackrock_io"
+	"github.com/rekki/blackrock/pkg/certs"
+	"github.com/rekki/blackrock/pkg/deadletter"
 	. "github.com/rekki/blackrock/pkg/logger"
+	"github.com/rekki/blackrock/pkg/queue"
 	_ "github.com/segmentio/kafka-go/snappy"
 
 	"google.golang.org/grpc"
@@ -17,11 +25,50 @@ func main() {
 	var remote = flag.String("search-grpc", ":8002", "connect to search grpc")
 	var dataTopic = flag.String("topic-data", "blackrock-data", "topic for the data")
 	var root = flag.String("root", "/blackrock", "root where to store the kafka offsets and locks")
+	var group = flag.String("group", "", "kafka consumer group, partitions are assigned by kafka and offsets committed there, nothing means one reader per partition with offsets in root")
 	var kafkaServers = flag.String("kafka", "localhost:9092", "comma separated list of kafka servers")
+	var pqueue = flag.String("queue", "kafka", "where to consume from, kafka or file:<dir> for the file log the producer writes to <dir>/<topic-data>")
 	var logLevel = flag.Int("log-level", 0, "log level")
+	var batchSize = flag.Int("batch-size", 500, "max events pushed to search at once")
+	var batchWait = flag.Duration("batch-wait", time.Second, "max time to wait for a batch to fill up")
+	var maxBackoff = flag.Duration("max-backoff", 30*time.Second, "max wait between retries when search fails")
+	var drainTimeout = flag.Duration("drain-timeout", 30*time.Second, "how long to keep trying to push the last batch on shutdown")
+	var lagEvery = flag.Duration("lag-every", 10*time.Second, "how often to log and update the per partition lag")
+	var pdeadLetters = flag.String("dead-letters", "", "where to store the events that can not be ingested, file:<path> or kafka:<topic>, default is file:<root>/dead_letters.bin")
+	var debugHttp = flag.String("debug-http", "localhost:6061", "bind to for /debug/vars with the lag per partition")
+	var schemas = flag.String("schemas", "", "json file with the event_type schemas, use the same as the producer so the events it would reject go to the dead letters")
+	var apiKey = flag.String("api-key", "", "api key sent to search, it needs the ingest scope, with it SayStats only returns the partition offsets the consumer starts from")
+	var tenant = flag.String("tenant", "", "tenant of the events when search runs with -tenants, not needed if the api key has one")
+	tlsFlags := certs.AddFlags(flag.CommandLine)
 	flag.Parse()
 	LogInit(*logLevel)
 
+	var schemaRegistry *spec.Schemas
+	if *schemas != "" {
+		var err error
+		schemaRegistry, err = spec.LoadSchemas(*schemas)
+		if err != nil {
+			Log.Fatal(err)
+		}
+	}
+
+	go func() {
+		Log.Info(tlsFlags.ListenAndServe(*debugHttp, http.DefaultServeMux))
+	}()
+
+	if *pdeadLetters == "" {
+		*pdeadLetters = "file:" + path.Join(*root, "dead_letters.bin")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-sigs
+		Log.Warnf("draining the current batches...")
+		cancel()
+	}()
+
 	err := os.MkdirAll(*root, 0700)
 	if err != nil {
 		Log.Fatal(err)
@@ -31,10 +78,21 @@ func main() {
 		conn *grpc.ClientConn
 	)
 
+	dialOptions, err := tlsFlags.DialOptions()
+	if err != nil {
+		Log.Fatal(err)
+	}
+	if *apiKey != "" {
+		dialOptions = append(dialOptions, auth.Credentials(*apiKey))
+	}
+	if *tenant != "" {
+		dialOptions = append(dialOptions, auth.Tenant(*tenant))
+	}
+
 	// just keep trying every second
 
 	for {
-		conn, err = grpc.Dial(*remote, grpc.WithInsecure())
+		conn, err = grpc.Dial(*remote, dialOptions...)
 		if err != nil {
 			Log.Warnf("error connecting, sleeping 1 second, err: %v", err.Error())
 			time.Sleep(1 * time.Second)
@@ -54,7 +112,28 @@ func main() {
 
 		break
 	}
-	err = consumeKafka(si, *root, *dataTopic, *kafkaServers)
+	deadLetters, err := deadletter.NewSink(*pdeadLetters, *kafkaServers)
+	if err != nil {
+		Log.Fatal(err)
+	}
+	defer deadLetters.Close()
+
+	q, err := queue.Open(*pqueue, *kafkaServers, *dataTopic)
+	if err != nil {
+		Log.Fatal(err)
+	}
+	defer q.Close()
+
+	cfg := batchConfig{size: *batchSize, wait: *batchWait, maxBackoff: *maxBackoff, drainTimeout: *drainTimeout, schemas: schemaRegistry}
+	if *group != "" {
+		k, ok := q.(*queue.Kafka)
+		if !ok {
+			Log.Fatal("-group needs -queue kafka")
+		}
+		err = consumeGroup(ctx, si, k, *group, cfg, *lagEvery, deadLetters)
+	} else {
+		err = consumePartitions(ctx, si, *root, q, cfg, *lagEvery, deadLetters)
+	}
 	if err != nil {
 		conn.Close()
 		Log.Fatalf("failed to run the proxy, err: %s", err.Error())
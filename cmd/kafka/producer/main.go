@@ -2,36 +2,87 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"io"
 	"net"
-	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 
 	"github.com/gogo/protobuf/proto"
 	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"github.com/oschwald/geoip2-golang"
+	"github.com/rekki/blackrock/pkg/auth"
 	spec "github.com/rekki/blackrock/pkg/blackrock_io"
+	"github.com/rekki/blackrock/pkg/certs"
 	"github.com/rekki/blackrock/pkg/depths"
 	. "github.com/rekki/blackrock/pkg/logger"
-	"github.com/segmentio/kafka-go"
-	"github.com/segmentio/kafka-go/snappy"
+	"github.com/rekki/blackrock/pkg/queue"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 
-	"strings"
 	"time"
 )
 
+var errNoSync = errors.New("the queue does not support synchronous pushes")
+
+// syncHeader asks for a synchronous push when the server is asynchronous by
+// default, over http it is the Grpc-Metadata-Blackrock-Sync header
+const syncHeader = "blackrock-sync"
+
+// defaultSyncChunk is how many envelopes of a synchronous push are
+// published at once
+const defaultSyncChunk = 500
+
 type server struct {
-	kw           *kafka.Writer
-	kafkaServers string
-	topic        string
+	q         queue.Queue
+	sync      bool
+	syncChunk int
+	pipeline  *spec.Pipeline
+	schemas   *spec.Schemas
 }
 
+func (s *server) wantsSync(ctx context.Context) bool {
+	if s.sync {
+		return true
+	}
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return false
+	}
+	for _, v := range md.Get(syncHeader) {
+		if v == "true" || v == "1" {
+			return true
+		}
+	}
+	return false
+}
+
+// SayPush publishes every envelope as it arrives, unless it is synchronous,
+// then they are published in chunks and the reply has a receipt for each of
+// them. Envelopes that do not pass the pipeline are not published, the
+// reply says which and why. Use SayPushSync to get the receipts while
+// pushing.
 func (s *server) SayPush(stream spec.Enqueue_SayPushServer) error {
+	if s.wantsSync(stream.Context()) {
+		out := &spec.Success{Success: true}
+		err := s.publishChunks(stream, func(chunk *spec.Success) error {
+			out.Success = out.Success && chunk.Success
+			out.Receipts = append(out.Receipts, chunk.Receipts...)
+			out.Rejections = append(out.Rejections, chunk.Rejections...)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		return stream.SendAndClose(out)
+	}
+
 	ctx := context.Background()
-	for {
+	r := spec.RequestFromContext(stream.Context())
+	out := &spec.Success{}
+	for i := int32(0); ; i++ {
 		envelope, err := stream.Recv()
 		if err == io.EOF {
 			break
@@ -40,8 +91,10 @@ func (s *server) SayPush(stream spec.Enqueue_SayPushServer) error {
 			return err
 		}
 
-		if envelope.Metadata.CreatedAtNs == 0 {
-			envelope.Metadata.CreatedAtNs = time.Now().UnixNano()
+		err = s.pipeline.Process(envelope, r)
+		if err != nil {
+			out.Rejections = append(out.Rejections, &spec.Rejection{Index: i, Reason: err.Error()})
+			continue
 		}
 
 		encoded, err := proto.Marshal(envelope)
@@ -49,35 +102,135 @@ func (s *server) SayPush(stream spec.Enqueue_SayPushServer) error {
 			return err
 		}
 
-		err = s.kw.WriteMessages(ctx, kafka.Message{
-			Value: encoded,
-		})
+		err = s.q.Publish(ctx, encoded)
+		if err != nil {
+			return err
+		}
+	}
 
+	out.Success = len(out.Rejections) == 0
+	return stream.SendAndClose(out)
+}
+
+// SayPushSync publishes the envelopes in chunks, and replies after every
+// chunk is stored with its receipts and rejections
+func (s *server) SayPushSync(stream spec.Enqueue_SayPushSyncServer) error {
+	return s.publishChunks(stream, stream.Send)
+}
+
+type envelopeStream interface {
+	Recv() (*spec.Envelope, error)
+	Context() context.Context
+}
+
+// publishChunks publishes synchronously syncChunk envelopes at a time, so
+// only that many are held in memory, reply is called after every chunk
+func (s *server) publishChunks(stream envelopeStream, reply func(*spec.Success) error) error {
+	publisher, ok := s.q.(queue.SyncPublisher)
+	if !ok {
+		return errNoSync
+	}
+	size := s.syncChunk
+	if size <= 0 {
+		size = defaultSyncChunk
+	}
+
+	r := spec.RequestFromContext(stream.Context())
+	out := &spec.Success{Success: true}
+	pending := [][]byte{}
+	indexes := []int32{}
+	flush := func() error {
+		if len(pending) == 0 && len(out.Rejections) == 0 {
+			return nil
+		}
+		if len(pending) > 0 {
+			receipts, err := publisher.PublishSync(stream.Context(), pending...)
+			if err != nil {
+				return err
+			}
+			for i, r := range receipts {
+				out.Receipts = append(out.Receipts, &spec.Receipt{Partition: int32(r.Partition), Offset: r.Offset, Id: uint64(r.Partition)<<56 | uint64(r.Offset), Index: indexes[i]})
+			}
+		}
+		err := reply(out)
+		out = &spec.Success{Success: true}
+		pending = pending[:0]
+		indexes = indexes[:0]
+		return err
+	}
+
+	for i := int32(0); ; i++ {
+		envelope, err := stream.Recv()
+		if err == io.EOF {
+			return flush()
+		}
 		if err != nil {
 			return err
 		}
 
+		err = s.pipeline.Process(envelope, r)
+		if err != nil {
+			out.Success = false
+			out.Rejections = append(out.Rejections, &spec.Rejection{Index: i, Reason: err.Error()})
+			continue
+		}
+
+		encoded, err := proto.Marshal(envelope)
+		if err != nil {
+			return err
+		}
+		pending = append(pending, encoded)
+		indexes = append(indexes, i)
+		if len(pending) >= size {
+			err = flush()
+			if err != nil {
+				return err
+			}
+		}
 	}
-	return stream.SendAndClose(&spec.Success{Success: true})
 }
 
-func runProxy(bindHttp string, bindGrpc string) error {
+func (s *server) SaySchemas(ctx context.Context, in *spec.SchemaRequest) (*spec.SchemaRegistry, error) {
+	return s.schemas.Registry(), nil
+}
+
+func (s *server) SaySetSchemas(ctx context.Context, in *spec.SchemaRegistry) (*spec.SchemaRegistry, error) {
+	err := s.schemas.Set(in)
+	if err != nil {
+		return nil, err
+	}
+	return s.schemas.Registry(), nil
+}
+
+func runProxy(bindHttp string, bindGrpc string, tlsFlags *certs.Flags) error {
 	ctx := context.Background()
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
-	mux := runtime.NewServeMux()
-	opts := []grpc.DialOption{grpc.WithInsecure()}
-	err := spec.RegisterEnqueueHandlerFromEndpoint(ctx, mux, bindGrpc, opts)
+	opts, err := tlsFlags.LocalDialOptions()
 	if err != nil {
 		return err
 	}
+	mux, err := newProxy(ctx, bindGrpc, opts)
+	if err != nil {
+		return err
+	}
+
+	return tlsFlags.ListenAndServe(bindHttp, mux)
+}
 
-	return http.ListenAndServe(bindHttp, mux)
+// newProxy is the grpc gateway in front of the grpc server at bindGrpc
+func newProxy(ctx context.Context, bindGrpc string, opts []grpc.DialOption) (*runtime.ServeMux, error) {
+	mux := runtime.NewServeMux(runtime.WithIncomingHeaderMatcher(auth.HeaderMatcher))
+	err := spec.RegisterEnqueueHandlerFromEndpoint(ctx, mux, bindGrpc, opts)
+	if err != nil {
+		return nil, err
+	}
+	return mux, nil
 }
 
 func (s *server) SayHealth(ctx context.Context, in *spec.HealthRequest) (*spec.Success, error) {
-	err := depths.HealthCheckKafka(s.kafkaServers, s.topic)
+	err := s.q.Health()
 	if err != nil {
 		return nil, err
 	}
@@ -88,50 +241,89 @@ func (s *server) SayHealth(ctx context.Context, in *spec.HealthRequest) (*spec.S
 func main() {
 	var dataTopic = flag.String("topic-data", "blackrock-data", "topic for the data")
 	var kafkaServers = flag.String("kafka", "localhost:9092", "comma separated list of kafka servers")
+	var pqueue = flag.String("queue", "kafka", "where to publish, kafka or file:<dir> for a local file log in <dir>/<topic-data>")
+	var geoipFile = flag.String("geoip", "", "path to https://dev.maxmind.com/geoip/geoip2/geolite2/ file, used for pushes through the http gateway")
+	var schemas = flag.String("schemas", "", "json file with the event_type schemas, changes made with /api/v1/admin/schemas are saved there")
+	var psync = flag.Bool("sync", false, "reply only after the events are stored, with their partition and offset, without it clients can still ask for it with the blackrock-sync: true metadata")
+	var syncChunk = flag.Int("sync-chunk", defaultSyncChunk, "synchronous pushes are published and acknowledged this many envelopes at a time")
+	var requiredAcks = flag.Int("required-acks", -1, "for synchronous pushes to kafka, -1 waits for all replicas, 1 only for the leader")
 	var statSleep = flag.Int("writer-stats", 60, "print writer stats every # seconds")
 	var logLevel = flag.Int("log-level", 0, "log level")
 	var bindHttp = flag.String("http", ":9001", "bind http")
 	var bindGrpc = flag.String("grpc", ":8001", "bind grpc")
+	var authKeys = flag.String("auth-keys", "", "json file with the api keys and their scopes, reloaded when it changes, nothing means no auth")
+	tlsFlags := certs.AddFlags(flag.CommandLine)
 	flag.Parse()
 
 	LogInit(*logLevel)
 
-	err := depths.HealthCheckKafka(*kafkaServers, *dataTopic)
+	var interceptors []Interceptor
+	if *authKeys != "" {
+		keys, err := auth.LoadKeys(*authKeys)
+		if err != nil {
+			Log.Fatal(err)
+		}
+		go keys.Watch(context.Background(), 10*time.Second)
+		interceptors = append(interceptors, keys.Interceptor())
+	}
+
+	schemaRegistry := spec.NewSchemas()
+	if *schemas != "" {
+		var err error
+		schemaRegistry, err = spec.LoadSchemas(*schemas)
+		if err != nil {
+			Log.Fatal(err)
+		}
+	}
+
+	q, err := queue.Open(*pqueue, *kafkaServers, *dataTopic)
 	if err != nil {
-		Log.Fatal(err.Error())
+		Log.Fatal(err)
 	}
+	defer q.Close()
 
-	kw := kafka.NewWriter(kafka.WriterConfig{
-		Brokers:          strings.Split(*kafkaServers, ","),
-		Topic:            *dataTopic,
-		Balancer:         &kafka.LeastBytes{},
-		BatchTimeout:     1 * time.Second,
-		CompressionCodec: snappy.NewCompressionCodec(),
-		Async:            true,
-	})
-	defer kw.Close()
+	err = q.Health()
+	if err != nil {
+		Log.Fatal(err.Error())
+	}
 
-	srv := &server{
-		kafkaServers: *kafkaServers,
-		kw:           kw,
-		topic:        *dataTopic,
+	var geoip *geoip2.Reader
+	if *geoipFile != "" {
+		geoip, err = geoip2.Open(*geoipFile)
+		if err != nil {
+			Log.Fatal(err)
+		}
+		defer geoip.Close()
 	}
 
-	go func() {
-		for {
-			s := kw.Stats()
-			Log.Infof("%s\n", depths.DumpObj(s))
+	srv := &server{q: q, sync: *psync, syncChunk: *syncChunk, pipeline: spec.NewPipeline(geoip, schemaRegistry), schemas: schemaRegistry}
 
-			time.Sleep(time.Duration(*statSleep) * time.Second)
+	if kw, ok := q.(*queue.Kafka); ok {
+		err = kw.SetRequiredAcks(*requiredAcks)
+		if err != nil {
+			Log.Fatal(err)
 		}
-	}()
+
+		go func() {
+			for {
+				s := kw.Stats()
+				Log.Infof("%s\n", depths.DumpObj(s))
+
+				time.Sleep(time.Duration(*statSleep) * time.Second)
+			}
+		}()
+	}
 
 	lis, err := net.Listen("tcp", *bindGrpc)
 	if err != nil {
 		Log.Fatalf("failed to listen: %v", err)
 	}
 
-	grpcServer := grpc.NewServer(AddLogging([]grpc.ServerOption{})...)
+	serverOptions, err := tlsFlags.ServerOptions()
+	if err != nil {
+		Log.Fatal(err)
+	}
+	grpcServer := grpc.NewServer(AddLogging(serverOptions, interceptors...)...)
 	spec.RegisterEnqueueServer(grpcServer, srv)
 
 	sigs := make(chan os.Signal, 1)
@@ -139,15 +331,15 @@ func main() {
 	go func() {
 		<-sigs
 		Log.Warnf("closing the writer...")
-		kw.Close()
+		q.Close()
 		os.Exit(0)
 	}()
 
 	go func() {
-		err := runProxy(*bindHttp, *bindGrpc)
+		err := runProxy(*bindHttp, *bindGrpc, tlsFlags)
 		if err != nil {
 			Log.Warnf("failed to run the proxy, err: %s", err.Error())
-			kw.Close()
+			q.Close()
 			os.Exit(0)
 		}
 	}()
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/rekki/blackrock/pkg/auth"
+	spec "github.com/rekki/blackrock/pkg/blackrock_io"
+	"github.com/rekki/blackrock/pkg/logger"
+	"github.com/rekki/blackrock/pkg/queue"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func init() {
+	logger.LogInit(3)
+}
+
+func TestProducerFileQueue(t *testing.T) {
+	dir, err := ioutil.TempDir("", "producer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	q, err := queue.Open("file:"+dir, "", "blackrock-data")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer q.Close()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	grpcServer := grpc.NewServer()
+	spec.RegisterEnqueueServer(grpcServer, &server{q: q, syncChunk: 2, pipeline: spec.NewPipeline(nil, nil), schemas: spec.NewSchemas()})
+	go func() {
+		_ = grpcServer.Serve(lis)
+	}()
+	defer grpcServer.Stop()
+
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	client := spec.NewEnqueueClient(conn)
+
+	_, err = client.SayHealth(context.Background(), &spec.HealthRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	push := func(ctx context.Context, ids ...string) *spec.Success {
+		t.Helper()
+		stream, err := client.SayPush(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, id := range ids {
+			err = stream.Send(&spec.Envelope{Metadata: &spec.Metadata{EventType: "click", ForeignType: "user", ForeignId: id}})
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		out, err := stream.CloseAndRecv()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return out
+	}
+
+	out := push(context.Background(), "a", "b")
+	if len(out.Receipts) != 0 {
+		t.Fatalf("expected no receipts for asynchronous push")
+	}
+
+	if !out.Success || len(out.Rejections) != 0 {
+		t.Fatalf("unexpected rejections %v", out.Rejections)
+	}
+
+	// the client asks for it, the invalid envelope is not published
+	out = push(metadata.AppendToOutgoingContext(context.Background(), syncHeader, "true"), "c", "", "d")
+	if len(out.Receipts) != 2 || out.Receipts[1].Index != 2 {
+		t.Fatalf("expected 2 receipts got %v", out.Receipts)
+	}
+	if out.Success || len(out.Rejections) != 1 || out.Rejections[0].Index != 1 || out.Rejections[0].Reason == "" {
+		t.Fatalf("unexpected rejections %v", out.Rejections)
+	}
+	receipts := map[string]*spec.Receipt{"c": out.Receipts[0], "d": out.Receipts[1]}
+
+	sub, err := queue.NewFileQueue(dir, "blackrock-data").Subscribe(0, queue.FirstOffset)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Close()
+	for _, id := range []string{"a", "b", "c", "d"} {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		m, err := sub.FetchMessage(ctx)
+		cancel()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		envelope := &spec.Envelope{}
+		err = proto.Unmarshal(m.Value, envelope)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if envelope.Metadata.ForeignId != id || envelope.Metadata.CreatedAtNs == 0 {
+			t.Fatalf("unexpected envelope %+v", envelope.Metadata)
+		}
+		if r, ok := receipts[id]; ok && (r.Offset != m.Offset || r.Partition != 0 || r.Id != uint64(m.Offset)) {
+			t.Fatalf("unexpected receipt %+v for offset %d", r, m.Offset)
+		}
+	}
+
+	// more than a chunk in one synchronous push
+	out = push(metadata.AppendToOutgoingContext(context.Background(), syncHeader, "true"), "e", "f", "g", "h", "i")
+	if !out.Success || len(out.Receipts) != 5 || out.Receipts[4].Index != 4 || out.Receipts[4].Offset <= out.Receipts[0].Offset {
+		t.Fatalf("expected 5 receipts got %v", out.Receipts)
+	}
+
+	// the receipts of a chunk come back while still pushing
+	ss, err := client.SayPushSync(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"j", "", "k"} {
+		err = ss.Send(&spec.Envelope{Metadata: &spec.Metadata{EventType: "click", ForeignType: "user", ForeignId: id}})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	chunk, err := ss.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if chunk.Success || len(chunk.Receipts) != 2 || chunk.Receipts[1].Index != 2 || len(chunk.Rejections) != 1 || chunk.Rejections[0].Index != 1 {
+		t.Fatalf("unexpected chunk %v", chunk)
+	}
+	err = ss.Send(&spec.Envelope{Metadata: &spec.Metadata{EventType: "click", ForeignType: "user", ForeignId: "l"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ss.CloseSend()
+	if err != nil {
+		t.Fatal(err)
+	}
+	chunk, err = ss.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !chunk.Success || len(chunk.Receipts) != 1 || chunk.Receipts[0].Index != 3 {
+		t.Fatalf("unexpected chunk %v", chunk)
+	}
+	if _, err = ss.Recv(); err != io.EOF {
+		t.Fatalf("expected eof got %v", err)
+	}
+}
+
+func TestProxyApiKey(t *testing.T) {
+	dir, err := ioutil.TempDir("", "producer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	err = ioutil.WriteFile(path.Join(dir, "keys.json"), []byte(`{"keys": [
+		{"key": "admin", "name": "admin", "scopes": ["admin"]},
+		{"key": "ingest", "name": "ingest", "scopes": ["ingest"]}
+	]}`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys, err := auth.LoadKeys(path.Join(dir, "keys.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	q, err := queue.Open("file:"+dir, "", "blackrock-data")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer q.Close()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	grpcServer := grpc.NewServer(logger.AddLogging([]grpc.ServerOption{}, keys.Interceptor())...)
+	spec.RegisterEnqueueServer(grpcServer, &server{q: q, pipeline: spec.NewPipeline(nil, nil), schemas: spec.NewSchemas()})
+	go func() {
+		_ = grpcServer.Serve(lis)
+	}()
+	defer grpcServer.Stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	mux, err := newProxy(ctx, lis.Addr().String(), []grpc.DialOption{grpc.WithInsecure()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	proxy := httptest.NewServer(mux)
+	defer proxy.Close()
+
+	for key, expected := range map[string]int{"admin": http.StatusOK, "ingest": http.StatusForbidden, "": http.StatusUnauthorized} {
+		r, err := http.NewRequest("GET", proxy.URL+"/api/v1/admin/schemas", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if key != "" {
+			r.Header.Set("X-Api-Key", key)
+		}
+		res, err := http.DefaultClient.Do(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+		if res.StatusCode != expected {
+			t.Fatalf("key %q: expected %d got %d", key, expected, res.StatusCode)
+		}
+	}
+}
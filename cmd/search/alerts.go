@@ -0,0 +1,250 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gogo/protobuf/jsonpb"
+	spec "github.com/rekki/blackrock/pkg/blackrock_io"
+	"github.com/rekki/blackrock/pkg/index"
+	. "github.com/rekki/blackrock/pkg/logger"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var errAlertsNeedIndex = status.Error(codes.Unimplemented, "alerts need a local index, they are not supported with -shards or -tenants")
+
+const (
+	alertAbove  = "above"
+	alertBelow  = "below"
+	alertChange = "change"
+)
+
+func loadAlertRules(filename string) ([]*spec.AlertRule, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	rules := &spec.AlertRules{}
+	err = jsonpb.Unmarshal(f, rules)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	for _, r := range rules.Rules {
+		if r.Name == "" || seen[r.Name] {
+			return nil, fmt.Errorf("alert %q: the names must be set and unique", r.Name)
+		}
+		seen[r.Name] = true
+		if r.Query == nil || r.WindowSec == 0 {
+			return nil, fmt.Errorf("alert %s: query and window_sec are required", r.Name)
+		}
+		if r.Kind != alertAbove && r.Kind != alertBelow && r.Kind != alertChange {
+			return nil, fmt.Errorf("alert %s: unknown kind %q, use above, below or change", r.Name, r.Kind)
+		}
+	}
+	return rules.Rules, nil
+}
+
+func isFiring(r *spec.AlertRule, count, previous uint64) bool {
+	switch r.Kind {
+	case alertAbove:
+		return float64(count) > r.Threshold
+	case alertBelow:
+		return float64(count) < r.Threshold
+	default:
+		base := math.Max(float64(previous), 1)
+		return math.Abs(float64(count)-float64(previous))/base > r.Threshold
+	}
+}
+
+func describe(r *spec.AlertRule, firing bool, count, previous uint64) string {
+	state := "resolved"
+	if firing {
+		state = "firing"
+	}
+	if r.Kind == alertChange {
+		return fmt.Sprintf("%s %s: %d events in the last %ds, %d in the window before, threshold %g", r.Name, state, count, r.WindowSec, previous, r.Threshold)
+	}
+	return fmt.Sprintf("%s %s: %d events in the last %ds, threshold %s %g", r.Name, state, count, r.WindowSec, r.Kind, r.Threshold)
+}
+
+// webhook posts the events as json, nothing means they are only logged
+func webhook(url string, timeout time.Duration) func(*spec.AlertEvent) error {
+	if url == "" {
+		return func(e *spec.AlertEvent) error {
+			Log.Warnf("alert %s", e.Message)
+			return nil
+		}
+	}
+
+	client := &http.Client{Timeout: timeout}
+	marshaler := &jsonpb.Marshaler{OrigName: true, EmitDefaults: true}
+	return func(e *spec.AlertEvent) error {
+		body, err := marshaler.MarshalToString(e)
+		if err != nil {
+			return err
+		}
+		res, err := client.Post(url, "application/json", strings.NewReader(body))
+		if err != nil {
+			return err
+		}
+		defer res.Body.Close()
+		_, _ = io.Copy(ioutil.Discard, res.Body)
+		if res.StatusCode/100 != 2 {
+			return fmt.Errorf("webhook returned %s", res.Status)
+		}
+		return nil
+	}
+}
+
+// alerter counts the events of the rules in the index, and notifies when
+// an alert starts or stops firing, the states and the history are only in
+// memory
+type alerter struct {
+	si         *index.SearchIndex
+	rules      []*spec.AlertRule
+	notify     func(*spec.AlertEvent) error
+	states     map[string]*spec.AlertState
+	history    []*spec.AlertEvent
+	maxHistory int
+	sync.Mutex
+}
+
+func newAlerter(si *index.SearchIndex, rules []*spec.AlertRule, notify func(*spec.AlertEvent) error, maxHistory int) *alerter {
+	a := &alerter{si: si, rules: rules, notify: notify, states: map[string]*spec.AlertState{}, maxHistory: maxHistory}
+	for _, r := range rules {
+		a.states[r.Name] = &spec.AlertState{Rule: r}
+	}
+	return a
+}
+
+// count returns the events in the last window and in the one before
+func (a *alerter) count(r *spec.AlertRule, now time.Time) (uint64, uint64, error) {
+	window := time.Duration(r.WindowSec) * time.Second
+	split := now.Add(-window).UnixNano()
+	from := now.Add(-2 * window).UnixNano()
+	to := now.UnixNano()
+
+	qr := &spec.SearchQueryRequest{
+		FromSecond: uint32(now.Add(-2 * window).Unix()),
+		ToSecond:   uint32(now.Unix()),
+		Query:      r.Query,
+	}
+	count := uint64(0)
+	previous := uint64(0)
+	err := a.si.ForEach(qr, 0, func(segment *index.Segment, did int32, score float32) error {
+		m := &spec.BasicMetadata{}
+		err := segment.ReadForwardDecode(did, m)
+		if err != nil {
+			return err
+		}
+		// the segments are bigger than the windows
+		if m.CreatedAtNs >= split && m.CreatedAtNs < to {
+			count++
+		} else if m.CreatedAtNs >= from && m.CreatedAtNs < split {
+			previous++
+		}
+		return nil
+	})
+	return count, previous, err
+}
+
+// check evaluates all the rules once
+func (a *alerter) check(now time.Time) {
+	for _, r := range a.rules {
+		count, previous, err := a.count(r, now)
+
+		a.Lock()
+		state := a.states[r.Name]
+		state.CheckedAtNs = now.UnixNano()
+		if err != nil {
+			state.Error = err.Error()
+			a.Unlock()
+			Log.Warnf("failed to check alert %s, err: %s", r.Name, err.Error())
+			continue
+		}
+		state.Error = ""
+		state.Count = count
+		state.Previous = previous
+
+		firing := isFiring(r, count, previous)
+		if firing == state.Firing {
+			a.Unlock()
+			continue
+		}
+		state.Firing = firing
+		state.ChangedAtNs = now.UnixNano()
+		event := &spec.AlertEvent{
+			Name:     r.Name,
+			Firing:   firing,
+			Count:    count,
+			Previous: previous,
+			AtNs:     now.UnixNano(),
+			Message:  describe(r, firing, count, previous),
+		}
+		a.history = append(a.history, event)
+		if len(a.history) > a.maxHistory {
+			a.history = a.history[len(a.history)-a.maxHistory:]
+		}
+		a.Unlock()
+
+		err = a.notify(event)
+		if err != nil {
+			Log.Warnf("failed to notify alert %s, err: %s", r.Name, err.Error())
+			a.Lock()
+			state.Error = "notify: " + err.Error()
+			a.Unlock()
+		}
+	}
+}
+
+func (a *alerter) run(ctx context.Context, every time.Duration) {
+	for {
+		a.check(time.Now())
+		select {
+		case <-time.After(every):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (a *alerter) SayAlerts(ctx context.Context, in *spec.AlertsRequest) (*spec.AlertsResponse, error) {
+	out := &spec.AlertsResponse{}
+	a.Lock()
+	defer a.Unlock()
+	for _, s := range a.states {
+		if in.Name != "" && in.Name != s.Rule.Name {
+			continue
+		}
+		copied := *s
+		out.States = append(out.States, &copied)
+	}
+	for _, e := range a.history {
+		if in.Name != "" && in.Name != e.Name {
+			continue
+		}
+		out.History = append(out.History, e)
+	}
+	if in.Name != "" && len(out.States) == 0 {
+		return nil, status.Errorf(codes.NotFound, "no alert %s", in.Name)
+	}
+
+	sort.Slice(out.States, func(i, j int) bool {
+		return out.States[i].Rule.Name < out.States[j].Rule.Name
+	})
+	return out, nil
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/jsonpb"
+	spec "github.com/rekki/blackrock/pkg/blackrock_io"
+)
+
+func TestAlerts(t *testing.T) {
+	root, err := ioutil.TempDir("", "alerts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	err = ioutil.WriteFile(path.Join(root, "alerts.json"), []byte(`{"rules": [
+		{"name": "many-clicks", "query": {"type": "TERM", "field": "event_type", "value": "click"}, "window_sec": 300, "kind": "above", "threshold": 5},
+		{"name": "no-views", "query": {"type": "TERM", "field": "event_type", "value": "view"}, "window_sec": 300, "kind": "below", "threshold": 1},
+		{"name": "clicks-changed", "query": {"type": "TERM", "field": "event_type", "value": "click"}, "window_sec": 300, "kind": "change", "threshold": 0.5}
+	]}`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules, err := loadAlertRules(path.Join(root, "alerts.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, bad := range []string{
+		`{"rules": [{"name": "x", "query": {"type": "TERM", "field": "a", "value": "b"}, "window_sec": 60, "kind": "sideways"}]}`,
+		`{"rules": [{"name": "x", "window_sec": 60, "kind": "above"}]}`,
+		`{"rules": [{"query": {"type": "TERM", "field": "a", "value": "b"}, "window_sec": 60, "kind": "above"}]}`,
+	} {
+		_ = ioutil.WriteFile(path.Join(root, "bad.json"), []byte(bad), 0600)
+		if _, err := loadAlertRules(path.Join(root, "bad.json")); err == nil {
+			t.Fatalf("expected error for %s", bad)
+		}
+	}
+
+	received := make(chan *spec.AlertEvent, 100)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		e := &spec.AlertEvent{}
+		err := jsonpb.Unmarshal(r.Body, e)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		received <- e
+	}))
+	defer receiver.Close()
+
+	s := newServer(path.Join(root, "data"), 100, 3600, false, "", "", time.Hour, time.Hour, "", 0)
+	a := newAlerter(s.si, rules, webhook(receiver.URL, time.Second), 4)
+	s.alerts = a
+
+	now := time.Now()
+	for i := 0; i < 10; i++ {
+		err := s.si.Ingest(&spec.Envelope{Metadata: &spec.Metadata{
+			CreatedAtNs: now.Add(-time.Duration(i) * time.Second).UnixNano(),
+			EventType:   "click",
+			ForeignType: "user",
+			ForeignId:   fmt.Sprintf("%d", i),
+		}})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	firing := func() map[string]bool {
+		res, err := s.SayAlerts(context.Background(), &spec.AlertsRequest{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		out := map[string]bool{}
+		for _, st := range res.States {
+			if st.Error != "" {
+				t.Fatalf("%s: %s", st.Rule.Name, st.Error)
+			}
+			if st.Firing {
+				out[st.Rule.Name] = true
+			}
+		}
+		return out
+	}
+	expectEvents := func(n int) []*spec.AlertEvent {
+		out := []*spec.AlertEvent{}
+		for i := 0; i < n; i++ {
+			select {
+			case e := <-received:
+				out = append(out, e)
+			case <-time.After(5 * time.Second):
+				t.Fatalf("expected %d events got %d", n, len(out))
+			}
+		}
+		select {
+		case e := <-received:
+			t.Fatalf("unexpected event %v", e)
+		default:
+		}
+		return out
+	}
+
+	a.check(now.Add(time.Second))
+	if f := firing(); len(f) != 3 {
+		t.Fatalf("expected all alerts firing got %v", f)
+	}
+	expectEvents(3)
+
+	// nothing changed, nothing is sent again
+	a.check(now.Add(2 * time.Second))
+	expectEvents(0)
+
+	// the clicks are in the previous window now
+	a.check(now.Add(301 * time.Second))
+	f := firing()
+	if f["many-clicks"] || !f["clicks-changed"] || !f["no-views"] {
+		t.Fatalf("unexpected firing %v", f)
+	}
+	events := expectEvents(1)
+	if events[0].Name != "many-clicks" || events[0].Firing || events[0].Count != 0 || events[0].Previous != 10 {
+		t.Fatalf("unexpected event %v", events[0])
+	}
+
+	res, err := s.SayAlerts(context.Background(), &spec.AlertsRequest{Name: "many-clicks"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.States) != 1 || len(res.History) != 2 || !res.History[0].Firing || res.History[1].Firing {
+		t.Fatalf("unexpected response %v", res)
+	}
+	if _, err := s.SayAlerts(context.Background(), &spec.AlertsRequest{Name: "nope"}); err == nil {
+		t.Fatal("expected not found")
+	}
+
+	// a failing webhook is reported in the state
+	broken := httptest.NewServer(http.NotFoundHandler())
+	defer broken.Close()
+	a.notify = webhook(broken.URL, time.Second)
+	a.check(now.Add(2 * 301 * time.Second))
+	res, err = s.SayAlerts(context.Background(), &spec.AlertsRequest{Name: "clicks-changed"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.States[0].Firing || res.States[0].Error == "" {
+		t.Fatalf("expected resolved with a notify error got %v", res.States[0])
+	}
+	res, err = s.SayAlerts(context.Background(), &spec.AlertsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.History) != 4 || res.History[0].Name == "many-clicks" {
+		t.Fatalf("expected the history to be capped to 4 got %v", res.History)
+	}
+}
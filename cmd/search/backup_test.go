@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	spec "github.com/rekki/blackrock/pkg/blackrock_io"
+)
+
+func TestBackupRoot(t *testing.T) {
+	root, err := ioutil.TempDir("", "backup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	s := newServer(path.Join(root, "data"), 100, 3600, false, "", "", time.Hour, time.Hour, "", 0)
+	err = s.si.Ingest(&spec.Envelope{Metadata: &spec.Metadata{CreatedAtNs: time.Now().UnixNano(), EventType: "click", ForeignType: "user", ForeignId: "1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.SayBackup(context.Background(), &spec.BackupRequest{Destination: "b"}); err != errNoBackupRoot {
+		t.Fatalf("expected no backup root got %v", err)
+	}
+
+	s.backupRoot = path.Join(root, "backups")
+	for _, bad := range []string{"/tmp/b", "../b", "x/../../b", "."} {
+		if _, err := s.SayBackup(context.Background(), &spec.BackupRequest{Destination: bad}); err != errBadBackupPath {
+			t.Fatalf("%s: expected bad path got %v", bad, err)
+		}
+		if _, err := s.SayRestore(context.Background(), &spec.RestoreRequest{Source: bad}); err != errBadBackupPath {
+			t.Fatalf("%s: expected bad path got %v", bad, err)
+		}
+	}
+
+	manifest, err := s.SayBackup(context.Background(), &spec.BackupRequest{Destination: "daily/b.tar"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path.Join(root, "backups", "daily", "b.tar")); err != nil {
+		t.Fatal(err)
+	}
+	restored, err := s.SayRestore(context.Background(), &spec.RestoreRequest{Source: "daily/b.tar"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(restored.Segments) != len(manifest.Segments) || len(restored.Segments) != 1 {
+		t.Fatalf("unexpected restore %v", restored)
+	}
+}
@@ -0,0 +1,599 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	spec "github.com/rekki/blackrock/pkg/blackrock_io"
+	. "github.com/rekki/blackrock/pkg/logger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var errBadShard = errors.New("shard must be host:port or host:port@from-to")
+var errNoShards = errors.New("all shards failed")
+var errNoShardForEnvelope = errors.New("no shard for the event")
+var errNotOnCoordinator = errors.New("not supported by the coordinator, run it on the shards")
+
+const failedShardsTrailer = "blackrock-failed-shards"
+
+type shard struct {
+	addr string
+
+	// seconds, 0 means no bound, used only when sharded by time
+	fromSecond uint32
+	toSecond   uint32
+
+	client spec.SearchClient
+}
+
+func (s *shard) hasTime(second uint32) bool {
+	return (s.fromSecond == 0 || second >= s.fromSecond) && (s.toSecond == 0 || second <= s.toSecond)
+}
+
+func (s *shard) overlaps(from, to uint32) bool {
+	return (s.fromSecond == 0 || to == 0 || to >= s.fromSecond) && (s.toSecond == 0 || from <= s.toSecond)
+}
+
+// coordinator implements the Search service by fanning out to the shards,
+// every shard is a normal search process with its own disk
+type coordinator struct {
+	shards  []*shard
+	byTime  bool
+	timeout time.Duration
+}
+
+// parseShards parses csv list of host:port, when sharded by time every
+// shard has its range in seconds host:port@from-to, from or to can be empty
+func parseShards(s string, byTime bool) ([]*shard, error) {
+	out := []*shard{}
+	for _, v := range strings.Split(s, ",") {
+		if len(v) == 0 {
+			continue
+		}
+
+		sh := &shard{addr: v}
+		if byTime {
+			splitted := strings.Split(v, "@")
+			if len(splitted) != 2 {
+				return nil, errBadShard
+			}
+			sh.addr = splitted[0]
+
+			fromTo := strings.Split(splitted[1], "-")
+			if len(fromTo) != 2 {
+				return nil, errBadShard
+			}
+
+			for i, p := range []*uint32{&sh.fromSecond, &sh.toSecond} {
+				if fromTo[i] == "" {
+					continue
+				}
+				n, err := strconv.ParseUint(fromTo[i], 10, 32)
+				if err != nil {
+					return nil, errBadShard
+				}
+				*p = uint32(n)
+			}
+		}
+		out = append(out, sh)
+	}
+
+	if len(out) == 0 {
+		return nil, errBadShard
+	}
+	return out, nil
+}
+
+func newCoordinator(shards []*shard, byTime bool, timeout time.Duration) (*coordinator, error) {
+	for _, s := range shards {
+		conn, err := grpc.Dial(s.addr, dialOptions...)
+		if err != nil {
+			return nil, err
+		}
+		s.client = spec.NewSearchClient(conn)
+	}
+	return &coordinator{shards: shards, byTime: byTime, timeout: timeout}, nil
+}
+
+// fanOut calls cb in parallel for the shards that can have events in
+// [from, to] seconds, cb gets the index of the shard so it can store the
+// result without locking, the shards that failed or did not answer in time
+// are returned
+func (c *coordinator) fanOut(ctx context.Context, from, to uint32, cb func(ctx context.Context, i int, s *shard) error) []*spec.ShardFailure {
+	errs := make([]error, len(c.shards))
+	var wg sync.WaitGroup
+	for i, s := range c.shards {
+		if c.byTime && !s.overlaps(from, to) {
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, s *shard) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, c.timeout)
+			defer cancel()
+
+			errs[i] = cb(ctx, i, s)
+		}(i, s)
+	}
+	wg.Wait()
+
+	failed := []*spec.ShardFailure{}
+	for i, err := range errs {
+		if err != nil {
+			Log.Warnf("shard %s failed, err: %s", c.shards[i].addr, err.Error())
+			failed = append(failed, &spec.ShardFailure{Shard: c.shards[i].addr, Error: err.Error()})
+		}
+	}
+	return failed
+}
+
+func (c *coordinator) SaySearch(ctx context.Context, qr *spec.SearchQueryRequest) (*spec.SearchQueryResponse, error) {
+	start := time.Now()
+	results := make([]*spec.SearchQueryResponse, len(c.shards))
+	failed := c.fanOut(ctx, qr.FromSecond, qr.ToSecond, func(ctx context.Context, i int, s *shard) error {
+		r, err := s.client.SaySearch(ctx, qr)
+		results[i] = r
+		return err
+	})
+
+	out := &spec.SearchQueryResponse{FailedShards: failed}
+	if qr.Explain {
+		out.Explain = &spec.QueryExplain{}
+	}
+
+	answered := 0
+	for i, r := range results {
+		if r == nil {
+			continue
+		}
+		answered++
+
+		out.Total += r.Total
+		out.Hits = append(out.Hits, r.Hits...)
+		if out.Explain != nil && r.Explain != nil {
+			for _, sp := range r.Explain.Segments {
+				sp.SegmentId = c.shards[i].addr + "/" + sp.SegmentId
+				out.Explain.Segments = append(out.Explain.Segments, sp)
+			}
+		}
+	}
+
+	if answered == 0 && len(failed) > 0 {
+		return nil, errNoShards
+	}
+
+	sort.SliceStable(out.Hits, func(i, j int) bool {
+		return out.Hits[i].Score > out.Hits[j].Score
+	})
+	if len(out.Hits) > int(qr.Limit) {
+		out.Hits = out.Hits[:qr.Limit]
+	}
+
+	if out.Explain != nil {
+		out.Explain.TookNs = time.Since(start).Nanoseconds()
+	}
+	return out, nil
+}
+
+func (c *coordinator) SayFetch(qr *spec.SearchQueryRequest, stream spec.Search_SayFetchServer) error {
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+
+	var lock sync.Mutex
+	sent := int32(0)
+	failed := c.fanOut(ctx, qr.FromSecond, qr.ToSecond, func(ctx context.Context, i int, s *shard) error {
+		client, err := s.client.SayFetch(ctx, qr)
+		if err != nil {
+			return err
+		}
+
+		for {
+			hit, err := client.Recv()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				if ctx.Err() == context.Canceled {
+					// limit reached
+					return nil
+				}
+				return err
+			}
+
+			lock.Lock()
+			if qr.Limit > 0 && sent >= qr.Limit {
+				lock.Unlock()
+				cancel()
+				return nil
+			}
+			err = stream.Send(hit)
+			sent++
+			if qr.Limit > 0 && sent >= qr.Limit {
+				cancel()
+			}
+			lock.Unlock()
+			if err != nil {
+				return err
+			}
+		}
+	})
+
+	if len(failed) > 0 {
+		shards := []string{}
+		for _, f := range failed {
+			shards = append(shards, f.Shard)
+		}
+		stream.SetTrailer(metadata.Pairs(failedShardsTrailer, strings.Join(shards, ",")))
+		if sent == 0 && len(failed) == len(c.shards) {
+			return errNoShards
+		}
+	}
+	return nil
+}
+
+func (c *coordinator) SayAggregate(ctx context.Context, qr *spec.AggregateRequest) (*spec.Aggregate, error) {
+	if qr.Query == nil {
+		return nil, errors.New("missing query")
+	}
+
+	results := make([]*spec.Aggregate, len(c.shards))
+	failed := c.fanOut(ctx, qr.Query.FromSecond, qr.Query.ToSecond, func(ctx context.Context, i int, s *shard) error {
+		r, err := s.client.SayAggregate(ctx, qr)
+		results[i] = r
+		return err
+	})
+
+	out := &spec.Aggregate{
+		Search:    map[string]*spec.CountPerKV{},
+		Count:     map[string]*spec.CountPerKV{},
+		EventType: map[string]*spec.CountPerKV{},
+		ForeignId: map[string]*spec.CountPerKV{},
+		Possible:  map[string]uint32{},
+	}
+
+	answered := 0
+	for _, r := range results {
+		if r == nil {
+			continue
+		}
+		answered++
+		mergeAggregate(out, r)
+	}
+
+	if answered == 0 && len(failed) > 0 {
+		return nil, errNoShards
+	}
+
+	sort.Slice(out.Sample, func(i, j int) bool {
+		return out.Sample[i].Metadata.CreatedAtNs < out.Sample[j].Metadata.CreatedAtNs
+	})
+	if len(out.Sample) > int(qr.SampleLimit) {
+		out.Sample = out.Sample[:qr.SampleLimit]
+	}
+	out.FailedShards = failed
+	return out, nil
+}
+
+func mergeCountPerKV(into, from map[string]*spec.CountPerKV) {
+	for k, v := range from {
+		m, ok := into[k]
+		if !ok {
+			into[k] = v
+			continue
+		}
+		if m.Count == nil {
+			m.Count = map[string]uint32{}
+		}
+
+		for value, count := range v.Count {
+			m.Count[value] += count
+		}
+		m.Total += v.Total
+	}
+}
+
+// mergeAggregate adds from into into, the unique counts in the chart are
+// exact only when the shards have different foreign ids, when sharded by
+// time a foreign id in a bucket that spans two shards is counted twice
+func mergeAggregate(into, from *spec.Aggregate) {
+	mergeCountPerKV(into.Search, from.Search)
+	mergeCountPerKV(into.Count, from.Count)
+	mergeCountPerKV(into.ForeignId, from.ForeignId)
+	mergeCountPerKV(into.EventType, from.EventType)
+
+	for k, v := range from.Possible {
+		into.Possible[k] += v
+	}
+	for k, v := range from.Numbers {
+		if into.Numbers == nil {
+			into.Numbers = map[string]*spec.NumberStats{}
+		}
+		m, ok := into.Numbers[k]
+		if !ok {
+			into.Numbers[k] = v
+			continue
+		}
+		m.Count += v.Count
+		m.Sum += v.Sum
+		if v.Min < m.Min {
+			m.Min = v.Min
+		}
+		if v.Max > m.Max {
+			m.Max = v.Max
+		}
+	}
+	into.Total += from.Total
+	into.Sample = append(into.Sample, from.Sample...)
+
+	if from.Chart == nil {
+		return
+	}
+	if into.Chart == nil {
+		into.Chart = from.Chart
+		return
+	}
+
+	if from.Chart.TimeStart < into.Chart.TimeStart {
+		into.Chart.TimeStart = from.Chart.TimeStart
+	}
+	if from.Chart.TimeEnd > into.Chart.TimeEnd {
+		into.Chart.TimeEnd = from.Chart.TimeEnd
+	}
+
+	for t, bucket := range from.Chart.Buckets {
+		existing, ok := into.Chart.Buckets[t]
+		if !ok {
+			if into.Chart.Buckets == nil {
+				into.Chart.Buckets = map[uint32]*spec.ChartBucketPerTime{}
+			}
+			into.Chart.Buckets[t] = bucket
+			continue
+		}
+		if existing.PerType == nil {
+			existing.PerType = map[string]*spec.PointPerEventType{}
+		}
+
+		for eventType, point := range bucket.PerType {
+			p, ok := existing.PerType[eventType]
+			if !ok {
+				existing.PerType[eventType] = point
+				continue
+			}
+			p.Count += point.Count
+			p.CountUnique += point.CountUnique
+		}
+	}
+}
+
+func (c *coordinator) shardFor(envelope *spec.Envelope) (*shard, error) {
+	if envelope.Metadata == nil {
+		return nil, errors.New("missing metadata")
+	}
+
+	if c.byTime {
+		ns := envelope.Metadata.CreatedAtNs
+		if ns == 0 {
+			ns = time.Now().UnixNano()
+			envelope.Metadata.CreatedAtNs = ns
+		}
+
+		for _, s := range c.shards {
+			if s.hasTime(uint32(ns / 1000000000)) {
+				return s, nil
+			}
+		}
+		return nil, errNoShardForEnvelope
+	}
+
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(envelope.Metadata.ForeignId))
+	return c.shards[h.Sum32()%uint32(len(c.shards))], nil
+}
+
+func (c *coordinator) SayPush(stream spec.Search_SayPushServer) error {
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+
+	clients := map[*shard]spec.Search_SayPushClient{}
+	for {
+		envelope, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		s, err := c.shardFor(envelope)
+		if err != nil {
+			return err
+		}
+
+		client, ok := clients[s]
+		if !ok {
+			client, err = s.client.SayPush(ctx)
+			if err != nil {
+				return fmt.Errorf("%s: %s", s.addr, err.Error())
+			}
+			clients[s] = client
+		}
+
+		err = client.Send(envelope)
+		if err != nil {
+			return fmt.Errorf("%s: %s", s.addr, err.Error())
+		}
+	}
+
+	for s, client := range clients {
+		_, err := client.CloseAndRecv()
+		if err != nil {
+			return fmt.Errorf("%s: %s", s.addr, err.Error())
+		}
+	}
+
+	return stream.SendAndClose(&spec.Success{Success: true})
+}
+
+// SayHealth is successful only if all shards are
+func (c *coordinator) SayHealth(ctx context.Context, in *spec.HealthRequest) (*spec.Success, error) {
+	failed := c.fanOut(ctx, 0, 0, func(ctx context.Context, i int, s *shard) error {
+		_, err := s.client.SayHealth(ctx, in)
+		return err
+	})
+	return &spec.Success{Success: len(failed) == 0}, nil
+}
+
+func (c *coordinator) SayStats(ctx context.Context, in *spec.StatsRequest) (*spec.StatsResponse, error) {
+	results := make([]*spec.StatsResponse, len(c.shards))
+	failed := c.fanOut(ctx, in.FromSecond, in.ToSecond, func(ctx context.Context, i int, s *shard) error {
+		r, err := s.client.SayStats(ctx, in)
+		results[i] = r
+		return err
+	})
+
+	out := &spec.StatsResponse{FailedShards: failed, PartitionOffsets: map[int32]int64{}}
+	for i, r := range results {
+		if r == nil {
+			continue
+		}
+		for partition, offset := range r.PartitionOffsets {
+			if current, ok := out.PartitionOffsets[partition]; !ok || offset > current {
+				out.PartitionOffsets[partition] = offset
+			}
+		}
+		for _, s := range r.Segments {
+			s.Id = c.shards[i].addr + "/" + s.Id
+			out.Segments = append(out.Segments, s)
+		}
+		out.Docs += r.Docs
+		out.ForwardBytes += r.ForwardBytes
+		out.InvertedBytes += r.InvertedBytes
+	}
+	return out, nil
+}
+
+func (c *coordinator) SayBackup(context.Context, *spec.BackupRequest) (*spec.SnapshotManifest, error) {
+	return nil, errNotOnCoordinator
+}
+
+func (c *coordinator) SayRestore(context.Context, *spec.RestoreRequest) (*spec.SnapshotManifest, error) {
+	return nil, errNotOnCoordinator
+}
+
+func (c *coordinator) SayReindex(context.Context, *spec.ReindexRequest) (*spec.ReindexStatus, error) {
+	return nil, errNotOnCoordinator
+}
+
+func (c *coordinator) SayReplicationManifest(context.Context, *spec.ReplicationRequest) (*spec.ReplicationManifest, error) {
+	return nil, errNotOnCoordinator
+}
+
+func (c *coordinator) SayShip(*spec.ShipRequest, spec.Search_SayShipServer) error {
+	return errNotOnCoordinator
+}
+
+func (c *coordinator) SaySchemas(context.Context, *spec.SchemaRequest) (*spec.SchemaRegistry, error) {
+	return nil, errNotOnCoordinator
+}
+
+func (c *coordinator) SaySetSchemas(context.Context, *spec.SchemaRegistry) (*spec.SchemaRegistry, error) {
+	return nil, errNotOnCoordinator
+}
+
+func (c *coordinator) SayPromote(context.Context, *spec.PromoteRequest) (*spec.Success, error) {
+	return nil, errNotOnCoordinator
+}
+
+func (c *coordinator) SayAlerts(context.Context, *spec.AlertsRequest) (*spec.AlertsResponse, error) {
+	return nil, errAlertsNeedIndex
+}
+
+// SayTail merges the tails of the shards that get new events, it stops when
+// any of them stops, the headers are sent when all of them are following
+func (c *coordinator) SayTail(in *spec.TailRequest, stream spec.Search_SayTailServer) error {
+	// the shards must be done with the stream before we return
+	var running sync.WaitGroup
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer func() {
+		cancel()
+		running.Wait()
+	}()
+
+	now := uint32(time.Now().Unix())
+	var lock sync.Mutex
+	var following sync.WaitGroup
+	ready := make(chan struct{})
+	errs := make(chan error, len(c.shards))
+	n := 0
+	for _, s := range c.shards {
+		if c.byTime && !s.overlaps(now, now) {
+			continue
+		}
+		n++
+		following.Add(1)
+		running.Add(1)
+		go func(s *shard) {
+			defer running.Done()
+			fail := func(err error) {
+				errs <- status.Errorf(status.Code(err), "shard %s: %s", s.addr, status.Convert(err).Message())
+			}
+			client, err := s.client.SayTail(ctx, in)
+			if err == nil {
+				_, err = client.Header()
+			}
+			following.Done()
+			if err != nil {
+				fail(err)
+				return
+			}
+			select {
+			case <-ready:
+			case <-ctx.Done():
+				return
+			}
+
+			for {
+				e, err := client.Recv()
+				if err != nil {
+					fail(err)
+					return
+				}
+				lock.Lock()
+				err = stream.Send(e)
+				lock.Unlock()
+				if err != nil {
+					errs <- err
+					return
+				}
+			}
+		}(s)
+	}
+	if n == 0 {
+		return errNoShards
+	}
+
+	following.Wait()
+	err := stream.SendHeader(metadata.MD{})
+	if err != nil {
+		return err
+	}
+	close(ready)
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,363 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	spec "github.com/rekki/blackrock/pkg/blackrock_io"
+	. "github.com/rekki/blackrock/pkg/logger"
+	"github.com/rekki/go-query/util/go_query_dsl"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const shardEnv = "BLACKROCK_SEARCH_SHARD"
+
+// the test binary runs itself as a search shard when shardEnv is set, the
+// value is the command line
+func TestMain(m *testing.M) {
+	if args := os.Getenv(shardEnv); args != "" {
+		os.Args = append([]string{os.Args[0]}, strings.Split(args, " ")...)
+		main()
+		return
+	}
+	LogInit(3)
+	os.Exit(m.Run())
+}
+
+func freeAddr(t *testing.T) string {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lis.Close()
+	return lis.Addr().String()
+}
+
+func startShard(t *testing.T, root string) (string, *exec.Cmd) {
+	addr := freeAddr(t)
+	cmd := exec.Command(os.Args[0])
+	cmd.Env = append(os.Environ(), fmt.Sprintf("%s=-root %s -grpc %s -http %s -log-level 3", shardEnv, root, addr, freeAddr(t)))
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	err := cmd.Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conn, err := grpc.Dial(addr, grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	client := spec.NewSearchClient(conn)
+	for i := 0; i < 100; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		_, err = client.SayHealth(ctx, &spec.HealthRequest{})
+		cancel()
+		if err == nil {
+			return addr, cmd
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	_ = cmd.Process.Kill()
+	t.Fatalf("shard %s did not start, err: %s", addr, err)
+	return "", nil
+}
+
+func startCoordinator(t *testing.T, addrs []string, timeout time.Duration) (spec.SearchClient, func()) {
+	shards, err := parseShards(strings.Join(addrs, ","), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := newCoordinator(shards, false, timeout)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	grpcServer := grpc.NewServer()
+	spec.RegisterSearchServer(grpcServer, c)
+	go func() {
+		_ = grpcServer.Serve(lis)
+	}()
+
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return spec.NewSearchClient(conn), func() {
+		conn.Close()
+		grpcServer.Stop()
+	}
+}
+
+func TestParseShards(t *testing.T) {
+	shards, err := parseShards("a:1@10-20,b:2@21-", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(shards) != 2 || shards[0].addr != "a:1" || shards[0].fromSecond != 10 || shards[0].toSecond != 20 || shards[1].fromSecond != 21 || shards[1].toSecond != 0 {
+		t.Fatalf("unexpected shards %+v %+v", shards[0], shards[1])
+	}
+	if !shards[1].hasTime(100) || shards[0].hasTime(21) || !shards[0].overlaps(0, 10) || shards[1].overlaps(1, 20) {
+		t.Fatalf("unexpected ranges")
+	}
+
+	for _, bad := range []string{"", "a:1", "a:1@x-1", "a:1@1"} {
+		_, err = parseShards(bad, true)
+		if err != errBadShard {
+			t.Fatalf("expected error for %s", bad)
+		}
+	}
+}
+
+func TestCoordinator(t *testing.T) {
+	addrs := []string{}
+	cmds := []*exec.Cmd{}
+	for i := 0; i < 2; i++ {
+		root, err := ioutil.TempDir("", "shard")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(root)
+
+		addr, cmd := startShard(t, root)
+		defer func() {
+			_ = cmd.Process.Kill()
+			_ = cmd.Wait()
+		}()
+		addrs = append(addrs, addr)
+		cmds = append(cmds, cmd)
+	}
+
+	client, stop := startCoordinator(t, addrs, 5*time.Second)
+	defer stop()
+	ctx := context.Background()
+
+	base := int64(1600000000)
+	push, err := client.SayPush(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 100; i++ {
+		eventType := "click"
+		if i%4 == 0 {
+			eventType = "view"
+		}
+		search := []spec.KV{{Key: "all", Value: "yes"}}
+		if i%10 == 0 {
+			search = append(search, spec.KV{Key: "rare", Value: "yes"})
+		}
+		err = push.Send(&spec.Envelope{
+			Metadata: &spec.Metadata{
+				CreatedAtNs: (base + int64(i)) * 1e9,
+				EventType:   eventType,
+				ForeignType: "user",
+				ForeignId:   fmt.Sprintf("%d", i),
+				Search:      search,
+				Numbers:     []spec.KF{{Key: "price", Value: float64(i) + 0.5}},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	_, err = push.CloseAndRecv()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err := client.SayStats(ctx, &spec.StatsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	perShard := map[string]uint64{}
+	for _, s := range stats.Segments {
+		perShard[strings.Split(s.Id, "/")[0]] += s.Docs
+	}
+	if stats.Docs != 100 || len(perShard) != 2 {
+		t.Fatalf("expected 100 docs on both shards, got %d %v", stats.Docs, perShard)
+	}
+
+	query := &spec.SearchQueryRequest{
+		FromSecond: uint32(base),
+		ToSecond:   uint32(base + 3600),
+		Limit:      15,
+		Query: &go_query_dsl.Query{
+			Type: go_query_dsl.Query_OR,
+			Queries: []*go_query_dsl.Query{
+				{Type: go_query_dsl.Query_TERM, Field: "all", Value: "yes"},
+				{Type: go_query_dsl.Query_TERM, Field: "rare", Value: "yes"},
+			},
+		},
+	}
+
+	search := func() *spec.SearchQueryResponse {
+		res, err := client.SaySearch(ctx, query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+
+	res := search()
+	if res.Total != 100 || len(res.Hits) != 15 || len(res.FailedShards) != 0 {
+		t.Fatalf("unexpected response total: %d hits: %d failed: %v", res.Total, len(res.Hits), res.FailedShards)
+	}
+	// the events with both terms score higher, they are spread on both
+	// shards so only a proper merge has them all on top
+	for i, hit := range res.Hits {
+		rare := false
+		for _, kv := range hit.Metadata.Search {
+			rare = rare || kv.Key == "rare"
+		}
+		if rare != (i < 10) {
+			t.Fatalf("unexpected hit %d: %+v", i, hit)
+		}
+		if i > 0 && res.Hits[i-1].Score < hit.Score {
+			t.Fatalf("hits are not sorted")
+		}
+	}
+
+	fetch := func(limit int32) (int, metadata.MD) {
+		query.Limit = limit
+		stream, err := client.SayFetch(ctx, query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		n := 0
+		for {
+			_, err := stream.Recv()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			n++
+		}
+		return n, stream.Trailer()
+	}
+	if n, _ := fetch(0); n != 100 {
+		t.Fatalf("expected 100 got %d", n)
+	}
+	if n, _ := fetch(30); n != 30 {
+		t.Fatalf("expected 30 got %d", n)
+	}
+	query.Limit = 15
+
+	agg, err := client.SayAggregate(ctx, &spec.AggregateRequest{
+		Query:         query,
+		Fields:        map[string]bool{"event_type": true, "all": true, "price": true},
+		SampleLimit:   5,
+		TimeBucketSec: 60,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	et := agg.EventType["event_type"]
+	if agg.Total != 100 || et.Count["view"] != 25 || et.Count["click"] != 75 || agg.Search["all"].Count["yes"] != 100 || len(agg.Sample) != 5 {
+		t.Fatalf("unexpected aggregate %+v", agg)
+	}
+	price := agg.Numbers["price"]
+	if price == nil || price.Count != 100 || price.Sum != 5000 || price.Min != 0.5 || price.Max != 99.5 {
+		t.Fatalf("unexpected numbers %+v", agg.Numbers)
+	}
+	if agg.Sample[0].Metadata.CreatedAtNs != base*1e9 {
+		t.Fatalf("expected the oldest sample first, got %+v", agg.Sample[0])
+	}
+	chartCount := uint32(0)
+	for _, b := range agg.Chart.Buckets {
+		for _, p := range b.PerType {
+			chartCount += p.Count
+		}
+	}
+	if chartCount != 100 {
+		t.Fatalf("expected 100 in the chart got %d", chartCount)
+	}
+
+	// one shard is down, the other still answers
+	_ = cmds[1].Process.Kill()
+	_ = cmds[1].Wait()
+
+	res = search()
+	if res.Total == 0 || res.Total >= 100 || len(res.FailedShards) != 1 || res.FailedShards[0].Shard != addrs[1] {
+		t.Fatalf("expected partial results total: %d failed: %v", res.Total, res.FailedShards)
+	}
+	n, trailer := fetch(0)
+	if n != int(res.Total) || strings.Join(trailer.Get(failedShardsTrailer), ",") != addrs[1] {
+		t.Fatalf("expected partial fetch %d %v", n, trailer)
+	}
+	agg, err = client.SayAggregate(ctx, &spec.AggregateRequest{Query: query, Fields: map[string]bool{"event_type": true}})
+	if err != nil || uint64(agg.Total) != res.Total || len(agg.FailedShards) != 1 {
+		t.Fatalf("expected partial aggregate %v %v", agg, err)
+	}
+	health, err := client.SayHealth(ctx, &spec.HealthRequest{})
+	if err != nil || health.Success {
+		t.Fatalf("expected failed health %v %v", health, err)
+	}
+}
+
+func TestCoordinatorSlowShard(t *testing.T) {
+	root, err := ioutil.TempDir("", "shard")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	addr, cmd := startShard(t, root)
+	defer func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	}()
+
+	// accepts connections but never answers
+	blackhole, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer blackhole.Close()
+	go func() {
+		conns := []net.Conn{}
+		for {
+			conn, err := blackhole.Accept()
+			if err != nil {
+				for _, c := range conns {
+					c.Close()
+				}
+				return
+			}
+			conns = append(conns, conn)
+		}
+	}()
+
+	client, stop := startCoordinator(t, []string{addr, blackhole.Addr().String()}, 500*time.Millisecond)
+	defer stop()
+
+	start := time.Now()
+	res, err := client.SaySearch(context.Background(), &spec.SearchQueryRequest{
+		Limit: 10,
+		Query: &go_query_dsl.Query{Type: go_query_dsl.Query_TERM, Field: "all", Value: "yes"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.FailedShards) != 1 || res.FailedShards[0].Shard != blackhole.Addr().String() {
+		t.Fatalf("expected the slow shard to fail, got %v", res.FailedShards)
+	}
+	if took := time.Since(start); took > 5*time.Second {
+		t.Fatalf("waited too long for the slow shard %s", took)
+	}
+}
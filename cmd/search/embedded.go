@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"io"
+	"path"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	spec "github.com/rekki/blackrock/pkg/blackrock_io"
+	"github.com/rekki/blackrock/pkg/index"
+	. "github.com/rekki/blackrock/pkg/logger"
+	"github.com/rekki/blackrock/pkg/queue"
+	pen "github.com/rekki/go-pen"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const embeddedBatchSize = 1000
+
+// embeddedQueue implements the Enqueue service so orgrim can push directly
+// to search without kafka. The pushes are acknowledged once they are synced
+// to the local log, and a single goroutine ingests them in order and stores
+// the offset it reached, so on restart we continue from there.
+type embeddedQueue struct {
+	log      *queue.FileLog
+	ack      *pen.OffsetWriter
+	si       *index.SearchIndex
+	follower *follower
+	pipeline *spec.Pipeline
+	schemas  *spec.Schemas
+}
+
+func newEmbeddedQueue(root string, maxFileBytes int64, si *index.SearchIndex, f *follower, schemas *spec.Schemas) (*embeddedQueue, error) {
+	l, err := queue.OpenFileLog(root, maxFileBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	ack, err := pen.NewOffsetWriter(path.Join(root, "acked.offset"))
+	if err != nil {
+		l.Close()
+		return nil, err
+	}
+	return &embeddedQueue{log: l, ack: ack, si: si, follower: f, pipeline: spec.NewPipeline(nil, schemas), schemas: schemas}, nil
+}
+
+func (q *embeddedQueue) SayPush(stream spec.Enqueue_SayPushServer) error {
+	if q.follower.readOnly() {
+		return errReadOnly
+	}
+
+	r := spec.RequestFromContext(stream.Context())
+	out := &spec.Success{}
+	records := [][]byte{}
+	for i := int32(0); ; i++ {
+		envelope, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		// reject what we would fail to ingest later
+		err = q.pipeline.Process(envelope, r)
+		if err != nil {
+			out.Rejections = append(out.Rejections, &spec.Rejection{Index: i, Reason: err.Error()})
+			continue
+		}
+
+		encoded, err := proto.Marshal(envelope)
+		if err != nil {
+			return err
+		}
+		records = append(records, encoded)
+	}
+
+	_, err := q.log.Append(records...)
+	if err != nil {
+		return err
+	}
+	out.Success = len(out.Rejections) == 0
+	return stream.SendAndClose(out)
+}
+
+// SayPushSync is not supported, SayPush already replies only after the
+// envelopes are synced to the local log
+func (q *embeddedQueue) SayPushSync(stream spec.Enqueue_SayPushSyncServer) error {
+	return status.Error(codes.Unimplemented, "use SayPush, it is synchronous with the embedded queue")
+}
+
+func (q *embeddedQueue) SayHealth(context.Context, *spec.HealthRequest) (*spec.Success, error) {
+	return &spec.Success{Success: true}, nil
+}
+
+func (q *embeddedQueue) SaySchemas(ctx context.Context, in *spec.SchemaRequest) (*spec.SchemaRegistry, error) {
+	return q.schemas.Registry(), nil
+}
+
+func (q *embeddedQueue) SaySetSchemas(ctx context.Context, in *spec.SchemaRegistry) (*spec.SchemaRegistry, error) {
+	err := q.schemas.Set(in)
+	if err != nil {
+		return nil, err
+	}
+	return q.schemas.Registry(), nil
+}
+
+// run ingests everything after the acknowledged offset until ctx is done
+func (q *embeddedQueue) run(ctx context.Context) error {
+	offset := q.ack.ReadOrDefault(0)
+	lastTruncate := time.Now()
+	for {
+		err := q.log.Wait(ctx, offset)
+		if err != nil {
+			return err
+		}
+
+		next, err := q.log.Read(offset, embeddedBatchSize, func(_ int64, data []byte) error {
+			envelope := &spec.Envelope{}
+			err := proto.Unmarshal(data, envelope)
+			if err == nil {
+				err = q.si.Ingest(envelope)
+			}
+			if err != nil {
+				Log.Warnf("skipping queued event, err: %s", err.Error())
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if next == offset {
+			continue
+		}
+
+		offset = next
+		err = q.ack.SetOffset(offset)
+		if err != nil {
+			return err
+		}
+
+		if time.Since(lastTruncate) > time.Minute {
+			err = q.log.Truncate(offset)
+			if err != nil {
+				Log.Warnf("failed to remove the ingested queue files, err: %s", err.Error())
+			}
+			lastTruncate = time.Now()
+		}
+	}
+}
+
+func (q *embeddedQueue) Close() error {
+	q.ack.Close()
+	return q.log.Close()
+}
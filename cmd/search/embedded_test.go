@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	spec "github.com/rekki/blackrock/pkg/blackrock_io"
+	"github.com/rekki/blackrock/pkg/index"
+	"google.golang.org/grpc"
+)
+
+func startEmbeddedQueue(t *testing.T, root string, si *index.SearchIndex) (*embeddedQueue, spec.EnqueueClient, func()) {
+	q, err := newEmbeddedQueue(root, 1024, si, nil, spec.NewSchemas())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	grpcServer := grpc.NewServer()
+	spec.RegisterEnqueueServer(grpcServer, q)
+	go func() {
+		_ = grpcServer.Serve(lis)
+	}()
+
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return q, spec.NewEnqueueClient(conn), func() {
+		conn.Close()
+		grpcServer.Stop()
+		q.Close()
+	}
+}
+
+func enqueue(client spec.EnqueueClient, envelopes ...*spec.Envelope) error {
+	stream, err := client.SayPush(context.Background())
+	if err != nil {
+		return err
+	}
+	for _, e := range envelopes {
+		err = stream.Send(e)
+		if err != nil {
+			return err
+		}
+	}
+	out, err := stream.CloseAndRecv()
+	if err != nil {
+		return err
+	}
+	if len(out.Rejections) > 0 {
+		return errors.New(out.Rejections[0].Reason)
+	}
+	return nil
+}
+
+func waitForDocs(t *testing.T, si *index.SearchIndex, docs uint64) {
+	for i := 0; i < 100; i++ {
+		if si.Stats(0, 0, 0).Docs == docs {
+			return
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	t.Fatalf("expected %d docs got %d", docs, si.Stats(0, 0, 0).Docs)
+}
+
+func TestEmbeddedQueue(t *testing.T) {
+	dir, err := ioutil.TempDir("", "embedded")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	si := index.NewSearchIndex(path.Join(dir, "index"), 100, 3600, false, map[string]bool{})
+	defer si.Close()
+	root := path.Join(dir, "queue")
+
+	now := time.Now().UnixNano()
+	events := func(n int) []*spec.Envelope {
+		out := []*spec.Envelope{}
+		for i := 0; i < n; i++ {
+			out = append(out, &spec.Envelope{Metadata: &spec.Metadata{CreatedAtNs: now, EventType: "click", ForeignType: "user", ForeignId: "a"}})
+		}
+		return out
+	}
+
+	// nothing is ingested until the queue runs, but the pushes are durable
+	_, client, stop := startEmbeddedQueue(t, root, si)
+	err = enqueue(client, events(30)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = enqueue(client, &spec.Envelope{Metadata: &spec.Metadata{EventType: "click"}})
+	if err == nil {
+		t.Fatalf("expected invalid event to be rejected")
+	}
+	stop()
+
+	q, client, stop := startEmbeddedQueue(t, root, si)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- q.run(ctx)
+	}()
+	waitForDocs(t, si, 30)
+
+	err = enqueue(client, events(5)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitForDocs(t, si, 35)
+	cancel()
+	if err = <-done; err != context.Canceled {
+		t.Fatal(err)
+	}
+	if q.ack.ReadOrDefault(0) != q.log.End() {
+		t.Fatalf("expected everything to be acknowledged")
+	}
+	stop()
+
+	// restarting continues from the acknowledged offset
+	q, client, stop = startEmbeddedQueue(t, root, si)
+	defer stop()
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		_ = q.run(ctx)
+	}()
+	err = enqueue(client, events(1)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitForDocs(t, si, 36)
+	time.Sleep(100 * time.Millisecond)
+	waitForDocs(t, si, 36)
+}
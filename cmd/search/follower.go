@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	spec "github.com/rekki/blackrock/pkg/blackrock_io"
+	"github.com/rekki/blackrock/pkg/index"
+	. "github.com/rekki/blackrock/pkg/logger"
+	"google.golang.org/grpc"
+)
+
+var errReadOnly = errors.New("read only follower, promote it first")
+
+// follower tails the leader until it is promoted, while following the
+// index is read only
+type follower struct {
+	leader    spec.SearchClient
+	cancel    context.CancelFunc
+	done      chan struct{}
+	promoted  bool
+	onPromote func()
+	sync.Mutex
+}
+
+func startFollower(si *index.SearchIndex, leaderAddr string, every time.Duration, onPromote func()) (*follower, error) {
+	conn, err := grpc.Dial(leaderAddr, dialOptions...)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	f := &follower{leader: spec.NewSearchClient(conn), cancel: cancel, done: make(chan struct{}), onPromote: onPromote}
+	go func() {
+		defer close(f.done)
+		for {
+			err := si.Replicate(ctx, f.leader)
+			if err != nil && ctx.Err() == nil {
+				Log.Warnf("failed to replicate from %s, err: %s", leaderAddr, err.Error())
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(every):
+			}
+		}
+	}()
+	return f, nil
+}
+
+func (f *follower) readOnly() bool {
+	if f == nil {
+		return false
+	}
+	f.Lock()
+	defer f.Unlock()
+	return !f.promoted
+}
+
+// promote stops following, if the leader is still up we catch up with it
+// first
+func (f *follower) promote(ctx context.Context, si *index.SearchIndex) {
+	f.Lock()
+	defer f.Unlock()
+	if f.promoted {
+		return
+	}
+
+	f.cancel()
+	<-f.done
+
+	err := si.Replicate(ctx, f.leader)
+	if err != nil {
+		Log.Warnf("promoting without catching up with the leader, err: %s", err.Error())
+	}
+
+	f.promoted = true
+	if f.onPromote != nil {
+		f.onPromote()
+	}
+}
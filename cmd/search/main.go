@@ -9,6 +9,8 @@ import (
 	"net/http"
 	_ "net/http/pprof"
 	"os"
+	"path"
+	"path/filepath"
 	"sort"
 	"strings"
 	"time"
@@ -16,15 +18,42 @@ import (
 	"github.com/gogo/gateway"
 	"github.com/gogo/protobuf/proto"
 	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"github.com/rekki/blackrock/pkg/auth"
 	spec "github.com/rekki/blackrock/pkg/blackrock_io"
+	"github.com/rekki/blackrock/pkg/certs"
 
 	"github.com/rekki/blackrock/pkg/index"
 	. "github.com/rekki/blackrock/pkg/logger"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 type server struct {
-	si *index.SearchIndex
+	si       *index.SearchIndex
+	follower *follower
+	alerts   *alerter
+	schemas  *spec.Schemas
+
+	// backups are written and restored only below it, empty means no
+	// backup or restore over grpc
+	backupRoot string
+}
+
+var errNoBackupRoot = errors.New("backup and restore need -backup-root")
+var errBadBackupPath = errors.New("backup path must be relative to -backup-root")
+
+// backupPath is p inside the backup root
+func (s *server) backupPath(p string) (string, error) {
+	if s.backupRoot == "" {
+		return "", errNoBackupRoot
+	}
+	p = path.Clean(filepath.ToSlash(p))
+	if path.IsAbs(p) || p == "." || p == ".." || strings.HasPrefix(p, "../") {
+		return "", errBadBackupPath
+	}
+	return path.Join(s.backupRoot, p), nil
 }
 
 func (s *server) SaySearch(ctx context.Context, qr *spec.SearchQueryRequest) (*spec.SearchQueryResponse, error) {
@@ -33,7 +62,7 @@ func (s *server) SaySearch(ctx context.Context, qr *spec.SearchQueryRequest) (*s
 	}
 
 	scored := []spec.Hit{}
-	err := s.si.ForEach(qr, 0, func(segment *index.Segment, did int32, score float32) error {
+	explain, err := s.si.ForEachExplain(qr, 0, func(segment *index.Segment, did int32, score float32) error {
 		out.Total++
 		if qr.Limit == 0 {
 			return nil
@@ -50,7 +79,8 @@ func (s *server) SaySearch(ctx context.Context, qr *spec.SearchQueryRequest) (*s
 			m := spec.Metadata{}
 			err := segment.ReadForwardDecode(did, &m)
 			if err != nil {
-				// FIXME(jackdoe): should we skip here? or return partial result.
+				// skip it and return partial result, use blackrock fsck to find out what is wrong
+				Log.Warnf("failed to decode did: %d, err: %s", did, err.Error())
 				return nil
 			}
 			hit.Id = m.Id
@@ -77,9 +107,10 @@ func (s *server) SaySearch(ctx context.Context, qr *spec.SearchQueryRequest) (*s
 	}
 
 	out.Hits = make([]*spec.Hit, len(scored))
-	for i, v := range scored {
-		out.Hits[i] = &v
+	for i := range scored {
+		out.Hits[i] = &scored[i]
 	}
+	out.Explain = explain
 
 	return out, nil
 }
@@ -99,6 +130,22 @@ func (s *server) SayFetch(qr *spec.SearchQueryRequest, stream spec.Search_SayFet
 	return err
 }
 
+func addNumber(into map[string]*spec.NumberStats, kf spec.KF) {
+	m, ok := into[kf.Key]
+	if !ok {
+		into[kf.Key] = &spec.NumberStats{Key: kf.Key, Count: 1, Sum: kf.Value, Min: kf.Value, Max: kf.Value}
+		return
+	}
+	m.Count++
+	m.Sum += kf.Value
+	if kf.Value < m.Min {
+		m.Min = kf.Value
+	}
+	if kf.Value > m.Max {
+		m.Max = kf.Value
+	}
+}
+
 func (s *server) SayAggregate(ctx context.Context, qr *spec.AggregateRequest) (*spec.Aggregate, error) {
 	steps := s.si.ExpandFromTo(qr.Query.FromSecond, qr.Query.ToSecond)
 	dates := []time.Time{}
@@ -113,6 +160,7 @@ func (s *server) SayAggregate(ctx context.Context, qr *spec.AggregateRequest) (*
 		Count:     map[string]*spec.CountPerKV{},
 		EventType: map[string]*spec.CountPerKV{},
 		ForeignId: map[string]*spec.CountPerKV{},
+		Numbers:   map[string]*spec.NumberStats{},
 		Possible:  map[string]uint32{},
 		Total:     0,
 	}
@@ -164,6 +212,11 @@ func (s *server) SayAggregate(ctx context.Context, qr *spec.AggregateRequest) (*
 
 		add(metadata.Search, out.Search)
 		add(metadata.Count, out.Count)
+		for _, kf := range metadata.Numbers {
+			if _, ok := qr.Fields[kf.Key]; ok {
+				addNumber(out.Numbers, kf)
+			}
+		}
 
 		if wantEventType {
 			etype.Count[metadata.EventType]++
@@ -209,6 +262,9 @@ func (s *server) SayAggregate(ctx context.Context, qr *spec.AggregateRequest) (*
 }
 
 func (s *server) SayPush(stream spec.Search_SayPushServer) error {
+	if s.follower.readOnly() {
+		return errReadOnly
+	}
 	for {
 		envelope, err := stream.Recv()
 		if err == io.EOF {
@@ -229,6 +285,120 @@ func (s *server) SayHealth(context.Context, *spec.HealthRequest) (*spec.Success,
 	return &spec.Success{Success: true}, nil
 }
 
+func (s *server) SayBackup(ctx context.Context, in *spec.BackupRequest) (*spec.SnapshotManifest, error) {
+	if in.Destination == "" {
+		return nil, errors.New("missing destination")
+	}
+	dest, err := s.backupPath(in.Destination)
+	if err != nil {
+		return nil, err
+	}
+	return s.si.Snapshot(dest, in.FromSecond, in.ToSecond)
+}
+
+func (s *server) SayRestore(ctx context.Context, in *spec.RestoreRequest) (*spec.SnapshotManifest, error) {
+	if in.Source == "" {
+		return nil, errors.New("missing source")
+	}
+	if s.follower.readOnly() {
+		return nil, errReadOnly
+	}
+	src, err := s.backupPath(in.Source)
+	if err != nil {
+		return nil, err
+	}
+	return s.si.Restore(src)
+}
+
+func (s *server) SayReindex(ctx context.Context, in *spec.ReindexRequest) (*spec.ReindexStatus, error) {
+	if !in.StatusOnly {
+		if s.follower.readOnly() {
+			return nil, errReadOnly
+		}
+		whitelist := map[string]bool{}
+		for _, v := range in.Whitelist {
+			whitelist[v] = true
+		}
+
+		err := s.si.StartReindex(in.FromSecond, in.ToSecond, whitelist)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return s.si.ReindexStatus(), nil
+}
+
+func (s *server) SayStats(ctx context.Context, in *spec.StatsRequest) (*spec.StatsResponse, error) {
+	top := int(in.TopEventTypes)
+	if top == 0 {
+		top = 10
+	}
+	return s.si.Stats(in.FromSecond, in.ToSecond, top), nil
+}
+
+func (s *server) SayReplicationManifest(ctx context.Context, in *spec.ReplicationRequest) (*spec.ReplicationManifest, error) {
+	return s.si.ReplicationManifest()
+}
+
+func (s *server) SayShip(in *spec.ShipRequest, stream spec.Search_SayShipServer) error {
+	return s.si.Ship(in, stream.Send)
+}
+
+func (s *server) SayPromote(ctx context.Context, in *spec.PromoteRequest) (*spec.Success, error) {
+	if s.follower != nil {
+		s.follower.promote(ctx, s.si)
+	}
+	return &spec.Success{Success: true}, nil
+}
+
+func (s *server) SaySchemas(ctx context.Context, in *spec.SchemaRequest) (*spec.SchemaRegistry, error) {
+	return s.schemas.Registry(), nil
+}
+
+func (s *server) SaySetSchemas(ctx context.Context, in *spec.SchemaRegistry) (*spec.SchemaRegistry, error) {
+	err := s.schemas.Set(in)
+	if err != nil {
+		return nil, err
+	}
+	return s.schemas.Registry(), nil
+}
+
+func (s *server) SayAlerts(ctx context.Context, in *spec.AlertsRequest) (*spec.AlertsResponse, error) {
+	if s.alerts == nil {
+		return &spec.AlertsResponse{}, nil
+	}
+	return s.alerts.SayAlerts(ctx, in)
+}
+
+func (s *server) SayTail(in *spec.TailRequest, stream spec.Search_SayTailServer) error {
+	tail, err := s.si.Tail(in.Query, tailBuffer, tailMaxDropped)
+	if err != nil {
+		return err
+	}
+	defer tail.Close()
+
+	// the headers tell the client the events are followed from now on
+	err = stream.SendHeader(metadata.MD{})
+	if err != nil {
+		return err
+	}
+
+	for {
+		m, dropped, err := tail.Next(stream.Context())
+		if err == index.ErrTailTooSlow {
+			return status.Error(codes.ResourceExhausted, err.Error())
+		}
+		if err != nil {
+			return err
+		}
+		err = stream.Send(&spec.TailEvent{Hit: &spec.Hit{Id: m.Id, Metadata: m}, Dropped: dropped})
+		if err != nil {
+			return err
+		}
+	}
+}
+
 func toHit(did int32, p *spec.Metadata) *spec.Hit {
 	id := p.Id
 	if id == 0 {
@@ -242,11 +412,90 @@ func toHit(did int32, p *spec.Metadata) *spec.Hit {
 	return hit
 }
 
-func runProxy(bindHttp string, bindGrpc string) error {
+func newServer(root string, maxOpenFD int, segmentStep int64, enableSegmentCache bool, pwhitelist string, ptiers string, mergeEvery time.Duration, saveCatalogEvery time.Duration, follow string, followEvery time.Duration) *server {
+	s, err := openServer(root, maxOpenFD, segmentStep, enableSegmentCache, pwhitelist, ptiers, mergeEvery, saveCatalogEvery, follow, followEvery)
+	if err != nil {
+		Log.Fatal(err)
+	}
+	return s
+}
+
+// openServer is newServer that returns the error instead of exiting, the
+// tenants are opened while search is already serving the others
+func openServer(root string, maxOpenFD int, segmentStep int64, enableSegmentCache bool, pwhitelist string, ptiers string, mergeEvery time.Duration, saveCatalogEvery time.Duration, follow string, followEvery time.Duration) (*server, error) {
+	whitelist := map[string]bool{}
+	for _, v := range strings.Split(pwhitelist, ",") {
+		if len(v) > 0 {
+			whitelist[v] = true
+		}
+	}
+	tiers, err := index.ParseTiers(ptiers)
+	if err != nil {
+		return nil, err
+	}
+
+	si, err := index.OpenSearchIndex(root, maxOpenFD, segmentStep, enableSegmentCache, whitelist)
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		for {
+			time.Sleep(saveCatalogEvery)
+			err := si.SaveCatalog()
+			if err != nil {
+				Log.Warnf("failed to save the catalog, err: %s", err.Error())
+			}
+		}
+	}()
+
+	startMerging := func() {
+		if len(tiers) == 0 {
+			return
+		}
+		go func() {
+			for {
+				err := si.Merge(tiers, time.Now())
+				if err != nil {
+					Log.Warnf("failed to merge segments, err: %s", err.Error())
+				}
+				time.Sleep(mergeEvery)
+			}
+		}()
+	}
+
+	if follow == "" {
+		startMerging()
+		return &server{si: si, schemas: spec.NewSchemas()}, nil
+	}
+
+	// the leader merges, we just follow
+	f, err := startFollower(si, follow, followEvery, startMerging)
+	if err != nil {
+		return nil, err
+	}
+	return &server{si: si, follower: f, schemas: spec.NewSchemas()}, nil
+}
+
+func runProxy(bindHttp string, bindGrpc string, tlsFlags *certs.Flags) error {
 	ctx := context.Background()
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
+	opts, err := tlsFlags.LocalDialOptions()
+	if err != nil {
+		return err
+	}
+
+	mux, err := newProxy(ctx, bindGrpc, opts)
+	if err != nil {
+		return err
+	}
+
+	return tlsFlags.ListenAndServe(bindHttp, mux)
+}
+
+// newProxy is the grpc gateway in front of the grpc server at bindGrpc
+func newProxy(ctx context.Context, bindGrpc string, opts []grpc.DialOption) (*runtime.ServeMux, error) {
 	jsonpb := &gateway.JSONPb{
 		EmitDefaults: true,
 		OrigName:     true,
@@ -256,46 +505,159 @@ func runProxy(bindHttp string, bindGrpc string) error {
 		// This is necessary to get error details properly
 		// marshalled in unary requests.
 		runtime.WithProtoErrorHandler(runtime.DefaultHTTPProtoErrorHandler),
+		runtime.WithIncomingHeaderMatcher(auth.HeaderMatcher),
 	)
 
-	opts := []grpc.DialOption{grpc.WithInsecure()}
-
 	err := spec.RegisterSearchHandlerFromEndpoint(ctx, mux, bindGrpc, opts)
 	if err != nil {
-		return err
+		return nil, err
 	}
-
-	return http.ListenAndServe(bindHttp, mux)
+	return mux, nil
 }
 
+// used to connect to the shards and the leader, set by main
+var dialOptions = []grpc.DialOption{grpc.WithInsecure()}
+
+// events kept for a slow SayTail client, and the dropped events after
+// which it is disconnected, set by main
+var tailBuffer = 1000
+var tailMaxDropped = uint64(100000)
+
 func main() {
 	var proot = flag.String("root", "/blackrock/data-topic", "root directory for the files root/topic")
 	var bindHttp = flag.String("http", ":9002", "bind to")
 	var bindGrpc = flag.String("grpc", ":8002", "bind to")
 
 	var logLevel = flag.Int("log-level", 0, "log level")
-	var segmentStep = flag.Int("segment-step", 3600, "segment step")
+	var segmentStep = flag.Int("segment-step", 3600, "segment step of the new segments, old segments keep their step")
+	var ptiers = flag.String("tiers", "", "csv list of step:age, merge segments older than age into segments of step seconds, e.g. 86400:48h,604800:720h")
+	var mergeEvery = flag.Duration("merge-every", 10*time.Minute, "how often to merge the old segments")
+	var saveCatalogEvery = flag.Duration("save-catalog-every", time.Minute, "how often to persist the segment stats")
 	var maxOpenFD = flag.Int("max-open-fd", 1000, "max open file descriptors to write")
-	var pwhitelist = flag.String("whitelist", "", "csv list of indexable search terms, nothing means all")
+	var pwhitelist = flag.String("whitelist", "", "csv list of indexable search terms, nothing means all, changing it affects only new events unless you run blackrock reindex")
 	var enableSegmentCache = flag.Bool("enable-segment-cache", false, "enable memory cache")
+	var pshards = flag.String("shards", "", "csv list of search grpc addresses, run as coordinator that fans out to them instead of using -root")
+	var shardBy = flag.String("shard-by", "foreign_id", "how the events are spread across the shards, foreign_id (hash) or time (host:port@from-to seconds in -shards)")
+	var shardTimeout = flag.Duration("shard-timeout", 10*time.Second, "per shard timeout, slower shards are reported as failed and the results are partial")
+	var follow = flag.String("follow", "", "leader grpc address, run as read only follower that replicates it until promoted")
+	var followEvery = flag.Duration("follow-every", time.Second, "how often to replicate from the leader")
+	var queueRoot = flag.String("queue", "", "directory of the local queue, if set search also runs the Enqueue service so orgrim can push to it without kafka")
+	var queueFileBytes = flag.Int64("queue-file-bytes", 64*1024*1024, "size of the local queue files, ingested files are removed")
+	var schemas = flag.String("schemas", "", "json file with the event_type schemas checked by the -queue pipeline, events pushed to the index are not checked again, changes made with /api/v1/admin/schemas are saved there")
+	var authKeys = flag.String("auth-keys", "", "json file with the api keys and their scopes, reloaded when it changes, nothing means no auth")
+	var apiKey = flag.String("api-key", "", "api key sent to the shards and the leader")
+	var ptenants = flag.String("tenants", "", "json file with the tenant quotas, e.g. {\"default\": {\"events_per_second\": 1000, \"max_bytes\": 0, \"max_concurrent_queries\": 4}, \"tenants\": {\"team-a\": {...}}, \"allow_unknown\": false, \"max_open_tenants\": 100}, if set every request needs a tenant, from its key or the blackrock-tenant header, and goes to the index in root/<tenant>, only the listed tenants are allowed unless allow_unknown is true")
+	var backupRoot = flag.String("backup-root", "", "directory the destination of SayBackup and the source of SayRestore are relative to, with -tenants it is <backup-root>/<tenant>, nothing means no backup and restore over grpc")
+	var alerts = flag.String("alerts", "", "json file with the alert rules, e.g. {\"rules\": [{\"name\": \"no-clicks\", \"query\": {...}, \"window_sec\": 600, \"kind\": \"below\", \"threshold\": 1}]}, kind is above, below or change")
+	var alertEvery = flag.Duration("alert-every", time.Minute, "how often to check the alerts")
+	var alertWebhook = flag.String("alert-webhook", "", "url to post the alerts to when they start or stop firing, nothing means they are only logged")
+	var alertHistory = flag.Int("alert-history", 1000, "number of alert events kept in memory for /api/v1/alerts")
+	flag.IntVar(&tailBuffer, "tail-buffer", tailBuffer, "events kept per /api/v1/tail client that reads too slowly, after that they are dropped")
+	flag.Uint64Var(&tailMaxDropped, "tail-max-dropped", tailMaxDropped, "disconnect a tail client after that many dropped events in a row, 0 means never")
+	tlsFlags := certs.AddFlags(flag.CommandLine)
 	flag.Parse()
 
 	LogInit(*logLevel)
 
+	var interceptors []Interceptor
+	if *authKeys != "" {
+		keys, err := auth.LoadKeys(*authKeys)
+		if err != nil {
+			Log.Fatal(err)
+		}
+		go keys.Watch(context.Background(), 10*time.Second)
+		interceptors = append(interceptors, keys.Interceptor())
+	}
+	var err error
+	dialOptions, err = tlsFlags.DialOptions()
+	if err != nil {
+		Log.Fatal(err)
+	}
+	if *apiKey != "" {
+		dialOptions = append(dialOptions, auth.Credentials(*apiKey))
+	}
+
+	schemaRegistry := spec.NewSchemas()
+	if *schemas != "" {
+		schemaRegistry, err = spec.LoadSchemas(*schemas)
+		if err != nil {
+			Log.Fatal(err)
+		}
+	}
+
 	go func() {
 		Log.Info(http.ListenAndServe("localhost:6060", nil))
 	}()
 
-	root := *proot
-	whitelist := map[string]bool{}
-	for _, v := range strings.Split(*pwhitelist, ",") {
-		if len(v) > 0 {
-			whitelist[v] = true
+	var srv spec.SearchServer
+	var embedded *embeddedQueue
+	if *ptenants != "" {
+		if *pshards != "" || *follow != "" || *queueRoot != "" || *alerts != "" {
+			Log.Fatal("-tenants can not be used with -shards, -follow, -queue or -alerts")
 		}
+		quotas, err := loadQuotas(*ptenants)
+		if err != nil {
+			Log.Fatal(err)
+		}
+		// the tenants are opened later, fail now if they all would
+		_, err = index.ParseTiers(*ptiers)
+		if err != nil {
+			Log.Fatal(err)
+		}
+		srv = newTenantServer(*proot, quotas, schemaRegistry, func(root string) (*server, error) {
+			s, err := openServer(root, *maxOpenFD, int64(*segmentStep), *enableSegmentCache, *pwhitelist, *ptiers, *mergeEvery, *saveCatalogEvery, "", 0)
+			if err != nil {
+				return nil, err
+			}
+			if *backupRoot != "" {
+				s.backupRoot = path.Join(*backupRoot, path.Base(root))
+			}
+			return s, nil
+		})
+	} else if *pshards != "" {
+		if *queueRoot != "" || *alerts != "" {
+			Log.Fatal("-queue and -alerts need a local index, they can not be used with -shards")
+		}
+		if *shardBy != "foreign_id" && *shardBy != "time" {
+			Log.Fatalf("unknown -shard-by %s", *shardBy)
+		}
+
+		shards, err := parseShards(*pshards, *shardBy == "time")
+		if err != nil {
+			Log.Fatal(err)
+		}
+
+		srv, err = newCoordinator(shards, *shardBy == "time", *shardTimeout)
+		if err != nil {
+			Log.Fatal(err)
+		}
+	} else {
+		s := newServer(*proot, *maxOpenFD, int64(*segmentStep), *enableSegmentCache, *pwhitelist, *ptiers, *mergeEvery, *saveCatalogEvery, *follow, *followEvery)
+		if *queueRoot != "" {
+			var err error
+			embedded, err = newEmbeddedQueue(*queueRoot, *queueFileBytes, s.si, s.follower, schemaRegistry)
+			if err != nil {
+				Log.Fatal(err)
+			}
+			go func() {
+				Log.Fatal(embedded.run(context.Background()))
+			}()
+		}
+		if *alerts != "" {
+			rules, err := loadAlertRules(*alerts)
+			if err != nil {
+				Log.Fatal(err)
+			}
+			s.alerts = newAlerter(s.si, rules, webhook(*alertWebhook, 10*time.Second), *alertHistory)
+			go s.alerts.run(context.Background(), *alertEvery)
+		}
+		s.schemas = schemaRegistry
+		s.backupRoot = *backupRoot
+		srv = s
 	}
-	si := index.NewSearchIndex(root, *maxOpenFD, int64(*segmentStep), *enableSegmentCache, whitelist)
+
 	go func() {
-		err := runProxy(*bindHttp, *bindGrpc)
+		err := runProxy(*bindHttp, *bindGrpc, tlsFlags)
 		if err != nil {
 			Log.Warnf("failed to run the proxy, err: %s", err.Error())
 			os.Exit(0)
@@ -307,9 +669,15 @@ func main() {
 		Log.Fatalf("failed to listen: %v", err)
 	}
 
-	grpcServer := grpc.NewServer(AddLogging([]grpc.ServerOption{})...)
-	srv := &server{si: si}
+	serverOptions, err := tlsFlags.ServerOptions()
+	if err != nil {
+		Log.Fatal(err)
+	}
+	grpcServer := grpc.NewServer(AddLogging(serverOptions, interceptors...)...)
 	spec.RegisterSearchServer(grpcServer, srv)
+	if embedded != nil {
+		spec.RegisterEnqueueServer(grpcServer, embedded)
+	}
 	err = grpcServer.Serve(lis)
 	Log.Fatal(err)
 }
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/rekki/blackrock/pkg/auth"
+	spec "github.com/rekki/blackrock/pkg/blackrock_io"
+	. "github.com/rekki/blackrock/pkg/logger"
+	"github.com/rekki/go-query/util/go_query_dsl"
+	"google.golang.org/grpc"
+)
+
+func TestTail(t *testing.T) {
+	root, err := ioutil.TempDir("", "tail")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	err = ioutil.WriteFile(path.Join(root, "keys.json"), []byte(`{"keys": [
+		{"key": "all", "name": "all", "scopes": ["admin"]},
+		{"key": "clicks", "name": "clicks", "scopes": ["search"], "event_types": ["click"]}
+	]}`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys, err := auth.LoadKeys(path.Join(root, "keys.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	addrs := []string{}
+	for _, name := range []string{"a", "b"} {
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		grpcServer := grpc.NewServer(AddLogging([]grpc.ServerOption{}, keys.Interceptor())...)
+		spec.RegisterSearchServer(grpcServer, newServer(path.Join(root, name), 100, 3600, false, "", "", time.Hour, time.Hour, "", 0))
+		go func() {
+			_ = grpcServer.Serve(lis)
+		}()
+		defer grpcServer.Stop()
+		addrs = append(addrs, lis.Addr().String())
+	}
+
+	client := func(addr, key string) spec.SearchClient {
+		conn, err := grpc.Dial(addr, grpc.WithInsecure(), auth.Credentials(key))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return spec.NewSearchClient(conn)
+	}
+	push := func(c spec.SearchClient, eventTypes ...string) {
+		stream, err := c.SayPush(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, e := range eventTypes {
+			err = stream.Send(&spec.Envelope{Metadata: &spec.Metadata{EventType: e, ForeignType: "user", ForeignId: "1"}})
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		_, err = stream.CloseAndRecv()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	tail := func(ctx context.Context, c spec.SearchClient) spec.Search_SayTailClient {
+		stream, err := c.SayTail(ctx, &spec.TailRequest{Query: &go_query_dsl.Query{Type: go_query_dsl.Query_TERM, Field: "blackrock", Value: "match_all"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = stream.Header()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return stream
+	}
+	expect := func(stream spec.Search_SayTailClient, eventTypes ...string) {
+		for _, e := range eventTypes {
+			got, err := stream.Recv()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Hit.Metadata.EventType != e || got.Dropped != 0 {
+				t.Fatalf("expected %s got %v", e, got)
+			}
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// the key can see only clicks
+	shard := client(addrs[0], "all")
+	restricted := tail(ctx, client(addrs[0], "clicks"))
+	push(shard, "view", "click", "view", "click")
+	expect(restricted, "click", "click")
+
+	defer func(saved []grpc.DialOption) {
+		dialOptions = saved
+	}(dialOptions)
+	dialOptions = []grpc.DialOption{grpc.WithInsecure(), auth.Credentials("all")}
+	coordinator, stop := startCoordinator(t, addrs, time.Second)
+	defer stop()
+	merged := tail(ctx, coordinator)
+	push(shard, "view")
+	expect(merged, "view")
+	push(client(addrs[1], "all"), "click")
+	expect(merged, "click")
+	push(shard, "click")
+	expect(merged, "click")
+	expect(restricted, "click")
+}
@@ -0,0 +1,395 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/rekki/blackrock/pkg/auth"
+	spec "github.com/rekki/blackrock/pkg/blackrock_io"
+	. "github.com/rekki/blackrock/pkg/logger"
+	"github.com/rekki/blackrock/pkg/ratelimit"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var errMissingTenant = status.Error(codes.InvalidArgument, "missing tenant, use the blackrock-tenant header or a key with a tenant")
+var errBadTenant = status.Error(codes.InvalidArgument, "tenant can have only a-z, 0-9, _ and -, up to 64 characters")
+var errNotWithTenants = errors.New("replication is not supported with tenants")
+
+var validTenant = regexp.MustCompile(`^[a-z0-9_-]{1,64}$`)
+
+const defaultMaxOpenTenants = 100
+
+// quota of a tenant, 0 means no limit
+type quota struct {
+	EventsPerSecond      float64 `json:"events_per_second"`
+	MaxBytes             int64   `json:"max_bytes"`
+	MaxConcurrentQueries int     `json:"max_concurrent_queries"`
+}
+
+// only the tenants listed are opened, unless allow_unknown is set, then any
+// tenant gets the default quota, up to max_open_tenants indexes are open
+type quotaConfig struct {
+	Default        quota            `json:"default"`
+	Tenants        map[string]quota `json:"tenants"`
+	AllowUnknown   bool             `json:"allow_unknown"`
+	MaxOpenTenants int              `json:"max_open_tenants"`
+}
+
+func loadQuotas(filename string) (*quotaConfig, error) {
+	c := &quotaConfig{}
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(data, c)
+	if err != nil {
+		return nil, err
+	}
+	for name := range c.Tenants {
+		if !validTenant.MatchString(name) {
+			return nil, fmt.Errorf("tenant %s: %s", name, errBadTenant.Error())
+		}
+	}
+	if c.MaxOpenTenants <= 0 {
+		c.MaxOpenTenants = defaultMaxOpenTenants
+	}
+	return c, nil
+}
+
+func (c *quotaConfig) of(tenant string) quota {
+	q, ok := c.Tenants[tenant]
+	if !ok {
+		return c.Default
+	}
+	return q
+}
+
+// tenant is in the map while its index is opened, ready is closed after
+// that and then either srv or err is set
+type tenant struct {
+	name    string
+	ready   chan struct{}
+	err     error
+	srv     *server
+	quota   quota
+	ingest  *ratelimit.Bucket
+	queries chan struct{}
+	bytes   int64
+	pushed  int64
+	sync.Mutex
+}
+
+// usedBytes is the size on disk from the last updateBytes plus the size of
+// the envelopes pushed since then
+func (t *tenant) usedBytes() int64 {
+	t.Lock()
+	defer t.Unlock()
+	return t.bytes + t.pushed
+}
+
+func (t *tenant) addPushed(n int) {
+	t.Lock()
+	t.pushed += int64(n)
+	t.Unlock()
+}
+
+func (t *tenant) updateBytes() {
+	size, err := t.srv.si.DiskBytes()
+	if err != nil {
+		Log.Warnf("failed to get the size of tenant %s, err: %s", t.name, err.Error())
+		return
+	}
+	t.Lock()
+	t.bytes = size
+	t.pushed = 0
+	t.Unlock()
+}
+
+// tenantServer routes every request to the index of its tenant in
+// root/<tenant>, the indexes are opened on first use
+type tenantServer struct {
+	root    string
+	open    func(root string) (*server, error)
+	quotas  *quotaConfig
+	schemas *spec.Schemas
+	tenants map[string]*tenant
+	sync.Mutex
+}
+
+func newTenantServer(root string, quotas *quotaConfig, schemas *spec.Schemas, open func(root string) (*server, error)) *tenantServer {
+	return &tenantServer{root: root, open: open, quotas: quotas, schemas: schemas, tenants: map[string]*tenant{}}
+}
+
+// tenantName is the tenant of the key, or the one in the header
+func tenantName(ctx context.Context) (string, error) {
+	name := ""
+	md, _ := metadata.FromIncomingContext(ctx)
+	if v := md.Get(auth.TenantHeader); len(v) > 0 {
+		name = v[0]
+	}
+
+	if key := auth.FromContext(ctx); key != nil && key.Tenant != "" {
+		if name != "" && name != key.Tenant {
+			return "", status.Errorf(codes.PermissionDenied, "key %s can not use tenant %s", key.Name, name)
+		}
+		name = key.Tenant
+	}
+
+	if name == "" {
+		return "", errMissingTenant
+	}
+	if !validTenant.MatchString(name) {
+		return "", errBadTenant
+	}
+	return name, nil
+}
+
+func (ts *tenantServer) tenant(ctx context.Context) (*tenant, error) {
+	name, err := tenantName(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ts.Lock()
+	t, ok := ts.tenants[name]
+	if !ok {
+		if _, listed := ts.quotas.Tenants[name]; !listed && !ts.quotas.AllowUnknown {
+			ts.Unlock()
+			return nil, status.Errorf(codes.PermissionDenied, "unknown tenant %s", name)
+		}
+		if len(ts.tenants) >= ts.quotas.MaxOpenTenants {
+			ts.Unlock()
+			return nil, status.Errorf(codes.ResourceExhausted, "%d tenants are open already", len(ts.tenants))
+		}
+		t = &tenant{name: name, quota: ts.quotas.of(name), ready: make(chan struct{})}
+		ts.tenants[name] = t
+	}
+	ts.Unlock()
+
+	if !ok {
+		// opening the index can take a while, the other tenants are not
+		// blocked by it
+		ts.openTenant(t)
+	}
+
+	select {
+	case <-t.ready:
+	case <-ctx.Done():
+		return nil, status.FromContextError(ctx.Err()).Err()
+	}
+	if t.err != nil {
+		return nil, t.err
+	}
+	return t, nil
+}
+
+// openTenant opens the index of the tenant and closes t.ready, if it fails
+// the tenant is removed so the next request tries again
+func (ts *tenantServer) openTenant(t *tenant) {
+	defer close(t.ready)
+
+	srv, err := ts.open(path.Join(ts.root, t.name))
+	if err != nil {
+		Log.Warnf("failed to open tenant %s, err: %s", t.name, err.Error())
+		t.err = status.Errorf(codes.Unavailable, "failed to open tenant %s", t.name)
+		ts.Lock()
+		delete(ts.tenants, t.name)
+		ts.Unlock()
+		return
+	}
+	t.srv = srv
+
+	q := t.quota
+	if q.EventsPerSecond > 0 {
+		t.ingest = ratelimit.NewBucket(q.EventsPerSecond, int(q.EventsPerSecond))
+	}
+	if q.MaxConcurrentQueries > 0 {
+		t.queries = make(chan struct{}, q.MaxConcurrentQueries)
+	}
+	if q.MaxBytes > 0 {
+		t.updateBytes()
+		go func() {
+			for {
+				time.Sleep(10 * time.Second)
+				t.updateBytes()
+			}
+		}()
+	}
+	Log.Infof("opened tenant %s with quota %+v", t.name, q)
+}
+
+// query returns the tenant and a func to call when the query is done
+func (ts *tenantServer) query(ctx context.Context) (*tenant, func(), error) {
+	t, err := ts.tenant(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if t.queries == nil {
+		return t, func() {}, nil
+	}
+
+	select {
+	case t.queries <- struct{}{}:
+		return t, func() { <-t.queries }, nil
+	default:
+		return nil, nil, status.Errorf(codes.ResourceExhausted, "tenant %s has %d queries running", t.name, t.quota.MaxConcurrentQueries)
+	}
+}
+
+// tenantPush checks the size quota and waits for the ingest rate of the
+// tenant on every envelope, so a long stream can not go over them
+type tenantPush struct {
+	spec.Search_SayPushServer
+	t *tenant
+}
+
+func (p *tenantPush) checkBytes() error {
+	if p.t.quota.MaxBytes > 0 && p.t.usedBytes() >= p.t.quota.MaxBytes {
+		return status.Errorf(codes.ResourceExhausted, "tenant %s uses %d bytes, quota is %d", p.t.name, p.t.usedBytes(), p.t.quota.MaxBytes)
+	}
+	return nil
+}
+
+func (p *tenantPush) Recv() (*spec.Envelope, error) {
+	envelope, err := p.Search_SayPushServer.Recv()
+	if err != nil {
+		return nil, err
+	}
+	err = p.checkBytes()
+	if err != nil {
+		return nil, err
+	}
+	if p.t.quota.MaxBytes > 0 {
+		p.t.addPushed(envelope.Size())
+	}
+	if p.t.ingest != nil {
+		err = p.t.ingest.Wait(p.Context())
+		if err != nil {
+			return nil, err
+		}
+	}
+	return envelope, nil
+}
+
+func (ts *tenantServer) SayPush(stream spec.Search_SayPushServer) error {
+	t, err := ts.tenant(stream.Context())
+	if err != nil {
+		return err
+	}
+	push := &tenantPush{Search_SayPushServer: stream, t: t}
+	err = push.checkBytes()
+	if err != nil {
+		return err
+	}
+	return t.srv.SayPush(push)
+}
+
+func (ts *tenantServer) SaySearch(ctx context.Context, qr *spec.SearchQueryRequest) (*spec.SearchQueryResponse, error) {
+	t, done, err := ts.query(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	return t.srv.SaySearch(ctx, qr)
+}
+
+func (ts *tenantServer) SayFetch(qr *spec.SearchQueryRequest, stream spec.Search_SayFetchServer) error {
+	t, done, err := ts.query(stream.Context())
+	if err != nil {
+		return err
+	}
+	defer done()
+	return t.srv.SayFetch(qr, stream)
+}
+
+func (ts *tenantServer) SayAggregate(ctx context.Context, qr *spec.AggregateRequest) (*spec.Aggregate, error) {
+	t, done, err := ts.query(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	return t.srv.SayAggregate(ctx, qr)
+}
+
+func (ts *tenantServer) SayHealth(context.Context, *spec.HealthRequest) (*spec.Success, error) {
+	return &spec.Success{Success: true}, nil
+}
+
+func (ts *tenantServer) SayBackup(ctx context.Context, in *spec.BackupRequest) (*spec.SnapshotManifest, error) {
+	t, err := ts.tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return t.srv.SayBackup(ctx, in)
+}
+
+func (ts *tenantServer) SayRestore(ctx context.Context, in *spec.RestoreRequest) (*spec.SnapshotManifest, error) {
+	t, err := ts.tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return t.srv.SayRestore(ctx, in)
+}
+
+func (ts *tenantServer) SayReindex(ctx context.Context, in *spec.ReindexRequest) (*spec.ReindexStatus, error) {
+	t, err := ts.tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return t.srv.SayReindex(ctx, in)
+}
+
+func (ts *tenantServer) SayStats(ctx context.Context, in *spec.StatsRequest) (*spec.StatsResponse, error) {
+	t, err := ts.tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return t.srv.SayStats(ctx, in)
+}
+
+func (ts *tenantServer) SayReplicationManifest(ctx context.Context, in *spec.ReplicationRequest) (*spec.ReplicationManifest, error) {
+	return nil, errNotWithTenants
+}
+
+func (ts *tenantServer) SayShip(in *spec.ShipRequest, stream spec.Search_SayShipServer) error {
+	return errNotWithTenants
+}
+
+func (ts *tenantServer) SayPromote(ctx context.Context, in *spec.PromoteRequest) (*spec.Success, error) {
+	return nil, errNotWithTenants
+}
+
+func (ts *tenantServer) SayAlerts(ctx context.Context, in *spec.AlertsRequest) (*spec.AlertsResponse, error) {
+	return nil, errAlertsNeedIndex
+}
+
+// tails are long running, they do not count as queries
+func (ts *tenantServer) SayTail(in *spec.TailRequest, stream spec.Search_SayTailServer) error {
+	t, err := ts.tenant(stream.Context())
+	if err != nil {
+		return err
+	}
+	return t.srv.SayTail(in, stream)
+}
+
+// the schemas are shared by all tenants
+func (ts *tenantServer) SaySchemas(ctx context.Context, in *spec.SchemaRequest) (*spec.SchemaRegistry, error) {
+	return ts.schemas.Registry(), nil
+}
+
+func (ts *tenantServer) SaySetSchemas(ctx context.Context, in *spec.SchemaRegistry) (*spec.SchemaRegistry, error) {
+	err := ts.schemas.Set(in)
+	if err != nil {
+		return nil, err
+	}
+	return ts.schemas.Registry(), nil
+}
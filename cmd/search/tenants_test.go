@@ -0,0 +1,241 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/rekki/blackrock/pkg/auth"
+	spec "github.com/rekki/blackrock/pkg/blackrock_io"
+	. "github.com/rekki/blackrock/pkg/logger"
+	"github.com/rekki/go-query/util/go_query_dsl"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestTenants(t *testing.T) {
+	root, err := ioutil.TempDir("", "tenants")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	err = ioutil.WriteFile(path.Join(root, "keys.json"), []byte(`{"keys": [
+		{"key": "ka", "name": "a", "scopes": ["admin"], "tenant": "a"},
+		{"key": "any", "name": "any", "scopes": ["admin"]}
+	]}`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys, err := auth.LoadKeys(path.Join(root, "keys.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ioutil.WriteFile(path.Join(root, "tenants.json"), []byte(`{
+		"tenants": {"a": {"events_per_second": 20, "max_concurrent_queries": 1}, "full": {"max_bytes": 1000}},
+		"allow_unknown": true,
+		"max_open_tenants": 3
+	}`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	quotas, err := loadQuotas(path.Join(root, "tenants.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ts := newTenantServer(path.Join(root, "data"), quotas, spec.NewSchemas(), func(root string) (*server, error) {
+		return openServer(root, 100, 3600, false, "", "", time.Hour, time.Hour, "", 0)
+	})
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	grpcServer := grpc.NewServer(AddLogging([]grpc.ServerOption{}, keys.Interceptor())...)
+	spec.RegisterSearchServer(grpcServer, ts)
+	go func() {
+		_ = grpcServer.Serve(lis)
+	}()
+	defer grpcServer.Stop()
+
+	client := func(key string, tenant string) spec.SearchClient {
+		opts := []grpc.DialOption{grpc.WithInsecure(), auth.Credentials(key)}
+		if tenant != "" {
+			opts = append(opts, auth.Tenant(tenant))
+		}
+		conn, err := grpc.Dial(lis.Addr().String(), opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return spec.NewSearchClient(conn)
+	}
+
+	push := func(c spec.SearchClient, n int) error {
+		stream, err := c.SayPush(context.Background())
+		if err != nil {
+			return err
+		}
+		for i := 0; i < n; i++ {
+			err = stream.Send(&spec.Envelope{Metadata: &spec.Metadata{
+				CreatedAtNs: time.Now().UnixNano(),
+				EventType:   "click",
+				ForeignType: "user",
+				ForeignId:   fmt.Sprintf("%d", i),
+			}})
+			if err != nil {
+				return err
+			}
+		}
+		_, err = stream.CloseAndRecv()
+		return err
+	}
+	count := func(c spec.SearchClient) uint64 {
+		now := uint32(time.Now().Unix())
+		res, err := c.SaySearch(context.Background(), &spec.SearchQueryRequest{
+			FromSecond: now - 3600,
+			ToSecond:   now + 3600,
+			Query:      &go_query_dsl.Query{Type: go_query_dsl.Query_TERM, Field: "event_type", Value: "click"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res.Total
+	}
+
+	a := client("ka", "")
+	b := client("any", "b")
+	if err := push(a, 10); err != nil {
+		t.Fatal(err)
+	}
+	if err := push(b, 5); err != nil {
+		t.Fatal(err)
+	}
+	if n := count(a); n != 10 {
+		t.Fatalf("expected 10 in a got %d", n)
+	}
+	if n := count(b); n != 5 {
+		t.Fatalf("expected 5 in b got %d", n)
+	}
+	if n := count(client("any", "a")); n != 10 {
+		t.Fatalf("expected 10 in a with header got %d", n)
+	}
+	if _, err := os.Stat(path.Join(root, "data", "b")); err != nil {
+		t.Fatal(err)
+	}
+
+	// the header works through the grpc gateway too
+	mux, err := newProxy(context.Background(), lis.Addr().String(), []grpc.DialOption{grpc.WithInsecure()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	proxy := httptest.NewServer(mux)
+	defer proxy.Close()
+	for tenant, expected := range map[string]int{"b": http.StatusOK, "": http.StatusBadRequest} {
+		r, err := http.NewRequest("GET", proxy.URL+"/api/v1/stats", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r.Header.Set("X-Api-Key", "any")
+		if tenant != "" {
+			r.Header.Set("Blackrock-Tenant", tenant)
+		}
+		res, err := http.DefaultClient.Do(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+		if res.StatusCode != expected {
+			t.Fatalf("tenant %q: expected %d got %d", tenant, expected, res.StatusCode)
+		}
+	}
+
+	_, err = client("ka", "b").SayStats(context.Background(), &spec.StatsRequest{})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected permission denied got %v", err)
+	}
+	_, err = client("any", "").SayStats(context.Background(), &spec.StatsRequest{})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected invalid argument got %v", err)
+	}
+	_, err = client("any", "../x").SayStats(context.Background(), &spec.StatsRequest{})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected invalid argument got %v", err)
+	}
+
+	// a broken tenant fails alone, and does not count as open
+	err = ioutil.WriteFile(path.Join(root, "data", "broken"), []byte("not a directory"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client("any", "broken").SayStats(context.Background(), &spec.StatsRequest{})
+	if status.Code(err) != codes.Unavailable {
+		t.Fatalf("expected unavailable got %v", err)
+	}
+	if n := count(a); n != 10 {
+		t.Fatalf("expected 10 in a got %d", n)
+	}
+
+	// 10 tokens left, the rest comes at 20 per second
+	started := time.Now()
+	if err := push(a, 30); err != nil {
+		t.Fatal(err)
+	}
+	if took := time.Since(started); took < 500*time.Millisecond {
+		t.Fatalf("expected throttled ingest, took %s", took)
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(auth.TenantHeader, "a"))
+	_, done, err := ts.query(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, err = ts.query(ctx)
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("expected resource exhausted got %v", err)
+	}
+	done()
+	_, done, err = ts.query(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	done()
+
+	// the quota is checked for every envelope of the stream
+	full := client("any", "full")
+	if err := push(full, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := push(full, 100); status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("expected resource exhausted got %v", err)
+	}
+	if n := count(full); n == 0 || n >= 100 {
+		t.Fatalf("expected the stream to stop at the quota got %d", n)
+	}
+	ts.tenants["full"].updateBytes()
+	if err := push(full, 1); status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("expected resource exhausted got %v", err)
+	}
+
+	// a, b and full are open
+	_, err = client("any", "d").SayStats(context.Background(), &spec.StatsRequest{})
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("expected resource exhausted got %v", err)
+	}
+	ts.quotas.AllowUnknown = false
+	_, err = client("any", "d").SayStats(context.Background(), &spec.StatsRequest{})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected permission denied got %v", err)
+	}
+	if _, err := os.Stat(path.Join(root, "data", "d")); !os.IsNotExist(err) {
+		t.Fatalf("expected no index for d got %v", err)
+	}
+}
@@ -0,0 +1,192 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	spec "github.com/rekki/blackrock/pkg/blackrock_io"
+	"github.com/rekki/blackrock/pkg/logger"
+)
+
+const (
+	ScopeIngest = "ingest"
+	ScopeSearch = "search"
+	ScopeAdmin  = "admin"
+)
+
+var errMissingKey = errors.New("missing api key, use authorization: Bearer <key> or x-api-key: <key>")
+var errUnknownKey = errors.New("unknown api key")
+var errEmptyKey = errors.New("api key can not be empty")
+
+// Key is one entry of the keys file, no event_types means all, the admin
+// scope allows everything. A key with a tenant can only use that tenant.
+// Keys that can not search or have event_types only get the partition
+// offsets from SayStats, and keys with event_types can not read the alerts.
+type Key struct {
+	Key        string   `json:"key"`
+	Name       string   `json:"name"`
+	Scopes     []string `json:"scopes"`
+	EventTypes []string `json:"event_types"`
+	Tenant     string   `json:"tenant"`
+}
+
+func (k *Key) Can(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == scope || s == ScopeAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+func (k *Key) AllowEventType(eventType string) bool {
+	if len(k.EventTypes) == 0 {
+		return true
+	}
+	for _, e := range k.EventTypes {
+		if e == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckEnvelope returns an error if the key can not push the envelope
+func (k *Key) CheckEnvelope(envelope *spec.Envelope) error {
+	if !k.Can(ScopeIngest) {
+		return fmt.Errorf("key %s can not %s", k.Name, ScopeIngest)
+	}
+	if envelope.Metadata != nil && !k.AllowEventType(envelope.Metadata.EventType) {
+		return fmt.Errorf("key %s can not push event_type %s", k.Name, envelope.Metadata.EventType)
+	}
+	return nil
+}
+
+type keysFile struct {
+	Keys []*Key `json:"keys"`
+}
+
+// Keys are the api keys loaded from a json file like {"keys": [{"key":
+// "secret", "name": "web", "scopes": ["ingest"], "event_types": ["click"]}]},
+// the file is read again by Reload when it changes
+type Keys struct {
+	filename string
+	modTime  time.Time
+	byKey    map[string]*Key
+	sync.RWMutex
+}
+
+func LoadKeys(filename string) (*Keys, error) {
+	k := &Keys{filename: filename, byKey: map[string]*Key{}}
+	err := k.Reload()
+	if err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// Reload reads the file if it was modified since the last load, on error
+// the old keys are kept
+func (k *Keys) Reload() error {
+	info, err := os.Stat(k.filename)
+	if err != nil {
+		return err
+	}
+
+	k.RLock()
+	same := info.ModTime().Equal(k.modTime)
+	k.RUnlock()
+	if same {
+		return nil
+	}
+
+	data, err := ioutil.ReadFile(k.filename)
+	if err != nil {
+		return err
+	}
+	f := &keysFile{}
+	err = json.Unmarshal(data, f)
+	if err != nil {
+		return err
+	}
+
+	byKey := map[string]*Key{}
+	for _, key := range f.Keys {
+		if key.Key == "" {
+			return errEmptyKey
+		}
+		byKey[key.Key] = key
+	}
+
+	k.Lock()
+	k.byKey = byKey
+	k.modTime = info.ModTime()
+	k.Unlock()
+	return nil
+}
+
+// Watch calls Reload every so often until ctx is done
+func (k *Keys) Watch(ctx context.Context, every time.Duration) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(every):
+		}
+
+		err := k.Reload()
+		if err != nil {
+			logger.Log.Warnf("failed to reload %s, keeping the old keys, err: %s", k.filename, err.Error())
+		}
+	}
+}
+
+func (k *Keys) Lookup(token string) (*Key, error) {
+	if token == "" {
+		return nil, errMissingKey
+	}
+
+	k.RLock()
+	defer k.RUnlock()
+	key, ok := k.byKey[token]
+	if !ok {
+		return nil, errUnknownKey
+	}
+	return key, nil
+}
+
+// FromHTTP looks up the key of the authorization or x-api-key header
+func (k *Keys) FromHTTP(r *http.Request) (*Key, error) {
+	token := bearer(r.Header.Get("authorization"))
+	if token == "" {
+		token = r.Header.Get("x-api-key")
+	}
+	return k.Lookup(token)
+}
+
+func bearer(v string) string {
+	if len(v) > 7 && strings.EqualFold(v[:7], "bearer ") {
+		return strings.TrimSpace(v[7:])
+	}
+	return ""
+}
+
+type keyContext struct{}
+
+// FromContext returns the key of the request, nil when there is no auth
+func FromContext(ctx context.Context) *Key {
+	key, _ := ctx.Value(keyContext{}).(*Key)
+	return key
+}
+
+func NewContext(ctx context.Context, key *Key) context.Context {
+	return context.WithValue(ctx, keyContext{}, key)
+}
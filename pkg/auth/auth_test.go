@@ -0,0 +1,181 @@
+package auth
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	spec "github.com/rekki/blackrock/pkg/blackrock_io"
+	"github.com/rekki/go-query/util/go_query_dsl"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func writeKeys(t *testing.T, fn string, data string, mtime time.Time) {
+	t.Helper()
+	err := ioutil.WriteFile(fn, []byte(data), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.Chtimes(fn, mtime, mtime)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func withKey(key string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+key))
+}
+
+type fakeStream struct {
+	grpc.ServerStream
+	ctx      context.Context
+	envelope *spec.Envelope
+}
+
+func (f *fakeStream) Context() context.Context {
+	return f.ctx
+}
+
+func (f *fakeStream) RecvMsg(m interface{}) error {
+	*(m.(*spec.Envelope)) = *f.envelope
+	return nil
+}
+
+func TestKeys(t *testing.T) {
+	dir, err := ioutil.TempDir("", "auth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	fn := path.Join(dir, "keys.json")
+	now := time.Now()
+	writeKeys(t, fn, `{"keys": [
+		{"key": "web", "name": "web", "scopes": ["ingest"], "event_types": ["click"]},
+		{"key": "dash", "name": "dash", "scopes": ["search"], "event_types": ["click", "view"]},
+		{"key": "root", "name": "root", "scopes": ["admin"]}
+	]}`, now.Add(-time.Minute))
+
+	keys, err := LoadKeys(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	unary := keys.UnaryServerInterceptor()
+	call := func(ctx context.Context, method string, req interface{}) codes.Code {
+		_, err := unary(ctx, req, &grpc.UnaryServerInfo{FullMethod: method}, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, nil
+		})
+		return status.Code(err)
+	}
+
+	if code := call(context.Background(), "/blackrock.io.Search/SayHealth", &spec.HealthRequest{}); code != codes.OK {
+		t.Fatalf("expected open health got %v", code)
+	}
+	if code := call(context.Background(), "/blackrock.io.Search/SaySearch", &spec.SearchQueryRequest{}); code != codes.Unauthenticated {
+		t.Fatalf("expected unauthenticated got %v", code)
+	}
+	if code := call(withKey("nope"), "/blackrock.io.Search/SaySearch", &spec.SearchQueryRequest{}); code != codes.Unauthenticated {
+		t.Fatalf("expected unauthenticated got %v", code)
+	}
+	if code := call(withKey("web"), "/blackrock.io.Search/SaySearch", &spec.SearchQueryRequest{}); code != codes.PermissionDenied {
+		t.Fatalf("expected permission denied got %v", code)
+	}
+	if code := call(withKey("dash"), "/blackrock.io.Search/SayBackup", &spec.BackupRequest{}); code != codes.PermissionDenied {
+		t.Fatalf("expected permission denied got %v", code)
+	}
+	if code := call(withKey("root"), "/blackrock.io.Search/SayBackup", &spec.BackupRequest{}); code != codes.OK {
+		t.Fatalf("expected ok got %v", code)
+	}
+
+	qr := &spec.SearchQueryRequest{Query: &go_query_dsl.Query{Type: go_query_dsl.Query_TERM, Field: "a", Value: "b"}}
+	if code := call(withKey("dash"), "/blackrock.io.Search/SaySearch", qr); code != codes.OK {
+		t.Fatalf("expected ok got %v", code)
+	}
+	if qr.Query.Type != go_query_dsl.Query_AND || len(qr.Query.Queries[1].Queries) != 2 || qr.Query.Queries[1].Queries[1].Value != "view" {
+		t.Fatalf("expected restricted query got %v", qr.Query)
+	}
+	agg := &spec.AggregateRequest{Query: &spec.SearchQueryRequest{}}
+	if code := call(withKey("dash"), "/blackrock.io.Search/SayAggregate", agg); code != codes.OK || agg.Query.Query == nil {
+		t.Fatalf("expected restricted aggregate got %v %v", code, agg.Query)
+	}
+
+	// ingest keys and keys with event types only get the partition offsets
+	statsCall := func(key string) (*spec.StatsResponse, codes.Code) {
+		resp, err := unary(withKey(key), &spec.StatsRequest{}, &grpc.UnaryServerInfo{FullMethod: "/blackrock.io.Search/SayStats"}, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return &spec.StatsResponse{Docs: 10, Segments: []*spec.SegmentStats{{Docs: 10}}, PartitionOffsets: map[int32]int64{0: 5}}, nil
+		})
+		if err != nil {
+			return nil, status.Code(err)
+		}
+		return resp.(*spec.StatsResponse), codes.OK
+	}
+	for _, name := range []string{"web", "dash"} {
+		stats, code := statsCall(name)
+		if code != codes.OK || stats.Docs != 0 || len(stats.Segments) != 0 || stats.PartitionOffsets[0] != 5 {
+			t.Fatalf("expected only offsets for %s got %v %v", name, code, stats)
+		}
+	}
+	if stats, code := statsCall("root"); code != codes.OK || stats.Docs != 10 {
+		t.Fatalf("expected all stats got %v %v", code, stats)
+	}
+	if code := call(withKey("dash"), "/blackrock.io.Search/SayAlerts", &spec.AlertsRequest{}); code != codes.PermissionDenied {
+		t.Fatalf("expected permission denied got %v", code)
+	}
+	if code := call(withKey("root"), "/blackrock.io.Search/SayAlerts", &spec.AlertsRequest{}); code != codes.OK {
+		t.Fatalf("expected ok got %v", code)
+	}
+
+	stream := keys.StreamServerInterceptor()
+	push := func(key string, eventType string) codes.Code {
+		ss := &fakeStream{ctx: withKey(key), envelope: &spec.Envelope{Metadata: &spec.Metadata{EventType: eventType}}}
+		return status.Code(stream(nil, ss, &grpc.StreamServerInfo{FullMethod: "/blackrock.io.Enqueue/SayPush"}, func(srv interface{}, ss grpc.ServerStream) error {
+			if FromContext(ss.Context()) == nil {
+				t.Fatal("expected key in the context")
+			}
+			return ss.RecvMsg(&spec.Envelope{})
+		}))
+	}
+	if code := push("web", "click"); code != codes.OK {
+		t.Fatalf("expected ok got %v", code)
+	}
+	if code := push("web", "view"); code != codes.PermissionDenied {
+		t.Fatalf("expected permission denied got %v", code)
+	}
+	if code := push("dash", "click"); code != codes.PermissionDenied {
+		t.Fatalf("expected permission denied got %v", code)
+	}
+
+	r, _ := http.NewRequest("POST", "/push/flatten", nil)
+	r.Header.Set("X-Api-Key", "web")
+	key, err := keys.FromHTTP(r)
+	if err != nil || key.Name != "web" {
+		t.Fatalf("unexpected http key %v %v", key, err)
+	}
+
+	// broken file keeps the old keys
+	writeKeys(t, fn, `{"keys": [{"name": "empty"}]}`, now)
+	if err := keys.Reload(); err != errEmptyKey {
+		t.Fatalf("expected empty key error got %v", err)
+	}
+	if _, err := keys.Lookup("web"); err != nil {
+		t.Fatal(err)
+	}
+
+	writeKeys(t, fn, `{"keys": [{"key": "web2", "scopes": ["ingest"]}]}`, now.Add(time.Minute))
+	if err := keys.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := keys.Lookup("web"); err != errUnknownKey {
+		t.Fatalf("expected unknown key got %v", err)
+	}
+	if _, err := keys.Lookup("web2"); err != nil {
+		t.Fatal(err)
+	}
+}
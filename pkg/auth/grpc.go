@@ -0,0 +1,208 @@
+package auth
+
+import (
+	"context"
+	"strings"
+
+	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	spec "github.com/rekki/blackrock/pkg/blackrock_io"
+	"github.com/rekki/blackrock/pkg/logger"
+	"github.com/rekki/go-query/util/go_query_dsl"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// scope of every method, health is open, whatever is not here needs admin
+var methodScopes = map[string]string{
+	"/blackrock.io.Enqueue/SayPush":     ScopeIngest,
+	"/blackrock.io.Enqueue/SayPushSync": ScopeIngest,
+	"/blackrock.io.Enqueue/SayHealth":   "",
+	"/blackrock.io.Search/SayPush":      ScopeIngest,
+	"/blackrock.io.Search/SayHealth":    "",
+	"/blackrock.io.Search/SaySearch":    ScopeSearch,
+	"/blackrock.io.Search/SayFetch":     ScopeSearch,
+	"/blackrock.io.Search/SayAggregate": ScopeSearch,
+	"/blackrock.io.Search/SayStats":     ScopeSearch,
+	"/blackrock.io.Search/SayAlerts":    ScopeSearch,
+	"/blackrock.io.Search/SayTail":      ScopeSearch,
+}
+
+// statsMethod is also open to ingest keys, the consumer reads the partition
+// offsets kept in the index with it
+const statsMethod = "/blackrock.io.Search/SayStats"
+
+func scopeOf(method string) string {
+	scope, ok := methodScopes[method]
+	if !ok {
+		return ScopeAdmin
+	}
+	return scope
+}
+
+func (k *Keys) fromContext(ctx context.Context, method string) (*Key, error) {
+	scope := scopeOf(method)
+	if scope == "" {
+		return nil, nil
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	token := ""
+	for _, h := range []string{"authorization", "grpcgateway-authorization"} {
+		if v := md.Get(h); len(v) > 0 && token == "" {
+			token = bearer(v[0])
+		}
+	}
+	if v := md.Get("x-api-key"); len(v) > 0 && token == "" {
+		token = v[0]
+	}
+
+	key, err := k.Lookup(token)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	if !key.Can(scope) && !(method == statsMethod && key.Can(ScopeIngest)) {
+		return nil, status.Errorf(codes.PermissionDenied, "key %s can not %s", key.Name, scope)
+	}
+	return key, nil
+}
+
+// restrict limits the query to the event types of the key
+func restrict(key *Key, qr *spec.SearchQueryRequest) {
+	if key == nil || len(key.EventTypes) == 0 || qr == nil {
+		return
+	}
+
+	allowed := &go_query_dsl.Query{Type: go_query_dsl.Query_OR}
+	for _, e := range key.EventTypes {
+		allowed.Queries = append(allowed.Queries, &go_query_dsl.Query{Type: go_query_dsl.Query_TERM, Field: "event_type", Value: e})
+	}
+	if qr.Query == nil {
+		qr.Query = allowed
+		return
+	}
+	qr.Query = &go_query_dsl.Query{Type: go_query_dsl.Query_AND, Queries: []*go_query_dsl.Query{qr.Query, allowed}}
+}
+
+func (k *Keys) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		key, err := k.fromContext(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+
+		switch r := req.(type) {
+		case *spec.SearchQueryRequest:
+			restrict(key, r)
+		case *spec.AggregateRequest:
+			restrict(key, r.Query)
+		case *spec.AlertsRequest:
+			// the alerts are not split by event type
+			if key != nil && len(key.EventTypes) > 0 {
+				return nil, status.Errorf(codes.PermissionDenied, "key %s is limited to some event types, alerts need a key without event_types", key.Name)
+			}
+		}
+
+		resp, err := handler(NewContext(ctx, key), req)
+		if stats, ok := resp.(*spec.StatsResponse); ok && key != nil && (!key.Can(ScopeSearch) || len(key.EventTypes) > 0) {
+			// the counts are not split by key, keys that can not search
+			// everything only get the partition offsets
+			resp = &spec.StatsResponse{PartitionOffsets: stats.PartitionOffsets}
+		}
+		return resp, err
+	}
+}
+
+func (k *Keys) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		key, err := k.fromContext(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+
+		wrapped := grpc_middleware.WrapServerStream(ss)
+		wrapped.WrappedContext = NewContext(ss.Context(), key)
+		return handler(srv, &checkedStream{WrappedServerStream: wrapped, key: key})
+	}
+}
+
+// checkedStream checks every pushed envelope and restricts the fetch and
+// tail queries
+type checkedStream struct {
+	*grpc_middleware.WrappedServerStream
+	key *Key
+}
+
+func (s *checkedStream) RecvMsg(m interface{}) error {
+	err := s.WrappedServerStream.RecvMsg(m)
+	if err != nil || s.key == nil {
+		return err
+	}
+
+	switch r := m.(type) {
+	case *spec.Envelope:
+		err = s.key.CheckEnvelope(r)
+		if err != nil {
+			return status.Error(codes.PermissionDenied, err.Error())
+		}
+	case *spec.SearchQueryRequest:
+		restrict(s.key, r)
+	case *spec.TailRequest:
+		qr := &spec.SearchQueryRequest{Query: r.Query}
+		restrict(s.key, qr)
+		r.Query = qr.Query
+	}
+	return nil
+}
+
+// HeaderMatcher passes the x-api-key and blackrock-tenant headers from the
+// grpc gateway to the grpc metadata, the rest is up to
+// runtime.DefaultHeaderMatcher
+func HeaderMatcher(key string) (string, bool) {
+	switch k := strings.ToLower(key); k {
+	case "x-api-key", TenantHeader:
+		return k, true
+	}
+	return runtime.DefaultHeaderMatcher(key)
+}
+
+// TenantHeader picks the tenant when search runs with tenants, keys with a
+// tenant do not need it
+const TenantHeader = "blackrock-tenant"
+
+// Credentials sends the key with every call of a client connection
+func Credentials(key string) grpc.DialOption {
+	return grpc.WithPerRPCCredentials(tokenCredentials(key))
+}
+
+// Tenant sends the tenant with every call of a client connection
+func Tenant(name string) grpc.DialOption {
+	return grpc.WithPerRPCCredentials(tenantCredentials(name))
+}
+
+type tenantCredentials string
+
+func (t tenantCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{TenantHeader: string(t)}, nil
+}
+
+func (t tenantCredentials) RequireTransportSecurity() bool {
+	return false
+}
+
+type tokenCredentials string
+
+func (t tokenCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + string(t)}, nil
+}
+
+func (t tokenCredentials) RequireTransportSecurity() bool {
+	return false
+}
+
+// Interceptor is what logger.AddLogging needs to check the keys
+func (k *Keys) Interceptor() logger.Interceptor {
+	return logger.Interceptor{Unary: k.UnaryServerInterceptor(), Stream: k.StreamServerInterceptor()}
+}
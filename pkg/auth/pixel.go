@@ -0,0 +1,53 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"time"
+)
+
+var errMissingSignature = errors.New("missing pixel signature")
+var errBadSignature = errors.New("bad pixel signature")
+var errExpiredSignature = errors.New("expired pixel signature")
+
+func pixelMac(secret []byte, path string, exp int64) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(path))
+	if exp > 0 {
+		mac.Write([]byte("?exp=" + strconv.FormatInt(exp, 10)))
+	}
+	return mac.Sum(nil)
+}
+
+// SignPixel is the hex hmac-sha256 of the pixel path, e.g.
+// /png/click/user/5/campaign:x, and of exp, the unix second it expires at,
+// 0 means it never expires
+func SignPixel(secret []byte, path string, exp int64) string {
+	return hex.EncodeToString(pixelMac(secret, path, exp))
+}
+
+// VerifyPixel checks the sig and exp query params of a pixel url
+func VerifyPixel(secret []byte, path string, exp string, sig string, now time.Time) error {
+	if sig == "" {
+		return errMissingSignature
+	}
+	var e int64
+	if exp != "" {
+		var err error
+		e, err = strconv.ParseInt(exp, 10, 64)
+		if err != nil || e <= 0 {
+			return errBadSignature
+		}
+	}
+	got, err := hex.DecodeString(sig)
+	if err != nil || !hmac.Equal(got, pixelMac(secret, path, e)) {
+		return errBadSignature
+	}
+	if e > 0 && now.Unix() > e {
+		return errExpiredSignature
+	}
+	return nil
+}
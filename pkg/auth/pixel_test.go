@@ -0,0 +1,41 @@
+package auth
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestPixel(t *testing.T) {
+	secret := []byte("secret")
+	p := "/png/click/user/5/campaign:x"
+	now := time.Now()
+
+	if err := VerifyPixel(secret, p, "", SignPixel(secret, p, 0), now); err != nil {
+		t.Fatal(err)
+	}
+	exp := now.Add(time.Minute).Unix()
+	sig := SignPixel(secret, p, exp)
+	if err := VerifyPixel(secret, p, strconv.FormatInt(exp, 10), sig, now); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, c := range []struct {
+		path, exp, sig string
+		now            time.Time
+		err            error
+	}{
+		{p, "", "", now, errMissingSignature},
+		{p, "", "zz", now, errBadSignature},
+		{p + "/campaign:y", strconv.FormatInt(exp, 10), sig, now, errBadSignature},
+		{p, strconv.FormatInt(exp+1, 10), sig, now, errBadSignature},
+		{p, "", sig, now, errBadSignature},
+		{p, "x", sig, now, errBadSignature},
+		{p, strconv.FormatInt(exp, 10), SignPixel([]byte("other"), p, exp), now, errBadSignature},
+		{p, strconv.FormatInt(exp, 10), sig, now.Add(time.Hour), errExpiredSignature},
+	} {
+		if err := VerifyPixel(secret, c.path, c.exp, c.sig, c.now); err != c.err {
+			t.Fatalf("%+v: expected %v got %v", c, c.err, err)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package blackrock_io
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	io "io"
+)
+
+var errTooManyFrames = errors.New("too many frames in the batch")
+
+// longer lines fail the whole batch, the array is limited only by the body
+const maxFrameBytes = 1024 * 1024
+
+// DecodeFrames decodes a json array of JsonFrames or one JsonFrame per line,
+// cb is called for every frame in order with the error of that frame only,
+// so one bad line does not fail the rest. max limits the frames, 0 is no
+// limit.
+func DecodeFrames(body io.Reader, max int, cb func(frame *JsonFrame, err error) error) error {
+	r := bufio.NewReaderSize(body, 64*1024)
+	first, err := firstByte(r)
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	n := 0
+	next := func(frame *JsonFrame, err error) error {
+		n++
+		if max > 0 && n > max {
+			return errTooManyFrames
+		}
+		return cb(frame, err)
+	}
+
+	if first == '[' {
+		return decodeArray(r, next)
+	}
+	return decodeLines(r, next)
+}
+
+func firstByte(r *bufio.Reader) (byte, error) {
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		switch b {
+		case ' ', '\t', '\r', '\n':
+			continue
+		}
+		return b, r.UnreadByte()
+	}
+}
+
+func decodeArray(r io.Reader, cb func(*JsonFrame, error) error) error {
+	dec := json.NewDecoder(r)
+	_, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	for dec.More() {
+		frame := &JsonFrame{}
+		err := dec.Decode(frame)
+		if _, ok := err.(*json.UnmarshalTypeError); !ok && err != nil {
+			// broken json, nothing after it can be trusted
+			return err
+		}
+		if err != nil {
+			frame = nil
+		}
+		err = cb(frame, err)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = dec.Token()
+	return err
+}
+
+func decodeLines(r io.Reader, cb func(*JsonFrame, error) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxFrameBytes)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		frame := &JsonFrame{}
+		err := json.Unmarshal(line, frame)
+		if err != nil {
+			frame = nil
+		}
+		err = cb(frame, err)
+		if err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
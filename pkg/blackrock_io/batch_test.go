@@ -0,0 +1,61 @@
+package blackrock_io
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+)
+
+func decodeAll(t *testing.T, body string, max int) ([]string, []error, error) {
+	t.Helper()
+	types := []string{}
+	errs := []error{}
+	err := DecodeFrames(strings.NewReader(body), max, func(frame *JsonFrame, err error) error {
+		if frame != nil {
+			types = append(types, frame.EventType)
+		} else {
+			types = append(types, "")
+		}
+		errs = append(errs, err)
+		return nil
+	})
+	return types, errs, err
+}
+
+func TestDecodeFrames(t *testing.T) {
+	types, errs, err := decodeAll(t, ` [{"event_type":"a"}, {"event_type":"b"}, {"event_type":1}, {"event_type":"c"}]`, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(types, ",") != "a,b,,c" || errs[0] != nil || errs[2] == nil || errs[3] != nil {
+		t.Fatalf("unexpected array %v %v", types, errs)
+	}
+
+	types, errs, err = decodeAll(t, "{\"event_type\":\"a\"}\n\n{broken\r\n{\"event_type\":\"b\"}", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(types, ",") != "a,,b" || errs[0] != nil || errs[1] == nil || errs[2] != nil {
+		t.Fatalf("unexpected lines %v %v", types, errs)
+	}
+
+	types, _, err = decodeAll(t, " \n ", 0)
+	if err != nil || len(types) != 0 {
+		t.Fatalf("unexpected empty %v %v", types, err)
+	}
+
+	_, _, err = decodeAll(t, `[{"event_type":"a"}, {"event_`, 0)
+	if err == nil {
+		t.Fatal("expected error for broken array")
+	}
+
+	_, _, err = decodeAll(t, "{}\n{\"event_type\":\""+strings.Repeat("a", maxFrameBytes)+"\"}\n{}", 0)
+	if err != bufio.ErrTooLong {
+		t.Fatalf("expected too long, got %v", err)
+	}
+
+	_, _, err = decodeAll(t, "{}\n{}\n{}", 2)
+	if err != errTooManyFrames {
+		t.Fatalf("expected too many frames, got %v", err)
+	}
+}
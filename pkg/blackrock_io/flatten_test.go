@@ -2,7 +2,11 @@ package blackrock_io
 
 import (
 	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path"
 	"sort"
+	"strings"
 	"testing"
 
 	"github.com/rekki/blackrock/pkg/depths"
@@ -89,3 +93,119 @@ func TestFlatten(t *testing.T) {
 	}
 
 }
+
+func TestFlattenRules(t *testing.T) {
+	body := `{
+		"event_type": "click",
+		"foreign_type": "user",
+		"foreign_id": "a",
+		"search": {
+			"Page": "HOME",
+			"shop_ref": {"x1": true},
+			"tags": ["a", "b"],
+			"debug": {"trace": "123"},
+			"deep": {"a": {"b": {"c": 1}}},
+			"long": "abcdefghij"
+		}
+	}`
+
+	// default rules, the event type has no rules of its own
+	e, err := DecodeAndFlatten(strings.NewReader(body), &FlattenConfig{Default: DefaultFlattenRules})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := makeKV("Page", "HOME", "shop_ref.x1", "true", "tags.0", "a", "tags.1", "b", "debug.trace", "123", "deep.a.b.c", "1", "long", "abcdefghij")
+	if !Equals(expected, e.Metadata.Search) {
+		t.Fatalf("unexpected default %v", e.Metadata.Search)
+	}
+
+	config := &FlattenConfig{
+		Default: DefaultFlattenRules,
+		EventTypes: map[string]FlattenRules{
+			"click": {
+				ExpandSuffixes: []string{"_ref"},
+				MaxDepth:       2,
+				Arrays:         "repeat",
+				MaxValueLength: 5,
+				Lowercase:      true,
+				Skip:           []string{"debug"},
+			},
+		},
+	}
+	e, err = DecodeAndFlatten(strings.NewReader(body), config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = makeKV("page", "home", "shop_ref", "x1", "tags", "a", "tags", "b", "deep.a", `{"b":`, "long", "abcde")
+	if !Equals(expected, e.Metadata.Search) {
+		t.Fatalf("unexpected click %v", e.Metadata.Search)
+	}
+
+	config.EventTypes["click"] = FlattenRules{Arrays: "skip", MaxKeyLength: 3}
+	e, err = DecodeAndFlatten(strings.NewReader(body), config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, kv := range e.Metadata.Search {
+		if strings.HasPrefix(kv.Key, "tag") || len(kv.Key) > 3 {
+			t.Fatalf("unexpected kv %v", kv)
+		}
+	}
+
+	dir, err := ioutil.TempDir("", "rules")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	fn := path.Join(dir, "rules.json")
+	err = ioutil.WriteFile(fn, []byte(`{"event_types":{"click":{"arrays":"zip"}}}`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = LoadFlattenConfig(fn)
+	if err != errUnknownArrays {
+		t.Fatalf("expected unknown arrays got %v", err)
+	}
+}
+
+func TestFlattenTyped(t *testing.T) {
+	body := `{
+		"event_type": "order",
+		"foreign_type": "user",
+		"foreign_id": "a",
+		"search": {"amount": 1.75},
+		"count": {"items": 3, "kind": "12"},
+		"properties": {"price": {"net": 1.75, "tax": 0.125}, "note": "x"}
+	}`
+	e, err := DecodeAndFlatten(strings.NewReader(body), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := e.Metadata
+
+	if !Equals(makeKV("amount", "1.75"), m.Search) {
+		t.Fatalf("unexpected search %v", m.Search)
+	}
+	if !Equals(makeKV("items", "3", "kind", "12"), m.Count) {
+		t.Fatalf("unexpected count %v", m.Count)
+	}
+
+	sort.Slice(m.Numbers, func(i, j int) bool {
+		return m.Numbers[i].Key < m.Numbers[j].Key
+	})
+	expected := []KF{{Key: "items", Value: 3}, {Key: "price.net", Value: 1.75}, {Key: "price.tax", Value: 0.125}}
+	if len(m.Numbers) != len(expected) {
+		t.Fatalf("unexpected numbers %v", m.Numbers)
+	}
+	for i, kf := range expected {
+		if m.Numbers[i] != kf {
+			t.Fatalf("unexpected numbers %v", m.Numbers)
+		}
+	}
+
+	for v, s := range map[interface{}]string{1.75: "1.75", float32(0.1): "0.1", 3.0: "3", int64(-7): "-7"} {
+		if ToString(v) != s {
+			t.Fatalf("expected %s got %s", s, ToString(v))
+		}
+	}
+}
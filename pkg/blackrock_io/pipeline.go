@@ -0,0 +1,72 @@
+package blackrock_io
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/oschwald/geoip2-golang"
+	"google.golang.org/grpc/metadata"
+)
+
+// Pipeline is what every entry point does with an envelope before it is
+// enqueued, so orgrim and the producer accept and enrich the same things
+type Pipeline struct {
+	geoip   *geoip2.Reader
+	schemas *Schemas
+}
+
+// NewPipeline returns a pipeline, geoip and schemas can be nil
+func NewPipeline(geoip *geoip2.Reader, schemas *Schemas) *Pipeline {
+	return &Pipeline{geoip: geoip, schemas: schemas}
+}
+
+// Process fills created_at_ns, decorates the envelope with the ip, geoip and
+// user agent of the request if there is one, and validates it against the
+// schema of its event_type
+func (p *Pipeline) Process(envelope *Envelope, r *http.Request) error {
+	if envelope.Metadata == nil {
+		return errMissingMetadata
+	}
+
+	if envelope.Metadata.CreatedAtNs == 0 {
+		envelope.Metadata.CreatedAtNs = time.Now().UnixNano()
+	}
+
+	if r != nil {
+		err := Decorate(p.geoip, r, envelope)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := ValidateEnvelope(envelope)
+	if err != nil {
+		return err
+	}
+	return p.schemas.Check(envelope)
+}
+
+// RequestFromContext rebuilds the http request of a grpc call that came
+// through the grpc gateway, which always sets x-forwarded-host, plain grpc
+// calls (e.g. from orgrim which already decorated the envelope) return nil.
+// The ip is the last one of x-forwarded-for, the one the gateway appended,
+// the ones before it come from the client.
+func RequestFromContext(ctx context.Context) *http.Request {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok || len(md.Get("x-forwarded-host")) == 0 {
+		return nil
+	}
+
+	r := &http.Request{Header: http.Header{}}
+	for _, v := range md.Get("grpcgateway-user-agent") {
+		r.Header.Add("User-Agent", v)
+	}
+	if forwarded := md.Get("x-forwarded-for"); len(forwarded) > 0 {
+		ips := strings.Split(forwarded[len(forwarded)-1], ",")
+		r.RemoteAddr = net.JoinHostPort(strings.TrimSpace(ips[len(ips)-1]), "0")
+	}
+	return r
+}
@@ -0,0 +1,65 @@
+package blackrock_io
+
+import (
+	"context"
+	"testing"
+
+	"github.com/tomasen/realip"
+	"google.golang.org/grpc/metadata"
+)
+
+func hasKey(kvs []KV, key string) bool {
+	for _, kv := range kvs {
+		if kv.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+func TestPipeline(t *testing.T) {
+	p := NewPipeline(nil, nil)
+
+	err := p.Process(&Envelope{}, nil)
+	if err != errMissingMetadata {
+		t.Fatalf("expected missing metadata got %v", err)
+	}
+
+	e := &Envelope{Metadata: &Metadata{EventType: "click", ForeignType: "user"}}
+	if p.Process(e, nil) == nil {
+		t.Fatalf("expected missing foreign_id")
+	}
+
+	e = &Envelope{Metadata: &Metadata{EventType: "click", ForeignType: "user", ForeignId: "a"}}
+	err = p.Process(e, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Metadata.CreatedAtNs == 0 || len(e.Metadata.Search) != 0 {
+		t.Fatalf("unexpected envelope %+v", e.Metadata)
+	}
+
+	// through the grpc gateway it is decorated like in orgrim
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("grpcgateway-user-agent", "curl/7.64.1", "x-forwarded-host", "example.com", "x-forwarded-for", "8.8.8.8, 1.2.3.4"))
+	r := RequestFromContext(ctx)
+	if r == nil || r.Header.Get("User-Agent") != "curl/7.64.1" {
+		t.Fatalf("expected request")
+	}
+	if ip := realip.FromRequest(r); ip != "1.2.3.4" {
+		t.Fatalf("expected the ip the gateway saw, got %s", ip)
+	}
+	err = p.Process(e, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !hasKey(e.Metadata.Search, "ua_browser_name") || !hasKey(e.Metadata.Search, "ip") {
+		t.Fatalf("expected decoration got %v", e.Metadata.Search)
+	}
+
+	if RequestFromContext(metadata.NewIncomingContext(context.Background(), metadata.Pairs("blackrock-sync", "true"))) != nil {
+		t.Fatalf("plain grpc calls are not decorated")
+	}
+	if RequestFromContext(metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-forwarded-for", "8.8.8.8"))) != nil {
+		t.Fatalf("plain grpc calls with x-forwarded-for are not decorated")
+	}
+}
@@ -0,0 +1,189 @@
+package blackrock_io
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+var errUnknownArrays = errors.New("arrays must be index, repeat or skip")
+
+// FlattenRules says how the json of a frame becomes KVs, the zero value
+// limits nothing and expands nothing
+type FlattenRules struct {
+	// a path element with one of these suffixes and a true leaf is expanded,
+	// a.restaurant_id.xyz: true becomes a.restaurant_id: xyz
+	ExpandSuffixes []string `json:"expand_suffixes"`
+
+	// deeper objects are kept as json in one value, 0 is no limit
+	MaxDepth int `json:"max_depth"`
+
+	// index: a.0, a.1, repeat: a for every element, skip: drop arrays
+	Arrays string `json:"arrays"`
+
+	// longer keys and values are cut, 0 is no limit
+	MaxKeyLength   int `json:"max_key_length"`
+	MaxValueLength int `json:"max_value_length"`
+
+	// lowercase keys and values
+	Lowercase bool `json:"lowercase"`
+
+	// dotted paths of the subtrees to drop, e.g. debug or user.address
+	Skip []string `json:"skip"`
+}
+
+// DefaultFlattenRules is the convention orgrim always had
+var DefaultFlattenRules = FlattenRules{ExpandSuffixes: []string{"_id", "_ids", "_code"}, Arrays: "index"}
+
+// FlattenConfig has the rules for every event type, event types that are
+// not listed use Default
+type FlattenConfig struct {
+	Default    FlattenRules            `json:"default"`
+	EventTypes map[string]FlattenRules `json:"event_types"`
+}
+
+// LoadFlattenConfig reads the json config, for example
+// {"default":{"expand_suffixes":["_id"]},"event_types":{"click":{"lowercase":true}}}
+func LoadFlattenConfig(filename string) (*FlattenConfig, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &FlattenConfig{}
+	err = json.Unmarshal(data, c)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.Default.validate()
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range c.EventTypes {
+		err = r.validate()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// For returns the rules of the event type, a nil config has the default
+// rules
+func (c *FlattenConfig) For(eventType string) *FlattenRules {
+	if c == nil {
+		return &DefaultFlattenRules
+	}
+	if r, ok := c.EventTypes[eventType]; ok {
+		return &r
+	}
+	return &c.Default
+}
+
+func (r *FlattenRules) validate() error {
+	switch r.Arrays {
+	case "", "index", "repeat", "skip":
+		return nil
+	}
+	return errUnknownArrays
+}
+
+func (r *FlattenRules) skip(path string) bool {
+	for _, s := range r.Skip {
+		if s == path {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *FlattenRules) expandable(p string) bool {
+	for _, s := range r.ExpandSuffixes {
+		if strings.HasSuffix(p, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// flatten appends the leaves of v to out, the numeric ones are also added to
+// numbers when it is not nil
+func (r *FlattenRules) flatten(out []KV, numbers *[]KF, v interface{}, path string, depth int) []KV {
+	if path != "" && r.skip(path) {
+		return out
+	}
+
+	join := func(k string) string {
+		if r.Lowercase {
+			k = strings.ToLower(k)
+		}
+		if path == "" {
+			return k
+		}
+		return path + "." + k
+	}
+
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if r.MaxDepth > 0 && depth >= r.MaxDepth {
+			return append(out, r.leafJSON(path, v))
+		}
+
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			out = r.flatten(out, numbers, v[k], join(k), depth+1)
+		}
+	case []interface{}:
+		switch r.Arrays {
+		case "skip":
+			return out
+		case "repeat":
+			for _, e := range v {
+				out = r.flatten(out, numbers, e, path, depth)
+			}
+		default:
+			if r.MaxDepth > 0 && depth >= r.MaxDepth {
+				return append(out, r.leafJSON(path, v))
+			}
+			for i, e := range v {
+				out = r.flatten(out, numbers, e, join(strconv.Itoa(i)), depth+1)
+			}
+		}
+	default:
+		if f, ok := ToFloat(v); ok && numbers != nil {
+			*numbers = append(*numbers, KF{Key: cut(path, r.MaxKeyLength), Value: f})
+		}
+		value := ToString(v)
+		if r.Lowercase {
+			value = strings.ToLower(value)
+		}
+		out = append(out, KV{Key: path, Value: value})
+	}
+	return out
+}
+
+func (r *FlattenRules) leafJSON(path string, v interface{}) KV {
+	data, _ := json.Marshal(v)
+	return KV{Key: path, Value: string(data)}
+}
+
+// cut at a rune boundary
+func cut(s string, max int) string {
+	if max <= 0 || len(s) <= max {
+		return s
+	}
+	s = s[:max]
+	for len(s) > 0 && !utf8.ValidString(s) {
+		s = s[:len(s)-1]
+	}
+	return s
+}
@@ -0,0 +1,210 @@
+package blackrock_io
+
+import (
+	"bytes"
+	"errors"
+	"expvar"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"sync"
+
+	"github.com/gogo/protobuf/jsonpb"
+	. "github.com/rekki/blackrock/pkg/logger"
+)
+
+var errMissingSchemaEventType = errors.New("schema needs event_type")
+
+var schemaRejections = expvar.NewMap("schema_rejections")
+
+// Schemas are the event_type schemas of one entry point, it is passed to the
+// Pipeline, and changed with SaySetSchemas
+type Schemas struct {
+	current     *SchemaRegistry
+	byEventType map[string]*EventSchema
+	filename    string
+	sync.RWMutex
+}
+
+// NewSchemas returns no schemas, nothing is checked
+func NewSchemas() *Schemas {
+	return &Schemas{current: &SchemaRegistry{}, byEventType: map[string]*EventSchema{}}
+}
+
+// LoadSchemas reads the schemas from a json file, Set then also writes them
+// there, a missing file means no schemas
+func LoadSchemas(filename string) (*Schemas, error) {
+	r := &SchemaRegistry{}
+	data, err := ioutil.ReadFile(filename)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if err == nil {
+		err = jsonpb.Unmarshal(bytes.NewReader(data), r)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	schemas := NewSchemas()
+	err = schemas.set(r, false)
+	if err != nil {
+		return nil, err
+	}
+	schemas.filename = filename
+	return schemas, nil
+}
+
+// Set replaces all the schemas
+func (schemas *Schemas) Set(r *SchemaRegistry) error {
+	return schemas.set(r, true)
+}
+
+func (schemas *Schemas) set(r *SchemaRegistry, save bool) error {
+	byEventType := map[string]*EventSchema{}
+	for _, s := range r.Schemas {
+		if s.EventType == "" {
+			return errMissingSchemaEventType
+		}
+		if _, ok := byEventType[s.EventType]; ok {
+			return fmt.Errorf("duplicate schema for event_type %s", s.EventType)
+		}
+		for k, t := range s.ValueTypes {
+			if t != "string" && t != "int" && t != "float" && t != "bool" {
+				return fmt.Errorf("unknown type %s for key %s, expected string, int, float or bool", t, k)
+			}
+		}
+		byEventType[s.EventType] = s
+	}
+
+	schemas.Lock()
+	defer schemas.Unlock()
+
+	if save && schemas.filename != "" {
+		m := jsonpb.Marshaler{OrigName: true, Indent: "  "}
+		data, err := m.MarshalToString(r)
+		if err != nil {
+			return err
+		}
+		tmp := schemas.filename + ".tmp"
+		err = ioutil.WriteFile(tmp, []byte(data), 0600)
+		if err != nil {
+			return err
+		}
+		err = os.Rename(tmp, schemas.filename)
+		if err != nil {
+			return err
+		}
+	}
+
+	schemas.current = r
+	schemas.byEventType = byEventType
+	return nil
+}
+
+// Registry returns the current schemas
+func (schemas *Schemas) Registry() *SchemaRegistry {
+	schemas.RLock()
+	defer schemas.RUnlock()
+	return schemas.current
+}
+
+// Check checks the envelope against the schema of its event_type, nil
+// schemas check nothing
+func (schemas *Schemas) Check(envelope *Envelope) error {
+	if schemas == nil || envelope.Metadata == nil {
+		return nil
+	}
+
+	schemas.RLock()
+	s, ok := schemas.byEventType[envelope.Metadata.EventType]
+	dryRun := schemas.current.DryRun
+	schemas.RUnlock()
+	if !ok {
+		return nil
+	}
+
+	err := checkSchema(s, envelope.Metadata)
+	if err == nil {
+		return nil
+	}
+
+	schemaRejections.Add(s.EventType, 1)
+	if dryRun {
+		Log.Infof("schema violation (dry run), foreign_id: %s, err: %s", envelope.Metadata.ForeignId, err.Error())
+		return nil
+	}
+	return err
+}
+
+// checkSchema ignores the keys added by the decoration, the consumer checks
+// envelopes that were already decorated
+func checkSchema(s *EventSchema, m *Metadata) error {
+	search := make([]KV, 0, len(m.Search))
+	for _, kv := range m.Search {
+		if !isDecoration(kv.Key) {
+			search = append(search, kv)
+		}
+	}
+
+	if len(s.ForeignTypes) > 0 {
+		allowed := false
+		for _, t := range s.ForeignTypes {
+			if t == m.ForeignType {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return fmt.Errorf("event_type %s does not allow foreign_type %s", s.EventType, m.ForeignType)
+		}
+	}
+
+	for _, k := range s.RequiredKeys {
+		found := false
+		for _, kv := range search {
+			if kv.Key == k {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("event_type %s requires search key %s", s.EventType, k)
+		}
+	}
+
+	if s.MaxSearch > 0 && len(search) > int(s.MaxSearch) {
+		return fmt.Errorf("event_type %s allows %d search keys, got %d", s.EventType, s.MaxSearch, len(search))
+	}
+	if s.MaxCount > 0 && len(m.Count) > int(s.MaxCount) {
+		return fmt.Errorf("event_type %s allows %d count keys, got %d", s.EventType, s.MaxCount, len(m.Count))
+	}
+	if s.MaxProperties > 0 && len(m.Properties) > int(s.MaxProperties) {
+		return fmt.Errorf("event_type %s allows %d properties, got %d", s.EventType, s.MaxProperties, len(m.Properties))
+	}
+
+	for _, kvs := range [][]KV{search, m.Count, m.Properties} {
+		for _, kv := range kvs {
+			t, ok := s.ValueTypes[kv.Key]
+			if !ok || isType(t, kv.Value) {
+				continue
+			}
+			return fmt.Errorf("event_type %s expects %s for key %s, got %q", s.EventType, t, kv.Key, kv.Value)
+		}
+	}
+	return nil
+}
+
+func isType(t string, v string) bool {
+	var err error
+	switch t {
+	case "int":
+		_, err = strconv.ParseInt(v, 10, 64)
+	case "float":
+		_, err = strconv.ParseFloat(v, 64)
+	case "bool":
+		_, err = strconv.ParseBool(v)
+	}
+	return err == nil
+}
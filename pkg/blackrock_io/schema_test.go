@@ -0,0 +1,144 @@
+package blackrock_io
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/rekki/blackrock/pkg/logger"
+)
+
+func TestSchema(t *testing.T) {
+	logger.LogInit(3)
+
+	dir, err := ioutil.TempDir("", "schema")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	fn := path.Join(dir, "schemas.json")
+
+	// missing file is no schemas
+	schemas, err := LoadSchemas(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := NewPipeline(nil, schemas)
+
+	err = schemas.Set(&SchemaRegistry{Schemas: []*EventSchema{{EventType: "click", ValueTypes: map[string]string{"a": "decimal"}}}})
+	if err == nil {
+		t.Fatalf("expected unknown type")
+	}
+
+	err = schemas.Set(&SchemaRegistry{Schemas: []*EventSchema{{
+		EventType:    "click",
+		RequiredKeys: []string{"page"},
+		ValueTypes:   map[string]string{"price": "float", "qty": "int"},
+		ForeignTypes: []string{"user"},
+		MaxSearch:    3,
+	}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	valid := func() *Envelope {
+		return &Envelope{Metadata: &Metadata{
+			EventType:   "click",
+			ForeignType: "user",
+			ForeignId:   "a",
+			Search:      []KV{{Key: "page", Value: "home"}, {Key: "qty", Value: "2"}},
+			Count:       []KV{{Key: "price", Value: "1.5"}},
+		}}
+	}
+
+	err = p.Process(valid(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// other event types are not checked
+	e := valid()
+	e.Metadata.EventType = "view"
+	e.Metadata.Search = nil
+	if p.Process(e, nil) != nil {
+		t.Fatalf("expected no schema for view")
+	}
+
+	invalid := []func(*Metadata){
+		func(m *Metadata) { m.ForeignType = "restaurant" },
+		func(m *Metadata) { m.Search = m.Search[1:] },
+		func(m *Metadata) { m.Search[1].Value = "two" },
+		func(m *Metadata) { m.Count[0].Value = "a lot" },
+		func(m *Metadata) { m.Search = append(m.Search, KV{Key: "b"}, KV{Key: "c"}) },
+	}
+	for i, f := range invalid {
+		e := valid()
+		f(e.Metadata)
+		if p.Process(e, nil) == nil {
+			t.Fatalf("expected %d to be rejected", i)
+		}
+	}
+	if schemaRejections.Get("click").String() != "5" {
+		t.Fatalf("expected 5 rejections got %s", schemaRejections.Get("click"))
+	}
+
+	// only the pipeline checks the schemas
+	e = valid()
+	e.Metadata.ForeignType = "restaurant"
+	if ValidateEnvelope(e) != nil || NewPipeline(nil, nil).Process(e, nil) != nil {
+		t.Fatalf("expected no schema check")
+	}
+
+	// saved to the file, and loaded back
+	schemas, err = LoadSchemas(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p = NewPipeline(nil, schemas)
+	r := schemas.Registry()
+	if len(r.Schemas) != 1 || r.Schemas[0].ValueTypes["qty"] != "int" {
+		t.Fatalf("unexpected schemas %v", r)
+	}
+
+	// in dry run the violations are only counted
+	r.DryRun = true
+	err = schemas.Set(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e = valid()
+	e.Metadata.ForeignType = "restaurant"
+	if p.Process(e, nil) != nil {
+		t.Fatalf("expected dry run to accept")
+	}
+
+	// the keys added by the decoration are not checked
+	err = schemas.Set(&SchemaRegistry{Schemas: []*EventSchema{{
+		EventType:  "pixel",
+		ValueTypes: map[string]string{"ua_is_bot": "int"},
+		MaxSearch:  1,
+	}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, err := http.NewRequest("GET", "/png", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.RemoteAddr = "1.2.3.4:5"
+	req.Header.Set("User-Agent", "Mozilla/5.0 (X11; Linux x86_64)")
+	e = &Envelope{Metadata: &Metadata{EventType: "pixel", ForeignType: "user", ForeignId: "a", Search: []KV{{Key: "page", Value: "home"}, {Key: "ip", Value: "1"}}}}
+	err = p.Process(e, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(e.Metadata.Search) < 10 {
+		t.Fatalf("expected decoration got %v", e.Metadata.Search)
+	}
+	e.Metadata.Search = append(e.Metadata.Search, KV{Key: "section", Value: "top"})
+	if p.Process(e, nil) == nil {
+		t.Fatalf("expected max_search to count the other keys")
+	}
+}
@@ -30,7 +30,7 @@ var _ = math.Inf
 // is compatible with the proto package it is being compiled against.
 // A compilation error at this line likely means your copy of the
 // proto package needs to be updated.
-const _ = proto.GoGoProtoPackageIsVersion2 // please upgrade the proto package
+const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
 
 type KV struct {
 	Key   string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
@@ -146,6 +146,9 @@ type Metadata struct {
 	ForeignType string            `protobuf:"bytes,10,opt,name=foreign_type,json=foreignType,proto3" json:"foreign_type,omitempty"`
 	Track       map[string]uint32 `protobuf:"bytes,11,rep,name=track,proto3" json:"track,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
 	Id          uint64            `protobuf:"fixed64,12,opt,name=id,proto3" json:"id,omitempty"`
+	// the numeric count and properties values, they are also in count
+	// and properties as strings
+	Numbers []KF `protobuf:"bytes,13,rep,name=numbers,proto3" json:"numbers"`
 }
 
 func (m *Metadata) Reset()         { *m = Metadata{} }
@@ -244,6 +247,13 @@ func (m *Metadata) GetId() uint64 {
 	return 0
 }
 
+func (m *Metadata) GetNumbers() []KF {
+	if m != nil {
+		return m.Numbers
+	}
+	return nil
+}
+
 type SearchableMetadata struct {
 	Search      []KV              `protobuf:"bytes,1,rep,name=search,proto3" json:"search"`
 	EventType   string            `protobuf:"bytes,7,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
@@ -325,6 +335,7 @@ type BasicMetadata struct {
 	EventType   string `protobuf:"bytes,7,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
 	ForeignId   string `protobuf:"bytes,9,opt,name=foreign_id,json=foreignId,proto3" json:"foreign_id,omitempty"`
 	ForeignType string `protobuf:"bytes,10,opt,name=foreign_type,json=foreignType,proto3" json:"foreign_type,omitempty"`
+	Id          uint64 `protobuf:"fixed64,12,opt,name=id,proto3" json:"id,omitempty"`
 }
 
 func (m *BasicMetadata) Reset()         { *m = BasicMetadata{} }
@@ -388,6 +399,13 @@ func (m *BasicMetadata) GetForeignType() string {
 	return ""
 }
 
+func (m *BasicMetadata) GetId() uint64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
 type CountableMetadata struct {
 	Search      []KV   `protobuf:"bytes,1,rep,name=search,proto3" json:"search"`
 	Count       []KV   `protobuf:"bytes,2,rep,name=count,proto3" json:"count"`
@@ -395,6 +413,7 @@ type CountableMetadata struct {
 	EventType   string `protobuf:"bytes,7,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
 	ForeignId   string `protobuf:"bytes,9,opt,name=foreign_id,json=foreignId,proto3" json:"foreign_id,omitempty"`
 	ForeignType string `protobuf:"bytes,10,opt,name=foreign_type,json=foreignType,proto3" json:"foreign_type,omitempty"`
+	Numbers     []KF   `protobuf:"bytes,13,rep,name=numbers,proto3" json:"numbers"`
 }
 
 func (m *CountableMetadata) Reset()         { *m = CountableMetadata{} }
@@ -472,6 +491,13 @@ func (m *CountableMetadata) GetForeignType() string {
 	return ""
 }
 
+func (m *CountableMetadata) GetNumbers() []KF {
+	if m != nil {
+		return m.Numbers
+	}
+	return nil
+}
+
 type Hit struct {
 	Id       uint64    `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
 	Score    float32   `protobuf:"fixed32,2,opt,name=score,proto3" json:"score,omitempty"`
@@ -537,6 +563,7 @@ type SearchQueryRequest struct {
 	ToSecond   uint32              `protobuf:"varint,2,opt,name=to_second,json=toSecond,proto3" json:"to_second,omitempty"`
 	Query      *go_query_dsl.Query `protobuf:"bytes,3,opt,name=query,proto3" json:"query,omitempty"`
 	Limit      int32               `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	Explain    bool                `protobuf:"varint,5,opt,name=explain,proto3" json:"explain,omitempty"`
 }
 
 func (m *SearchQueryRequest) Reset()         { *m = SearchQueryRequest{} }
@@ -600,6 +627,157 @@ func (m *SearchQueryRequest) GetLimit() int32 {
 	return 0
 }
 
+func (m *SearchQueryRequest) GetExplain() bool {
+	if m != nil {
+		return m.Explain
+	}
+	return false
+}
+
+type SegmentPlan struct {
+	SegmentId  string `protobuf:"bytes,1,opt,name=segment_id,json=segmentId,proto3" json:"segment_id,omitempty"`
+	Plan       string `protobuf:"bytes,2,opt,name=plan,proto3" json:"plan,omitempty"`
+	Estimated  uint64 `protobuf:"varint,3,opt,name=estimated,proto3" json:"estimated,omitempty"`
+	Skipped    bool   `protobuf:"varint,4,opt,name=skipped,proto3" json:"skipped,omitempty"`
+	SkipReason string `protobuf:"bytes,5,opt,name=skip_reason,json=skipReason,proto3" json:"skip_reason,omitempty"`
+	Matched    uint64 `protobuf:"varint,6,opt,name=matched,proto3" json:"matched,omitempty"`
+	TookNs     int64  `protobuf:"varint,7,opt,name=took_ns,json=tookNs,proto3" json:"took_ns,omitempty"`
+}
+
+func (m *SegmentPlan) Reset()         { *m = SegmentPlan{} }
+func (m *SegmentPlan) String() string { return proto.CompactTextString(m) }
+func (*SegmentPlan) ProtoMessage()    {}
+func (*SegmentPlan) Descriptor() ([]byte, []int) {
+	return fileDescriptor_423806180556987f, []int{8}
+}
+func (m *SegmentPlan) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *SegmentPlan) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_SegmentPlan.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *SegmentPlan) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SegmentPlan.Merge(m, src)
+}
+func (m *SegmentPlan) XXX_Size() int {
+	return m.Size()
+}
+func (m *SegmentPlan) XXX_DiscardUnknown() {
+	xxx_messageInfo_SegmentPlan.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SegmentPlan proto.InternalMessageInfo
+
+func (m *SegmentPlan) GetSegmentId() string {
+	if m != nil {
+		return m.SegmentId
+	}
+	return ""
+}
+
+func (m *SegmentPlan) GetPlan() string {
+	if m != nil {
+		return m.Plan
+	}
+	return ""
+}
+
+func (m *SegmentPlan) GetEstimated() uint64 {
+	if m != nil {
+		return m.Estimated
+	}
+	return 0
+}
+
+func (m *SegmentPlan) GetSkipped() bool {
+	if m != nil {
+		return m.Skipped
+	}
+	return false
+}
+
+func (m *SegmentPlan) GetSkipReason() string {
+	if m != nil {
+		return m.SkipReason
+	}
+	return ""
+}
+
+func (m *SegmentPlan) GetMatched() uint64 {
+	if m != nil {
+		return m.Matched
+	}
+	return 0
+}
+
+func (m *SegmentPlan) GetTookNs() int64 {
+	if m != nil {
+		return m.TookNs
+	}
+	return 0
+}
+
+type QueryExplain struct {
+	Segments []*SegmentPlan `protobuf:"bytes,1,rep,name=segments,proto3" json:"segments,omitempty"`
+	TookNs   int64          `protobuf:"varint,2,opt,name=took_ns,json=tookNs,proto3" json:"took_ns,omitempty"`
+}
+
+func (m *QueryExplain) Reset()         { *m = QueryExplain{} }
+func (m *QueryExplain) String() string { return proto.CompactTextString(m) }
+func (*QueryExplain) ProtoMessage()    {}
+func (*QueryExplain) Descriptor() ([]byte, []int) {
+	return fileDescriptor_423806180556987f, []int{9}
+}
+func (m *QueryExplain) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *QueryExplain) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_QueryExplain.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *QueryExplain) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryExplain.Merge(m, src)
+}
+func (m *QueryExplain) XXX_Size() int {
+	return m.Size()
+}
+func (m *QueryExplain) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryExplain.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryExplain proto.InternalMessageInfo
+
+func (m *QueryExplain) GetSegments() []*SegmentPlan {
+	if m != nil {
+		return m.Segments
+	}
+	return nil
+}
+
+func (m *QueryExplain) GetTookNs() int64 {
+	if m != nil {
+		return m.TookNs
+	}
+	return 0
+}
+
 type CountPerKV struct {
 	Count map[string]uint32 `protobuf:"bytes,1,rep,name=count,proto3" json:"count,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
 	Total uint32            `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
@@ -610,7 +788,7 @@ func (m *CountPerKV) Reset()         { *m = CountPerKV{} }
 func (m *CountPerKV) String() string { return proto.CompactTextString(m) }
 func (*CountPerKV) ProtoMessage()    {}
 func (*CountPerKV) Descriptor() ([]byte, []int) {
-	return fileDescriptor_423806180556987f, []int{8}
+	return fileDescriptor_423806180556987f, []int{10}
 }
 func (m *CountPerKV) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -671,7 +849,7 @@ func (m *PointPerEventType) Reset()         { *m = PointPerEventType{} }
 func (m *PointPerEventType) String() string { return proto.CompactTextString(m) }
 func (*PointPerEventType) ProtoMessage()    {}
 func (*PointPerEventType) Descriptor() ([]byte, []int) {
-	return fileDescriptor_423806180556987f, []int{9}
+	return fileDescriptor_423806180556987f, []int{11}
 }
 func (m *PointPerEventType) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -736,7 +914,7 @@ func (m *ChartBucketPerTime) Reset()         { *m = ChartBucketPerTime{} }
 func (m *ChartBucketPerTime) String() string { return proto.CompactTextString(m) }
 func (*ChartBucketPerTime) ProtoMessage()    {}
 func (*ChartBucketPerTime) Descriptor() ([]byte, []int) {
-	return fileDescriptor_423806180556987f, []int{10}
+	return fileDescriptor_423806180556987f, []int{12}
 }
 func (m *ChartBucketPerTime) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -783,7 +961,7 @@ func (m *Chart) Reset()         { *m = Chart{} }
 func (m *Chart) String() string { return proto.CompactTextString(m) }
 func (*Chart) ProtoMessage()    {}
 func (*Chart) Descriptor() ([]byte, []int) {
-	return fileDescriptor_423806180556987f, []int{11}
+	return fileDescriptor_423806180556987f, []int{13}
 }
 func (m *Chart) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -851,7 +1029,7 @@ func (m *AggregateRequest) Reset()         { *m = AggregateRequest{} }
 func (m *AggregateRequest) String() string { return proto.CompactTextString(m) }
 func (*AggregateRequest) ProtoMessage()    {}
 func (*AggregateRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_423806180556987f, []int{12}
+	return fileDescriptor_423806180556987f, []int{14}
 }
 func (m *AggregateRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -908,22 +1086,152 @@ func (m *AggregateRequest) GetTimeBucketSec() uint32 {
 	return 0
 }
 
+type NumberStats struct {
+	Key   string  `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Count uint32  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	Sum   float64 `protobuf:"fixed64,3,opt,name=sum,proto3" json:"sum,omitempty"`
+	Min   float64 `protobuf:"fixed64,4,opt,name=min,proto3" json:"min,omitempty"`
+	Max   float64 `protobuf:"fixed64,5,opt,name=max,proto3" json:"max,omitempty"`
+}
+
+func (m *NumberStats) Reset()         { *m = NumberStats{} }
+func (m *NumberStats) String() string { return proto.CompactTextString(m) }
+func (*NumberStats) ProtoMessage()    {}
+func (*NumberStats) Descriptor() ([]byte, []int) {
+	return fileDescriptor_423806180556987f, []int{15}
+}
+func (m *NumberStats) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *NumberStats) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_NumberStats.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *NumberStats) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NumberStats.Merge(m, src)
+}
+func (m *NumberStats) XXX_Size() int {
+	return m.Size()
+}
+func (m *NumberStats) XXX_DiscardUnknown() {
+	xxx_messageInfo_NumberStats.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NumberStats proto.InternalMessageInfo
+
+func (m *NumberStats) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *NumberStats) GetCount() uint32 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+func (m *NumberStats) GetSum() float64 {
+	if m != nil {
+		return m.Sum
+	}
+	return 0
+}
+
+func (m *NumberStats) GetMin() float64 {
+	if m != nil {
+		return m.Min
+	}
+	return 0
+}
+
+func (m *NumberStats) GetMax() float64 {
+	if m != nil {
+		return m.Max
+	}
+	return 0
+}
+
+type ShardFailure struct {
+	Shard string `protobuf:"bytes,1,opt,name=shard,proto3" json:"shard,omitempty"`
+	Error string `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (m *ShardFailure) Reset()         { *m = ShardFailure{} }
+func (m *ShardFailure) String() string { return proto.CompactTextString(m) }
+func (*ShardFailure) ProtoMessage()    {}
+func (*ShardFailure) Descriptor() ([]byte, []int) {
+	return fileDescriptor_423806180556987f, []int{16}
+}
+func (m *ShardFailure) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ShardFailure) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ShardFailure.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ShardFailure) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ShardFailure.Merge(m, src)
+}
+func (m *ShardFailure) XXX_Size() int {
+	return m.Size()
+}
+func (m *ShardFailure) XXX_DiscardUnknown() {
+	xxx_messageInfo_ShardFailure.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ShardFailure proto.InternalMessageInfo
+
+func (m *ShardFailure) GetShard() string {
+	if m != nil {
+		return m.Shard
+	}
+	return ""
+}
+
+func (m *ShardFailure) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
 type Aggregate struct {
-	Search    map[string]*CountPerKV `protobuf:"bytes,1,rep,name=search,proto3" json:"search,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	Count     map[string]*CountPerKV `protobuf:"bytes,2,rep,name=count,proto3" json:"count,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	ForeignId map[string]*CountPerKV `protobuf:"bytes,3,rep,name=foreign_id,json=foreignId,proto3" json:"foreign_id,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	EventType map[string]*CountPerKV `protobuf:"bytes,4,rep,name=event_type,json=eventType,proto3" json:"event_type,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	Possible  map[string]uint32      `protobuf:"bytes,5,rep,name=possible,proto3" json:"possible,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
-	Total     uint32                 `protobuf:"varint,6,opt,name=total,proto3" json:"total,omitempty"`
-	Sample    []*Hit                 `protobuf:"bytes,7,rep,name=sample,proto3" json:"sample,omitempty"`
-	Chart     *Chart                 `protobuf:"bytes,8,opt,name=chart,proto3" json:"chart,omitempty"`
+	Search       map[string]*CountPerKV  `protobuf:"bytes,1,rep,name=search,proto3" json:"search,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Count        map[string]*CountPerKV  `protobuf:"bytes,2,rep,name=count,proto3" json:"count,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	ForeignId    map[string]*CountPerKV  `protobuf:"bytes,3,rep,name=foreign_id,json=foreignId,proto3" json:"foreign_id,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	EventType    map[string]*CountPerKV  `protobuf:"bytes,4,rep,name=event_type,json=eventType,proto3" json:"event_type,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Possible     map[string]uint32       `protobuf:"bytes,5,rep,name=possible,proto3" json:"possible,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	Total        uint32                  `protobuf:"varint,6,opt,name=total,proto3" json:"total,omitempty"`
+	Sample       []*Hit                  `protobuf:"bytes,7,rep,name=sample,proto3" json:"sample,omitempty"`
+	Chart        *Chart                  `protobuf:"bytes,8,opt,name=chart,proto3" json:"chart,omitempty"`
+	FailedShards []*ShardFailure         `protobuf:"bytes,9,rep,name=failed_shards,json=failedShards,proto3" json:"failed_shards,omitempty"`
+	Numbers      map[string]*NumberStats `protobuf:"bytes,10,rep,name=numbers,proto3" json:"numbers,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 }
 
 func (m *Aggregate) Reset()         { *m = Aggregate{} }
 func (m *Aggregate) String() string { return proto.CompactTextString(m) }
 func (*Aggregate) ProtoMessage()    {}
 func (*Aggregate) Descriptor() ([]byte, []int) {
-	return fileDescriptor_423806180556987f, []int{13}
+	return fileDescriptor_423806180556987f, []int{17}
 }
 func (m *Aggregate) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1008,16 +1316,32 @@ func (m *Aggregate) GetChart() *Chart {
 	return nil
 }
 
+func (m *Aggregate) GetFailedShards() []*ShardFailure {
+	if m != nil {
+		return m.FailedShards
+	}
+	return nil
+}
+
+func (m *Aggregate) GetNumbers() map[string]*NumberStats {
+	if m != nil {
+		return m.Numbers
+	}
+	return nil
+}
+
 type SearchQueryResponse struct {
-	Hits  []*Hit `protobuf:"bytes,1,rep,name=hits,proto3" json:"hits,omitempty"`
-	Total uint64 `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	Hits         []*Hit          `protobuf:"bytes,1,rep,name=hits,proto3" json:"hits,omitempty"`
+	Total        uint64          `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	Explain      *QueryExplain   `protobuf:"bytes,3,opt,name=explain,proto3" json:"explain,omitempty"`
+	FailedShards []*ShardFailure `protobuf:"bytes,4,rep,name=failed_shards,json=failedShards,proto3" json:"failed_shards,omitempty"`
 }
 
 func (m *SearchQueryResponse) Reset()         { *m = SearchQueryResponse{} }
 func (m *SearchQueryResponse) String() string { return proto.CompactTextString(m) }
 func (*SearchQueryResponse) ProtoMessage()    {}
 func (*SearchQueryResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_423806180556987f, []int{14}
+	return fileDescriptor_423806180556987f, []int{18}
 }
 func (m *SearchQueryResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1060,6 +1384,20 @@ func (m *SearchQueryResponse) GetTotal() uint64 {
 	return 0
 }
 
+func (m *SearchQueryResponse) GetExplain() *QueryExplain {
+	if m != nil {
+		return m.Explain
+	}
+	return nil
+}
+
+func (m *SearchQueryResponse) GetFailedShards() []*ShardFailure {
+	if m != nil {
+		return m.FailedShards
+	}
+	return nil
+}
+
 type Envelope struct {
 	Metadata *Metadata `protobuf:"bytes,1,opt,name=metadata,proto3" json:"metadata,omitempty"`
 	Payload  []byte    `protobuf:"bytes,2,opt,name=payload,proto3" json:"payload,omitempty"`
@@ -1069,7 +1407,7 @@ func (m *Envelope) Reset()         { *m = Envelope{} }
 func (m *Envelope) String() string { return proto.CompactTextString(m) }
 func (*Envelope) ProtoMessage()    {}
 func (*Envelope) Descriptor() ([]byte, []int) {
-	return fileDescriptor_423806180556987f, []int{15}
+	return fileDescriptor_423806180556987f, []int{19}
 }
 func (m *Envelope) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1113,14 +1451,16 @@ func (m *Envelope) GetPayload() []byte {
 }
 
 type Success struct {
-	Success bool `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Success    bool         `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Receipts   []*Receipt   `protobuf:"bytes,2,rep,name=receipts,proto3" json:"receipts,omitempty"`
+	Rejections []*Rejection `protobuf:"bytes,3,rep,name=rejections,proto3" json:"rejections,omitempty"`
 }
 
 func (m *Success) Reset()         { *m = Success{} }
 func (m *Success) String() string { return proto.CompactTextString(m) }
 func (*Success) ProtoMessage()    {}
 func (*Success) Descriptor() ([]byte, []int) {
-	return fileDescriptor_423806180556987f, []int{16}
+	return fileDescriptor_423806180556987f, []int{20}
 }
 func (m *Success) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1156,21 +1496,41 @@ func (m *Success) GetSuccess() bool {
 	return false
 }
 
-type HealthRequest struct {
+func (m *Success) GetReceipts() []*Receipt {
+	if m != nil {
+		return m.Receipts
+	}
+	return nil
 }
 
-func (m *HealthRequest) Reset()         { *m = HealthRequest{} }
-func (m *HealthRequest) String() string { return proto.CompactTextString(m) }
-func (*HealthRequest) ProtoMessage()    {}
-func (*HealthRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_423806180556987f, []int{17}
+func (m *Success) GetRejections() []*Rejection {
+	if m != nil {
+		return m.Rejections
+	}
+	return nil
 }
-func (m *HealthRequest) XXX_Unmarshal(b []byte) error {
+
+// where a pushed envelope ended up, id is what the consumer sets as
+// metadata.id, index is the position of the envelope in the push
+type Receipt struct {
+	Partition int32  `protobuf:"varint,1,opt,name=partition,proto3" json:"partition,omitempty"`
+	Offset    int64  `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	Id        uint64 `protobuf:"fixed64,3,opt,name=id,proto3" json:"id,omitempty"`
+	Index     int32  `protobuf:"varint,4,opt,name=index,proto3" json:"index,omitempty"`
+}
+
+func (m *Receipt) Reset()         { *m = Receipt{} }
+func (m *Receipt) String() string { return proto.CompactTextString(m) }
+func (*Receipt) ProtoMessage()    {}
+func (*Receipt) Descriptor() ([]byte, []int) {
+	return fileDescriptor_423806180556987f, []int{21}
+}
+func (m *Receipt) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *HealthRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *Receipt) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_HealthRequest.Marshal(b, m, deterministic)
+		return xxx_messageInfo_Receipt.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -1180,2182 +1540,13315 @@ func (m *HealthRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error
 		return b[:n], nil
 	}
 }
-func (m *HealthRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_HealthRequest.Merge(m, src)
+func (m *Receipt) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Receipt.Merge(m, src)
 }
-func (m *HealthRequest) XXX_Size() int {
+func (m *Receipt) XXX_Size() int {
 	return m.Size()
 }
-func (m *HealthRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_HealthRequest.DiscardUnknown(m)
+func (m *Receipt) XXX_DiscardUnknown() {
+	xxx_messageInfo_Receipt.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_HealthRequest proto.InternalMessageInfo
+var xxx_messageInfo_Receipt proto.InternalMessageInfo
 
-func init() {
-	proto.RegisterType((*KV)(nil), "blackrock.io.KV")
-	golang_proto.RegisterType((*KV)(nil), "blackrock.io.KV")
-	proto.RegisterType((*KF)(nil), "blackrock.io.KF")
-	golang_proto.RegisterType((*KF)(nil), "blackrock.io.KF")
-	proto.RegisterType((*Metadata)(nil), "blackrock.io.Metadata")
-	golang_proto.RegisterType((*Metadata)(nil), "blackrock.io.Metadata")
-	proto.RegisterMapType((map[string]uint32)(nil), "blackrock.io.Metadata.TrackEntry")
-	golang_proto.RegisterMapType((map[string]uint32)(nil), "blackrock.io.Metadata.TrackEntry")
-	proto.RegisterType((*SearchableMetadata)(nil), "blackrock.io.SearchableMetadata")
-	golang_proto.RegisterType((*SearchableMetadata)(nil), "blackrock.io.SearchableMetadata")
-	proto.RegisterMapType((map[string]uint32)(nil), "blackrock.io.SearchableMetadata.TrackEntry")
-	golang_proto.RegisterMapType((map[string]uint32)(nil), "blackrock.io.SearchableMetadata.TrackEntry")
-	proto.RegisterType((*BasicMetadata)(nil), "blackrock.io.BasicMetadata")
-	golang_proto.RegisterType((*BasicMetadata)(nil), "blackrock.io.BasicMetadata")
-	proto.RegisterType((*CountableMetadata)(nil), "blackrock.io.CountableMetadata")
-	golang_proto.RegisterType((*CountableMetadata)(nil), "blackrock.io.CountableMetadata")
-	proto.RegisterType((*Hit)(nil), "blackrock.io.Hit")
-	golang_proto.RegisterType((*Hit)(nil), "blackrock.io.Hit")
-	proto.RegisterType((*SearchQueryRequest)(nil), "blackrock.io.SearchQueryRequest")
-	golang_proto.RegisterType((*SearchQueryRequest)(nil), "blackrock.io.SearchQueryRequest")
-	proto.RegisterType((*CountPerKV)(nil), "blackrock.io.CountPerKV")
-	golang_proto.RegisterType((*CountPerKV)(nil), "blackrock.io.CountPerKV")
-	proto.RegisterMapType((map[string]uint32)(nil), "blackrock.io.CountPerKV.CountEntry")
-	golang_proto.RegisterMapType((map[string]uint32)(nil), "blackrock.io.CountPerKV.CountEntry")
-	proto.RegisterType((*PointPerEventType)(nil), "blackrock.io.PointPerEventType")
-	golang_proto.RegisterType((*PointPerEventType)(nil), "blackrock.io.PointPerEventType")
-	proto.RegisterType((*ChartBucketPerTime)(nil), "blackrock.io.ChartBucketPerTime")
-	golang_proto.RegisterType((*ChartBucketPerTime)(nil), "blackrock.io.ChartBucketPerTime")
-	proto.RegisterMapType((map[string]*PointPerEventType)(nil), "blackrock.io.ChartBucketPerTime.PerTypeEntry")
-	golang_proto.RegisterMapType((map[string]*PointPerEventType)(nil), "blackrock.io.ChartBucketPerTime.PerTypeEntry")
-	proto.RegisterType((*Chart)(nil), "blackrock.io.Chart")
-	golang_proto.RegisterType((*Chart)(nil), "blackrock.io.Chart")
-	proto.RegisterMapType((map[uint32]*ChartBucketPerTime)(nil), "blackrock.io.Chart.BucketsEntry")
-	golang_proto.RegisterMapType((map[uint32]*ChartBucketPerTime)(nil), "blackrock.io.Chart.BucketsEntry")
-	proto.RegisterType((*AggregateRequest)(nil), "blackrock.io.AggregateRequest")
-	golang_proto.RegisterType((*AggregateRequest)(nil), "blackrock.io.AggregateRequest")
-	proto.RegisterMapType((map[string]bool)(nil), "blackrock.io.AggregateRequest.FieldsEntry")
-	golang_proto.RegisterMapType((map[string]bool)(nil), "blackrock.io.AggregateRequest.FieldsEntry")
-	proto.RegisterType((*Aggregate)(nil), "blackrock.io.Aggregate")
-	golang_proto.RegisterType((*Aggregate)(nil), "blackrock.io.Aggregate")
-	proto.RegisterMapType((map[string]*CountPerKV)(nil), "blackrock.io.Aggregate.CountEntry")
-	golang_proto.RegisterMapType((map[string]*CountPerKV)(nil), "blackrock.io.Aggregate.CountEntry")
-	proto.RegisterMapType((map[string]*CountPerKV)(nil), "blackrock.io.Aggregate.EventTypeEntry")
-	golang_proto.RegisterMapType((map[string]*CountPerKV)(nil), "blackrock.io.Aggregate.EventTypeEntry")
-	proto.RegisterMapType((map[string]*CountPerKV)(nil), "blackrock.io.Aggregate.ForeignIdEntry")
-	golang_proto.RegisterMapType((map[string]*CountPerKV)(nil), "blackrock.io.Aggregate.ForeignIdEntry")
-	proto.RegisterMapType((map[string]uint32)(nil), "blackrock.io.Aggregate.PossibleEntry")
-	golang_proto.RegisterMapType((map[string]uint32)(nil), "blackrock.io.Aggregate.PossibleEntry")
-	proto.RegisterMapType((map[string]*CountPerKV)(nil), "blackrock.io.Aggregate.SearchEntry")
-	golang_proto.RegisterMapType((map[string]*CountPerKV)(nil), "blackrock.io.Aggregate.SearchEntry")
-	proto.RegisterType((*SearchQueryResponse)(nil), "blackrock.io.SearchQueryResponse")
-	golang_proto.RegisterType((*SearchQueryResponse)(nil), "blackrock.io.SearchQueryResponse")
-	proto.RegisterType((*Envelope)(nil), "blackrock.io.Envelope")
-	golang_proto.RegisterType((*Envelope)(nil), "blackrock.io.Envelope")
-	proto.RegisterType((*Success)(nil), "blackrock.io.Success")
-	golang_proto.RegisterType((*Success)(nil), "blackrock.io.Success")
-	proto.RegisterType((*HealthRequest)(nil), "blackrock.io.HealthRequest")
-	golang_proto.RegisterType((*HealthRequest)(nil), "blackrock.io.HealthRequest")
+func (m *Receipt) GetPartition() int32 {
+	if m != nil {
+		return m.Partition
+	}
+	return 0
 }
 
-func init() { proto.RegisterFile("spec.proto", fileDescriptor_423806180556987f) }
-func init() { golang_proto.RegisterFile("spec.proto", fileDescriptor_423806180556987f) }
-
-var fileDescriptor_423806180556987f = []byte{
-	// 1409 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xcc, 0x58, 0xcd, 0x6f, 0x13, 0x47,
-	0x14, 0xcf, 0xda, 0xf1, 0xd7, 0xb3, 0x0d, 0x64, 0xa0, 0xb0, 0x18, 0xea, 0x38, 0x8b, 0x40, 0x21,
-	0x85, 0x75, 0x9b, 0x0a, 0x0a, 0xe1, 0x94, 0x20, 0x47, 0xa9, 0x68, 0xab, 0x74, 0x4d, 0xa3, 0x4a,
-	0x54, 0xb2, 0xc6, 0xeb, 0x89, 0xbd, 0xf2, 0x7a, 0x67, 0xb3, 0x3b, 0x1b, 0xc9, 0xd7, 0xb6, 0x7f,
-	0x00, 0x12, 0x3d, 0xf4, 0x5a, 0x6e, 0xbd, 0x71, 0xea, 0xa5, 0x97, 0x1e, 0x39, 0x22, 0x55, 0x95,
-	0x7a, 0xaa, 0x10, 0xe9, 0x1f, 0x52, 0xed, 0xcc, 0xac, 0xbd, 0x6b, 0xc7, 0x09, 0xd0, 0x20, 0x71,
-	0x62, 0xe7, 0xbd, 0xdf, 0xfb, 0x98, 0xdf, 0xfb, 0x18, 0x13, 0x00, 0xdf, 0x25, 0xa6, 0xee, 0x7a,
-	0x94, 0x51, 0x54, 0x6a, 0xdb, 0xd8, 0xec, 0x7b, 0xd4, 0xec, 0xeb, 0x16, 0xad, 0xdc, 0xec, 0x5a,
-	0xac, 0x17, 0xb4, 0x75, 0x93, 0x0e, 0xea, 0x5d, 0xda, 0xa5, 0x75, 0x0e, 0x6a, 0x07, 0xbb, 0xfc,
-	0xc4, 0x0f, 0xfc, 0x4b, 0x18, 0x57, 0x6e, 0xc5, 0xe0, 0x1e, 0xe9, 0xf7, 0xad, 0x7a, 0x97, 0xde,
-	0xdc, 0x0b, 0x88, 0x37, 0xac, 0x07, 0xcc, 0xb2, 0xeb, 0x5d, 0xda, 0xe2, 0xa7, 0x56, 0xc7, 0xb7,
-	0xeb, 0x1d, 0xdf, 0x96, 0x66, 0x97, 0xbb, 0x94, 0x76, 0x6d, 0x52, 0xc7, 0xae, 0x55, 0xc7, 0x8e,
-	0x43, 0x19, 0x66, 0x16, 0x75, 0x7c, 0xa1, 0xd5, 0x6e, 0x40, 0xea, 0xc1, 0x0e, 0x3a, 0x03, 0xe9,
-	0x3e, 0x19, 0xaa, 0x4a, 0x4d, 0x59, 0x2e, 0x18, 0xe1, 0x27, 0x3a, 0x07, 0x99, 0x7d, 0x6c, 0x07,
-	0x44, 0x4d, 0x71, 0x99, 0x38, 0x70, 0xf4, 0xe6, 0x71, 0x68, 0x25, 0x42, 0xff, 0x96, 0x86, 0xfc,
-	0x97, 0x84, 0xe1, 0x0e, 0x66, 0x18, 0xe9, 0x90, 0xf5, 0x09, 0xf6, 0xcc, 0x9e, 0xaa, 0xd4, 0xd2,
-	0xcb, 0xc5, 0xd5, 0x33, 0x7a, 0x9c, 0x0b, 0xfd, 0xc1, 0xce, 0xc6, 0xfc, 0xf3, 0x7f, 0x16, 0xe7,
-	0x0c, 0x89, 0x42, 0x37, 0x20, 0x63, 0xd2, 0xc0, 0x61, 0x6a, 0xea, 0x48, 0xb8, 0x00, 0xa1, 0xdb,
-	0x00, 0xae, 0x47, 0x5d, 0xe2, 0x31, 0x8b, 0xf8, 0x6a, 0xfa, 0x48, 0x93, 0x18, 0x12, 0x69, 0x50,
-	0x36, 0x3d, 0x82, 0x19, 0xe9, 0xb4, 0x30, 0x6b, 0x39, 0xbe, 0x9a, 0xa9, 0x29, 0xcb, 0x69, 0xa3,
-	0x28, 0x85, 0xeb, 0xec, 0x2b, 0x1f, 0x7d, 0x08, 0x40, 0xf6, 0x89, 0xc3, 0x5a, 0x6c, 0xe8, 0x12,
-	0x35, 0xc7, 0x6f, 0x5d, 0xe0, 0x92, 0x87, 0x43, 0x97, 0x84, 0xea, 0x5d, 0xea, 0x11, 0xab, 0xeb,
-	0xb4, 0xac, 0x8e, 0x5a, 0x10, 0x6a, 0x29, 0xf9, 0xbc, 0x83, 0x96, 0xa0, 0x14, 0xa9, 0xb9, 0x3d,
-	0x70, 0x40, 0x51, 0xca, 0xb8, 0x87, 0xcf, 0x20, 0xc3, 0x3c, 0x6c, 0xf6, 0xd5, 0x22, 0xcf, 0x7b,
-	0x29, 0x99, 0x77, 0xc4, 0xa0, 0xfe, 0x30, 0xc4, 0x34, 0x1c, 0xe6, 0x0d, 0x0d, 0x81, 0x47, 0xa7,
-	0x20, 0x65, 0x75, 0xd4, 0x52, 0x4d, 0x59, 0xce, 0x1a, 0x29, 0xab, 0x53, 0xb9, 0x03, 0x30, 0x06,
-	0x1d, 0x57, 0xa6, 0xb2, 0x2c, 0xd3, 0x5a, 0xea, 0x8e, 0xb2, 0x56, 0x7a, 0xf1, 0xcb, 0xe2, 0xdc,
-	0xe3, 0xa7, 0x8b, 0x73, 0x3f, 0x3f, 0x5d, 0x9c, 0xd3, 0x9e, 0xa5, 0x00, 0x35, 0x79, 0x19, 0x70,
-	0xdb, 0x26, 0x6f, 0x5d, 0xc2, 0x77, 0x4e, 0xdc, 0x7a, 0x92, 0xb8, 0x8f, 0x92, 0xf9, 0x4c, 0xdf,
-	0x60, 0x9a, 0xc2, 0x13, 0xa3, 0xec, 0xa9, 0x02, 0xe5, 0x0d, 0xec, 0x5b, 0xe6, 0x88, 0xad, 0xf7,
-	0xa1, 0xb5, 0x26, 0x92, 0xfc, 0x31, 0x05, 0x0b, 0xf7, 0xc3, 0x79, 0xf9, 0x5f, 0x65, 0x7d, 0xb3,
-	0xc9, 0x7c, 0x0f, 0x69, 0x68, 0x41, 0x7a, 0xcb, 0x62, 0x72, 0x7a, 0xc2, 0x5a, 0xcf, 0x87, 0xd3,
-	0x13, 0x96, 0xda, 0x37, 0xa9, 0x27, 0x4a, 0x9d, 0x32, 0xc4, 0x01, 0xad, 0x42, 0x7e, 0x20, 0x99,
-	0x52, 0xd3, 0x35, 0x65, 0xb9, 0xb8, 0x7a, 0xfe, 0xf0, 0xf9, 0x34, 0x46, 0x38, 0xed, 0x89, 0x12,
-	0xcd, 0xcf, 0xd7, 0xe1, 0x42, 0x36, 0xc8, 0x5e, 0x40, 0x7c, 0x86, 0x16, 0xa1, 0xb8, 0xeb, 0xd1,
-	0x41, 0xcb, 0x27, 0x26, 0x75, 0x44, 0xe4, 0xb2, 0x01, 0xa1, 0xa8, 0xc9, 0x25, 0xe8, 0x12, 0x14,
-	0x18, 0x8d, 0xd4, 0xa2, 0xe1, 0xf2, 0x8c, 0x4a, 0xe5, 0x75, 0xc8, 0xf0, 0xf5, 0x2e, 0xb3, 0x38,
-	0xab, 0x77, 0xa9, 0xce, 0x05, 0x7a, 0xb8, 0xeb, 0x45, 0x20, 0x81, 0x08, 0x6f, 0x62, 0x5b, 0x03,
-	0x8b, 0xa9, 0xf3, 0x35, 0x65, 0x39, 0x63, 0x88, 0x83, 0xf6, 0xab, 0x02, 0xc0, 0xab, 0xbf, 0x4d,
-	0xbc, 0x07, 0x3b, 0xe8, 0x6e, 0x54, 0x46, 0x51, 0xf5, 0x2b, 0xc9, 0x5b, 0x8d, 0x81, 0xe2, 0x53,
-	0x0e, 0x8d, 0xa8, 0xe9, 0x39, 0xc8, 0x30, 0xca, 0xb0, 0x1d, 0x0d, 0x05, 0x3f, 0x44, 0xc3, 0x93,
-	0x1e, 0x0d, 0x4f, 0x38, 0x5c, 0x63, 0xe3, 0x37, 0x19, 0x2e, 0xed, 0x07, 0x05, 0x16, 0xb6, 0xa9,
-	0xc5, 0x53, 0x68, 0x8c, 0x1a, 0xe1, 0xdc, 0x38, 0x65, 0x8e, 0x17, 0xd9, 0x2c, 0x41, 0x89, 0x7f,
-	0xb4, 0x02, 0xc7, 0xda, 0x1b, 0x39, 0x2b, 0x72, 0xd9, 0x37, 0x5c, 0x84, 0xce, 0x43, 0xb6, 0x1d,
-	0x98, 0x7d, 0xc2, 0x78, 0x76, 0x65, 0x43, 0x9e, 0x26, 0x1a, 0x6f, 0x7e, 0xa2, 0xf1, 0xb4, 0xdf,
-	0x15, 0x40, 0xf7, 0x7b, 0xd8, 0x63, 0x1b, 0x1c, 0xbe, 0x4d, 0xbc, 0x87, 0xd6, 0x80, 0xa0, 0x2d,
-	0xc8, 0xbb, 0xc4, 0x13, 0x36, 0x82, 0xbc, 0x9b, 0x13, 0xe4, 0x4d, 0xd9, 0xe8, 0xe1, 0xbf, 0x43,
-	0x97, 0x08, 0x1a, 0x73, 0xae, 0x38, 0x55, 0x1e, 0x41, 0x29, 0xae, 0x38, 0x84, 0xa2, 0x5b, 0x71,
-	0x8a, 0x8a, 0xab, 0x8b, 0xc9, 0x40, 0x53, 0x14, 0x25, 0x38, 0x4c, 0x41, 0x86, 0x67, 0x82, 0xd6,
-	0x20, 0x27, 0x2e, 0xec, 0xcb, 0x7c, 0x6b, 0x87, 0xe4, 0xab, 0x8b, 0x84, 0x7d, 0x99, 0xa2, 0x34,
-	0x08, 0x29, 0x62, 0xd6, 0x80, 0xb4, 0x7c, 0x86, 0x3d, 0x26, 0xb9, 0x2d, 0x84, 0x92, 0x66, 0x28,
-	0x40, 0x17, 0x21, 0xcf, 0xd5, 0xc4, 0xe9, 0x48, 0x6e, 0x73, 0xe1, 0xb9, 0xe1, 0x74, 0xd0, 0x35,
-	0x38, 0xcd, 0x55, 0xc2, 0x53, 0xd8, 0xd6, 0x9c, 0xe1, 0xb2, 0x51, 0x0e, 0xc5, 0x22, 0x5a, 0x93,
-	0x98, 0x95, 0xef, 0xa0, 0x14, 0x0f, 0x1d, 0x27, 0xa1, 0x2c, 0x48, 0xb8, 0x9d, 0x24, 0xa1, 0x76,
-	0x1c, 0xdb, 0x71, 0x16, 0x7e, 0x4a, 0xc1, 0x99, 0xf5, 0x6e, 0xd7, 0x23, 0x5d, 0xcc, 0x48, 0x34,
-	0x89, 0xb7, 0xa3, 0x59, 0x52, 0x0e, 0x73, 0x38, 0x3d, 0xba, 0xd1, 0x60, 0x6d, 0x40, 0x76, 0xd7,
-	0x22, 0x76, 0xc7, 0x97, 0xbb, 0x6f, 0x25, 0x69, 0x38, 0x19, 0x47, 0xdf, 0xe4, 0x60, 0xc1, 0xa8,
-	0xb4, 0x0c, 0xdb, 0xd5, 0xc7, 0x03, 0xd7, 0x26, 0x2d, 0x31, 0xa3, 0x69, 0x3e, 0xa3, 0x45, 0x21,
-	0xfb, 0x22, 0x14, 0xbd, 0x36, 0x73, 0x77, 0xa1, 0x18, 0x8b, 0x70, 0xdc, 0x80, 0xe5, 0xe3, 0xb4,
-	0xfc, 0x95, 0x85, 0xc2, 0x28, 0x5d, 0x74, 0x6f, 0xe2, 0x09, 0xb8, 0x32, 0xe3, 0x5e, 0x92, 0x1a,
-	0x79, 0x21, 0xf9, 0x1e, 0xdc, 0x49, 0xbe, 0x07, 0xda, 0x2c, 0xdb, 0xe9, 0x3d, 0xd2, 0x48, 0x2c,
-	0x76, 0xf1, 0xab, 0xed, 0xda, 0x2c, 0xf3, 0xcd, 0x68, 0xe1, 0x0b, 0x17, 0xb1, 0x07, 0xa0, 0x31,
-	0x31, 0xc5, 0x47, 0xba, 0x19, 0x8d, 0x8a, 0x74, 0x33, 0x7e, 0x66, 0xd6, 0x21, 0xef, 0x52, 0xdf,
-	0xb7, 0xda, 0x36, 0x51, 0x33, 0xdc, 0xc9, 0xd5, 0x59, 0x4e, 0xb6, 0x25, 0x4e, 0xf8, 0x18, 0x99,
-	0x8d, 0x17, 0x63, 0x36, 0xbe, 0x18, 0xaf, 0x43, 0x56, 0x54, 0x57, 0xcd, 0x71, 0xb7, 0x0b, 0x49,
-	0xb7, 0x5b, 0x16, 0x33, 0x24, 0x20, 0x5c, 0xf2, 0x66, 0xd8, 0xce, 0x6a, 0x5e, 0x2e, 0xf9, 0xe9,
-	0x4e, 0x37, 0x04, 0xa2, 0xd2, 0x84, 0x62, 0xac, 0x1a, 0x87, 0x14, 0x5f, 0x4f, 0x4e, 0x8d, 0x3a,
-	0x6b, 0xc1, 0xc7, 0xda, 0xa2, 0x62, 0x1c, 0xb3, 0xb1, 0xdf, 0xc6, 0xe7, 0x0e, 0x9c, 0x4a, 0xd6,
-	0xee, 0xe4, 0xfc, 0x26, 0x8b, 0x79, 0x42, 0x7e, 0xef, 0x41, 0x39, 0x51, 0xdf, 0x37, 0x7a, 0xb8,
-	0x0c, 0x38, 0x9b, 0x58, 0x1f, 0xbe, 0x4b, 0x1d, 0x9f, 0xa0, 0xab, 0x30, 0xdf, 0xb3, 0x46, 0xeb,
-	0xf7, 0x90, 0x06, 0xe0, 0xea, 0xe4, 0xc3, 0x3a, 0x2f, 0xfb, 0x47, 0xfb, 0x16, 0xf2, 0x0d, 0x67,
-	0x9f, 0xd8, 0xd4, 0x4d, 0xfe, 0x1c, 0x51, 0x5e, 0xef, 0xe7, 0x08, 0x52, 0x21, 0xe7, 0xe2, 0xa1,
-	0x4d, 0xb1, 0xf8, 0x51, 0x51, 0x32, 0xa2, 0xa3, 0x76, 0x05, 0x72, 0xcd, 0xc0, 0x34, 0x89, 0xef,
-	0x87, 0x20, 0x5f, 0x7c, 0x72, 0xbf, 0x79, 0x23, 0x3a, 0x6a, 0xa7, 0xa1, 0xbc, 0x45, 0xb0, 0xcd,
-	0x7a, 0x72, 0xab, 0xad, 0x3e, 0x53, 0x20, 0xd7, 0x70, 0xf6, 0x02, 0x12, 0x10, 0xd4, 0x84, 0x5c,
-	0x13, 0x0f, 0xb7, 0x03, 0xbf, 0x87, 0x26, 0x12, 0x89, 0x52, 0xae, 0x7c, 0x30, 0xb1, 0x5d, 0xa5,
-	0xdb, 0x0b, 0xdf, 0xff, 0xf9, 0xef, 0x93, 0xd4, 0x82, 0x56, 0xe2, 0xff, 0x33, 0xdd, 0xff, 0xa4,
-	0xee, 0x06, 0x7e, 0x6f, 0x4d, 0x59, 0x59, 0x56, 0xd0, 0x36, 0x14, 0x9a, 0x78, 0x28, 0x82, 0xa2,
-	0x4b, 0x13, 0x64, 0xc5, 0x53, 0x99, 0xe5, 0xfb, 0x34, 0xf7, 0x5d, 0x40, 0xb9, 0x7a, 0x8f, 0xc3,
-	0x57, 0x5f, 0xa6, 0x21, 0x2b, 0xea, 0xf2, 0x6e, 0x32, 0xee, 0xf3, 0x8c, 0x65, 0x84, 0x63, 0x9f,
-	0x93, 0xca, 0xd2, 0x11, 0x08, 0xd1, 0x31, 0xda, 0x45, 0x1e, 0xec, 0xac, 0x76, 0x2a, 0x0a, 0x26,
-	0xb6, 0xed, 0x9a, 0xb2, 0x82, 0x1e, 0x41, 0xbe, 0x89, 0x87, 0x9b, 0x84, 0xbd, 0x56, 0xac, 0xe9,
-	0x66, 0xd3, 0x54, 0xee, 0x1b, 0x69, 0xe5, 0xc8, 0xf7, 0x6e, 0xe8, 0x6b, 0x4d, 0x59, 0xf9, 0x58,
-	0x41, 0x04, 0x4a, 0x4d, 0x3c, 0x1c, 0x3f, 0x0d, 0xd5, 0xa3, 0x9f, 0xb8, 0xca, 0x85, 0x19, 0x7a,
-	0xed, 0x32, 0x0f, 0x72, 0x5e, 0x5b, 0x88, 0x82, 0xe0, 0x48, 0x15, 0xde, 0xe1, 0xc4, 0x4b, 0xbc,
-	0x71, 0xf9, 0xf9, 0xab, 0xaa, 0xf2, 0xe2, 0x55, 0x55, 0x79, 0xf9, 0xaa, 0xaa, 0x3c, 0x3e, 0xa8,
-	0xce, 0xfd, 0x71, 0x50, 0x55, 0x5e, 0x1c, 0x54, 0xe7, 0xfe, 0x3e, 0xa8, 0xce, 0xb5, 0xb3, 0xfc,
-	0xcf, 0x1d, 0x9f, 0xfe, 0x17, 0x00, 0x00, 0xff, 0xff, 0x37, 0x66, 0xca, 0xbe, 0x8e, 0x11, 0x00,
-	0x00,
+func (m *Receipt) GetOffset() int64 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
+func (m *Receipt) GetId() uint64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
 
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
+func (m *Receipt) GetIndex() int32 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
 
-// EnqueueClient is the client API for Enqueue service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type EnqueueClient interface {
-	SayPush(ctx context.Context, opts ...grpc.CallOption) (Enqueue_SayPushClient, error)
-	SayHealth(ctx context.Context, in *HealthRequest, opts ...grpc.CallOption) (*Success, error)
+// an envelope of the push that was not enqueued
+type Rejection struct {
+	Index  int32  `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Reason string `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
 }
 
-type enqueueClient struct {
-	cc *grpc.ClientConn
+func (m *Rejection) Reset()         { *m = Rejection{} }
+func (m *Rejection) String() string { return proto.CompactTextString(m) }
+func (*Rejection) ProtoMessage()    {}
+func (*Rejection) Descriptor() ([]byte, []int) {
+	return fileDescriptor_423806180556987f, []int{22}
+}
+func (m *Rejection) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Rejection) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Rejection.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *Rejection) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Rejection.Merge(m, src)
+}
+func (m *Rejection) XXX_Size() int {
+	return m.Size()
+}
+func (m *Rejection) XXX_DiscardUnknown() {
+	xxx_messageInfo_Rejection.DiscardUnknown(m)
 }
 
-func NewEnqueueClient(cc *grpc.ClientConn) EnqueueClient {
-	return &enqueueClient{cc}
+var xxx_messageInfo_Rejection proto.InternalMessageInfo
+
+func (m *Rejection) GetIndex() int32 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
 }
 
-func (c *enqueueClient) SayPush(ctx context.Context, opts ...grpc.CallOption) (Enqueue_SayPushClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_Enqueue_serviceDesc.Streams[0], "/blackrock.io.Enqueue/SayPush", opts...)
-	if err != nil {
-		return nil, err
+func (m *Rejection) GetReason() string {
+	if m != nil {
+		return m.Reason
 	}
-	x := &enqueueSayPushClient{stream}
-	return x, nil
+	return ""
 }
 
-type Enqueue_SayPushClient interface {
-	Send(*Envelope) error
-	CloseAndRecv() (*Success, error)
-	grpc.ClientStream
+type HealthRequest struct {
 }
 
-type enqueueSayPushClient struct {
-	grpc.ClientStream
+func (m *HealthRequest) Reset()         { *m = HealthRequest{} }
+func (m *HealthRequest) String() string { return proto.CompactTextString(m) }
+func (*HealthRequest) ProtoMessage()    {}
+func (*HealthRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_423806180556987f, []int{23}
+}
+func (m *HealthRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *HealthRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_HealthRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *HealthRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HealthRequest.Merge(m, src)
+}
+func (m *HealthRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *HealthRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_HealthRequest.DiscardUnknown(m)
 }
 
-func (x *enqueueSayPushClient) Send(m *Envelope) error {
-	return x.ClientStream.SendMsg(m)
+var xxx_messageInfo_HealthRequest proto.InternalMessageInfo
+
+type SnapshotFile struct {
+	Name      string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	SizeBytes int64  `protobuf:"varint,2,opt,name=size_bytes,json=sizeBytes,proto3" json:"size_bytes,omitempty"`
+	Sha256    string `protobuf:"bytes,3,opt,name=sha256,proto3" json:"sha256,omitempty"`
 }
 
-func (x *enqueueSayPushClient) CloseAndRecv() (*Success, error) {
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
+func (m *SnapshotFile) Reset()         { *m = SnapshotFile{} }
+func (m *SnapshotFile) String() string { return proto.CompactTextString(m) }
+func (*SnapshotFile) ProtoMessage()    {}
+func (*SnapshotFile) Descriptor() ([]byte, []int) {
+	return fileDescriptor_423806180556987f, []int{24}
+}
+func (m *SnapshotFile) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *SnapshotFile) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_SnapshotFile.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	m := new(Success)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
+}
+func (m *SnapshotFile) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SnapshotFile.Merge(m, src)
+}
+func (m *SnapshotFile) XXX_Size() int {
+	return m.Size()
+}
+func (m *SnapshotFile) XXX_DiscardUnknown() {
+	xxx_messageInfo_SnapshotFile.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SnapshotFile proto.InternalMessageInfo
+
+func (m *SnapshotFile) GetName() string {
+	if m != nil {
+		return m.Name
 	}
-	return m, nil
+	return ""
 }
 
-func (c *enqueueClient) SayHealth(ctx context.Context, in *HealthRequest, opts ...grpc.CallOption) (*Success, error) {
-	out := new(Success)
-	err := c.cc.Invoke(ctx, "/blackrock.io.Enqueue/SayHealth", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *SnapshotFile) GetSizeBytes() int64 {
+	if m != nil {
+		return m.SizeBytes
 	}
-	return out, nil
+	return 0
 }
 
-// EnqueueServer is the server API for Enqueue service.
-type EnqueueServer interface {
-	SayPush(Enqueue_SayPushServer) error
-	SayHealth(context.Context, *HealthRequest) (*Success, error)
+func (m *SnapshotFile) GetSha256() string {
+	if m != nil {
+		return m.Sha256
+	}
+	return ""
 }
 
-// UnimplementedEnqueueServer can be embedded to have forward compatible implementations.
-type UnimplementedEnqueueServer struct {
+type SnapshotSegment struct {
+	Id      string          `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	StartNs int64           `protobuf:"varint,2,opt,name=start_ns,json=startNs,proto3" json:"start_ns,omitempty"`
+	Files   []*SnapshotFile `protobuf:"bytes,3,rep,name=files,proto3" json:"files,omitempty"`
 }
 
-func (*UnimplementedEnqueueServer) SayPush(srv Enqueue_SayPushServer) error {
-	return status.Errorf(codes.Unimplemented, "method SayPush not implemented")
+func (m *SnapshotSegment) Reset()         { *m = SnapshotSegment{} }
+func (m *SnapshotSegment) String() string { return proto.CompactTextString(m) }
+func (*SnapshotSegment) ProtoMessage()    {}
+func (*SnapshotSegment) Descriptor() ([]byte, []int) {
+	return fileDescriptor_423806180556987f, []int{25}
 }
-func (*UnimplementedEnqueueServer) SayHealth(ctx context.Context, req *HealthRequest) (*Success, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method SayHealth not implemented")
+func (m *SnapshotSegment) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
 }
-
-func RegisterEnqueueServer(s *grpc.Server, srv EnqueueServer) {
-	s.RegisterService(&_Enqueue_serviceDesc, srv)
+func (m *SnapshotSegment) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_SnapshotSegment.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
 }
-
-func _Enqueue_SayPush_Handler(srv interface{}, stream grpc.ServerStream) error {
-	return srv.(EnqueueServer).SayPush(&enqueueSayPushServer{stream})
+func (m *SnapshotSegment) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SnapshotSegment.Merge(m, src)
 }
-
-type Enqueue_SayPushServer interface {
-	SendAndClose(*Success) error
-	Recv() (*Envelope, error)
-	grpc.ServerStream
+func (m *SnapshotSegment) XXX_Size() int {
+	return m.Size()
 }
-
-type enqueueSayPushServer struct {
-	grpc.ServerStream
+func (m *SnapshotSegment) XXX_DiscardUnknown() {
+	xxx_messageInfo_SnapshotSegment.DiscardUnknown(m)
 }
 
-func (x *enqueueSayPushServer) SendAndClose(m *Success) error {
-	return x.ServerStream.SendMsg(m)
-}
+var xxx_messageInfo_SnapshotSegment proto.InternalMessageInfo
 
-func (x *enqueueSayPushServer) Recv() (*Envelope, error) {
-	m := new(Envelope)
-	if err := x.ServerStream.RecvMsg(m); err != nil {
-		return nil, err
+func (m *SnapshotSegment) GetId() string {
+	if m != nil {
+		return m.Id
 	}
-	return m, nil
+	return ""
 }
 
-func _Enqueue_SayHealth_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(HealthRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(EnqueueServer).SayHealth(ctx, in)
+func (m *SnapshotSegment) GetStartNs() int64 {
+	if m != nil {
+		return m.StartNs
 	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/blackrock.io.Enqueue/SayHealth",
+	return 0
+}
+
+func (m *SnapshotSegment) GetFiles() []*SnapshotFile {
+	if m != nil {
+		return m.Files
 	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(EnqueueServer).SayHealth(ctx, req.(*HealthRequest))
-	}
-	return interceptor(ctx, in, info, handler)
-}
-
-var _Enqueue_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "blackrock.io.Enqueue",
-	HandlerType: (*EnqueueServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "SayHealth",
-			Handler:    _Enqueue_SayHealth_Handler,
-		},
-	},
-	Streams: []grpc.StreamDesc{
-		{
-			StreamName:    "SayPush",
-			Handler:       _Enqueue_SayPush_Handler,
-			ClientStreams: true,
-		},
-	},
-	Metadata: "spec.proto",
+	return nil
 }
 
-// SearchClient is the client API for Search service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type SearchClient interface {
-	SayPush(ctx context.Context, opts ...grpc.CallOption) (Search_SayPushClient, error)
-	SaySearch(ctx context.Context, in *SearchQueryRequest, opts ...grpc.CallOption) (*SearchQueryResponse, error)
-	SayFetch(ctx context.Context, in *SearchQueryRequest, opts ...grpc.CallOption) (Search_SayFetchClient, error)
-	SayAggregate(ctx context.Context, in *AggregateRequest, opts ...grpc.CallOption) (*Aggregate, error)
-	SayHealth(ctx context.Context, in *HealthRequest, opts ...grpc.CallOption) (*Success, error)
+type SnapshotManifest struct {
+	SegmentStep int64              `protobuf:"varint,1,opt,name=segment_step,json=segmentStep,proto3" json:"segment_step,omitempty"`
+	CreatedAtNs int64              `protobuf:"varint,2,opt,name=created_at_ns,json=createdAtNs,proto3" json:"created_at_ns,omitempty"`
+	Segments    []*SnapshotSegment `protobuf:"bytes,3,rep,name=segments,proto3" json:"segments,omitempty"`
 }
 
-type searchClient struct {
-	cc *grpc.ClientConn
+func (m *SnapshotManifest) Reset()         { *m = SnapshotManifest{} }
+func (m *SnapshotManifest) String() string { return proto.CompactTextString(m) }
+func (*SnapshotManifest) ProtoMessage()    {}
+func (*SnapshotManifest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_423806180556987f, []int{26}
 }
-
-func NewSearchClient(cc *grpc.ClientConn) SearchClient {
-	return &searchClient{cc}
+func (m *SnapshotManifest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
 }
-
-func (c *searchClient) SayPush(ctx context.Context, opts ...grpc.CallOption) (Search_SayPushClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_Search_serviceDesc.Streams[0], "/blackrock.io.Search/SayPush", opts...)
-	if err != nil {
-		return nil, err
+func (m *SnapshotManifest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_SnapshotManifest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	x := &searchSayPushClient{stream}
-	return x, nil
 }
-
-type Search_SayPushClient interface {
-	Send(*Envelope) error
-	CloseAndRecv() (*Success, error)
-	grpc.ClientStream
+func (m *SnapshotManifest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SnapshotManifest.Merge(m, src)
 }
-
-type searchSayPushClient struct {
-	grpc.ClientStream
+func (m *SnapshotManifest) XXX_Size() int {
+	return m.Size()
 }
-
-func (x *searchSayPushClient) Send(m *Envelope) error {
-	return x.ClientStream.SendMsg(m)
+func (m *SnapshotManifest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SnapshotManifest.DiscardUnknown(m)
 }
 
-func (x *searchSayPushClient) CloseAndRecv() (*Success, error) {
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
-	}
-	m := new(Success)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
+var xxx_messageInfo_SnapshotManifest proto.InternalMessageInfo
+
+func (m *SnapshotManifest) GetSegmentStep() int64 {
+	if m != nil {
+		return m.SegmentStep
 	}
-	return m, nil
+	return 0
 }
 
-func (c *searchClient) SaySearch(ctx context.Context, in *SearchQueryRequest, opts ...grpc.CallOption) (*SearchQueryResponse, error) {
-	out := new(SearchQueryResponse)
-	err := c.cc.Invoke(ctx, "/blackrock.io.Search/SaySearch", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *SnapshotManifest) GetCreatedAtNs() int64 {
+	if m != nil {
+		return m.CreatedAtNs
 	}
-	return out, nil
+	return 0
 }
 
-func (c *searchClient) SayFetch(ctx context.Context, in *SearchQueryRequest, opts ...grpc.CallOption) (Search_SayFetchClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_Search_serviceDesc.Streams[1], "/blackrock.io.Search/SayFetch", opts...)
-	if err != nil {
-		return nil, err
-	}
-	x := &searchSayFetchClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
-	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
+func (m *SnapshotManifest) GetSegments() []*SnapshotSegment {
+	if m != nil {
+		return m.Segments
 	}
-	return x, nil
+	return nil
 }
 
-type Search_SayFetchClient interface {
-	Recv() (*Hit, error)
-	grpc.ClientStream
+type BackupRequest struct {
+	Destination string `protobuf:"bytes,1,opt,name=destination,proto3" json:"destination,omitempty"`
+	FromSecond  uint32 `protobuf:"varint,2,opt,name=from_second,json=fromSecond,proto3" json:"from_second,omitempty"`
+	ToSecond    uint32 `protobuf:"varint,3,opt,name=to_second,json=toSecond,proto3" json:"to_second,omitempty"`
 }
 
-type searchSayFetchClient struct {
-	grpc.ClientStream
+func (m *BackupRequest) Reset()         { *m = BackupRequest{} }
+func (m *BackupRequest) String() string { return proto.CompactTextString(m) }
+func (*BackupRequest) ProtoMessage()    {}
+func (*BackupRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_423806180556987f, []int{27}
 }
-
-func (x *searchSayFetchClient) Recv() (*Hit, error) {
-	m := new(Hit)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
+func (m *BackupRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *BackupRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_BackupRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	return m, nil
+}
+func (m *BackupRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BackupRequest.Merge(m, src)
+}
+func (m *BackupRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *BackupRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BackupRequest.DiscardUnknown(m)
 }
 
-func (c *searchClient) SayAggregate(ctx context.Context, in *AggregateRequest, opts ...grpc.CallOption) (*Aggregate, error) {
-	out := new(Aggregate)
-	err := c.cc.Invoke(ctx, "/blackrock.io.Search/SayAggregate", in, out, opts...)
-	if err != nil {
-		return nil, err
+var xxx_messageInfo_BackupRequest proto.InternalMessageInfo
+
+func (m *BackupRequest) GetDestination() string {
+	if m != nil {
+		return m.Destination
 	}
-	return out, nil
+	return ""
 }
 
-func (c *searchClient) SayHealth(ctx context.Context, in *HealthRequest, opts ...grpc.CallOption) (*Success, error) {
-	out := new(Success)
-	err := c.cc.Invoke(ctx, "/blackrock.io.Search/SayHealth", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *BackupRequest) GetFromSecond() uint32 {
+	if m != nil {
+		return m.FromSecond
 	}
-	return out, nil
+	return 0
 }
 
-// SearchServer is the server API for Search service.
-type SearchServer interface {
-	SayPush(Search_SayPushServer) error
-	SaySearch(context.Context, *SearchQueryRequest) (*SearchQueryResponse, error)
-	SayFetch(*SearchQueryRequest, Search_SayFetchServer) error
-	SayAggregate(context.Context, *AggregateRequest) (*Aggregate, error)
-	SayHealth(context.Context, *HealthRequest) (*Success, error)
+func (m *BackupRequest) GetToSecond() uint32 {
+	if m != nil {
+		return m.ToSecond
+	}
+	return 0
 }
 
-// UnimplementedSearchServer can be embedded to have forward compatible implementations.
-type UnimplementedSearchServer struct {
+type RestoreRequest struct {
+	Source string `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
 }
 
-func (*UnimplementedSearchServer) SayPush(srv Search_SayPushServer) error {
-	return status.Errorf(codes.Unimplemented, "method SayPush not implemented")
+func (m *RestoreRequest) Reset()         { *m = RestoreRequest{} }
+func (m *RestoreRequest) String() string { return proto.CompactTextString(m) }
+func (*RestoreRequest) ProtoMessage()    {}
+func (*RestoreRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_423806180556987f, []int{28}
 }
-func (*UnimplementedSearchServer) SaySearch(ctx context.Context, req *SearchQueryRequest) (*SearchQueryResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method SaySearch not implemented")
+func (m *RestoreRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
 }
-func (*UnimplementedSearchServer) SayFetch(req *SearchQueryRequest, srv Search_SayFetchServer) error {
-	return status.Errorf(codes.Unimplemented, "method SayFetch not implemented")
+func (m *RestoreRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_RestoreRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
 }
-func (*UnimplementedSearchServer) SayAggregate(ctx context.Context, req *AggregateRequest) (*Aggregate, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method SayAggregate not implemented")
+func (m *RestoreRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RestoreRequest.Merge(m, src)
 }
-func (*UnimplementedSearchServer) SayHealth(ctx context.Context, req *HealthRequest) (*Success, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method SayHealth not implemented")
+func (m *RestoreRequest) XXX_Size() int {
+	return m.Size()
 }
-
-func RegisterSearchServer(s *grpc.Server, srv SearchServer) {
-	s.RegisterService(&_Search_serviceDesc, srv)
+func (m *RestoreRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RestoreRequest.DiscardUnknown(m)
 }
 
-func _Search_SayPush_Handler(srv interface{}, stream grpc.ServerStream) error {
-	return srv.(SearchServer).SayPush(&searchSayPushServer{stream})
-}
+var xxx_messageInfo_RestoreRequest proto.InternalMessageInfo
 
-type Search_SayPushServer interface {
-	SendAndClose(*Success) error
-	Recv() (*Envelope, error)
-	grpc.ServerStream
+func (m *RestoreRequest) GetSource() string {
+	if m != nil {
+		return m.Source
+	}
+	return ""
 }
 
-type searchSayPushServer struct {
-	grpc.ServerStream
+type ReindexRequest struct {
+	FromSecond uint32   `protobuf:"varint,1,opt,name=from_second,json=fromSecond,proto3" json:"from_second,omitempty"`
+	ToSecond   uint32   `protobuf:"varint,2,opt,name=to_second,json=toSecond,proto3" json:"to_second,omitempty"`
+	Whitelist  []string `protobuf:"bytes,3,rep,name=whitelist,proto3" json:"whitelist,omitempty"`
+	StatusOnly bool     `protobuf:"varint,4,opt,name=status_only,json=statusOnly,proto3" json:"status_only,omitempty"`
 }
 
-func (x *searchSayPushServer) SendAndClose(m *Success) error {
-	return x.ServerStream.SendMsg(m)
+func (m *ReindexRequest) Reset()         { *m = ReindexRequest{} }
+func (m *ReindexRequest) String() string { return proto.CompactTextString(m) }
+func (*ReindexRequest) ProtoMessage()    {}
+func (*ReindexRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_423806180556987f, []int{29}
 }
-
-func (x *searchSayPushServer) Recv() (*Envelope, error) {
-	m := new(Envelope)
-	if err := x.ServerStream.RecvMsg(m); err != nil {
-		return nil, err
+func (m *ReindexRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ReindexRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ReindexRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	return m, nil
+}
+func (m *ReindexRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReindexRequest.Merge(m, src)
+}
+func (m *ReindexRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *ReindexRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReindexRequest.DiscardUnknown(m)
 }
 
-func _Search_SaySearch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SearchQueryRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(SearchServer).SaySearch(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/blackrock.io.Search/SaySearch",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(SearchServer).SaySearch(ctx, req.(*SearchQueryRequest))
+var xxx_messageInfo_ReindexRequest proto.InternalMessageInfo
+
+func (m *ReindexRequest) GetFromSecond() uint32 {
+	if m != nil {
+		return m.FromSecond
 	}
-	return interceptor(ctx, in, info, handler)
+	return 0
 }
 
-func _Search_SayFetch_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(SearchQueryRequest)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
+func (m *ReindexRequest) GetToSecond() uint32 {
+	if m != nil {
+		return m.ToSecond
 	}
-	return srv.(SearchServer).SayFetch(m, &searchSayFetchServer{stream})
+	return 0
 }
 
-type Search_SayFetchServer interface {
-	Send(*Hit) error
-	grpc.ServerStream
+func (m *ReindexRequest) GetWhitelist() []string {
+	if m != nil {
+		return m.Whitelist
+	}
+	return nil
 }
 
-type searchSayFetchServer struct {
-	grpc.ServerStream
+func (m *ReindexRequest) GetStatusOnly() bool {
+	if m != nil {
+		return m.StatusOnly
+	}
+	return false
 }
 
-func (x *searchSayFetchServer) Send(m *Hit) error {
-	return x.ServerStream.SendMsg(m)
+type ReindexStatus struct {
+	Running       bool     `protobuf:"varint,1,opt,name=running,proto3" json:"running,omitempty"`
+	Whitelist     []string `protobuf:"bytes,2,rep,name=whitelist,proto3" json:"whitelist,omitempty"`
+	SegmentsTotal uint32   `protobuf:"varint,3,opt,name=segments_total,json=segmentsTotal,proto3" json:"segments_total,omitempty"`
+	SegmentsDone  uint32   `protobuf:"varint,4,opt,name=segments_done,json=segmentsDone,proto3" json:"segments_done,omitempty"`
+	Error         string   `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	StartedAtNs   int64    `protobuf:"varint,6,opt,name=started_at_ns,json=startedAtNs,proto3" json:"started_at_ns,omitempty"`
+	FinishedAtNs  int64    `protobuf:"varint,7,opt,name=finished_at_ns,json=finishedAtNs,proto3" json:"finished_at_ns,omitempty"`
 }
 
-func _Search_SayAggregate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(AggregateRequest)
-	if err := dec(in); err != nil {
-		return nil, err
+func (m *ReindexStatus) Reset()         { *m = ReindexStatus{} }
+func (m *ReindexStatus) String() string { return proto.CompactTextString(m) }
+func (*ReindexStatus) ProtoMessage()    {}
+func (*ReindexStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_423806180556987f, []int{30}
+}
+func (m *ReindexStatus) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ReindexStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ReindexStatus.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	if interceptor == nil {
-		return srv.(SearchServer).SayAggregate(ctx, in)
+}
+func (m *ReindexStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReindexStatus.Merge(m, src)
+}
+func (m *ReindexStatus) XXX_Size() int {
+	return m.Size()
+}
+func (m *ReindexStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReindexStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReindexStatus proto.InternalMessageInfo
+
+func (m *ReindexStatus) GetRunning() bool {
+	if m != nil {
+		return m.Running
 	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/blackrock.io.Search/SayAggregate",
+	return false
+}
+
+func (m *ReindexStatus) GetWhitelist() []string {
+	if m != nil {
+		return m.Whitelist
 	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(SearchServer).SayAggregate(ctx, req.(*AggregateRequest))
+	return nil
+}
+
+func (m *ReindexStatus) GetSegmentsTotal() uint32 {
+	if m != nil {
+		return m.SegmentsTotal
 	}
-	return interceptor(ctx, in, info, handler)
+	return 0
 }
 
-func _Search_SayHealth_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(HealthRequest)
-	if err := dec(in); err != nil {
-		return nil, err
+func (m *ReindexStatus) GetSegmentsDone() uint32 {
+	if m != nil {
+		return m.SegmentsDone
 	}
-	if interceptor == nil {
-		return srv.(SearchServer).SayHealth(ctx, in)
+	return 0
+}
+
+func (m *ReindexStatus) GetError() string {
+	if m != nil {
+		return m.Error
 	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/blackrock.io.Search/SayHealth",
+	return ""
+}
+
+func (m *ReindexStatus) GetStartedAtNs() int64 {
+	if m != nil {
+		return m.StartedAtNs
 	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(SearchServer).SayHealth(ctx, req.(*HealthRequest))
+	return 0
+}
+
+func (m *ReindexStatus) GetFinishedAtNs() int64 {
+	if m != nil {
+		return m.FinishedAtNs
 	}
-	return interceptor(ctx, in, info, handler)
+	return 0
 }
 
-var _Search_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "blackrock.io.Search",
-	HandlerType: (*SearchServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "SaySearch",
-			Handler:    _Search_SaySearch_Handler,
-		},
-		{
-			MethodName: "SayAggregate",
-			Handler:    _Search_SayAggregate_Handler,
-		},
-		{
-			MethodName: "SayHealth",
-			Handler:    _Search_SayHealth_Handler,
-		},
-	},
-	Streams: []grpc.StreamDesc{
-		{
-			StreamName:    "SayPush",
-			Handler:       _Search_SayPush_Handler,
-			ClientStreams: true,
-		},
-		{
-			StreamName:    "SayFetch",
-			Handler:       _Search_SayFetch_Handler,
-			ServerStreams: true,
-		},
-	},
-	Metadata: "spec.proto",
+type SegmentStats struct {
+	Id               string            `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Step             int64             `protobuf:"varint,2,opt,name=step,proto3" json:"step,omitempty"`
+	StartNs          int64             `protobuf:"varint,3,opt,name=start_ns,json=startNs,proto3" json:"start_ns,omitempty"`
+	Docs             uint64            `protobuf:"varint,4,opt,name=docs,proto3" json:"docs,omitempty"`
+	MinCreatedAtNs   int64             `protobuf:"varint,5,opt,name=min_created_at_ns,json=minCreatedAtNs,proto3" json:"min_created_at_ns,omitempty"`
+	MaxCreatedAtNs   int64             `protobuf:"varint,6,opt,name=max_created_at_ns,json=maxCreatedAtNs,proto3" json:"max_created_at_ns,omitempty"`
+	ForwardBytes     int64             `protobuf:"varint,7,opt,name=forward_bytes,json=forwardBytes,proto3" json:"forward_bytes,omitempty"`
+	InvertedBytes    int64             `protobuf:"varint,8,opt,name=inverted_bytes,json=invertedBytes,proto3" json:"inverted_bytes,omitempty"`
+	Terms            uint64            `protobuf:"varint,9,opt,name=terms,proto3" json:"terms,omitempty"`
+	Sealed           bool              `protobuf:"varint,10,opt,name=sealed,proto3" json:"sealed,omitempty"`
+	EventTypes       map[string]uint64 `protobuf:"bytes,11,rep,name=event_types,json=eventTypes,proto3" json:"event_types,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	PartitionOffsets map[int32]int64   `protobuf:"bytes,12,rep,name=partition_offsets,json=partitionOffsets,proto3" json:"partition_offsets,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+}
+
+func (m *SegmentStats) Reset()         { *m = SegmentStats{} }
+func (m *SegmentStats) String() string { return proto.CompactTextString(m) }
+func (*SegmentStats) ProtoMessage()    {}
+func (*SegmentStats) Descriptor() ([]byte, []int) {
+	return fileDescriptor_423806180556987f, []int{31}
+}
+func (m *SegmentStats) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *SegmentStats) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_SegmentStats.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *SegmentStats) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SegmentStats.Merge(m, src)
+}
+func (m *SegmentStats) XXX_Size() int {
+	return m.Size()
+}
+func (m *SegmentStats) XXX_DiscardUnknown() {
+	xxx_messageInfo_SegmentStats.DiscardUnknown(m)
 }
 
-func (m *KV) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+var xxx_messageInfo_SegmentStats proto.InternalMessageInfo
+
+func (m *SegmentStats) GetId() string {
+	if m != nil {
+		return m.Id
 	}
-	return dAtA[:n], nil
+	return ""
 }
 
-func (m *KV) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (m *SegmentStats) GetStep() int64 {
+	if m != nil {
+		return m.Step
+	}
+	return 0
 }
 
-func (m *KV) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.Value) > 0 {
-		i -= len(m.Value)
-		copy(dAtA[i:], m.Value)
-		i = encodeVarintSpec(dAtA, i, uint64(len(m.Value)))
-		i--
-		dAtA[i] = 0x12
+func (m *SegmentStats) GetStartNs() int64 {
+	if m != nil {
+		return m.StartNs
 	}
-	if len(m.Key) > 0 {
-		i -= len(m.Key)
-		copy(dAtA[i:], m.Key)
-		i = encodeVarintSpec(dAtA, i, uint64(len(m.Key)))
-		i--
-		dAtA[i] = 0xa
+	return 0
+}
+
+func (m *SegmentStats) GetDocs() uint64 {
+	if m != nil {
+		return m.Docs
 	}
-	return len(dAtA) - i, nil
+	return 0
 }
 
-func (m *KF) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+func (m *SegmentStats) GetMinCreatedAtNs() int64 {
+	if m != nil {
+		return m.MinCreatedAtNs
 	}
-	return dAtA[:n], nil
+	return 0
 }
 
-func (m *KF) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (m *SegmentStats) GetMaxCreatedAtNs() int64 {
+	if m != nil {
+		return m.MaxCreatedAtNs
+	}
+	return 0
 }
 
-func (m *KF) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.Value != 0 {
-		i -= 8
-		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.Value))))
-		i--
-		dAtA[i] = 0x11
+func (m *SegmentStats) GetForwardBytes() int64 {
+	if m != nil {
+		return m.ForwardBytes
 	}
-	if len(m.Key) > 0 {
-		i -= len(m.Key)
-		copy(dAtA[i:], m.Key)
-		i = encodeVarintSpec(dAtA, i, uint64(len(m.Key)))
-		i--
-		dAtA[i] = 0xa
+	return 0
+}
+
+func (m *SegmentStats) GetInvertedBytes() int64 {
+	if m != nil {
+		return m.InvertedBytes
 	}
-	return len(dAtA) - i, nil
+	return 0
 }
 
-func (m *Metadata) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+func (m *SegmentStats) GetTerms() uint64 {
+	if m != nil {
+		return m.Terms
 	}
-	return dAtA[:n], nil
+	return 0
 }
 
-func (m *Metadata) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (m *SegmentStats) GetSealed() bool {
+	if m != nil {
+		return m.Sealed
+	}
+	return false
 }
 
-func (m *Metadata) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.Id != 0 {
-		i -= 8
-		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(m.Id))
-		i--
-		dAtA[i] = 0x61
+func (m *SegmentStats) GetEventTypes() map[string]uint64 {
+	if m != nil {
+		return m.EventTypes
 	}
-	if len(m.Track) > 0 {
-		for k := range m.Track {
-			v := m.Track[k]
-			baseI := i
-			i = encodeVarintSpec(dAtA, i, uint64(v))
-			i--
-			dAtA[i] = 0x10
-			i -= len(k)
-			copy(dAtA[i:], k)
-			i = encodeVarintSpec(dAtA, i, uint64(len(k)))
-			i--
-			dAtA[i] = 0xa
-			i = encodeVarintSpec(dAtA, i, uint64(baseI-i))
-			i--
-			dAtA[i] = 0x5a
-		}
+	return nil
+}
+
+func (m *SegmentStats) GetPartitionOffsets() map[int32]int64 {
+	if m != nil {
+		return m.PartitionOffsets
 	}
-	if len(m.ForeignType) > 0 {
-		i -= len(m.ForeignType)
-		copy(dAtA[i:], m.ForeignType)
-		i = encodeVarintSpec(dAtA, i, uint64(len(m.ForeignType)))
-		i--
-		dAtA[i] = 0x52
+	return nil
+}
+
+type SegmentCatalog struct {
+	Segments []*SegmentStats `protobuf:"bytes,1,rep,name=segments,proto3" json:"segments,omitempty"`
+}
+
+func (m *SegmentCatalog) Reset()         { *m = SegmentCatalog{} }
+func (m *SegmentCatalog) String() string { return proto.CompactTextString(m) }
+func (*SegmentCatalog) ProtoMessage()    {}
+func (*SegmentCatalog) Descriptor() ([]byte, []int) {
+	return fileDescriptor_423806180556987f, []int{32}
+}
+func (m *SegmentCatalog) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *SegmentCatalog) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_SegmentCatalog.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	if len(m.ForeignId) > 0 {
-		i -= len(m.ForeignId)
-		copy(dAtA[i:], m.ForeignId)
-		i = encodeVarintSpec(dAtA, i, uint64(len(m.ForeignId)))
-		i--
-		dAtA[i] = 0x4a
-	}
-	if len(m.EventType) > 0 {
-		i -= len(m.EventType)
-		copy(dAtA[i:], m.EventType)
-		i = encodeVarintSpec(dAtA, i, uint64(len(m.EventType)))
-		i--
-		dAtA[i] = 0x3a
-	}
-	if m.CreatedAtNs != 0 {
-		i = encodeVarintSpec(dAtA, i, uint64(m.CreatedAtNs))
-		i--
-		dAtA[i] = 0x28
-	}
-	if len(m.Properties) > 0 {
-		for iNdEx := len(m.Properties) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size, err := m.Properties[iNdEx].MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
-					return 0, err
-				}
-				i -= size
-				i = encodeVarintSpec(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0x1a
-		}
-	}
-	if len(m.Count) > 0 {
-		for iNdEx := len(m.Count) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size, err := m.Count[iNdEx].MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
-					return 0, err
-				}
-				i -= size
-				i = encodeVarintSpec(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0x12
-		}
-	}
-	if len(m.Search) > 0 {
-		for iNdEx := len(m.Search) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size, err := m.Search[iNdEx].MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
-					return 0, err
-				}
-				i -= size
-				i = encodeVarintSpec(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0xa
-		}
-	}
-	return len(dAtA) - i, nil
+}
+func (m *SegmentCatalog) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SegmentCatalog.Merge(m, src)
+}
+func (m *SegmentCatalog) XXX_Size() int {
+	return m.Size()
+}
+func (m *SegmentCatalog) XXX_DiscardUnknown() {
+	xxx_messageInfo_SegmentCatalog.DiscardUnknown(m)
 }
 
-func (m *SearchableMetadata) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+var xxx_messageInfo_SegmentCatalog proto.InternalMessageInfo
+
+func (m *SegmentCatalog) GetSegments() []*SegmentStats {
+	if m != nil {
+		return m.Segments
 	}
-	return dAtA[:n], nil
+	return nil
 }
 
-func (m *SearchableMetadata) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+type StatsRequest struct {
+	FromSecond    uint32 `protobuf:"varint,1,opt,name=from_second,json=fromSecond,proto3" json:"from_second,omitempty"`
+	ToSecond      uint32 `protobuf:"varint,2,opt,name=to_second,json=toSecond,proto3" json:"to_second,omitempty"`
+	TopEventTypes uint32 `protobuf:"varint,3,opt,name=top_event_types,json=topEventTypes,proto3" json:"top_event_types,omitempty"`
 }
 
-func (m *SearchableMetadata) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.Track) > 0 {
-		for k := range m.Track {
-			v := m.Track[k]
-			baseI := i
-			i = encodeVarintSpec(dAtA, i, uint64(v))
-			i--
-			dAtA[i] = 0x10
-			i -= len(k)
-			copy(dAtA[i:], k)
-			i = encodeVarintSpec(dAtA, i, uint64(len(k)))
-			i--
-			dAtA[i] = 0xa
-			i = encodeVarintSpec(dAtA, i, uint64(baseI-i))
-			i--
-			dAtA[i] = 0x5a
+func (m *StatsRequest) Reset()         { *m = StatsRequest{} }
+func (m *StatsRequest) String() string { return proto.CompactTextString(m) }
+func (*StatsRequest) ProtoMessage()    {}
+func (*StatsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_423806180556987f, []int{33}
+}
+func (m *StatsRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *StatsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_StatsRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
+		return b[:n], nil
 	}
-	if len(m.ForeignType) > 0 {
-		i -= len(m.ForeignType)
-		copy(dAtA[i:], m.ForeignType)
-		i = encodeVarintSpec(dAtA, i, uint64(len(m.ForeignType)))
-		i--
-		dAtA[i] = 0x52
+}
+func (m *StatsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StatsRequest.Merge(m, src)
+}
+func (m *StatsRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *StatsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_StatsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StatsRequest proto.InternalMessageInfo
+
+func (m *StatsRequest) GetFromSecond() uint32 {
+	if m != nil {
+		return m.FromSecond
 	}
-	if len(m.ForeignId) > 0 {
-		i -= len(m.ForeignId)
-		copy(dAtA[i:], m.ForeignId)
-		i = encodeVarintSpec(dAtA, i, uint64(len(m.ForeignId)))
-		i--
-		dAtA[i] = 0x4a
+	return 0
+}
+
+func (m *StatsRequest) GetToSecond() uint32 {
+	if m != nil {
+		return m.ToSecond
 	}
-	if len(m.EventType) > 0 {
-		i -= len(m.EventType)
-		copy(dAtA[i:], m.EventType)
-		i = encodeVarintSpec(dAtA, i, uint64(len(m.EventType)))
-		i--
-		dAtA[i] = 0x3a
+	return 0
+}
+
+func (m *StatsRequest) GetTopEventTypes() uint32 {
+	if m != nil {
+		return m.TopEventTypes
 	}
-	if len(m.Search) > 0 {
-		for iNdEx := len(m.Search) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size, err := m.Search[iNdEx].MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
-					return 0, err
-				}
-				i -= size
-				i = encodeVarintSpec(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0xa
+	return 0
+}
+
+type StatsResponse struct {
+	Segments         []*SegmentStats `protobuf:"bytes,1,rep,name=segments,proto3" json:"segments,omitempty"`
+	Docs             uint64          `protobuf:"varint,2,opt,name=docs,proto3" json:"docs,omitempty"`
+	ForwardBytes     int64           `protobuf:"varint,3,opt,name=forward_bytes,json=forwardBytes,proto3" json:"forward_bytes,omitempty"`
+	InvertedBytes    int64           `protobuf:"varint,4,opt,name=inverted_bytes,json=invertedBytes,proto3" json:"inverted_bytes,omitempty"`
+	FailedShards     []*ShardFailure `protobuf:"bytes,5,rep,name=failed_shards,json=failedShards,proto3" json:"failed_shards,omitempty"`
+	PartitionOffsets map[int32]int64 `protobuf:"bytes,6,rep,name=partition_offsets,json=partitionOffsets,proto3" json:"partition_offsets,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+}
+
+func (m *StatsResponse) Reset()         { *m = StatsResponse{} }
+func (m *StatsResponse) String() string { return proto.CompactTextString(m) }
+func (*StatsResponse) ProtoMessage()    {}
+func (*StatsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_423806180556987f, []int{34}
+}
+func (m *StatsResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *StatsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_StatsResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
+		return b[:n], nil
 	}
-	return len(dAtA) - i, nil
+}
+func (m *StatsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StatsResponse.Merge(m, src)
+}
+func (m *StatsResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *StatsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_StatsResponse.DiscardUnknown(m)
 }
 
-func (m *BasicMetadata) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+var xxx_messageInfo_StatsResponse proto.InternalMessageInfo
+
+func (m *StatsResponse) GetSegments() []*SegmentStats {
+	if m != nil {
+		return m.Segments
 	}
-	return dAtA[:n], nil
+	return nil
 }
 
-func (m *BasicMetadata) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (m *StatsResponse) GetDocs() uint64 {
+	if m != nil {
+		return m.Docs
+	}
+	return 0
 }
 
-func (m *BasicMetadata) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.ForeignType) > 0 {
-		i -= len(m.ForeignType)
-		copy(dAtA[i:], m.ForeignType)
-		i = encodeVarintSpec(dAtA, i, uint64(len(m.ForeignType)))
-		i--
-		dAtA[i] = 0x52
-	}
-	if len(m.ForeignId) > 0 {
-		i -= len(m.ForeignId)
-		copy(dAtA[i:], m.ForeignId)
-		i = encodeVarintSpec(dAtA, i, uint64(len(m.ForeignId)))
-		i--
-		dAtA[i] = 0x4a
+func (m *StatsResponse) GetForwardBytes() int64 {
+	if m != nil {
+		return m.ForwardBytes
 	}
-	if len(m.EventType) > 0 {
-		i -= len(m.EventType)
-		copy(dAtA[i:], m.EventType)
-		i = encodeVarintSpec(dAtA, i, uint64(len(m.EventType)))
-		i--
-		dAtA[i] = 0x3a
+	return 0
+}
+
+func (m *StatsResponse) GetInvertedBytes() int64 {
+	if m != nil {
+		return m.InvertedBytes
 	}
-	if m.CreatedAtNs != 0 {
-		i = encodeVarintSpec(dAtA, i, uint64(m.CreatedAtNs))
-		i--
-		dAtA[i] = 0x28
+	return 0
+}
+
+func (m *StatsResponse) GetFailedShards() []*ShardFailure {
+	if m != nil {
+		return m.FailedShards
 	}
-	return len(dAtA) - i, nil
+	return nil
 }
 
-func (m *CountableMetadata) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+func (m *StatsResponse) GetPartitionOffsets() map[int32]int64 {
+	if m != nil {
+		return m.PartitionOffsets
 	}
-	return dAtA[:n], nil
+	return nil
 }
 
-func (m *CountableMetadata) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+type ReplicationRequest struct {
 }
 
-func (m *CountableMetadata) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.ForeignType) > 0 {
-		i -= len(m.ForeignType)
-		copy(dAtA[i:], m.ForeignType)
-		i = encodeVarintSpec(dAtA, i, uint64(len(m.ForeignType)))
-		i--
-		dAtA[i] = 0x52
-	}
-	if len(m.ForeignId) > 0 {
-		i -= len(m.ForeignId)
-		copy(dAtA[i:], m.ForeignId)
-		i = encodeVarintSpec(dAtA, i, uint64(len(m.ForeignId)))
-		i--
-		dAtA[i] = 0x4a
-	}
-	if len(m.EventType) > 0 {
-		i -= len(m.EventType)
-		copy(dAtA[i:], m.EventType)
-		i = encodeVarintSpec(dAtA, i, uint64(len(m.EventType)))
-		i--
-		dAtA[i] = 0x3a
-	}
-	if m.CreatedAtNs != 0 {
-		i = encodeVarintSpec(dAtA, i, uint64(m.CreatedAtNs))
-		i--
-		dAtA[i] = 0x28
-	}
-	if len(m.Count) > 0 {
-		for iNdEx := len(m.Count) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size, err := m.Count[iNdEx].MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
-					return 0, err
-				}
-				i -= size
-				i = encodeVarintSpec(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0x12
-		}
-	}
-	if len(m.Search) > 0 {
-		for iNdEx := len(m.Search) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size, err := m.Search[iNdEx].MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
-					return 0, err
-				}
-				i -= size
-				i = encodeVarintSpec(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0xa
+func (m *ReplicationRequest) Reset()         { *m = ReplicationRequest{} }
+func (m *ReplicationRequest) String() string { return proto.CompactTextString(m) }
+func (*ReplicationRequest) ProtoMessage()    {}
+func (*ReplicationRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_423806180556987f, []int{35}
+}
+func (m *ReplicationRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ReplicationRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ReplicationRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
+		return b[:n], nil
 	}
-	return len(dAtA) - i, nil
 }
-
-func (m *Hit) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
-	}
-	return dAtA[:n], nil
+func (m *ReplicationRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReplicationRequest.Merge(m, src)
+}
+func (m *ReplicationRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *ReplicationRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReplicationRequest.DiscardUnknown(m)
 }
 
-func (m *Hit) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+var xxx_messageInfo_ReplicationRequest proto.InternalMessageInfo
+
+type ReplicaSegment struct {
+	Id           string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ForwardBytes int64  `protobuf:"varint,2,opt,name=forward_bytes,json=forwardBytes,proto3" json:"forward_bytes,omitempty"`
+	Sealed       bool   `protobuf:"varint,3,opt,name=sealed,proto3" json:"sealed,omitempty"`
 }
 
-func (m *Hit) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.Metadata != nil {
-		{
-			size, err := m.Metadata.MarshalToSizedBuffer(dAtA[:i])
-			if err != nil {
-				return 0, err
-			}
-			i -= size
-			i = encodeVarintSpec(dAtA, i, uint64(size))
+func (m *ReplicaSegment) Reset()         { *m = ReplicaSegment{} }
+func (m *ReplicaSegment) String() string { return proto.CompactTextString(m) }
+func (*ReplicaSegment) ProtoMessage()    {}
+func (*ReplicaSegment) Descriptor() ([]byte, []int) {
+	return fileDescriptor_423806180556987f, []int{36}
+}
+func (m *ReplicaSegment) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ReplicaSegment) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ReplicaSegment.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
-		i--
-		dAtA[i] = 0x1a
+		return b[:n], nil
 	}
-	if m.Score != 0 {
-		i -= 4
-		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.Score))))
-		i--
-		dAtA[i] = 0x15
+}
+func (m *ReplicaSegment) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReplicaSegment.Merge(m, src)
+}
+func (m *ReplicaSegment) XXX_Size() int {
+	return m.Size()
+}
+func (m *ReplicaSegment) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReplicaSegment.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReplicaSegment proto.InternalMessageInfo
+
+func (m *ReplicaSegment) GetId() string {
+	if m != nil {
+		return m.Id
 	}
-	if m.Id != 0 {
-		i = encodeVarintSpec(dAtA, i, uint64(m.Id))
-		i--
-		dAtA[i] = 0x8
+	return ""
+}
+
+func (m *ReplicaSegment) GetForwardBytes() int64 {
+	if m != nil {
+		return m.ForwardBytes
 	}
-	return len(dAtA) - i, nil
+	return 0
 }
 
-func (m *SearchQueryRequest) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+func (m *ReplicaSegment) GetSealed() bool {
+	if m != nil {
+		return m.Sealed
 	}
-	return dAtA[:n], nil
+	return false
 }
 
-func (m *SearchQueryRequest) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+type ReplicationManifest struct {
+	Segments  []*ReplicaSegment `protobuf:"bytes,1,rep,name=segments,proto3" json:"segments,omitempty"`
+	Whitelist []string          `protobuf:"bytes,2,rep,name=whitelist,proto3" json:"whitelist,omitempty"`
 }
 
-func (m *SearchQueryRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.Limit != 0 {
-		i = encodeVarintSpec(dAtA, i, uint64(m.Limit))
-		i--
-		dAtA[i] = 0x20
-	}
-	if m.Query != nil {
-		{
-			size, err := m.Query.MarshalToSizedBuffer(dAtA[:i])
-			if err != nil {
-				return 0, err
-			}
-			i -= size
-			i = encodeVarintSpec(dAtA, i, uint64(size))
+func (m *ReplicationManifest) Reset()         { *m = ReplicationManifest{} }
+func (m *ReplicationManifest) String() string { return proto.CompactTextString(m) }
+func (*ReplicationManifest) ProtoMessage()    {}
+func (*ReplicationManifest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_423806180556987f, []int{37}
+}
+func (m *ReplicationManifest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ReplicationManifest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ReplicationManifest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
-		i--
-		dAtA[i] = 0x1a
-	}
-	if m.ToSecond != 0 {
-		i = encodeVarintSpec(dAtA, i, uint64(m.ToSecond))
-		i--
-		dAtA[i] = 0x10
+		return b[:n], nil
 	}
-	if m.FromSecond != 0 {
-		i = encodeVarintSpec(dAtA, i, uint64(m.FromSecond))
-		i--
-		dAtA[i] = 0x8
+}
+func (m *ReplicationManifest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReplicationManifest.Merge(m, src)
+}
+func (m *ReplicationManifest) XXX_Size() int {
+	return m.Size()
+}
+func (m *ReplicationManifest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReplicationManifest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReplicationManifest proto.InternalMessageInfo
+
+func (m *ReplicationManifest) GetSegments() []*ReplicaSegment {
+	if m != nil {
+		return m.Segments
 	}
-	return len(dAtA) - i, nil
+	return nil
 }
 
-func (m *CountPerKV) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+func (m *ReplicationManifest) GetWhitelist() []string {
+	if m != nil {
+		return m.Whitelist
 	}
-	return dAtA[:n], nil
+	return nil
 }
 
-func (m *CountPerKV) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+type ShipRequest struct {
+	SegmentId string `protobuf:"bytes,1,opt,name=segment_id,json=segmentId,proto3" json:"segment_id,omitempty"`
+	Offset    int64  `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	Files     bool   `protobuf:"varint,3,opt,name=files,proto3" json:"files,omitempty"`
+	MaxBytes  int64  `protobuf:"varint,4,opt,name=max_bytes,json=maxBytes,proto3" json:"max_bytes,omitempty"`
 }
 
-func (m *CountPerKV) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.Key) > 0 {
-		i -= len(m.Key)
-		copy(dAtA[i:], m.Key)
-		i = encodeVarintSpec(dAtA, i, uint64(len(m.Key)))
-		i--
-		dAtA[i] = 0x1a
-	}
-	if m.Total != 0 {
-		i = encodeVarintSpec(dAtA, i, uint64(m.Total))
-		i--
-		dAtA[i] = 0x10
-	}
-	if len(m.Count) > 0 {
-		for k := range m.Count {
-			v := m.Count[k]
-			baseI := i
-			i = encodeVarintSpec(dAtA, i, uint64(v))
-			i--
-			dAtA[i] = 0x10
-			i -= len(k)
-			copy(dAtA[i:], k)
-			i = encodeVarintSpec(dAtA, i, uint64(len(k)))
-			i--
-			dAtA[i] = 0xa
-			i = encodeVarintSpec(dAtA, i, uint64(baseI-i))
-			i--
-			dAtA[i] = 0xa
+func (m *ShipRequest) Reset()         { *m = ShipRequest{} }
+func (m *ShipRequest) String() string { return proto.CompactTextString(m) }
+func (*ShipRequest) ProtoMessage()    {}
+func (*ShipRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_423806180556987f, []int{38}
+}
+func (m *ShipRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ShipRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ShipRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
+		return b[:n], nil
 	}
-	return len(dAtA) - i, nil
 }
-
-func (m *PointPerEventType) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
-	}
-	return dAtA[:n], nil
+func (m *ShipRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ShipRequest.Merge(m, src)
+}
+func (m *ShipRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *ShipRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ShipRequest.DiscardUnknown(m)
 }
 
-func (m *PointPerEventType) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+var xxx_messageInfo_ShipRequest proto.InternalMessageInfo
+
+func (m *ShipRequest) GetSegmentId() string {
+	if m != nil {
+		return m.SegmentId
+	}
+	return ""
 }
 
-func (m *PointPerEventType) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.EventType) > 0 {
-		i -= len(m.EventType)
-		copy(dAtA[i:], m.EventType)
-		i = encodeVarintSpec(dAtA, i, uint64(len(m.EventType)))
-		i--
-		dAtA[i] = 0x22
-	}
-	if m.Bucket != 0 {
-		i = encodeVarintSpec(dAtA, i, uint64(m.Bucket))
-		i--
-		dAtA[i] = 0x18
-	}
-	if m.CountUnique != 0 {
-		i = encodeVarintSpec(dAtA, i, uint64(m.CountUnique))
-		i--
-		dAtA[i] = 0x10
+func (m *ShipRequest) GetOffset() int64 {
+	if m != nil {
+		return m.Offset
 	}
-	if m.Count != 0 {
-		i = encodeVarintSpec(dAtA, i, uint64(m.Count))
-		i--
-		dAtA[i] = 0x8
+	return 0
+}
+
+func (m *ShipRequest) GetFiles() bool {
+	if m != nil {
+		return m.Files
 	}
-	return len(dAtA) - i, nil
+	return false
 }
 
-func (m *ChartBucketPerTime) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+func (m *ShipRequest) GetMaxBytes() int64 {
+	if m != nil {
+		return m.MaxBytes
 	}
-	return dAtA[:n], nil
+	return 0
 }
 
-func (m *ChartBucketPerTime) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+type ShipChunk struct {
+	Name       string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Offset     int64    `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	Data       []byte   `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+	Records    [][]byte `protobuf:"bytes,4,rep,name=records,proto3" json:"records,omitempty"`
+	NextOffset int64    `protobuf:"varint,5,opt,name=next_offset,json=nextOffset,proto3" json:"next_offset,omitempty"`
 }
 
-func (m *ChartBucketPerTime) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.PerType) > 0 {
-		for k := range m.PerType {
-			v := m.PerType[k]
-			baseI := i
-			if v != nil {
-				{
-					size, err := v.MarshalToSizedBuffer(dAtA[:i])
-					if err != nil {
-						return 0, err
-					}
-					i -= size
-					i = encodeVarintSpec(dAtA, i, uint64(size))
-				}
-				i--
-				dAtA[i] = 0x12
-			}
-			i -= len(k)
-			copy(dAtA[i:], k)
-			i = encodeVarintSpec(dAtA, i, uint64(len(k)))
-			i--
-			dAtA[i] = 0xa
-			i = encodeVarintSpec(dAtA, i, uint64(baseI-i))
-			i--
-			dAtA[i] = 0xa
+func (m *ShipChunk) Reset()         { *m = ShipChunk{} }
+func (m *ShipChunk) String() string { return proto.CompactTextString(m) }
+func (*ShipChunk) ProtoMessage()    {}
+func (*ShipChunk) Descriptor() ([]byte, []int) {
+	return fileDescriptor_423806180556987f, []int{39}
+}
+func (m *ShipChunk) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ShipChunk) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ShipChunk.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
+		return b[:n], nil
 	}
-	return len(dAtA) - i, nil
+}
+func (m *ShipChunk) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ShipChunk.Merge(m, src)
+}
+func (m *ShipChunk) XXX_Size() int {
+	return m.Size()
+}
+func (m *ShipChunk) XXX_DiscardUnknown() {
+	xxx_messageInfo_ShipChunk.DiscardUnknown(m)
 }
 
-func (m *Chart) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+var xxx_messageInfo_ShipChunk proto.InternalMessageInfo
+
+func (m *ShipChunk) GetName() string {
+	if m != nil {
+		return m.Name
 	}
-	return dAtA[:n], nil
+	return ""
 }
 
-func (m *Chart) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (m *ShipChunk) GetOffset() int64 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
 }
 
-func (m *Chart) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.TimeBucketSec != 0 {
-		i = encodeVarintSpec(dAtA, i, uint64(m.TimeBucketSec))
-		i--
-		dAtA[i] = 0x20
-	}
-	if m.TimeEnd != 0 {
-		i = encodeVarintSpec(dAtA, i, uint64(m.TimeEnd))
-		i--
-		dAtA[i] = 0x18
-	}
-	if m.TimeStart != 0 {
-		i = encodeVarintSpec(dAtA, i, uint64(m.TimeStart))
-		i--
-		dAtA[i] = 0x10
+func (m *ShipChunk) GetData() []byte {
+	if m != nil {
+		return m.Data
 	}
-	if len(m.Buckets) > 0 {
-		for k := range m.Buckets {
-			v := m.Buckets[k]
-			baseI := i
-			if v != nil {
-				{
-					size, err := v.MarshalToSizedBuffer(dAtA[:i])
-					if err != nil {
-						return 0, err
-					}
-					i -= size
-					i = encodeVarintSpec(dAtA, i, uint64(size))
-				}
-				i--
-				dAtA[i] = 0x12
-			}
-			i = encodeVarintSpec(dAtA, i, uint64(k))
-			i--
-			dAtA[i] = 0x8
-			i = encodeVarintSpec(dAtA, i, uint64(baseI-i))
-			i--
-			dAtA[i] = 0xa
-		}
+	return nil
+}
+
+func (m *ShipChunk) GetRecords() [][]byte {
+	if m != nil {
+		return m.Records
 	}
-	return len(dAtA) - i, nil
+	return nil
 }
 
-func (m *AggregateRequest) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+func (m *ShipChunk) GetNextOffset() int64 {
+	if m != nil {
+		return m.NextOffset
 	}
-	return dAtA[:n], nil
+	return 0
 }
 
-func (m *AggregateRequest) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+type PromoteRequest struct {
 }
 
-func (m *AggregateRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.TimeBucketSec != 0 {
-		i = encodeVarintSpec(dAtA, i, uint64(m.TimeBucketSec))
-		i--
-		dAtA[i] = 0x20
-	}
-	if m.SampleLimit != 0 {
-		i = encodeVarintSpec(dAtA, i, uint64(m.SampleLimit))
-		i--
-		dAtA[i] = 0x18
-	}
-	if len(m.Fields) > 0 {
-		for k := range m.Fields {
-			v := m.Fields[k]
-			baseI := i
-			i--
-			if v {
-				dAtA[i] = 1
-			} else {
-				dAtA[i] = 0
-			}
-			i--
-			dAtA[i] = 0x10
-			i -= len(k)
-			copy(dAtA[i:], k)
-			i = encodeVarintSpec(dAtA, i, uint64(len(k)))
-			i--
-			dAtA[i] = 0xa
-			i = encodeVarintSpec(dAtA, i, uint64(baseI-i))
-			i--
-			dAtA[i] = 0x12
+func (m *PromoteRequest) Reset()         { *m = PromoteRequest{} }
+func (m *PromoteRequest) String() string { return proto.CompactTextString(m) }
+func (*PromoteRequest) ProtoMessage()    {}
+func (*PromoteRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_423806180556987f, []int{40}
+}
+func (m *PromoteRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *PromoteRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_PromoteRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
+		return b[:n], nil
 	}
-	if m.Query != nil {
-		{
-			size, err := m.Query.MarshalToSizedBuffer(dAtA[:i])
-			if err != nil {
-				return 0, err
-			}
-			i -= size
-			i = encodeVarintSpec(dAtA, i, uint64(size))
+}
+func (m *PromoteRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PromoteRequest.Merge(m, src)
+}
+func (m *PromoteRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *PromoteRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PromoteRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PromoteRequest proto.InternalMessageInfo
+
+type DeadLetter struct {
+	Value       []byte `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+	Topic       string `protobuf:"bytes,2,opt,name=topic,proto3" json:"topic,omitempty"`
+	Partition   int32  `protobuf:"varint,3,opt,name=partition,proto3" json:"partition,omitempty"`
+	Offset      int64  `protobuf:"varint,4,opt,name=offset,proto3" json:"offset,omitempty"`
+	Reason      string `protobuf:"bytes,5,opt,name=reason,proto3" json:"reason,omitempty"`
+	CreatedAtNs int64  `protobuf:"varint,6,opt,name=created_at_ns,json=createdAtNs,proto3" json:"created_at_ns,omitempty"`
+}
+
+func (m *DeadLetter) Reset()         { *m = DeadLetter{} }
+func (m *DeadLetter) String() string { return proto.CompactTextString(m) }
+func (*DeadLetter) ProtoMessage()    {}
+func (*DeadLetter) Descriptor() ([]byte, []int) {
+	return fileDescriptor_423806180556987f, []int{41}
+}
+func (m *DeadLetter) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *DeadLetter) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_DeadLetter.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
-		i--
-		dAtA[i] = 0xa
+		return b[:n], nil
 	}
-	return len(dAtA) - i, nil
+}
+func (m *DeadLetter) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeadLetter.Merge(m, src)
+}
+func (m *DeadLetter) XXX_Size() int {
+	return m.Size()
+}
+func (m *DeadLetter) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeadLetter.DiscardUnknown(m)
 }
 
-func (m *Aggregate) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+var xxx_messageInfo_DeadLetter proto.InternalMessageInfo
+
+func (m *DeadLetter) GetValue() []byte {
+	if m != nil {
+		return m.Value
 	}
-	return dAtA[:n], nil
+	return nil
 }
 
-func (m *Aggregate) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (m *DeadLetter) GetTopic() string {
+	if m != nil {
+		return m.Topic
+	}
+	return ""
 }
 
-func (m *Aggregate) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.Chart != nil {
-		{
-			size, err := m.Chart.MarshalToSizedBuffer(dAtA[:i])
-			if err != nil {
-				return 0, err
-			}
-			i -= size
-			i = encodeVarintSpec(dAtA, i, uint64(size))
-		}
-		i--
-		dAtA[i] = 0x42
-	}
-	if len(m.Sample) > 0 {
-		for iNdEx := len(m.Sample) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size, err := m.Sample[iNdEx].MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
-					return 0, err
-				}
-				i -= size
-				i = encodeVarintSpec(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0x3a
-		}
+func (m *DeadLetter) GetPartition() int32 {
+	if m != nil {
+		return m.Partition
 	}
-	if m.Total != 0 {
-		i = encodeVarintSpec(dAtA, i, uint64(m.Total))
-		i--
-		dAtA[i] = 0x30
+	return 0
+}
+
+func (m *DeadLetter) GetOffset() int64 {
+	if m != nil {
+		return m.Offset
 	}
-	if len(m.Possible) > 0 {
-		for k := range m.Possible {
-			v := m.Possible[k]
-			baseI := i
-			i = encodeVarintSpec(dAtA, i, uint64(v))
-			i--
-			dAtA[i] = 0x10
-			i -= len(k)
-			copy(dAtA[i:], k)
-			i = encodeVarintSpec(dAtA, i, uint64(len(k)))
-			i--
-			dAtA[i] = 0xa
-			i = encodeVarintSpec(dAtA, i, uint64(baseI-i))
-			i--
-			dAtA[i] = 0x2a
-		}
+	return 0
+}
+
+func (m *DeadLetter) GetReason() string {
+	if m != nil {
+		return m.Reason
 	}
-	if len(m.EventType) > 0 {
-		for k := range m.EventType {
-			v := m.EventType[k]
-			baseI := i
-			if v != nil {
-				{
-					size, err := v.MarshalToSizedBuffer(dAtA[:i])
-					if err != nil {
-						return 0, err
-					}
-					i -= size
-					i = encodeVarintSpec(dAtA, i, uint64(size))
-				}
-				i--
-				dAtA[i] = 0x12
-			}
-			i -= len(k)
-			copy(dAtA[i:], k)
-			i = encodeVarintSpec(dAtA, i, uint64(len(k)))
-			i--
-			dAtA[i] = 0xa
-			i = encodeVarintSpec(dAtA, i, uint64(baseI-i))
-			i--
-			dAtA[i] = 0x22
-		}
+	return ""
+}
+
+func (m *DeadLetter) GetCreatedAtNs() int64 {
+	if m != nil {
+		return m.CreatedAtNs
 	}
-	if len(m.ForeignId) > 0 {
-		for k := range m.ForeignId {
-			v := m.ForeignId[k]
-			baseI := i
-			if v != nil {
-				{
-					size, err := v.MarshalToSizedBuffer(dAtA[:i])
-					if err != nil {
-						return 0, err
-					}
-					i -= size
-					i = encodeVarintSpec(dAtA, i, uint64(size))
-				}
-				i--
-				dAtA[i] = 0x12
-			}
-			i -= len(k)
-			copy(dAtA[i:], k)
-			i = encodeVarintSpec(dAtA, i, uint64(len(k)))
-			i--
-			dAtA[i] = 0xa
-			i = encodeVarintSpec(dAtA, i, uint64(baseI-i))
-			i--
-			dAtA[i] = 0x1a
+	return 0
+}
+
+// what the envelopes of an event_type must look like, value_types is
+// key -> string, int, float or bool, the search keys added by the decoration
+// (ip, geoip_*, ua_*) are not checked, 0 max means no limit
+type EventSchema struct {
+	EventType     string            `protobuf:"bytes,1,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
+	RequiredKeys  []string          `protobuf:"bytes,2,rep,name=required_keys,json=requiredKeys,proto3" json:"required_keys,omitempty"`
+	ValueTypes    map[string]string `protobuf:"bytes,3,rep,name=value_types,json=valueTypes,proto3" json:"value_types,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	ForeignTypes  []string          `protobuf:"bytes,4,rep,name=foreign_types,json=foreignTypes,proto3" json:"foreign_types,omitempty"`
+	MaxSearch     int32             `protobuf:"varint,5,opt,name=max_search,json=maxSearch,proto3" json:"max_search,omitempty"`
+	MaxCount      int32             `protobuf:"varint,6,opt,name=max_count,json=maxCount,proto3" json:"max_count,omitempty"`
+	MaxProperties int32             `protobuf:"varint,7,opt,name=max_properties,json=maxProperties,proto3" json:"max_properties,omitempty"`
+}
+
+func (m *EventSchema) Reset()         { *m = EventSchema{} }
+func (m *EventSchema) String() string { return proto.CompactTextString(m) }
+func (*EventSchema) ProtoMessage()    {}
+func (*EventSchema) Descriptor() ([]byte, []int) {
+	return fileDescriptor_423806180556987f, []int{42}
+}
+func (m *EventSchema) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *EventSchema) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_EventSchema.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
+		return b[:n], nil
 	}
-	if len(m.Count) > 0 {
-		for k := range m.Count {
-			v := m.Count[k]
-			baseI := i
-			if v != nil {
-				{
-					size, err := v.MarshalToSizedBuffer(dAtA[:i])
-					if err != nil {
-						return 0, err
-					}
-					i -= size
-					i = encodeVarintSpec(dAtA, i, uint64(size))
-				}
-				i--
-				dAtA[i] = 0x12
-			}
-			i -= len(k)
-			copy(dAtA[i:], k)
-			i = encodeVarintSpec(dAtA, i, uint64(len(k)))
-			i--
-			dAtA[i] = 0xa
-			i = encodeVarintSpec(dAtA, i, uint64(baseI-i))
-			i--
-			dAtA[i] = 0x12
-		}
+}
+func (m *EventSchema) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EventSchema.Merge(m, src)
+}
+func (m *EventSchema) XXX_Size() int {
+	return m.Size()
+}
+func (m *EventSchema) XXX_DiscardUnknown() {
+	xxx_messageInfo_EventSchema.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EventSchema proto.InternalMessageInfo
+
+func (m *EventSchema) GetEventType() string {
+	if m != nil {
+		return m.EventType
 	}
-	if len(m.Search) > 0 {
-		for k := range m.Search {
-			v := m.Search[k]
-			baseI := i
-			if v != nil {
-				{
-					size, err := v.MarshalToSizedBuffer(dAtA[:i])
-					if err != nil {
-						return 0, err
-					}
-					i -= size
-					i = encodeVarintSpec(dAtA, i, uint64(size))
-				}
-				i--
-				dAtA[i] = 0x12
-			}
-			i -= len(k)
-			copy(dAtA[i:], k)
-			i = encodeVarintSpec(dAtA, i, uint64(len(k)))
-			i--
-			dAtA[i] = 0xa
-			i = encodeVarintSpec(dAtA, i, uint64(baseI-i))
-			i--
-			dAtA[i] = 0xa
-		}
+	return ""
+}
+
+func (m *EventSchema) GetRequiredKeys() []string {
+	if m != nil {
+		return m.RequiredKeys
 	}
-	return len(dAtA) - i, nil
+	return nil
 }
 
-func (m *SearchQueryResponse) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+func (m *EventSchema) GetValueTypes() map[string]string {
+	if m != nil {
+		return m.ValueTypes
 	}
-	return dAtA[:n], nil
+	return nil
 }
 
-func (m *SearchQueryResponse) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (m *EventSchema) GetForeignTypes() []string {
+	if m != nil {
+		return m.ForeignTypes
+	}
+	return nil
 }
 
-func (m *SearchQueryResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.Total != 0 {
-		i = encodeVarintSpec(dAtA, i, uint64(m.Total))
-		i--
-		dAtA[i] = 0x10
+func (m *EventSchema) GetMaxSearch() int32 {
+	if m != nil {
+		return m.MaxSearch
 	}
-	if len(m.Hits) > 0 {
-		for iNdEx := len(m.Hits) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size, err := m.Hits[iNdEx].MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
-					return 0, err
-				}
-				i -= size
-				i = encodeVarintSpec(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0xa
-		}
+	return 0
+}
+
+func (m *EventSchema) GetMaxCount() int32 {
+	if m != nil {
+		return m.MaxCount
 	}
-	return len(dAtA) - i, nil
+	return 0
 }
 
-func (m *Envelope) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+func (m *EventSchema) GetMaxProperties() int32 {
+	if m != nil {
+		return m.MaxProperties
 	}
-	return dAtA[:n], nil
+	return 0
 }
 
-func (m *Envelope) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+// event types without a schema accept anything, in dry_run the violations
+// are only logged
+type SchemaRegistry struct {
+	Schemas []*EventSchema `protobuf:"bytes,1,rep,name=schemas,proto3" json:"schemas,omitempty"`
+	DryRun  bool           `protobuf:"varint,2,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
 }
 
-func (m *Envelope) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.Payload) > 0 {
-		i -= len(m.Payload)
-		copy(dAtA[i:], m.Payload)
-		i = encodeVarintSpec(dAtA, i, uint64(len(m.Payload)))
-		i--
-		dAtA[i] = 0x12
-	}
-	if m.Metadata != nil {
-		{
-			size, err := m.Metadata.MarshalToSizedBuffer(dAtA[:i])
-			if err != nil {
-				return 0, err
-			}
-			i -= size
-			i = encodeVarintSpec(dAtA, i, uint64(size))
+func (m *SchemaRegistry) Reset()         { *m = SchemaRegistry{} }
+func (m *SchemaRegistry) String() string { return proto.CompactTextString(m) }
+func (*SchemaRegistry) ProtoMessage()    {}
+func (*SchemaRegistry) Descriptor() ([]byte, []int) {
+	return fileDescriptor_423806180556987f, []int{43}
+}
+func (m *SchemaRegistry) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *SchemaRegistry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_SchemaRegistry.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
-		i--
-		dAtA[i] = 0xa
+		return b[:n], nil
 	}
-	return len(dAtA) - i, nil
+}
+func (m *SchemaRegistry) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SchemaRegistry.Merge(m, src)
+}
+func (m *SchemaRegistry) XXX_Size() int {
+	return m.Size()
+}
+func (m *SchemaRegistry) XXX_DiscardUnknown() {
+	xxx_messageInfo_SchemaRegistry.DiscardUnknown(m)
 }
 
-func (m *Success) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+var xxx_messageInfo_SchemaRegistry proto.InternalMessageInfo
+
+func (m *SchemaRegistry) GetSchemas() []*EventSchema {
+	if m != nil {
+		return m.Schemas
 	}
-	return dAtA[:n], nil
+	return nil
 }
 
-func (m *Success) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (m *SchemaRegistry) GetDryRun() bool {
+	if m != nil {
+		return m.DryRun
+	}
+	return false
 }
 
-func (m *Success) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.Success {
-		i--
-		if m.Success {
-			dAtA[i] = 1
-		} else {
-			dAtA[i] = 0
-		}
-		i--
-		dAtA[i] = 0x8
-	}
-	return len(dAtA) - i, nil
+type SchemaRequest struct {
 }
 
-func (m *HealthRequest) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+func (m *SchemaRequest) Reset()         { *m = SchemaRequest{} }
+func (m *SchemaRequest) String() string { return proto.CompactTextString(m) }
+func (*SchemaRequest) ProtoMessage()    {}
+func (*SchemaRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_423806180556987f, []int{44}
+}
+func (m *SchemaRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *SchemaRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_SchemaRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	return dAtA[:n], nil
 }
-
-func (m *HealthRequest) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (m *SchemaRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SchemaRequest.Merge(m, src)
+}
+func (m *SchemaRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *SchemaRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SchemaRequest.DiscardUnknown(m)
 }
 
-func (m *HealthRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	return len(dAtA) - i, nil
+var xxx_messageInfo_SchemaRequest proto.InternalMessageInfo
+
+// a saved query counted every -alert-every over the last window_sec, kind
+// is above or below, firing when the count is above or below threshold, or
+// change, firing when the count changed by more than threshold (0.5 is 50%)
+// compared to the window before
+type AlertRule struct {
+	Name      string              `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Query     *go_query_dsl.Query `protobuf:"bytes,2,opt,name=query,proto3" json:"query,omitempty"`
+	WindowSec uint32              `protobuf:"varint,3,opt,name=window_sec,json=windowSec,proto3" json:"window_sec,omitempty"`
+	Kind      string              `protobuf:"bytes,4,opt,name=kind,proto3" json:"kind,omitempty"`
+	Threshold float64             `protobuf:"fixed64,5,opt,name=threshold,proto3" json:"threshold,omitempty"`
 }
 
-func encodeVarintSpec(dAtA []byte, offset int, v uint64) int {
-	offset -= sovSpec(v)
-	base := offset
-	for v >= 1<<7 {
-		dAtA[offset] = uint8(v&0x7f | 0x80)
-		v >>= 7
-		offset++
-	}
-	dAtA[offset] = uint8(v)
-	return base
+func (m *AlertRule) Reset()         { *m = AlertRule{} }
+func (m *AlertRule) String() string { return proto.CompactTextString(m) }
+func (*AlertRule) ProtoMessage()    {}
+func (*AlertRule) Descriptor() ([]byte, []int) {
+	return fileDescriptor_423806180556987f, []int{45}
 }
-func (m *KV) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	l = len(m.Key)
-	if l > 0 {
-		n += 1 + l + sovSpec(uint64(l))
+func (m *AlertRule) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *AlertRule) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_AlertRule.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	l = len(m.Value)
-	if l > 0 {
-		n += 1 + l + sovSpec(uint64(l))
+}
+func (m *AlertRule) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AlertRule.Merge(m, src)
+}
+func (m *AlertRule) XXX_Size() int {
+	return m.Size()
+}
+func (m *AlertRule) XXX_DiscardUnknown() {
+	xxx_messageInfo_AlertRule.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AlertRule proto.InternalMessageInfo
+
+func (m *AlertRule) GetName() string {
+	if m != nil {
+		return m.Name
 	}
-	return n
+	return ""
 }
 
-func (m *KF) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *AlertRule) GetQuery() *go_query_dsl.Query {
+	if m != nil {
+		return m.Query
 	}
-	var l int
-	_ = l
-	l = len(m.Key)
-	if l > 0 {
-		n += 1 + l + sovSpec(uint64(l))
+	return nil
+}
+
+func (m *AlertRule) GetWindowSec() uint32 {
+	if m != nil {
+		return m.WindowSec
 	}
-	if m.Value != 0 {
-		n += 9
+	return 0
+}
+
+func (m *AlertRule) GetKind() string {
+	if m != nil {
+		return m.Kind
 	}
-	return n
+	return ""
 }
 
-func (m *Metadata) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *AlertRule) GetThreshold() float64 {
+	if m != nil {
+		return m.Threshold
 	}
-	var l int
-	_ = l
-	if len(m.Search) > 0 {
-		for _, e := range m.Search {
-			l = e.Size()
-			n += 1 + l + sovSpec(uint64(l))
+	return 0
+}
+
+type AlertRules struct {
+	Rules []*AlertRule `protobuf:"bytes,1,rep,name=rules,proto3" json:"rules,omitempty"`
+}
+
+func (m *AlertRules) Reset()         { *m = AlertRules{} }
+func (m *AlertRules) String() string { return proto.CompactTextString(m) }
+func (*AlertRules) ProtoMessage()    {}
+func (*AlertRules) Descriptor() ([]byte, []int) {
+	return fileDescriptor_423806180556987f, []int{46}
+}
+func (m *AlertRules) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *AlertRules) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_AlertRules.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
+		return b[:n], nil
 	}
-	if len(m.Count) > 0 {
-		for _, e := range m.Count {
-			l = e.Size()
-			n += 1 + l + sovSpec(uint64(l))
-		}
+}
+func (m *AlertRules) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AlertRules.Merge(m, src)
+}
+func (m *AlertRules) XXX_Size() int {
+	return m.Size()
+}
+func (m *AlertRules) XXX_DiscardUnknown() {
+	xxx_messageInfo_AlertRules.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AlertRules proto.InternalMessageInfo
+
+func (m *AlertRules) GetRules() []*AlertRule {
+	if m != nil {
+		return m.Rules
 	}
-	if len(m.Properties) > 0 {
-		for _, e := range m.Properties {
-			l = e.Size()
-			n += 1 + l + sovSpec(uint64(l))
+	return nil
+}
+
+type AlertState struct {
+	Rule        *AlertRule `protobuf:"bytes,1,opt,name=rule,proto3" json:"rule,omitempty"`
+	Firing      bool       `protobuf:"varint,2,opt,name=firing,proto3" json:"firing,omitempty"`
+	Count       uint64     `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	Previous    uint64     `protobuf:"varint,4,opt,name=previous,proto3" json:"previous,omitempty"`
+	CheckedAtNs int64      `protobuf:"varint,5,opt,name=checked_at_ns,json=checkedAtNs,proto3" json:"checked_at_ns,omitempty"`
+	ChangedAtNs int64      `protobuf:"varint,6,opt,name=changed_at_ns,json=changedAtNs,proto3" json:"changed_at_ns,omitempty"`
+	Error       string     `protobuf:"bytes,7,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (m *AlertState) Reset()         { *m = AlertState{} }
+func (m *AlertState) String() string { return proto.CompactTextString(m) }
+func (*AlertState) ProtoMessage()    {}
+func (*AlertState) Descriptor() ([]byte, []int) {
+	return fileDescriptor_423806180556987f, []int{47}
+}
+func (m *AlertState) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *AlertState) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_AlertState.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
+		return b[:n], nil
 	}
-	if m.CreatedAtNs != 0 {
-		n += 1 + sovSpec(uint64(m.CreatedAtNs))
+}
+func (m *AlertState) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AlertState.Merge(m, src)
+}
+func (m *AlertState) XXX_Size() int {
+	return m.Size()
+}
+func (m *AlertState) XXX_DiscardUnknown() {
+	xxx_messageInfo_AlertState.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AlertState proto.InternalMessageInfo
+
+func (m *AlertState) GetRule() *AlertRule {
+	if m != nil {
+		return m.Rule
 	}
-	l = len(m.EventType)
-	if l > 0 {
-		n += 1 + l + sovSpec(uint64(l))
+	return nil
+}
+
+func (m *AlertState) GetFiring() bool {
+	if m != nil {
+		return m.Firing
 	}
-	l = len(m.ForeignId)
-	if l > 0 {
-		n += 1 + l + sovSpec(uint64(l))
+	return false
+}
+
+func (m *AlertState) GetCount() uint64 {
+	if m != nil {
+		return m.Count
 	}
-	l = len(m.ForeignType)
-	if l > 0 {
-		n += 1 + l + sovSpec(uint64(l))
+	return 0
+}
+
+func (m *AlertState) GetPrevious() uint64 {
+	if m != nil {
+		return m.Previous
 	}
-	if len(m.Track) > 0 {
-		for k, v := range m.Track {
-			_ = k
-			_ = v
-			mapEntrySize := 1 + len(k) + sovSpec(uint64(len(k))) + 1 + sovSpec(uint64(v))
-			n += mapEntrySize + 1 + sovSpec(uint64(mapEntrySize))
-		}
-	}
-	if m.Id != 0 {
-		n += 9
-	}
-	return n
+	return 0
 }
 
-func (m *SearchableMetadata) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	if len(m.Search) > 0 {
-		for _, e := range m.Search {
-			l = e.Size()
-			n += 1 + l + sovSpec(uint64(l))
-		}
-	}
-	l = len(m.EventType)
-	if l > 0 {
-		n += 1 + l + sovSpec(uint64(l))
+func (m *AlertState) GetCheckedAtNs() int64 {
+	if m != nil {
+		return m.CheckedAtNs
 	}
-	l = len(m.ForeignId)
-	if l > 0 {
-		n += 1 + l + sovSpec(uint64(l))
+	return 0
+}
+
+func (m *AlertState) GetChangedAtNs() int64 {
+	if m != nil {
+		return m.ChangedAtNs
 	}
-	l = len(m.ForeignType)
-	if l > 0 {
-		n += 1 + l + sovSpec(uint64(l))
+	return 0
+}
+
+func (m *AlertState) GetError() string {
+	if m != nil {
+		return m.Error
 	}
-	if len(m.Track) > 0 {
-		for k, v := range m.Track {
-			_ = k
-			_ = v
-			mapEntrySize := 1 + len(k) + sovSpec(uint64(len(k))) + 1 + sovSpec(uint64(v))
-			n += mapEntrySize + 1 + sovSpec(uint64(mapEntrySize))
+	return ""
+}
+
+// what is sent to the webhook when an alert starts or stops firing
+type AlertEvent struct {
+	Name     string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Firing   bool   `protobuf:"varint,2,opt,name=firing,proto3" json:"firing,omitempty"`
+	Count    uint64 `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	Previous uint64 `protobuf:"varint,4,opt,name=previous,proto3" json:"previous,omitempty"`
+	AtNs     int64  `protobuf:"varint,5,opt,name=at_ns,json=atNs,proto3" json:"at_ns,omitempty"`
+	Message  string `protobuf:"bytes,6,opt,name=message,proto3" json:"message,omitempty"`
+}
+
+func (m *AlertEvent) Reset()         { *m = AlertEvent{} }
+func (m *AlertEvent) String() string { return proto.CompactTextString(m) }
+func (*AlertEvent) ProtoMessage()    {}
+func (*AlertEvent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_423806180556987f, []int{48}
+}
+func (m *AlertEvent) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *AlertEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_AlertEvent.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
+		return b[:n], nil
 	}
-	return n
+}
+func (m *AlertEvent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AlertEvent.Merge(m, src)
+}
+func (m *AlertEvent) XXX_Size() int {
+	return m.Size()
+}
+func (m *AlertEvent) XXX_DiscardUnknown() {
+	xxx_messageInfo_AlertEvent.DiscardUnknown(m)
 }
 
-func (m *BasicMetadata) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	if m.CreatedAtNs != 0 {
-		n += 1 + sovSpec(uint64(m.CreatedAtNs))
+var xxx_messageInfo_AlertEvent proto.InternalMessageInfo
+
+func (m *AlertEvent) GetName() string {
+	if m != nil {
+		return m.Name
 	}
-	l = len(m.EventType)
-	if l > 0 {
-		n += 1 + l + sovSpec(uint64(l))
+	return ""
+}
+
+func (m *AlertEvent) GetFiring() bool {
+	if m != nil {
+		return m.Firing
 	}
-	l = len(m.ForeignId)
-	if l > 0 {
-		n += 1 + l + sovSpec(uint64(l))
+	return false
+}
+
+func (m *AlertEvent) GetCount() uint64 {
+	if m != nil {
+		return m.Count
 	}
-	l = len(m.ForeignType)
-	if l > 0 {
-		n += 1 + l + sovSpec(uint64(l))
+	return 0
+}
+
+func (m *AlertEvent) GetPrevious() uint64 {
+	if m != nil {
+		return m.Previous
 	}
-	return n
+	return 0
 }
 
-func (m *CountableMetadata) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *AlertEvent) GetAtNs() int64 {
+	if m != nil {
+		return m.AtNs
 	}
-	var l int
-	_ = l
-	if len(m.Search) > 0 {
-		for _, e := range m.Search {
-			l = e.Size()
-			n += 1 + l + sovSpec(uint64(l))
-		}
+	return 0
+}
+
+func (m *AlertEvent) GetMessage() string {
+	if m != nil {
+		return m.Message
 	}
-	if len(m.Count) > 0 {
-		for _, e := range m.Count {
-			l = e.Size()
-			n += 1 + l + sovSpec(uint64(l))
+	return ""
+}
+
+// name filters the states and the history, nothing means all alerts
+type AlertsRequest struct {
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+}
+
+func (m *AlertsRequest) Reset()         { *m = AlertsRequest{} }
+func (m *AlertsRequest) String() string { return proto.CompactTextString(m) }
+func (*AlertsRequest) ProtoMessage()    {}
+func (*AlertsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_423806180556987f, []int{49}
+}
+func (m *AlertsRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *AlertsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_AlertsRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
+		return b[:n], nil
 	}
-	if m.CreatedAtNs != 0 {
-		n += 1 + sovSpec(uint64(m.CreatedAtNs))
-	}
-	l = len(m.EventType)
-	if l > 0 {
-		n += 1 + l + sovSpec(uint64(l))
-	}
-	l = len(m.ForeignId)
-	if l > 0 {
-		n += 1 + l + sovSpec(uint64(l))
-	}
-	l = len(m.ForeignType)
-	if l > 0 {
-		n += 1 + l + sovSpec(uint64(l))
-	}
-	return n
+}
+func (m *AlertsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AlertsRequest.Merge(m, src)
+}
+func (m *AlertsRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *AlertsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AlertsRequest.DiscardUnknown(m)
 }
 
-func (m *Hit) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	if m.Id != 0 {
-		n += 1 + sovSpec(uint64(m.Id))
-	}
-	if m.Score != 0 {
-		n += 5
-	}
-	if m.Metadata != nil {
-		l = m.Metadata.Size()
-		n += 1 + l + sovSpec(uint64(l))
+var xxx_messageInfo_AlertsRequest proto.InternalMessageInfo
+
+func (m *AlertsRequest) GetName() string {
+	if m != nil {
+		return m.Name
 	}
-	return n
+	return ""
 }
 
-func (m *SearchQueryRequest) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	if m.FromSecond != 0 {
-		n += 1 + sovSpec(uint64(m.FromSecond))
-	}
-	if m.ToSecond != 0 {
-		n += 1 + sovSpec(uint64(m.ToSecond))
-	}
-	if m.Query != nil {
-		l = m.Query.Size()
-		n += 1 + l + sovSpec(uint64(l))
-	}
-	if m.Limit != 0 {
-		n += 1 + sovSpec(uint64(m.Limit))
-	}
-	return n
+type AlertsResponse struct {
+	States  []*AlertState `protobuf:"bytes,1,rep,name=states,proto3" json:"states,omitempty"`
+	History []*AlertEvent `protobuf:"bytes,2,rep,name=history,proto3" json:"history,omitempty"`
 }
 
-func (m *CountPerKV) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	if len(m.Count) > 0 {
-		for k, v := range m.Count {
-			_ = k
-			_ = v
-			mapEntrySize := 1 + len(k) + sovSpec(uint64(len(k))) + 1 + sovSpec(uint64(v))
-			n += mapEntrySize + 1 + sovSpec(uint64(mapEntrySize))
+func (m *AlertsResponse) Reset()         { *m = AlertsResponse{} }
+func (m *AlertsResponse) String() string { return proto.CompactTextString(m) }
+func (*AlertsResponse) ProtoMessage()    {}
+func (*AlertsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_423806180556987f, []int{50}
+}
+func (m *AlertsResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *AlertsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_AlertsResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
+		return b[:n], nil
 	}
-	if m.Total != 0 {
-		n += 1 + sovSpec(uint64(m.Total))
-	}
-	l = len(m.Key)
-	if l > 0 {
-		n += 1 + l + sovSpec(uint64(l))
-	}
-	return n
+}
+func (m *AlertsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AlertsResponse.Merge(m, src)
+}
+func (m *AlertsResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *AlertsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_AlertsResponse.DiscardUnknown(m)
 }
 
-func (m *PointPerEventType) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	if m.Count != 0 {
-		n += 1 + sovSpec(uint64(m.Count))
-	}
-	if m.CountUnique != 0 {
-		n += 1 + sovSpec(uint64(m.CountUnique))
-	}
-	if m.Bucket != 0 {
-		n += 1 + sovSpec(uint64(m.Bucket))
-	}
-	l = len(m.EventType)
-	if l > 0 {
-		n += 1 + l + sovSpec(uint64(l))
+var xxx_messageInfo_AlertsResponse proto.InternalMessageInfo
+
+func (m *AlertsResponse) GetStates() []*AlertState {
+	if m != nil {
+		return m.States
 	}
-	return n
+	return nil
 }
 
-func (m *ChartBucketPerTime) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *AlertsResponse) GetHistory() []*AlertEvent {
+	if m != nil {
+		return m.History
 	}
-	var l int
-	_ = l
-	if len(m.PerType) > 0 {
-		for k, v := range m.PerType {
-			_ = k
-			_ = v
-			l = 0
-			if v != nil {
-				l = v.Size()
-				l += 1 + sovSpec(uint64(l))
-			}
-			mapEntrySize := 1 + len(k) + sovSpec(uint64(len(k))) + l
-			n += mapEntrySize + 1 + sovSpec(uint64(mapEntrySize))
+	return nil
+}
+
+type TailRequest struct {
+	Query *go_query_dsl.Query `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+}
+
+func (m *TailRequest) Reset()         { *m = TailRequest{} }
+func (m *TailRequest) String() string { return proto.CompactTextString(m) }
+func (*TailRequest) ProtoMessage()    {}
+func (*TailRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_423806180556987f, []int{51}
+}
+func (m *TailRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TailRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TailRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
+		return b[:n], nil
 	}
-	return n
+}
+func (m *TailRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TailRequest.Merge(m, src)
+}
+func (m *TailRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *TailRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_TailRequest.DiscardUnknown(m)
 }
 
-func (m *Chart) Size() (n int) {
-	if m == nil {
-		return 0
+var xxx_messageInfo_TailRequest proto.InternalMessageInfo
+
+func (m *TailRequest) GetQuery() *go_query_dsl.Query {
+	if m != nil {
+		return m.Query
 	}
-	var l int
-	_ = l
-	if len(m.Buckets) > 0 {
-		for k, v := range m.Buckets {
-			_ = k
-			_ = v
-			l = 0
-			if v != nil {
-				l = v.Size()
-				l += 1 + sovSpec(uint64(l))
-			}
-			mapEntrySize := 1 + sovSpec(uint64(k)) + l
-			n += mapEntrySize + 1 + sovSpec(uint64(mapEntrySize))
+	return nil
+}
+
+// dropped is the number of matching events skipped before this one because
+// the client was too slow to read them
+type TailEvent struct {
+	Hit     *Hit   `protobuf:"bytes,1,opt,name=hit,proto3" json:"hit,omitempty"`
+	Dropped uint64 `protobuf:"varint,2,opt,name=dropped,proto3" json:"dropped,omitempty"`
+}
+
+func (m *TailEvent) Reset()         { *m = TailEvent{} }
+func (m *TailEvent) String() string { return proto.CompactTextString(m) }
+func (*TailEvent) ProtoMessage()    {}
+func (*TailEvent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_423806180556987f, []int{52}
+}
+func (m *TailEvent) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TailEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TailEvent.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
+		return b[:n], nil
 	}
-	if m.TimeStart != 0 {
-		n += 1 + sovSpec(uint64(m.TimeStart))
-	}
-	if m.TimeEnd != 0 {
-		n += 1 + sovSpec(uint64(m.TimeEnd))
+}
+func (m *TailEvent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TailEvent.Merge(m, src)
+}
+func (m *TailEvent) XXX_Size() int {
+	return m.Size()
+}
+func (m *TailEvent) XXX_DiscardUnknown() {
+	xxx_messageInfo_TailEvent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TailEvent proto.InternalMessageInfo
+
+func (m *TailEvent) GetHit() *Hit {
+	if m != nil {
+		return m.Hit
 	}
-	if m.TimeBucketSec != 0 {
-		n += 1 + sovSpec(uint64(m.TimeBucketSec))
+	return nil
+}
+
+func (m *TailEvent) GetDropped() uint64 {
+	if m != nil {
+		return m.Dropped
 	}
-	return n
+	return 0
 }
 
-func (m *AggregateRequest) Size() (n int) {
-	if m == nil {
-		return 0
+func init() {
+	proto.RegisterType((*KV)(nil), "blackrock.io.KV")
+	golang_proto.RegisterType((*KV)(nil), "blackrock.io.KV")
+	proto.RegisterType((*KF)(nil), "blackrock.io.KF")
+	golang_proto.RegisterType((*KF)(nil), "blackrock.io.KF")
+	proto.RegisterType((*Metadata)(nil), "blackrock.io.Metadata")
+	golang_proto.RegisterType((*Metadata)(nil), "blackrock.io.Metadata")
+	proto.RegisterMapType((map[string]uint32)(nil), "blackrock.io.Metadata.TrackEntry")
+	golang_proto.RegisterMapType((map[string]uint32)(nil), "blackrock.io.Metadata.TrackEntry")
+	proto.RegisterType((*SearchableMetadata)(nil), "blackrock.io.SearchableMetadata")
+	golang_proto.RegisterType((*SearchableMetadata)(nil), "blackrock.io.SearchableMetadata")
+	proto.RegisterMapType((map[string]uint32)(nil), "blackrock.io.SearchableMetadata.TrackEntry")
+	golang_proto.RegisterMapType((map[string]uint32)(nil), "blackrock.io.SearchableMetadata.TrackEntry")
+	proto.RegisterType((*BasicMetadata)(nil), "blackrock.io.BasicMetadata")
+	golang_proto.RegisterType((*BasicMetadata)(nil), "blackrock.io.BasicMetadata")
+	proto.RegisterType((*CountableMetadata)(nil), "blackrock.io.CountableMetadata")
+	golang_proto.RegisterType((*CountableMetadata)(nil), "blackrock.io.CountableMetadata")
+	proto.RegisterType((*Hit)(nil), "blackrock.io.Hit")
+	golang_proto.RegisterType((*Hit)(nil), "blackrock.io.Hit")
+	proto.RegisterType((*SearchQueryRequest)(nil), "blackrock.io.SearchQueryRequest")
+	golang_proto.RegisterType((*SearchQueryRequest)(nil), "blackrock.io.SearchQueryRequest")
+	proto.RegisterType((*SegmentPlan)(nil), "blackrock.io.SegmentPlan")
+	golang_proto.RegisterType((*SegmentPlan)(nil), "blackrock.io.SegmentPlan")
+	proto.RegisterType((*QueryExplain)(nil), "blackrock.io.QueryExplain")
+	golang_proto.RegisterType((*QueryExplain)(nil), "blackrock.io.QueryExplain")
+	proto.RegisterType((*CountPerKV)(nil), "blackrock.io.CountPerKV")
+	golang_proto.RegisterType((*CountPerKV)(nil), "blackrock.io.CountPerKV")
+	proto.RegisterMapType((map[string]uint32)(nil), "blackrock.io.CountPerKV.CountEntry")
+	golang_proto.RegisterMapType((map[string]uint32)(nil), "blackrock.io.CountPerKV.CountEntry")
+	proto.RegisterType((*PointPerEventType)(nil), "blackrock.io.PointPerEventType")
+	golang_proto.RegisterType((*PointPerEventType)(nil), "blackrock.io.PointPerEventType")
+	proto.RegisterType((*ChartBucketPerTime)(nil), "blackrock.io.ChartBucketPerTime")
+	golang_proto.RegisterType((*ChartBucketPerTime)(nil), "blackrock.io.ChartBucketPerTime")
+	proto.RegisterMapType((map[string]*PointPerEventType)(nil), "blackrock.io.ChartBucketPerTime.PerTypeEntry")
+	golang_proto.RegisterMapType((map[string]*PointPerEventType)(nil), "blackrock.io.ChartBucketPerTime.PerTypeEntry")
+	proto.RegisterType((*Chart)(nil), "blackrock.io.Chart")
+	golang_proto.RegisterType((*Chart)(nil), "blackrock.io.Chart")
+	proto.RegisterMapType((map[uint32]*ChartBucketPerTime)(nil), "blackrock.io.Chart.BucketsEntry")
+	golang_proto.RegisterMapType((map[uint32]*ChartBucketPerTime)(nil), "blackrock.io.Chart.BucketsEntry")
+	proto.RegisterType((*AggregateRequest)(nil), "blackrock.io.AggregateRequest")
+	golang_proto.RegisterType((*AggregateRequest)(nil), "blackrock.io.AggregateRequest")
+	proto.RegisterMapType((map[string]bool)(nil), "blackrock.io.AggregateRequest.FieldsEntry")
+	golang_proto.RegisterMapType((map[string]bool)(nil), "blackrock.io.AggregateRequest.FieldsEntry")
+	proto.RegisterType((*NumberStats)(nil), "blackrock.io.NumberStats")
+	golang_proto.RegisterType((*NumberStats)(nil), "blackrock.io.NumberStats")
+	proto.RegisterType((*ShardFailure)(nil), "blackrock.io.ShardFailure")
+	golang_proto.RegisterType((*ShardFailure)(nil), "blackrock.io.ShardFailure")
+	proto.RegisterType((*Aggregate)(nil), "blackrock.io.Aggregate")
+	golang_proto.RegisterType((*Aggregate)(nil), "blackrock.io.Aggregate")
+	proto.RegisterMapType((map[string]*CountPerKV)(nil), "blackrock.io.Aggregate.CountEntry")
+	golang_proto.RegisterMapType((map[string]*CountPerKV)(nil), "blackrock.io.Aggregate.CountEntry")
+	proto.RegisterMapType((map[string]*CountPerKV)(nil), "blackrock.io.Aggregate.EventTypeEntry")
+	golang_proto.RegisterMapType((map[string]*CountPerKV)(nil), "blackrock.io.Aggregate.EventTypeEntry")
+	proto.RegisterMapType((map[string]*CountPerKV)(nil), "blackrock.io.Aggregate.ForeignIdEntry")
+	golang_proto.RegisterMapType((map[string]*CountPerKV)(nil), "blackrock.io.Aggregate.ForeignIdEntry")
+	proto.RegisterMapType((map[string]*NumberStats)(nil), "blackrock.io.Aggregate.NumbersEntry")
+	golang_proto.RegisterMapType((map[string]*NumberStats)(nil), "blackrock.io.Aggregate.NumbersEntry")
+	proto.RegisterMapType((map[string]uint32)(nil), "blackrock.io.Aggregate.PossibleEntry")
+	golang_proto.RegisterMapType((map[string]uint32)(nil), "blackrock.io.Aggregate.PossibleEntry")
+	proto.RegisterMapType((map[string]*CountPerKV)(nil), "blackrock.io.Aggregate.SearchEntry")
+	golang_proto.RegisterMapType((map[string]*CountPerKV)(nil), "blackrock.io.Aggregate.SearchEntry")
+	proto.RegisterType((*SearchQueryResponse)(nil), "blackrock.io.SearchQueryResponse")
+	golang_proto.RegisterType((*SearchQueryResponse)(nil), "blackrock.io.SearchQueryResponse")
+	proto.RegisterType((*Envelope)(nil), "blackrock.io.Envelope")
+	golang_proto.RegisterType((*Envelope)(nil), "blackrock.io.Envelope")
+	proto.RegisterType((*Success)(nil), "blackrock.io.Success")
+	golang_proto.RegisterType((*Success)(nil), "blackrock.io.Success")
+	proto.RegisterType((*Receipt)(nil), "blackrock.io.Receipt")
+	golang_proto.RegisterType((*Receipt)(nil), "blackrock.io.Receipt")
+	proto.RegisterType((*Rejection)(nil), "blackrock.io.Rejection")
+	golang_proto.RegisterType((*Rejection)(nil), "blackrock.io.Rejection")
+	proto.RegisterType((*HealthRequest)(nil), "blackrock.io.HealthRequest")
+	golang_proto.RegisterType((*HealthRequest)(nil), "blackrock.io.HealthRequest")
+	proto.RegisterType((*SnapshotFile)(nil), "blackrock.io.SnapshotFile")
+	golang_proto.RegisterType((*SnapshotFile)(nil), "blackrock.io.SnapshotFile")
+	proto.RegisterType((*SnapshotSegment)(nil), "blackrock.io.SnapshotSegment")
+	golang_proto.RegisterType((*SnapshotSegment)(nil), "blackrock.io.SnapshotSegment")
+	proto.RegisterType((*SnapshotManifest)(nil), "blackrock.io.SnapshotManifest")
+	golang_proto.RegisterType((*SnapshotManifest)(nil), "blackrock.io.SnapshotManifest")
+	proto.RegisterType((*BackupRequest)(nil), "blackrock.io.BackupRequest")
+	golang_proto.RegisterType((*BackupRequest)(nil), "blackrock.io.BackupRequest")
+	proto.RegisterType((*RestoreRequest)(nil), "blackrock.io.RestoreRequest")
+	golang_proto.RegisterType((*RestoreRequest)(nil), "blackrock.io.RestoreRequest")
+	proto.RegisterType((*ReindexRequest)(nil), "blackrock.io.ReindexRequest")
+	golang_proto.RegisterType((*ReindexRequest)(nil), "blackrock.io.ReindexRequest")
+	proto.RegisterType((*ReindexStatus)(nil), "blackrock.io.ReindexStatus")
+	golang_proto.RegisterType((*ReindexStatus)(nil), "blackrock.io.ReindexStatus")
+	proto.RegisterType((*SegmentStats)(nil), "blackrock.io.SegmentStats")
+	golang_proto.RegisterType((*SegmentStats)(nil), "blackrock.io.SegmentStats")
+	proto.RegisterMapType((map[string]uint64)(nil), "blackrock.io.SegmentStats.EventTypesEntry")
+	golang_proto.RegisterMapType((map[string]uint64)(nil), "blackrock.io.SegmentStats.EventTypesEntry")
+	proto.RegisterMapType((map[int32]int64)(nil), "blackrock.io.SegmentStats.PartitionOffsetsEntry")
+	golang_proto.RegisterMapType((map[int32]int64)(nil), "blackrock.io.SegmentStats.PartitionOffsetsEntry")
+	proto.RegisterType((*SegmentCatalog)(nil), "blackrock.io.SegmentCatalog")
+	golang_proto.RegisterType((*SegmentCatalog)(nil), "blackrock.io.SegmentCatalog")
+	proto.RegisterType((*StatsRequest)(nil), "blackrock.io.StatsRequest")
+	golang_proto.RegisterType((*StatsRequest)(nil), "blackrock.io.StatsRequest")
+	proto.RegisterType((*StatsResponse)(nil), "blackrock.io.StatsResponse")
+	golang_proto.RegisterType((*StatsResponse)(nil), "blackrock.io.StatsResponse")
+	proto.RegisterMapType((map[int32]int64)(nil), "blackrock.io.StatsResponse.PartitionOffsetsEntry")
+	golang_proto.RegisterMapType((map[int32]int64)(nil), "blackrock.io.StatsResponse.PartitionOffsetsEntry")
+	proto.RegisterType((*ReplicationRequest)(nil), "blackrock.io.ReplicationRequest")
+	golang_proto.RegisterType((*ReplicationRequest)(nil), "blackrock.io.ReplicationRequest")
+	proto.RegisterType((*ReplicaSegment)(nil), "blackrock.io.ReplicaSegment")
+	golang_proto.RegisterType((*ReplicaSegment)(nil), "blackrock.io.ReplicaSegment")
+	proto.RegisterType((*ReplicationManifest)(nil), "blackrock.io.ReplicationManifest")
+	golang_proto.RegisterType((*ReplicationManifest)(nil), "blackrock.io.ReplicationManifest")
+	proto.RegisterType((*ShipRequest)(nil), "blackrock.io.ShipRequest")
+	golang_proto.RegisterType((*ShipRequest)(nil), "blackrock.io.ShipRequest")
+	proto.RegisterType((*ShipChunk)(nil), "blackrock.io.ShipChunk")
+	golang_proto.RegisterType((*ShipChunk)(nil), "blackrock.io.ShipChunk")
+	proto.RegisterType((*PromoteRequest)(nil), "blackrock.io.PromoteRequest")
+	golang_proto.RegisterType((*PromoteRequest)(nil), "blackrock.io.PromoteRequest")
+	proto.RegisterType((*DeadLetter)(nil), "blackrock.io.DeadLetter")
+	golang_proto.RegisterType((*DeadLetter)(nil), "blackrock.io.DeadLetter")
+	proto.RegisterType((*EventSchema)(nil), "blackrock.io.EventSchema")
+	golang_proto.RegisterType((*EventSchema)(nil), "blackrock.io.EventSchema")
+	proto.RegisterMapType((map[string]string)(nil), "blackrock.io.EventSchema.ValueTypesEntry")
+	golang_proto.RegisterMapType((map[string]string)(nil), "blackrock.io.EventSchema.ValueTypesEntry")
+	proto.RegisterType((*SchemaRegistry)(nil), "blackrock.io.SchemaRegistry")
+	golang_proto.RegisterType((*SchemaRegistry)(nil), "blackrock.io.SchemaRegistry")
+	proto.RegisterType((*SchemaRequest)(nil), "blackrock.io.SchemaRequest")
+	golang_proto.RegisterType((*SchemaRequest)(nil), "blackrock.io.SchemaRequest")
+	proto.RegisterType((*AlertRule)(nil), "blackrock.io.AlertRule")
+	golang_proto.RegisterType((*AlertRule)(nil), "blackrock.io.AlertRule")
+	proto.RegisterType((*AlertRules)(nil), "blackrock.io.AlertRules")
+	golang_proto.RegisterType((*AlertRules)(nil), "blackrock.io.AlertRules")
+	proto.RegisterType((*AlertState)(nil), "blackrock.io.AlertState")
+	golang_proto.RegisterType((*AlertState)(nil), "blackrock.io.AlertState")
+	proto.RegisterType((*AlertEvent)(nil), "blackrock.io.AlertEvent")
+	golang_proto.RegisterType((*AlertEvent)(nil), "blackrock.io.AlertEvent")
+	proto.RegisterType((*AlertsRequest)(nil), "blackrock.io.AlertsRequest")
+	golang_proto.RegisterType((*AlertsRequest)(nil), "blackrock.io.AlertsRequest")
+	proto.RegisterType((*AlertsResponse)(nil), "blackrock.io.AlertsResponse")
+	golang_proto.RegisterType((*AlertsResponse)(nil), "blackrock.io.AlertsResponse")
+	proto.RegisterType((*TailRequest)(nil), "blackrock.io.TailRequest")
+	golang_proto.RegisterType((*TailRequest)(nil), "blackrock.io.TailRequest")
+	proto.RegisterType((*TailEvent)(nil), "blackrock.io.TailEvent")
+	golang_proto.RegisterType((*TailEvent)(nil), "blackrock.io.TailEvent")
+}
+
+func init() { proto.RegisterFile("spec.proto", fileDescriptor_423806180556987f) }
+func init() { golang_proto.RegisterFile("spec.proto", fileDescriptor_423806180556987f) }
+
+var fileDescriptor_423806180556987f = []byte{
+	// 3430 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xdc, 0x3a, 0x4d, 0x6f, 0x1c, 0xc7,
+	0x72, 0x9c, 0xfd, 0xde, 0xda, 0x5d, 0x8a, 0x6a, 0x51, 0xd2, 0x6a, 0x45, 0x51, 0xd4, 0xc8, 0x32,
+	0x28, 0xd9, 0x22, 0x65, 0x3a, 0x92, 0x25, 0x1a, 0x49, 0x20, 0xc9, 0x14, 0x64, 0xcb, 0x96, 0x99,
+	0x59, 0x59, 0x70, 0xe2, 0x58, 0x8b, 0xe6, 0x6e, 0x73, 0x77, 0xb2, 0xb3, 0x33, 0xa3, 0xe9, 0x59,
+	0x8a, 0x9b, 0x53, 0x90, 0x00, 0xb9, 0x19, 0x31, 0x10, 0x1f, 0x12, 0xe4, 0x14, 0xdf, 0x12, 0xc0,
+	0x40, 0x90, 0x43, 0x2e, 0xc9, 0x21, 0x47, 0x3f, 0xbc, 0x77, 0x30, 0xf0, 0x2e, 0xef, 0xf4, 0xf0,
+	0x6c, 0xf9, 0xf0, 0xee, 0xef, 0x0f, 0x3c, 0x74, 0x75, 0xf7, 0xec, 0xcc, 0xec, 0x2e, 0x29, 0xf9,
+	0xd1, 0x80, 0xf1, 0x4e, 0xdc, 0xaa, 0xae, 0xae, 0xaa, 0xae, 0xae, 0xaa, 0xae, 0xaa, 0x21, 0x00,
+	0xf7, 0x59, 0x7b, 0xcd, 0x0f, 0xbc, 0xd0, 0x23, 0xd5, 0x1d, 0x87, 0xb6, 0xfb, 0x81, 0xd7, 0xee,
+	0xaf, 0xd9, 0x5e, 0xe3, 0x6a, 0xd7, 0x0e, 0x7b, 0xc3, 0x9d, 0xb5, 0xb6, 0x37, 0x58, 0xef, 0x7a,
+	0x5d, 0x6f, 0x1d, 0x89, 0x76, 0x86, 0xbb, 0x08, 0x21, 0x80, 0xbf, 0xe4, 0xe6, 0xc6, 0xf5, 0x18,
+	0x79, 0xc0, 0xfa, 0x7d, 0x7b, 0xbd, 0xeb, 0x5d, 0x7d, 0x3a, 0x64, 0xc1, 0x68, 0x7d, 0x18, 0xda,
+	0xce, 0x7a, 0xd7, 0x6b, 0x21, 0xd4, 0xea, 0x70, 0x67, 0xbd, 0xc3, 0x1d, 0xb5, 0x6d, 0xa9, 0xeb,
+	0x79, 0x5d, 0x87, 0xad, 0x53, 0xdf, 0x5e, 0xa7, 0xae, 0xeb, 0x85, 0x34, 0xb4, 0x3d, 0x97, 0xcb,
+	0x55, 0xf3, 0x75, 0xc8, 0x3c, 0x78, 0x4c, 0x16, 0x20, 0xdb, 0x67, 0xa3, 0xba, 0xb1, 0x62, 0xac,
+	0x96, 0x2d, 0xf1, 0x93, 0x2c, 0x42, 0x7e, 0x8f, 0x3a, 0x43, 0x56, 0xcf, 0x20, 0x4e, 0x02, 0x48,
+	0x7d, 0xef, 0x30, 0x6a, 0x43, 0x53, 0xff, 0x36, 0x0b, 0xa5, 0x0f, 0x58, 0x48, 0x3b, 0x34, 0xa4,
+	0x64, 0x0d, 0x0a, 0x9c, 0xd1, 0xa0, 0xdd, 0xab, 0x1b, 0x2b, 0xd9, 0xd5, 0xca, 0xc6, 0xc2, 0x5a,
+	0xdc, 0x16, 0x6b, 0x0f, 0x1e, 0xdf, 0xc9, 0x7d, 0xfd, 0xeb, 0xf3, 0x73, 0x96, 0xa2, 0x22, 0xaf,
+	0x43, 0xbe, 0xed, 0x0d, 0xdd, 0xb0, 0x9e, 0x39, 0x90, 0x5c, 0x12, 0x91, 0x1b, 0x00, 0x7e, 0xe0,
+	0xf9, 0x2c, 0x08, 0x6d, 0xc6, 0xeb, 0xd9, 0x03, 0xb7, 0xc4, 0x28, 0x89, 0x09, 0xb5, 0x76, 0xc0,
+	0x68, 0xc8, 0x3a, 0x2d, 0x1a, 0xb6, 0x5c, 0x5e, 0xcf, 0xaf, 0x18, 0xab, 0x59, 0xab, 0xa2, 0x90,
+	0xb7, 0xc3, 0x87, 0x9c, 0x9c, 0x03, 0x60, 0x7b, 0xcc, 0x0d, 0x5b, 0xe1, 0xc8, 0x67, 0xf5, 0x22,
+	0x9e, 0xba, 0x8c, 0x98, 0x47, 0x23, 0x9f, 0x89, 0xe5, 0x5d, 0x2f, 0x60, 0x76, 0xd7, 0x6d, 0xd9,
+	0x9d, 0x7a, 0x59, 0x2e, 0x2b, 0xcc, 0xbb, 0x1d, 0x72, 0x01, 0xaa, 0x7a, 0x19, 0xf7, 0x03, 0x12,
+	0x54, 0x14, 0x0e, 0x39, 0xbc, 0x05, 0xf9, 0x30, 0xa0, 0xed, 0x7e, 0xbd, 0x82, 0x7a, 0x5f, 0x48,
+	0xea, 0xad, 0x2d, 0xb8, 0xf6, 0x48, 0xd0, 0x6c, 0xb9, 0x61, 0x30, 0xb2, 0x24, 0x3d, 0x99, 0x87,
+	0x8c, 0xdd, 0xa9, 0x57, 0x57, 0x8c, 0xd5, 0x82, 0x95, 0xb1, 0x3b, 0xe4, 0x1a, 0x14, 0xdd, 0xe1,
+	0x60, 0x87, 0x05, 0xbc, 0x5e, 0x9b, 0x6a, 0x82, 0x7b, 0xca, 0x04, 0x9a, 0xac, 0x71, 0x13, 0x60,
+	0xcc, 0xf6, 0xb0, 0x8b, 0xad, 0xa9, 0x8b, 0xdd, 0xcc, 0xdc, 0x34, 0x36, 0xab, 0xdf, 0xfc, 0xfb,
+	0xf9, 0xb9, 0xcf, 0xbf, 0x3c, 0x3f, 0xf7, 0x2f, 0x5f, 0x9e, 0x9f, 0x33, 0xff, 0x2b, 0x03, 0xa4,
+	0x89, 0x17, 0x47, 0x77, 0x1c, 0xf6, 0x83, 0x2f, 0xfd, 0x47, 0x37, 0xf5, 0xed, 0xa4, 0xa9, 0x5f,
+	0x4b, 0xea, 0x33, 0x79, 0x82, 0x49, 0xa3, 0x1f, 0x99, 0xc9, 0xfe, 0xdb, 0x80, 0xda, 0x1d, 0xca,
+	0xed, 0x76, 0x64, 0xad, 0x9f, 0x84, 0x33, 0xa6, 0x7c, 0x2a, 0xa5, 0xf4, 0xff, 0x64, 0xe0, 0xf8,
+	0x5d, 0x11, 0x71, 0x7f, 0xd0, 0x35, 0xbf, 0x5c, 0x6c, 0xff, 0x24, 0xcc, 0xf2, 0xd2, 0xa1, 0x95,
+	0x32, 0x5c, 0x0b, 0xb2, 0xf7, 0xed, 0x50, 0x59, 0x57, 0x78, 0x4b, 0x0e, 0x23, 0x76, 0x11, 0xf2,
+	0xbc, 0xed, 0x05, 0xd2, 0x59, 0x32, 0x96, 0x04, 0xc8, 0x06, 0x94, 0x06, 0xca, 0xb6, 0xf5, 0xec,
+	0x8a, 0xb1, 0x5a, 0xd9, 0x38, 0x35, 0x3d, 0x27, 0x58, 0x11, 0x9d, 0xf9, 0x95, 0xa1, 0x23, 0xf0,
+	0x2f, 0xc4, 0x23, 0x60, 0xb1, 0xa7, 0x43, 0xc6, 0x43, 0x72, 0x1e, 0x2a, 0xbb, 0x81, 0x37, 0x68,
+	0x71, 0xd6, 0xf6, 0x5c, 0x29, 0xb9, 0x66, 0x81, 0x40, 0x35, 0x11, 0x43, 0xce, 0x42, 0x39, 0xf4,
+	0xf4, 0xb2, 0x74, 0xd9, 0x52, 0xe8, 0xa9, 0xc5, 0xcb, 0x90, 0xc7, 0x27, 0x45, 0x69, 0x71, 0x62,
+	0xad, 0xeb, 0xad, 0x21, 0x62, 0x4d, 0xbc, 0x2f, 0x52, 0x90, 0xa4, 0x10, 0x27, 0x71, 0xec, 0x81,
+	0x1d, 0xd6, 0x73, 0x2b, 0xc6, 0x6a, 0xde, 0x92, 0x00, 0xa9, 0x43, 0x91, 0xed, 0xfb, 0x0e, 0xb5,
+	0x5d, 0xbc, 0xb5, 0x92, 0xa5, 0x41, 0xf3, 0x17, 0x06, 0x54, 0x9a, 0xac, 0x3b, 0x60, 0x6e, 0xb8,
+	0xed, 0x50, 0x57, 0x5c, 0x11, 0x97, 0x60, 0x4b, 0x59, 0xa8, 0x6c, 0x95, 0x15, 0xe6, 0xdd, 0x0e,
+	0x21, 0x90, 0xf3, 0x1d, 0xea, 0xaa, 0xe7, 0x08, 0x7f, 0x93, 0x25, 0x28, 0x33, 0x1e, 0xda, 0x03,
+	0xe1, 0x05, 0xa8, 0x61, 0xce, 0x1a, 0x23, 0x84, 0x68, 0xde, 0xb7, 0x7d, 0x9f, 0x75, 0x50, 0xa5,
+	0x92, 0xa5, 0x41, 0x61, 0x13, 0xf1, 0xb3, 0x15, 0x30, 0xca, 0x3d, 0xa9, 0x58, 0xd9, 0x02, 0x81,
+	0xb2, 0x10, 0x23, 0xb6, 0x0e, 0x68, 0xd8, 0xee, 0xb1, 0x4e, 0xbd, 0x80, 0x6c, 0x35, 0x48, 0x4e,
+	0x43, 0x31, 0xf4, 0xbc, 0xbe, 0xf0, 0xc2, 0x22, 0x7a, 0x61, 0x41, 0x80, 0x0f, 0xb9, 0xf9, 0x04,
+	0xaa, 0x68, 0x8e, 0x2d, 0x79, 0x3c, 0x72, 0x1d, 0x4a, 0x4a, 0x79, 0xae, 0x82, 0xe2, 0x4c, 0x3a,
+	0xd7, 0x44, 0x67, 0xb7, 0x22, 0xd2, 0x38, 0xff, 0x4c, 0x82, 0xff, 0x7f, 0x18, 0x00, 0x18, 0x78,
+	0xdb, 0x2c, 0x78, 0xf0, 0x98, 0xdc, 0xd2, 0x11, 0x24, 0x79, 0x5f, 0x4c, 0xf2, 0x1e, 0x13, 0xca,
+	0x9f, 0x2a, 0x7f, 0xc9, 0x70, 0x5a, 0x84, 0x7c, 0xe8, 0x85, 0xd4, 0xd1, 0xf9, 0x09, 0x01, 0x9d,
+	0xc7, 0xb2, 0x51, 0x1e, 0x13, 0x79, 0x6e, 0xbc, 0xf9, 0x65, 0xf2, 0x9c, 0xf9, 0x0f, 0x06, 0x1c,
+	0xdf, 0xf6, 0x6c, 0x54, 0x61, 0x2b, 0x8a, 0xc1, 0xc5, 0xb1, 0xca, 0x48, 0x2f, 0xb5, 0xb9, 0x00,
+	0x55, 0xfc, 0xd1, 0x1a, 0xba, 0xf6, 0xd3, 0x88, 0x59, 0x05, 0x71, 0x1f, 0x21, 0x8a, 0x9c, 0x82,
+	0xc2, 0xce, 0xb0, 0xdd, 0x67, 0x21, 0x6a, 0x57, 0xb3, 0x14, 0x94, 0x8a, 0xf9, 0x5c, 0x2a, 0xe6,
+	0xcd, 0xff, 0x35, 0x80, 0xdc, 0xed, 0xd1, 0x20, 0xbc, 0x83, 0xe4, 0xdb, 0x2c, 0x78, 0x64, 0x0f,
+	0x18, 0xb9, 0x0f, 0x25, 0x9f, 0x05, 0x72, 0x8f, 0x34, 0xde, 0xd5, 0x94, 0xf1, 0x26, 0xf6, 0xac,
+	0x89, 0xbf, 0x23, 0x9f, 0x49, 0x33, 0x16, 0x7d, 0x09, 0x35, 0x3e, 0x81, 0x6a, 0x7c, 0x61, 0x8a,
+	0x89, 0xae, 0xc7, 0x4d, 0x54, 0xd9, 0x38, 0x9f, 0x14, 0x34, 0x61, 0xa2, 0x84, 0x0d, 0x33, 0x90,
+	0x47, 0x4d, 0xc8, 0x26, 0x14, 0xe5, 0x81, 0xb5, 0x23, 0xad, 0x4c, 0xd1, 0x77, 0x4d, 0x2a, 0xcc,
+	0x95, 0x8a, 0x6a, 0x83, 0x30, 0x51, 0x68, 0x0f, 0x58, 0x8b, 0x87, 0x34, 0x08, 0x95, 0x6d, 0xcb,
+	0x02, 0xd3, 0x14, 0x08, 0x72, 0x06, 0x4a, 0xb8, 0xcc, 0xdc, 0x8e, 0xb2, 0x6d, 0x51, 0xc0, 0x5b,
+	0x6e, 0x87, 0xbc, 0x0a, 0xc7, 0x70, 0x49, 0x72, 0x12, 0xf9, 0x01, 0x2d, 0x5c, 0xb3, 0x6a, 0x02,
+	0x2d, 0xa5, 0x35, 0x59, 0xbb, 0xf1, 0xd7, 0x50, 0x8d, 0x8b, 0x8e, 0x1b, 0xa1, 0x26, 0x8d, 0x70,
+	0x23, 0x69, 0x84, 0x95, 0xc3, 0xac, 0x1d, 0xb7, 0xc2, 0x17, 0x19, 0x58, 0xb8, 0xdd, 0xed, 0x06,
+	0xac, 0x4b, 0x43, 0xa6, 0x53, 0xda, 0x0d, 0x9d, 0x94, 0x8c, 0x69, 0x0c, 0x27, 0x73, 0xa0, 0xce,
+	0x50, 0x77, 0xa0, 0xb0, 0x6b, 0x33, 0xa7, 0xc3, 0xd5, 0xb3, 0x73, 0x25, 0xb9, 0x31, 0x2d, 0x67,
+	0xed, 0x1e, 0x12, 0x4b, 0x8b, 0xaa, 0x9d, 0xc2, 0x5d, 0x39, 0x1d, 0xf8, 0x0e, 0x6b, 0xc9, 0x64,
+	0x97, 0xc5, 0x64, 0x57, 0x91, 0xb8, 0xf7, 0x05, 0xea, 0x85, 0x2d, 0x77, 0x0b, 0x2a, 0x31, 0x09,
+	0x87, 0x05, 0x58, 0x29, 0x6e, 0x96, 0x3e, 0x54, 0x1e, 0xe2, 0x2b, 0xd3, 0x0c, 0x69, 0xc8, 0xa7,
+	0x6f, 0xd5, 0x0f, 0x6c, 0x2c, 0xd6, 0x16, 0x20, 0xcb, 0x87, 0x03, 0xd4, 0xd9, 0xb0, 0xc4, 0x4f,
+	0x81, 0x19, 0xd8, 0x2e, 0xea, 0x67, 0x58, 0xe2, 0x27, 0x62, 0xe8, 0x7e, 0x3d, 0xaf, 0x30, 0x74,
+	0xdf, 0xdc, 0x84, 0x6a, 0xb3, 0x47, 0x83, 0xce, 0x3d, 0x6a, 0x3b, 0xc3, 0x00, 0xe3, 0x98, 0x0b,
+	0x58, 0xc9, 0x93, 0x80, 0xc0, 0xb2, 0x20, 0xf0, 0x02, 0xdd, 0x2f, 0x20, 0x60, 0x7e, 0x55, 0x82,
+	0x72, 0x64, 0x57, 0xf2, 0x76, 0xaa, 0x4c, 0xb8, 0x38, 0xe3, 0x02, 0xd4, 0x1d, 0x2a, 0xcb, 0xcb,
+	0x2d, 0xe4, 0x66, 0xb2, 0x66, 0x30, 0x67, 0xed, 0x9d, 0x4c, 0x78, 0x5b, 0x89, 0xc7, 0x5f, 0xf6,
+	0x06, 0xaf, 0xce, 0xda, 0x7e, 0x4f, 0x17, 0x05, 0x92, 0x45, 0xac, 0x48, 0xd8, 0x4a, 0xa5, 0x9b,
+	0x03, 0xd9, 0x44, 0x31, 0xad, 0xd8, 0x8c, 0x4b, 0x91, 0xdb, 0x50, 0xf2, 0x3d, 0xce, 0xed, 0x1d,
+	0x87, 0xd5, 0xf3, 0xc8, 0xe4, 0xd2, 0x2c, 0x26, 0xdb, 0x8a, 0x4e, 0xf2, 0x88, 0xb6, 0x8d, 0x33,
+	0x78, 0x21, 0x9e, 0xc1, 0x2f, 0x43, 0x41, 0xba, 0x61, 0xbd, 0x88, 0x6c, 0x8f, 0x27, 0xd9, 0xde,
+	0xb7, 0x43, 0x4b, 0x11, 0x88, 0x67, 0xbd, 0x2d, 0xe2, 0xae, 0x5e, 0x52, 0xcf, 0xfa, 0x64, 0x48,
+	0x5a, 0x92, 0x82, 0xfc, 0x39, 0xd4, 0x76, 0xa9, 0xed, 0xb0, 0x4e, 0x0b, 0xef, 0x99, 0xd7, 0xcb,
+	0xc8, 0xbc, 0x91, 0x0a, 0xba, 0x98, 0x83, 0x58, 0x55, 0xb9, 0x01, 0x71, 0x9c, 0xfc, 0xd9, 0xb8,
+	0x70, 0x02, 0xdc, 0xfa, 0xca, 0xac, 0xe3, 0x4a, 0x97, 0xd6, 0x29, 0x4c, 0x77, 0x28, 0x4d, 0x51,
+	0x26, 0x44, 0xee, 0x30, 0xc5, 0xd7, 0xd7, 0x92, 0xf9, 0xa5, 0x3e, 0xeb, 0x29, 0x8c, 0x05, 0x50,
+	0xc3, 0x3a, 0xe4, 0x6d, 0xfb, 0x21, 0x3c, 0x1f, 0xc3, 0x7c, 0xd2, 0x79, 0x8e, 0x8e, 0x6f, 0xd2,
+	0x9b, 0x8e, 0x88, 0xef, 0xdb, 0x50, 0x4b, 0x38, 0xd8, 0xcb, 0x3c, 0xf1, 0x8d, 0x8f, 0xa0, 0x1a,
+	0xbf, 0xae, 0x29, 0x7b, 0xd7, 0x93, 0x2a, 0xa5, 0xaa, 0x9f, 0x58, 0xfa, 0x8a, 0x27, 0xb6, 0x9f,
+	0x19, 0x70, 0x22, 0x91, 0xc0, 0xb9, 0xef, 0xb9, 0x9c, 0x91, 0x4b, 0x90, 0xeb, 0xd9, 0xd1, 0x03,
+	0x38, 0xc5, 0xb3, 0x71, 0x39, 0x59, 0xda, 0xe4, 0x74, 0x60, 0xfc, 0xc9, 0xb8, 0x06, 0x95, 0x65,
+	0x6c, 0xca, 0x79, 0xe3, 0x75, 0x5b, 0x54, 0x9f, 0x4e, 0x3a, 0x7e, 0xee, 0xe5, 0x1c, 0xdf, 0xfc,
+	0x18, 0x4a, 0x5b, 0xee, 0x1e, 0x73, 0x3c, 0x3f, 0x59, 0xd0, 0x1b, 0x2f, 0x56, 0xd0, 0x8b, 0x22,
+	0xd4, 0xa7, 0x23, 0xc7, 0xa3, 0xb2, 0x2c, 0xaf, 0x5a, 0x1a, 0x34, 0xff, 0xc9, 0x80, 0x62, 0x73,
+	0xd8, 0x6e, 0x33, 0xce, 0x05, 0x15, 0x97, 0x3f, 0xeb, 0x86, 0xaa, 0x72, 0xd5, 0xca, 0x1b, 0x50,
+	0x0a, 0x58, 0x9b, 0xd9, 0x7e, 0xa8, 0x1f, 0xbc, 0x93, 0x49, 0x99, 0x96, 0x5c, 0xb5, 0x22, 0x32,
+	0xf2, 0x16, 0x40, 0xc0, 0xfe, 0x86, 0xb5, 0x71, 0x40, 0xa4, 0x32, 0xe5, 0xe9, 0xf4, 0x26, 0xb5,
+	0x6e, 0xc5, 0x48, 0x4d, 0x06, 0x45, 0xc5, 0x4d, 0x14, 0xe5, 0x3e, 0x0d, 0x42, 0x5b, 0x2c, 0xa0,
+	0x4a, 0x79, 0x6b, 0x8c, 0x10, 0xb5, 0x9c, 0xb7, 0xbb, 0xcb, 0x59, 0xa8, 0xcb, 0x5b, 0x09, 0xa9,
+	0xbe, 0x28, 0x1b, 0x4d, 0x32, 0x16, 0x21, 0x6f, 0xbb, 0x1d, 0xb6, 0xaf, 0xbb, 0x09, 0x04, 0xcc,
+	0x5b, 0x50, 0x8e, 0xe4, 0x8f, 0x49, 0x8c, 0x18, 0x89, 0x10, 0xa0, 0xca, 0x7a, 0xf9, 0x10, 0x29,
+	0xc8, 0x3c, 0x06, 0xb5, 0xfb, 0x8c, 0x3a, 0x61, 0x4f, 0xbd, 0xee, 0xe6, 0x5f, 0x42, 0xb5, 0xe9,
+	0x52, 0x9f, 0xf7, 0xbc, 0xf0, 0x9e, 0xed, 0x30, 0xd1, 0x60, 0xb8, 0x74, 0xc0, 0x94, 0x0b, 0xe3,
+	0x6f, 0xec, 0x49, 0xec, 0xbf, 0x65, 0xad, 0x9d, 0x51, 0xc8, 0x74, 0x41, 0x5e, 0x16, 0x98, 0x3b,
+	0x02, 0x21, 0x64, 0xf1, 0x1e, 0xdd, 0xb8, 0x7e, 0x43, 0x95, 0xcd, 0x0a, 0x32, 0x5d, 0x38, 0xa6,
+	0x59, 0xab, 0x2a, 0x3f, 0xd6, 0xf7, 0x95, 0xf1, 0x7c, 0x67, 0xa0, 0x84, 0x35, 0xd9, 0xb8, 0xd0,
+	0x2f, 0x22, 0xfc, 0x90, 0x93, 0x6b, 0x90, 0xdf, 0xb5, 0x9d, 0x68, 0x8a, 0x95, 0x76, 0xb8, 0x98,
+	0xce, 0x96, 0x24, 0x34, 0xbf, 0x30, 0x60, 0x41, 0xe3, 0x3f, 0xa0, 0xae, 0xbd, 0x2b, 0xaa, 0x24,
+	0x51, 0xa9, 0xa8, 0x7e, 0x8a, 0x87, 0xcc, 0x47, 0xd9, 0x59, 0xab, 0xa2, 0x70, 0xcd, 0x90, 0xf9,
+	0x93, 0x8d, 0x75, 0x66, 0xb2, 0xb1, 0xbe, 0x15, 0xeb, 0x63, 0xa4, 0x42, 0xe7, 0xa6, 0x2b, 0xa4,
+	0x4e, 0x3a, 0xee, 0x65, 0x4c, 0x4f, 0xcc, 0x37, 0xda, 0xfd, 0xa1, 0xaf, 0x0b, 0xb7, 0x15, 0xa8,
+	0x74, 0x44, 0x7f, 0xe6, 0xd2, 0xc8, 0x39, 0xca, 0x56, 0x1c, 0x95, 0xee, 0x56, 0x33, 0x07, 0x77,
+	0xab, 0xd9, 0x64, 0xb7, 0x6a, 0xae, 0xc2, 0xbc, 0xc5, 0x78, 0xe8, 0x05, 0x51, 0xa9, 0x28, 0x6e,
+	0xc8, 0x1b, 0x06, 0x6d, 0x7d, 0xad, 0x0a, 0x32, 0x3f, 0x33, 0x04, 0x29, 0x7a, 0xcc, 0xd1, 0x34,
+	0xca, 0x4b, 0x50, 0x7e, 0xd6, 0xb3, 0x43, 0xe6, 0xd8, 0x3c, 0x44, 0x3b, 0x95, 0xad, 0x31, 0x42,
+	0xf0, 0xe6, 0x21, 0x0d, 0x87, 0xbc, 0xe5, 0xb9, 0xce, 0x48, 0xb5, 0xa3, 0x20, 0x51, 0x1f, 0xba,
+	0xce, 0xc8, 0xfc, 0x9d, 0x01, 0x35, 0xa5, 0x4f, 0x13, 0xb1, 0x22, 0xae, 0x83, 0xa1, 0xeb, 0xda,
+	0x6e, 0x57, 0xc7, 0xb5, 0x02, 0x93, 0xa2, 0x32, 0x69, 0x51, 0x97, 0x60, 0x5e, 0x5f, 0x40, 0x4b,
+	0xe6, 0x42, 0x69, 0xa5, 0x9a, 0xc6, 0x3e, 0x12, 0x48, 0x72, 0x11, 0x22, 0x44, 0xab, 0xe3, 0xb9,
+	0x4c, 0x95, 0xa8, 0xda, 0x65, 0xf8, 0x3b, 0x9e, 0xcb, 0xc6, 0x35, 0x5d, 0x3e, 0x56, 0xd3, 0x09,
+	0xaf, 0x41, 0x57, 0x8d, 0xbc, 0xa6, 0xa0, 0x3c, 0x4b, 0x22, 0xd1, 0x6b, 0x5e, 0x81, 0xf9, 0x5d,
+	0xdb, 0xb5, 0x79, 0x2f, 0x22, 0x92, 0xdd, 0x72, 0x55, 0x63, 0x05, 0x95, 0xf9, 0x6d, 0x0e, 0xaa,
+	0x4d, 0xed, 0x8f, 0xa2, 0x90, 0x4d, 0x47, 0x09, 0x81, 0x1c, 0xfa, 0xae, 0xf4, 0x4b, 0xfc, 0x9d,
+	0x88, 0x9c, 0x6c, 0x32, 0x72, 0x08, 0xe4, 0x3a, 0x5e, 0x9b, 0xe3, 0x59, 0x72, 0x16, 0xfe, 0x26,
+	0x97, 0xe1, 0xf8, 0xc0, 0x76, 0x5b, 0xd3, 0x06, 0x48, 0xf3, 0x03, 0xdb, 0xbd, 0x1b, 0x73, 0x75,
+	0x41, 0x4a, 0xf7, 0x53, 0xa4, 0x05, 0x45, 0x4a, 0xf7, 0xe3, 0xa4, 0x17, 0xa1, 0xb6, 0xeb, 0x05,
+	0xcf, 0x68, 0xd0, 0x51, 0xb9, 0x41, 0x1f, 0x4f, 0x22, 0x65, 0x7a, 0xb8, 0x04, 0xf3, 0xb6, 0xbb,
+	0xc7, 0xd0, 0x52, 0x92, 0xaa, 0x84, 0x54, 0x35, 0x8d, 0x95, 0x64, 0xe2, 0xd1, 0x62, 0xc1, 0x80,
+	0xd7, 0xcb, 0xea, 0xd1, 0x12, 0x00, 0x7a, 0x2e, 0xa3, 0x0e, 0xeb, 0xe0, 0x30, 0xaa, 0x64, 0x29,
+	0x88, 0x3c, 0x80, 0xca, 0xb8, 0x0a, 0xe5, 0xf5, 0xca, 0xb4, 0x4e, 0x26, 0x6e, 0xd3, 0x71, 0x25,
+	0xaa, 0x0a, 0x2b, 0x88, 0x4a, 0x51, 0x4e, 0x3e, 0x85, 0xe3, 0x51, 0x6a, 0x6e, 0xc9, 0x4c, 0xcc,
+	0xeb, 0x55, 0x64, 0x79, 0xed, 0x00, 0x96, 0xdb, 0x7a, 0xcf, 0x87, 0x72, 0x8b, 0x64, 0xbc, 0xe0,
+	0xa7, 0xd0, 0x8d, 0x3f, 0x85, 0x63, 0x29, 0xe9, 0x87, 0xd5, 0x18, 0xb9, 0x78, 0x8d, 0x71, 0x17,
+	0x4e, 0x4e, 0x95, 0x14, 0x67, 0x92, 0x9f, 0xc2, 0x24, 0x1b, 0xaf, 0x28, 0xee, 0xc3, 0xbc, 0xd2,
+	0xfd, 0x2e, 0x0d, 0xa9, 0xe3, 0x75, 0xc9, 0x8d, 0x89, 0xc9, 0x4c, 0x63, 0xf6, 0x59, 0x63, 0xe9,
+	0x2c, 0x84, 0xaa, 0x44, 0x1d, 0x49, 0xc2, 0x10, 0x5d, 0xa2, 0xe7, 0xb7, 0xe2, 0x77, 0xa9, 0x02,
+	0x35, 0xf4, 0xfc, 0xb1, 0xd5, 0xcc, 0xcf, 0xb2, 0x50, 0x53, 0x62, 0x55, 0x2d, 0xf4, 0x03, 0xf5,
+	0x8f, 0xa2, 0x23, 0x13, 0x8b, 0x8e, 0x09, 0x3f, 0xce, 0xbe, 0x90, 0x1f, 0xe7, 0xa6, 0xf9, 0xf1,
+	0x44, 0xc1, 0x94, 0x7f, 0xc9, 0x4e, 0xe1, 0xc9, 0x34, 0x6f, 0x2c, 0x20, 0x93, 0x37, 0x52, 0x4c,
+	0xe2, 0x06, 0x79, 0x61, 0x77, 0x3c, 0x12, 0x7f, 0x5a, 0x04, 0x62, 0x31, 0xdf, 0xb1, 0xdb, 0xf8,
+	0x60, 0xe9, 0x62, 0xe2, 0x53, 0x98, 0x57, 0xd8, 0x59, 0x0f, 0xfe, 0x84, 0xa5, 0x33, 0x53, 0x2c,
+	0x3d, 0x0e, 0xfa, 0x6c, 0x3c, 0xe8, 0xcd, 0x01, 0x9c, 0x88, 0x09, 0x8d, 0x9e, 0xf8, 0x9b, 0x13,
+	0x9e, 0xb0, 0x94, 0x2e, 0xd6, 0xe2, 0x3a, 0xc5, 0x7c, 0xe1, 0xc0, 0x37, 0xc4, 0x7c, 0x06, 0x95,
+	0x66, 0xcf, 0x8e, 0x9e, 0xed, 0x43, 0x26, 0xb3, 0xb3, 0x4a, 0xba, 0xc5, 0x71, 0x1d, 0x83, 0xe3,
+	0x0b, 0x04, 0x44, 0x50, 0x88, 0x24, 0x1b, 0xf7, 0xa3, 0xd2, 0x80, 0xee, 0xe3, 0xf9, 0xcd, 0x7f,
+	0x34, 0xa0, 0x2c, 0x24, 0xdf, 0xed, 0x0d, 0xdd, 0xfe, 0xd4, 0x8a, 0x6c, 0x96, 0x30, 0xe1, 0xdc,
+	0x7a, 0x5a, 0x5e, 0xb5, 0x72, 0xba, 0x80, 0x0e, 0x58, 0xdb, 0xd3, 0xb5, 0x7b, 0xd5, 0xd2, 0xa0,
+	0x08, 0x5d, 0x97, 0xed, 0x87, 0xca, 0xc9, 0xd4, 0x73, 0x00, 0x02, 0x25, 0xfd, 0xc2, 0x5c, 0x80,
+	0xf9, 0xed, 0xc0, 0x1b, 0x78, 0xd1, 0x30, 0xc8, 0xfc, 0x4f, 0x03, 0xe0, 0x1d, 0x46, 0x3b, 0xef,
+	0xb3, 0x30, 0x64, 0xc1, 0xd8, 0x41, 0x0c, 0x14, 0x28, 0x01, 0xd9, 0x7f, 0xf8, 0x76, 0x5b, 0x0f,
+	0x41, 0x10, 0x48, 0x56, 0xc4, 0xd9, 0xd9, 0x15, 0x71, 0x2e, 0x71, 0xa2, 0x71, 0x21, 0x9b, 0x8f,
+	0x17, 0xb2, 0x93, 0x45, 0x5b, 0x61, 0xa2, 0x68, 0x33, 0xbf, 0xcf, 0x40, 0x05, 0x73, 0x48, 0xb3,
+	0xdd, 0x63, 0x03, 0x9a, 0x9a, 0x94, 0x1a, 0xe9, 0xaf, 0x23, 0x17, 0xa1, 0x16, 0xb0, 0xa7, 0x43,
+	0x3b, 0x60, 0x9d, 0x56, 0x9f, 0x8d, 0xb8, 0xf2, 0x88, 0xaa, 0x46, 0x3e, 0x60, 0x23, 0x4e, 0xde,
+	0x83, 0x0a, 0x1e, 0x32, 0x4a, 0x56, 0xc2, 0xdf, 0x2e, 0x27, 0xfd, 0x2d, 0x26, 0x73, 0xed, 0xb1,
+	0x20, 0x8e, 0xbf, 0x3b, 0x7b, 0x11, 0x42, 0x05, 0x43, 0xf4, 0xbd, 0x45, 0xde, 0x4f, 0xd9, 0xaa,
+	0xc6, 0x3e, 0xb8, 0xe0, 0xec, 0x52, 0x78, 0x8a, 0x9a, 0x18, 0xe5, 0xa5, 0xdd, 0x06, 0x74, 0x5f,
+	0xf6, 0x87, 0xda, 0x91, 0xe4, 0x4c, 0xa8, 0x80, 0xab, 0xc2, 0x91, 0xb0, 0xd7, 0x15, 0x29, 0x4b,
+	0x2c, 0xc6, 0x3e, 0x09, 0x17, 0x91, 0xa2, 0x36, 0xa0, 0xfb, 0xdb, 0x11, 0x52, 0x3c, 0x50, 0x29,
+	0x35, 0x5f, 0xf4, 0x4b, 0x38, 0xe6, 0x82, 0x27, 0x30, 0x2f, 0x0f, 0x6b, 0xb1, 0xae, 0xcd, 0xc5,
+	0xee, 0x37, 0xa1, 0xc8, 0x11, 0x33, 0x63, 0xe8, 0x1f, 0x33, 0x90, 0xa5, 0x29, 0xc5, 0xcc, 0xbf,
+	0x13, 0x8c, 0x5a, 0xc1, 0xd0, 0x55, 0x93, 0xbe, 0x42, 0x27, 0x18, 0x59, 0x43, 0xec, 0x59, 0x34,
+	0x7f, 0xe9, 0x84, 0xff, 0x66, 0x40, 0xf9, 0xb6, 0xc3, 0x82, 0xd0, 0x1a, 0xce, 0xe8, 0x58, 0xa2,
+	0x0f, 0x36, 0x99, 0x43, 0x3f, 0xd8, 0x9c, 0x03, 0x78, 0x66, 0xbb, 0x1d, 0xef, 0x19, 0x8e, 0x28,
+	0xe5, 0xe3, 0x53, 0x96, 0x98, 0x26, 0x6b, 0x0b, 0xee, 0x7d, 0xdb, 0xed, 0xa8, 0xb9, 0x3a, 0xfe,
+	0x16, 0x9e, 0x1c, 0xf6, 0x02, 0xc6, 0x7b, 0x9e, 0xd3, 0x51, 0x23, 0xc2, 0x31, 0xc2, 0x7c, 0x1b,
+	0x20, 0x52, 0x8e, 0x93, 0xab, 0x90, 0x0f, 0xc4, 0x8f, 0xba, 0x31, 0xad, 0x8d, 0x8c, 0x08, 0x2d,
+	0x49, 0x65, 0x7e, 0x6b, 0xa8, 0xdd, 0x22, 0xb7, 0x33, 0xf2, 0x1a, 0xe4, 0x04, 0x5e, 0x35, 0xcb,
+	0x33, 0x37, 0x23, 0x91, 0x08, 0x95, 0x5d, 0x3b, 0x10, 0xa5, 0xb2, 0xb2, 0x9f, 0x84, 0xc6, 0x53,
+	0x50, 0xf9, 0x6d, 0x48, 0x02, 0xa4, 0x01, 0x25, 0x3f, 0x60, 0x7b, 0xb6, 0x37, 0xd4, 0x95, 0x62,
+	0x04, 0x63, 0x70, 0xf5, 0x58, 0xbb, 0x3f, 0xf1, 0xa9, 0x51, 0x22, 0xb1, 0xf6, 0x43, 0x1a, 0xea,
+	0x76, 0x27, 0x02, 0x50, 0x22, 0x91, 0x26, 0xaa, 0x9c, 0x8b, 0xf1, 0x69, 0xe8, 0xbf, 0xea, 0x33,
+	0xa2, 0x1b, 0xcc, 0xca, 0x6f, 0x47, 0x74, 0x94, 0x13, 0x90, 0x8f, 0x1f, 0x21, 0x47, 0x85, 0x5e,
+	0xe2, 0xc3, 0x16, 0xe3, 0x9c, 0x76, 0x19, 0x6a, 0x5d, 0xb6, 0x34, 0x68, 0x5e, 0x84, 0x1a, 0xaa,
+	0x16, 0x95, 0x37, 0x53, 0xb4, 0x33, 0xf7, 0x60, 0x5e, 0x13, 0xa9, 0x62, 0xe4, 0x1a, 0x14, 0xb8,
+	0xb8, 0x30, 0x7d, 0xcd, 0xf5, 0x29, 0x37, 0x85, 0x37, 0x6a, 0x29, 0x3a, 0xb2, 0x01, 0xc5, 0x9e,
+	0x2d, 0x9a, 0xb4, 0x51, 0x3d, 0x33, 0x73, 0x0b, 0x1a, 0xc8, 0xd2, 0x84, 0xe6, 0x4d, 0xa8, 0x3c,
+	0xa2, 0xb6, 0xa3, 0x55, 0xbb, 0x9c, 0xfc, 0x00, 0x70, 0x80, 0x93, 0x9b, 0xef, 0x41, 0x59, 0xec,
+	0x94, 0x06, 0xbf, 0x08, 0xd9, 0x9e, 0x1d, 0xaa, 0x5d, 0x53, 0x86, 0x48, 0x62, 0x55, 0x98, 0xa8,
+	0x13, 0x78, 0xf8, 0xd9, 0x50, 0x56, 0x4a, 0x1a, 0xdc, 0xf8, 0x79, 0x16, 0x8a, 0x5b, 0xee, 0xd3,
+	0x21, 0x1b, 0x32, 0xd2, 0x84, 0x62, 0x93, 0x8e, 0xb6, 0x87, 0xbc, 0x47, 0x52, 0x93, 0x1c, 0x3d,
+	0xf3, 0x69, 0xa4, 0xa6, 0x2d, 0x6a, 0x60, 0x63, 0x9e, 0xfe, 0xfb, 0x5f, 0x7e, 0xff, 0xcf, 0x99,
+	0xe3, 0x66, 0x15, 0xff, 0x21, 0x67, 0xef, 0x8d, 0x75, 0x7f, 0xc8, 0x7b, 0x9b, 0xc6, 0x95, 0x55,
+	0x83, 0xdc, 0x81, 0x8a, 0x62, 0xda, 0x1c, 0xb9, 0xed, 0x97, 0x65, 0x3c, 0xb7, 0x6a, 0x5c, 0x33,
+	0xc8, 0x36, 0x94, 0x9b, 0x74, 0x24, 0x47, 0x1d, 0xe4, 0x6c, 0xea, 0x8c, 0xf1, 0x01, 0xc8, 0x2c,
+	0x36, 0xc7, 0x50, 0xbf, 0x32, 0x29, 0xae, 0xf7, 0x24, 0x93, 0x5d, 0x80, 0x26, 0x1d, 0x35, 0x55,
+	0xb2, 0x4a, 0xb1, 0x4c, 0xe4, 0xa7, 0xc6, 0xd2, 0xf4, 0x45, 0x99, 0x1c, 0xcd, 0x73, 0xc8, 0xf9,
+	0x34, 0x39, 0xa9, 0x4f, 0x4e, 0x3b, 0x03, 0xdb, 0x5d, 0xd7, 0x69, 0x70, 0x00, 0x35, 0x21, 0x87,
+	0x85, 0x5a, 0xd4, 0x81, 0xdc, 0x0e, 0x91, 0xb5, 0x82, 0xb2, 0x1a, 0xe6, 0x74, 0x59, 0x9b, 0xc6,
+	0x95, 0x8d, 0xff, 0xab, 0x42, 0x41, 0x3d, 0x25, 0x3f, 0xca, 0x65, 0xf6, 0xf1, 0x22, 0x94, 0x84,
+	0x43, 0xbf, 0x51, 0x35, 0x2e, 0x1c, 0x40, 0x21, 0x63, 0xcd, 0x3c, 0x83, 0xc2, 0x4e, 0x98, 0xf3,
+	0x5a, 0x98, 0x7c, 0x1a, 0x37, 0x8d, 0x2b, 0xe4, 0x13, 0x28, 0x35, 0xe9, 0xe8, 0x1e, 0x0b, 0x5f,
+	0x48, 0xd6, 0xa4, 0xeb, 0x9b, 0x75, 0xe4, 0x4d, 0xcc, 0x9a, 0xe6, 0xbd, 0x2b, 0x78, 0x6d, 0x1a,
+	0x57, 0xae, 0x19, 0x84, 0x41, 0xb5, 0x49, 0x47, 0xe3, 0xcf, 0x38, 0xcb, 0x07, 0x7f, 0x37, 0x6b,
+	0x9c, 0x9e, 0xb1, 0x6e, 0x2e, 0xa1, 0x90, 0x53, 0xe6, 0xf1, 0xe8, 0x52, 0xf4, 0x92, 0x38, 0xc3,
+	0xd1, 0x7b, 0xae, 0x8d, 0x1c, 0xe5, 0x0c, 0x2a, 0xcd, 0x31, 0x31, 0x99, 0x6a, 0x2c, 0x4f, 0x9f,
+	0x69, 0xe9, 0x4a, 0xdb, 0x3c, 0x8f, 0xac, 0xcf, 0x98, 0x8b, 0x49, 0x77, 0xda, 0x41, 0x26, 0x42,
+	0x79, 0x07, 0x83, 0x44, 0x4d, 0x9f, 0xc8, 0x44, 0x19, 0x1e, 0x1f, 0x4a, 0x1d, 0x2a, 0x6c, 0x86,
+	0xef, 0x06, 0x92, 0x8b, 0x90, 0x66, 0x2b, 0x69, 0x72, 0xe4, 0x39, 0x21, 0x2d, 0x3e, 0xd7, 0x6a,
+	0x9c, 0x9d, 0xba, 0x2a, 0xa7, 0x4c, 0xb3, 0x45, 0x21, 0x91, 0x10, 0xf5, 0x57, 0xe8, 0x59, 0x72,
+	0x3c, 0xd3, 0x98, 0xda, 0x85, 0x4d, 0x15, 0x93, 0xe8, 0xd0, 0xcc, 0x93, 0x28, 0xe6, 0x18, 0x89,
+	0xbc, 0x8b, 0x23, 0xbf, 0xbf, 0x33, 0xe0, 0x14, 0x9e, 0x63, 0xb2, 0xb5, 0x59, 0x99, 0xda, 0xc8,
+	0xc4, 0x5a, 0xae, 0xc6, 0x85, 0x99, 0x14, 0x91, 0x21, 0x2f, 0xa0, 0xd8, 0xb3, 0xe4, 0x4c, 0xfa,
+	0x74, 0x11, 0x29, 0xb9, 0x8d, 0xa1, 0x2f, 0x7a, 0x0e, 0x92, 0xfe, 0xff, 0x8c, 0x71, 0x07, 0xd4,
+	0x38, 0x3d, 0xb9, 0x84, 0x2d, 0x8a, 0x39, 0x77, 0xcd, 0x20, 0x6d, 0xbc, 0x0c, 0xd5, 0x2e, 0xa4,
+	0x2f, 0x23, 0xd9, 0x45, 0xcc, 0xf2, 0xdc, 0x19, 0xd7, 0xe0, 0xcb, 0xcd, 0xe2, 0x1a, 0xfe, 0x38,
+	0x93, 0x30, 0x79, 0x82, 0x11, 0x2a, 0x6b, 0x8a, 0xf4, 0xa9, 0x12, 0xe5, 0x48, 0x63, 0x69, 0xfa,
+	0xa2, 0x72, 0xb0, 0x53, 0x28, 0x69, 0x81, 0x44, 0xa9, 0x91, 0x4a, 0x96, 0x1f, 0xe3, 0xf5, 0x8a,
+	0x0a, 0x20, 0x7d, 0xbd, 0xb1, 0x7a, 0xa2, 0x71, 0x7a, 0x72, 0x09, 0x0b, 0x86, 0xc9, 0xf4, 0x1e,
+	0x52, 0xdb, 0xc1, 0xa4, 0x78, 0x67, 0xe9, 0xeb, 0xef, 0x96, 0x8d, 0x6f, 0xbe, 0x5b, 0x36, 0x7e,
+	0xf3, 0xdd, 0xb2, 0xf1, 0xf9, 0xf3, 0xe5, 0xb9, 0xff, 0x7f, 0xbe, 0x6c, 0x7c, 0xf3, 0x7c, 0x79,
+	0xee, 0x57, 0xcf, 0x97, 0xe7, 0x76, 0x0a, 0xf8, 0xcf, 0xb5, 0x6f, 0xfe, 0x7e, 0x00, 0x01, 0xe2,
+	0x54, 0xee, 0xfc, 0x2b, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// EnqueueClient is the client API for Enqueue service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type EnqueueClient interface {
+	SayPush(ctx context.Context, opts ...grpc.CallOption) (Enqueue_SayPushClient, error)
+	SayPushSync(ctx context.Context, opts ...grpc.CallOption) (Enqueue_SayPushSyncClient, error)
+	SayHealth(ctx context.Context, in *HealthRequest, opts ...grpc.CallOption) (*Success, error)
+	SaySchemas(ctx context.Context, in *SchemaRequest, opts ...grpc.CallOption) (*SchemaRegistry, error)
+	SaySetSchemas(ctx context.Context, in *SchemaRegistry, opts ...grpc.CallOption) (*SchemaRegistry, error)
+}
+
+type enqueueClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewEnqueueClient(cc *grpc.ClientConn) EnqueueClient {
+	return &enqueueClient{cc}
+}
+
+func (c *enqueueClient) SayPush(ctx context.Context, opts ...grpc.CallOption) (Enqueue_SayPushClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Enqueue_serviceDesc.Streams[0], "/blackrock.io.Enqueue/SayPush", opts...)
+	if err != nil {
+		return nil, err
 	}
-	var l int
-	_ = l
-	if m.Query != nil {
-		l = m.Query.Size()
-		n += 1 + l + sovSpec(uint64(l))
+	x := &enqueueSayPushClient{stream}
+	return x, nil
+}
+
+type Enqueue_SayPushClient interface {
+	Send(*Envelope) error
+	CloseAndRecv() (*Success, error)
+	grpc.ClientStream
+}
+
+type enqueueSayPushClient struct {
+	grpc.ClientStream
+}
+
+func (x *enqueueSayPushClient) Send(m *Envelope) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *enqueueSayPushClient) CloseAndRecv() (*Success, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
 	}
-	if len(m.Fields) > 0 {
-		for k, v := range m.Fields {
-			_ = k
-			_ = v
-			mapEntrySize := 1 + len(k) + sovSpec(uint64(len(k))) + 1 + 1
-			n += mapEntrySize + 1 + sovSpec(uint64(mapEntrySize))
-		}
+	m := new(Success)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
 	}
-	if m.SampleLimit != 0 {
-		n += 1 + sovSpec(uint64(m.SampleLimit))
+	return m, nil
+}
+
+func (c *enqueueClient) SayPushSync(ctx context.Context, opts ...grpc.CallOption) (Enqueue_SayPushSyncClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Enqueue_serviceDesc.Streams[1], "/blackrock.io.Enqueue/SayPushSync", opts...)
+	if err != nil {
+		return nil, err
 	}
-	if m.TimeBucketSec != 0 {
-		n += 1 + sovSpec(uint64(m.TimeBucketSec))
+	x := &enqueueSayPushSyncClient{stream}
+	return x, nil
+}
+
+type Enqueue_SayPushSyncClient interface {
+	Send(*Envelope) error
+	Recv() (*Success, error)
+	grpc.ClientStream
+}
+
+type enqueueSayPushSyncClient struct {
+	grpc.ClientStream
+}
+
+func (x *enqueueSayPushSyncClient) Send(m *Envelope) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *enqueueSayPushSyncClient) Recv() (*Success, error) {
+	m := new(Success)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
 	}
-	return n
+	return m, nil
 }
 
-func (m *Aggregate) Size() (n int) {
-	if m == nil {
-		return 0
+func (c *enqueueClient) SayHealth(ctx context.Context, in *HealthRequest, opts ...grpc.CallOption) (*Success, error) {
+	out := new(Success)
+	err := c.cc.Invoke(ctx, "/blackrock.io.Enqueue/SayHealth", in, out, opts...)
+	if err != nil {
+		return nil, err
 	}
-	var l int
-	_ = l
-	if len(m.Search) > 0 {
+	return out, nil
+}
+
+func (c *enqueueClient) SaySchemas(ctx context.Context, in *SchemaRequest, opts ...grpc.CallOption) (*SchemaRegistry, error) {
+	out := new(SchemaRegistry)
+	err := c.cc.Invoke(ctx, "/blackrock.io.Enqueue/SaySchemas", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *enqueueClient) SaySetSchemas(ctx context.Context, in *SchemaRegistry, opts ...grpc.CallOption) (*SchemaRegistry, error) {
+	out := new(SchemaRegistry)
+	err := c.cc.Invoke(ctx, "/blackrock.io.Enqueue/SaySetSchemas", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// EnqueueServer is the server API for Enqueue service.
+type EnqueueServer interface {
+	SayPush(Enqueue_SayPushServer) error
+	SayPushSync(Enqueue_SayPushSyncServer) error
+	SayHealth(context.Context, *HealthRequest) (*Success, error)
+	SaySchemas(context.Context, *SchemaRequest) (*SchemaRegistry, error)
+	SaySetSchemas(context.Context, *SchemaRegistry) (*SchemaRegistry, error)
+}
+
+// UnimplementedEnqueueServer can be embedded to have forward compatible implementations.
+type UnimplementedEnqueueServer struct {
+}
+
+func (*UnimplementedEnqueueServer) SayPush(srv Enqueue_SayPushServer) error {
+	return status.Errorf(codes.Unimplemented, "method SayPush not implemented")
+}
+func (*UnimplementedEnqueueServer) SayPushSync(srv Enqueue_SayPushSyncServer) error {
+	return status.Errorf(codes.Unimplemented, "method SayPushSync not implemented")
+}
+func (*UnimplementedEnqueueServer) SayHealth(ctx context.Context, req *HealthRequest) (*Success, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SayHealth not implemented")
+}
+func (*UnimplementedEnqueueServer) SaySchemas(ctx context.Context, req *SchemaRequest) (*SchemaRegistry, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SaySchemas not implemented")
+}
+func (*UnimplementedEnqueueServer) SaySetSchemas(ctx context.Context, req *SchemaRegistry) (*SchemaRegistry, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SaySetSchemas not implemented")
+}
+
+func RegisterEnqueueServer(s *grpc.Server, srv EnqueueServer) {
+	s.RegisterService(&_Enqueue_serviceDesc, srv)
+}
+
+func _Enqueue_SayPush_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(EnqueueServer).SayPush(&enqueueSayPushServer{stream})
+}
+
+type Enqueue_SayPushServer interface {
+	SendAndClose(*Success) error
+	Recv() (*Envelope, error)
+	grpc.ServerStream
+}
+
+type enqueueSayPushServer struct {
+	grpc.ServerStream
+}
+
+func (x *enqueueSayPushServer) SendAndClose(m *Success) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *enqueueSayPushServer) Recv() (*Envelope, error) {
+	m := new(Envelope)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Enqueue_SayPushSync_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(EnqueueServer).SayPushSync(&enqueueSayPushSyncServer{stream})
+}
+
+type Enqueue_SayPushSyncServer interface {
+	Send(*Success) error
+	Recv() (*Envelope, error)
+	grpc.ServerStream
+}
+
+type enqueueSayPushSyncServer struct {
+	grpc.ServerStream
+}
+
+func (x *enqueueSayPushSyncServer) Send(m *Success) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *enqueueSayPushSyncServer) Recv() (*Envelope, error) {
+	m := new(Envelope)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Enqueue_SayHealth_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HealthRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EnqueueServer).SayHealth(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/blackrock.io.Enqueue/SayHealth",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EnqueueServer).SayHealth(ctx, req.(*HealthRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Enqueue_SaySchemas_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SchemaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EnqueueServer).SaySchemas(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/blackrock.io.Enqueue/SaySchemas",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EnqueueServer).SaySchemas(ctx, req.(*SchemaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Enqueue_SaySetSchemas_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SchemaRegistry)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EnqueueServer).SaySetSchemas(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/blackrock.io.Enqueue/SaySetSchemas",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EnqueueServer).SaySetSchemas(ctx, req.(*SchemaRegistry))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Enqueue_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "blackrock.io.Enqueue",
+	HandlerType: (*EnqueueServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SayHealth",
+			Handler:    _Enqueue_SayHealth_Handler,
+		},
+		{
+			MethodName: "SaySchemas",
+			Handler:    _Enqueue_SaySchemas_Handler,
+		},
+		{
+			MethodName: "SaySetSchemas",
+			Handler:    _Enqueue_SaySetSchemas_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SayPush",
+			Handler:       _Enqueue_SayPush_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "SayPushSync",
+			Handler:       _Enqueue_SayPushSync_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "spec.proto",
+}
+
+// SearchClient is the client API for Search service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type SearchClient interface {
+	SayPush(ctx context.Context, opts ...grpc.CallOption) (Search_SayPushClient, error)
+	SaySearch(ctx context.Context, in *SearchQueryRequest, opts ...grpc.CallOption) (*SearchQueryResponse, error)
+	SayFetch(ctx context.Context, in *SearchQueryRequest, opts ...grpc.CallOption) (Search_SayFetchClient, error)
+	SayAggregate(ctx context.Context, in *AggregateRequest, opts ...grpc.CallOption) (*Aggregate, error)
+	SayHealth(ctx context.Context, in *HealthRequest, opts ...grpc.CallOption) (*Success, error)
+	SayBackup(ctx context.Context, in *BackupRequest, opts ...grpc.CallOption) (*SnapshotManifest, error)
+	SayRestore(ctx context.Context, in *RestoreRequest, opts ...grpc.CallOption) (*SnapshotManifest, error)
+	SayReindex(ctx context.Context, in *ReindexRequest, opts ...grpc.CallOption) (*ReindexStatus, error)
+	SayStats(ctx context.Context, in *StatsRequest, opts ...grpc.CallOption) (*StatsResponse, error)
+	SayReplicationManifest(ctx context.Context, in *ReplicationRequest, opts ...grpc.CallOption) (*ReplicationManifest, error)
+	SayShip(ctx context.Context, in *ShipRequest, opts ...grpc.CallOption) (Search_SayShipClient, error)
+	SayPromote(ctx context.Context, in *PromoteRequest, opts ...grpc.CallOption) (*Success, error)
+	SaySchemas(ctx context.Context, in *SchemaRequest, opts ...grpc.CallOption) (*SchemaRegistry, error)
+	SaySetSchemas(ctx context.Context, in *SchemaRegistry, opts ...grpc.CallOption) (*SchemaRegistry, error)
+	SayAlerts(ctx context.Context, in *AlertsRequest, opts ...grpc.CallOption) (*AlertsResponse, error)
+	SayTail(ctx context.Context, in *TailRequest, opts ...grpc.CallOption) (Search_SayTailClient, error)
+}
+
+type searchClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewSearchClient(cc *grpc.ClientConn) SearchClient {
+	return &searchClient{cc}
+}
+
+func (c *searchClient) SayPush(ctx context.Context, opts ...grpc.CallOption) (Search_SayPushClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Search_serviceDesc.Streams[0], "/blackrock.io.Search/SayPush", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &searchSayPushClient{stream}
+	return x, nil
+}
+
+type Search_SayPushClient interface {
+	Send(*Envelope) error
+	CloseAndRecv() (*Success, error)
+	grpc.ClientStream
+}
+
+type searchSayPushClient struct {
+	grpc.ClientStream
+}
+
+func (x *searchSayPushClient) Send(m *Envelope) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *searchSayPushClient) CloseAndRecv() (*Success, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(Success)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *searchClient) SaySearch(ctx context.Context, in *SearchQueryRequest, opts ...grpc.CallOption) (*SearchQueryResponse, error) {
+	out := new(SearchQueryResponse)
+	err := c.cc.Invoke(ctx, "/blackrock.io.Search/SaySearch", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *searchClient) SayFetch(ctx context.Context, in *SearchQueryRequest, opts ...grpc.CallOption) (Search_SayFetchClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Search_serviceDesc.Streams[1], "/blackrock.io.Search/SayFetch", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &searchSayFetchClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Search_SayFetchClient interface {
+	Recv() (*Hit, error)
+	grpc.ClientStream
+}
+
+type searchSayFetchClient struct {
+	grpc.ClientStream
+}
+
+func (x *searchSayFetchClient) Recv() (*Hit, error) {
+	m := new(Hit)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *searchClient) SayAggregate(ctx context.Context, in *AggregateRequest, opts ...grpc.CallOption) (*Aggregate, error) {
+	out := new(Aggregate)
+	err := c.cc.Invoke(ctx, "/blackrock.io.Search/SayAggregate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *searchClient) SayHealth(ctx context.Context, in *HealthRequest, opts ...grpc.CallOption) (*Success, error) {
+	out := new(Success)
+	err := c.cc.Invoke(ctx, "/blackrock.io.Search/SayHealth", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *searchClient) SayBackup(ctx context.Context, in *BackupRequest, opts ...grpc.CallOption) (*SnapshotManifest, error) {
+	out := new(SnapshotManifest)
+	err := c.cc.Invoke(ctx, "/blackrock.io.Search/SayBackup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *searchClient) SayRestore(ctx context.Context, in *RestoreRequest, opts ...grpc.CallOption) (*SnapshotManifest, error) {
+	out := new(SnapshotManifest)
+	err := c.cc.Invoke(ctx, "/blackrock.io.Search/SayRestore", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *searchClient) SayReindex(ctx context.Context, in *ReindexRequest, opts ...grpc.CallOption) (*ReindexStatus, error) {
+	out := new(ReindexStatus)
+	err := c.cc.Invoke(ctx, "/blackrock.io.Search/SayReindex", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *searchClient) SayStats(ctx context.Context, in *StatsRequest, opts ...grpc.CallOption) (*StatsResponse, error) {
+	out := new(StatsResponse)
+	err := c.cc.Invoke(ctx, "/blackrock.io.Search/SayStats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *searchClient) SayReplicationManifest(ctx context.Context, in *ReplicationRequest, opts ...grpc.CallOption) (*ReplicationManifest, error) {
+	out := new(ReplicationManifest)
+	err := c.cc.Invoke(ctx, "/blackrock.io.Search/SayReplicationManifest", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *searchClient) SayShip(ctx context.Context, in *ShipRequest, opts ...grpc.CallOption) (Search_SayShipClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Search_serviceDesc.Streams[2], "/blackrock.io.Search/SayShip", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &searchSayShipClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Search_SayShipClient interface {
+	Recv() (*ShipChunk, error)
+	grpc.ClientStream
+}
+
+type searchSayShipClient struct {
+	grpc.ClientStream
+}
+
+func (x *searchSayShipClient) Recv() (*ShipChunk, error) {
+	m := new(ShipChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *searchClient) SayPromote(ctx context.Context, in *PromoteRequest, opts ...grpc.CallOption) (*Success, error) {
+	out := new(Success)
+	err := c.cc.Invoke(ctx, "/blackrock.io.Search/SayPromote", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *searchClient) SaySchemas(ctx context.Context, in *SchemaRequest, opts ...grpc.CallOption) (*SchemaRegistry, error) {
+	out := new(SchemaRegistry)
+	err := c.cc.Invoke(ctx, "/blackrock.io.Search/SaySchemas", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *searchClient) SaySetSchemas(ctx context.Context, in *SchemaRegistry, opts ...grpc.CallOption) (*SchemaRegistry, error) {
+	out := new(SchemaRegistry)
+	err := c.cc.Invoke(ctx, "/blackrock.io.Search/SaySetSchemas", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *searchClient) SayAlerts(ctx context.Context, in *AlertsRequest, opts ...grpc.CallOption) (*AlertsResponse, error) {
+	out := new(AlertsResponse)
+	err := c.cc.Invoke(ctx, "/blackrock.io.Search/SayAlerts", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *searchClient) SayTail(ctx context.Context, in *TailRequest, opts ...grpc.CallOption) (Search_SayTailClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Search_serviceDesc.Streams[3], "/blackrock.io.Search/SayTail", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &searchSayTailClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Search_SayTailClient interface {
+	Recv() (*TailEvent, error)
+	grpc.ClientStream
+}
+
+type searchSayTailClient struct {
+	grpc.ClientStream
+}
+
+func (x *searchSayTailClient) Recv() (*TailEvent, error) {
+	m := new(TailEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// SearchServer is the server API for Search service.
+type SearchServer interface {
+	SayPush(Search_SayPushServer) error
+	SaySearch(context.Context, *SearchQueryRequest) (*SearchQueryResponse, error)
+	SayFetch(*SearchQueryRequest, Search_SayFetchServer) error
+	SayAggregate(context.Context, *AggregateRequest) (*Aggregate, error)
+	SayHealth(context.Context, *HealthRequest) (*Success, error)
+	SayBackup(context.Context, *BackupRequest) (*SnapshotManifest, error)
+	SayRestore(context.Context, *RestoreRequest) (*SnapshotManifest, error)
+	SayReindex(context.Context, *ReindexRequest) (*ReindexStatus, error)
+	SayStats(context.Context, *StatsRequest) (*StatsResponse, error)
+	SayReplicationManifest(context.Context, *ReplicationRequest) (*ReplicationManifest, error)
+	SayShip(*ShipRequest, Search_SayShipServer) error
+	SayPromote(context.Context, *PromoteRequest) (*Success, error)
+	SaySchemas(context.Context, *SchemaRequest) (*SchemaRegistry, error)
+	SaySetSchemas(context.Context, *SchemaRegistry) (*SchemaRegistry, error)
+	SayAlerts(context.Context, *AlertsRequest) (*AlertsResponse, error)
+	SayTail(*TailRequest, Search_SayTailServer) error
+}
+
+// UnimplementedSearchServer can be embedded to have forward compatible implementations.
+type UnimplementedSearchServer struct {
+}
+
+func (*UnimplementedSearchServer) SayPush(srv Search_SayPushServer) error {
+	return status.Errorf(codes.Unimplemented, "method SayPush not implemented")
+}
+func (*UnimplementedSearchServer) SaySearch(ctx context.Context, req *SearchQueryRequest) (*SearchQueryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SaySearch not implemented")
+}
+func (*UnimplementedSearchServer) SayFetch(req *SearchQueryRequest, srv Search_SayFetchServer) error {
+	return status.Errorf(codes.Unimplemented, "method SayFetch not implemented")
+}
+func (*UnimplementedSearchServer) SayAggregate(ctx context.Context, req *AggregateRequest) (*Aggregate, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SayAggregate not implemented")
+}
+func (*UnimplementedSearchServer) SayHealth(ctx context.Context, req *HealthRequest) (*Success, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SayHealth not implemented")
+}
+func (*UnimplementedSearchServer) SayBackup(ctx context.Context, req *BackupRequest) (*SnapshotManifest, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SayBackup not implemented")
+}
+func (*UnimplementedSearchServer) SayRestore(ctx context.Context, req *RestoreRequest) (*SnapshotManifest, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SayRestore not implemented")
+}
+func (*UnimplementedSearchServer) SayReindex(ctx context.Context, req *ReindexRequest) (*ReindexStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SayReindex not implemented")
+}
+func (*UnimplementedSearchServer) SayStats(ctx context.Context, req *StatsRequest) (*StatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SayStats not implemented")
+}
+func (*UnimplementedSearchServer) SayReplicationManifest(ctx context.Context, req *ReplicationRequest) (*ReplicationManifest, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SayReplicationManifest not implemented")
+}
+func (*UnimplementedSearchServer) SayShip(req *ShipRequest, srv Search_SayShipServer) error {
+	return status.Errorf(codes.Unimplemented, "method SayShip not implemented")
+}
+func (*UnimplementedSearchServer) SayPromote(ctx context.Context, req *PromoteRequest) (*Success, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SayPromote not implemented")
+}
+func (*UnimplementedSearchServer) SaySchemas(ctx context.Context, req *SchemaRequest) (*SchemaRegistry, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SaySchemas not implemented")
+}
+func (*UnimplementedSearchServer) SaySetSchemas(ctx context.Context, req *SchemaRegistry) (*SchemaRegistry, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SaySetSchemas not implemented")
+}
+func (*UnimplementedSearchServer) SayAlerts(ctx context.Context, req *AlertsRequest) (*AlertsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SayAlerts not implemented")
+}
+func (*UnimplementedSearchServer) SayTail(req *TailRequest, srv Search_SayTailServer) error {
+	return status.Errorf(codes.Unimplemented, "method SayTail not implemented")
+}
+
+func RegisterSearchServer(s *grpc.Server, srv SearchServer) {
+	s.RegisterService(&_Search_serviceDesc, srv)
+}
+
+func _Search_SayPush_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(SearchServer).SayPush(&searchSayPushServer{stream})
+}
+
+type Search_SayPushServer interface {
+	SendAndClose(*Success) error
+	Recv() (*Envelope, error)
+	grpc.ServerStream
+}
+
+type searchSayPushServer struct {
+	grpc.ServerStream
+}
+
+func (x *searchSayPushServer) SendAndClose(m *Success) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *searchSayPushServer) Recv() (*Envelope, error) {
+	m := new(Envelope)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Search_SaySearch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchQueryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SearchServer).SaySearch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/blackrock.io.Search/SaySearch",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SearchServer).SaySearch(ctx, req.(*SearchQueryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Search_SayFetch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SearchQueryRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SearchServer).SayFetch(m, &searchSayFetchServer{stream})
+}
+
+type Search_SayFetchServer interface {
+	Send(*Hit) error
+	grpc.ServerStream
+}
+
+type searchSayFetchServer struct {
+	grpc.ServerStream
+}
+
+func (x *searchSayFetchServer) Send(m *Hit) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Search_SayAggregate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AggregateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SearchServer).SayAggregate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/blackrock.io.Search/SayAggregate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SearchServer).SayAggregate(ctx, req.(*AggregateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Search_SayHealth_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HealthRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SearchServer).SayHealth(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/blackrock.io.Search/SayHealth",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SearchServer).SayHealth(ctx, req.(*HealthRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Search_SayBackup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BackupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SearchServer).SayBackup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/blackrock.io.Search/SayBackup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SearchServer).SayBackup(ctx, req.(*BackupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Search_SayRestore_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SearchServer).SayRestore(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/blackrock.io.Search/SayRestore",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SearchServer).SayRestore(ctx, req.(*RestoreRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Search_SayReindex_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReindexRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SearchServer).SayReindex(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/blackrock.io.Search/SayReindex",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SearchServer).SayReindex(ctx, req.(*ReindexRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Search_SayStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SearchServer).SayStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/blackrock.io.Search/SayStats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SearchServer).SayStats(ctx, req.(*StatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Search_SayReplicationManifest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReplicationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SearchServer).SayReplicationManifest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/blackrock.io.Search/SayReplicationManifest",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SearchServer).SayReplicationManifest(ctx, req.(*ReplicationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Search_SayShip_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ShipRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SearchServer).SayShip(m, &searchSayShipServer{stream})
+}
+
+type Search_SayShipServer interface {
+	Send(*ShipChunk) error
+	grpc.ServerStream
+}
+
+type searchSayShipServer struct {
+	grpc.ServerStream
+}
+
+func (x *searchSayShipServer) Send(m *ShipChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Search_SayPromote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PromoteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SearchServer).SayPromote(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/blackrock.io.Search/SayPromote",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SearchServer).SayPromote(ctx, req.(*PromoteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Search_SaySchemas_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SchemaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SearchServer).SaySchemas(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/blackrock.io.Search/SaySchemas",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SearchServer).SaySchemas(ctx, req.(*SchemaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Search_SaySetSchemas_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SchemaRegistry)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SearchServer).SaySetSchemas(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/blackrock.io.Search/SaySetSchemas",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SearchServer).SaySetSchemas(ctx, req.(*SchemaRegistry))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Search_SayAlerts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AlertsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SearchServer).SayAlerts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/blackrock.io.Search/SayAlerts",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SearchServer).SayAlerts(ctx, req.(*AlertsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Search_SayTail_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(TailRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SearchServer).SayTail(m, &searchSayTailServer{stream})
+}
+
+type Search_SayTailServer interface {
+	Send(*TailEvent) error
+	grpc.ServerStream
+}
+
+type searchSayTailServer struct {
+	grpc.ServerStream
+}
+
+func (x *searchSayTailServer) Send(m *TailEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _Search_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "blackrock.io.Search",
+	HandlerType: (*SearchServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SaySearch",
+			Handler:    _Search_SaySearch_Handler,
+		},
+		{
+			MethodName: "SayAggregate",
+			Handler:    _Search_SayAggregate_Handler,
+		},
+		{
+			MethodName: "SayHealth",
+			Handler:    _Search_SayHealth_Handler,
+		},
+		{
+			MethodName: "SayBackup",
+			Handler:    _Search_SayBackup_Handler,
+		},
+		{
+			MethodName: "SayRestore",
+			Handler:    _Search_SayRestore_Handler,
+		},
+		{
+			MethodName: "SayReindex",
+			Handler:    _Search_SayReindex_Handler,
+		},
+		{
+			MethodName: "SayStats",
+			Handler:    _Search_SayStats_Handler,
+		},
+		{
+			MethodName: "SayReplicationManifest",
+			Handler:    _Search_SayReplicationManifest_Handler,
+		},
+		{
+			MethodName: "SayPromote",
+			Handler:    _Search_SayPromote_Handler,
+		},
+		{
+			MethodName: "SaySchemas",
+			Handler:    _Search_SaySchemas_Handler,
+		},
+		{
+			MethodName: "SaySetSchemas",
+			Handler:    _Search_SaySetSchemas_Handler,
+		},
+		{
+			MethodName: "SayAlerts",
+			Handler:    _Search_SayAlerts_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SayPush",
+			Handler:       _Search_SayPush_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "SayFetch",
+			Handler:       _Search_SayFetch_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SayShip",
+			Handler:       _Search_SayShip_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SayTail",
+			Handler:       _Search_SayTail_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "spec.proto",
+}
+
+func (m *KV) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *KV) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *KV) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Value) > 0 {
+		i -= len(m.Value)
+		copy(dAtA[i:], m.Value)
+		i = encodeVarintSpec(dAtA, i, uint64(len(m.Value)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Key) > 0 {
+		i -= len(m.Key)
+		copy(dAtA[i:], m.Key)
+		i = encodeVarintSpec(dAtA, i, uint64(len(m.Key)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *KF) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *KF) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *KF) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Value != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.Value))))
+		i--
+		dAtA[i] = 0x11
+	}
+	if len(m.Key) > 0 {
+		i -= len(m.Key)
+		copy(dAtA[i:], m.Key)
+		i = encodeVarintSpec(dAtA, i, uint64(len(m.Key)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Metadata) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Metadata) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Metadata) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Numbers) > 0 {
+		for iNdEx := len(m.Numbers) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Numbers[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintSpec(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x6a
+		}
+	}
+	if m.Id != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(m.Id))
+		i--
+		dAtA[i] = 0x61
+	}
+	if len(m.Track) > 0 {
+		for k := range m.Track {
+			v := m.Track[k]
+			baseI := i
+			i = encodeVarintSpec(dAtA, i, uint64(v))
+			i--
+			dAtA[i] = 0x10
+			i -= len(k)
+			copy(dAtA[i:], k)
+			i = encodeVarintSpec(dAtA, i, uint64(len(k)))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintSpec(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0x5a
+		}
+	}
+	if len(m.ForeignType) > 0 {
+		i -= len(m.ForeignType)
+		copy(dAtA[i:], m.ForeignType)
+		i = encodeVarintSpec(dAtA, i, uint64(len(m.ForeignType)))
+		i--
+		dAtA[i] = 0x52
+	}
+	if len(m.ForeignId) > 0 {
+		i -= len(m.ForeignId)
+		copy(dAtA[i:], m.ForeignId)
+		i = encodeVarintSpec(dAtA, i, uint64(len(m.ForeignId)))
+		i--
+		dAtA[i] = 0x4a
+	}
+	if len(m.EventType) > 0 {
+		i -= len(m.EventType)
+		copy(dAtA[i:], m.EventType)
+		i = encodeVarintSpec(dAtA, i, uint64(len(m.EventType)))
+		i--
+		dAtA[i] = 0x3a
+	}
+	if m.CreatedAtNs != 0 {
+		i = encodeVarintSpec(dAtA, i, uint64(m.CreatedAtNs))
+		i--
+		dAtA[i] = 0x28
+	}
+	if len(m.Properties) > 0 {
+		for iNdEx := len(m.Properties) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Properties[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintSpec(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if len(m.Count) > 0 {
+		for iNdEx := len(m.Count) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Count[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintSpec(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if len(m.Search) > 0 {
+		for iNdEx := len(m.Search) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Search[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintSpec(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *SearchableMetadata) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SearchableMetadata) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SearchableMetadata) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Track) > 0 {
+		for k := range m.Track {
+			v := m.Track[k]
+			baseI := i
+			i = encodeVarintSpec(dAtA, i, uint64(v))
+			i--
+			dAtA[i] = 0x10
+			i -= len(k)
+			copy(dAtA[i:], k)
+			i = encodeVarintSpec(dAtA, i, uint64(len(k)))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintSpec(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0x5a
+		}
+	}
+	if len(m.ForeignType) > 0 {
+		i -= len(m.ForeignType)
+		copy(dAtA[i:], m.ForeignType)
+		i = encodeVarintSpec(dAtA, i, uint64(len(m.ForeignType)))
+		i--
+		dAtA[i] = 0x52
+	}
+	if len(m.ForeignId) > 0 {
+		i -= len(m.ForeignId)
+		copy(dAtA[i:], m.ForeignId)
+		i = encodeVarintSpec(dAtA, i, uint64(len(m.ForeignId)))
+		i--
+		dAtA[i] = 0x4a
+	}
+	if len(m.EventType) > 0 {
+		i -= len(m.EventType)
+		copy(dAtA[i:], m.EventType)
+		i = encodeVarintSpec(dAtA, i, uint64(len(m.EventType)))
+		i--
+		dAtA[i] = 0x3a
+	}
+	if len(m.Search) > 0 {
+		for iNdEx := len(m.Search) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Search[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintSpec(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *BasicMetadata) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *BasicMetadata) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *BasicMetadata) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Id != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(m.Id))
+		i--
+		dAtA[i] = 0x61
+	}
+	if len(m.ForeignType) > 0 {
+		i -= len(m.ForeignType)
+		copy(dAtA[i:], m.ForeignType)
+		i = encodeVarintSpec(dAtA, i, uint64(len(m.ForeignType)))
+		i--
+		dAtA[i] = 0x52
+	}
+	if len(m.ForeignId) > 0 {
+		i -= len(m.ForeignId)
+		copy(dAtA[i:], m.ForeignId)
+		i = encodeVarintSpec(dAtA, i, uint64(len(m.ForeignId)))
+		i--
+		dAtA[i] = 0x4a
+	}
+	if len(m.EventType) > 0 {
+		i -= len(m.EventType)
+		copy(dAtA[i:], m.EventType)
+		i = encodeVarintSpec(dAtA, i, uint64(len(m.EventType)))
+		i--
+		dAtA[i] = 0x3a
+	}
+	if m.CreatedAtNs != 0 {
+		i = encodeVarintSpec(dAtA, i, uint64(m.CreatedAtNs))
+		i--
+		dAtA[i] = 0x28
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *CountableMetadata) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *CountableMetadata) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *CountableMetadata) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Numbers) > 0 {
+		for iNdEx := len(m.Numbers) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Numbers[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintSpec(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x6a
+		}
+	}
+	if len(m.ForeignType) > 0 {
+		i -= len(m.ForeignType)
+		copy(dAtA[i:], m.ForeignType)
+		i = encodeVarintSpec(dAtA, i, uint64(len(m.ForeignType)))
+		i--
+		dAtA[i] = 0x52
+	}
+	if len(m.ForeignId) > 0 {
+		i -= len(m.ForeignId)
+		copy(dAtA[i:], m.ForeignId)
+		i = encodeVarintSpec(dAtA, i, uint64(len(m.ForeignId)))
+		i--
+		dAtA[i] = 0x4a
+	}
+	if len(m.EventType) > 0 {
+		i -= len(m.EventType)
+		copy(dAtA[i:], m.EventType)
+		i = encodeVarintSpec(dAtA, i, uint64(len(m.EventType)))
+		i--
+		dAtA[i] = 0x3a
+	}
+	if m.CreatedAtNs != 0 {
+		i = encodeVarintSpec(dAtA, i, uint64(m.CreatedAtNs))
+		i--
+		dAtA[i] = 0x28
+	}
+	if len(m.Count) > 0 {
+		for iNdEx := len(m.Count) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Count[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintSpec(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if len(m.Search) > 0 {
+		for iNdEx := len(m.Search) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Search[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintSpec(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Hit) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Hit) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Hit) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Metadata != nil {
+		{
+			size, err := m.Metadata.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintSpec(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x1a
+	}
+	if m.Score != 0 {
+		i -= 4
+		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.Score))))
+		i--
+		dAtA[i] = 0x15
+	}
+	if m.Id != 0 {
+		i = encodeVarintSpec(dAtA, i, uint64(m.Id))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *SearchQueryRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SearchQueryRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SearchQueryRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Explain {
+		i--
+		if m.Explain {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x28
+	}
+	if m.Limit != 0 {
+		i = encodeVarintSpec(dAtA, i, uint64(m.Limit))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.Query != nil {
+		{
+			size, err := m.Query.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintSpec(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x1a
+	}
+	if m.ToSecond != 0 {
+		i = encodeVarintSpec(dAtA, i, uint64(m.ToSecond))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.FromSecond != 0 {
+		i = encodeVarintSpec(dAtA, i, uint64(m.FromSecond))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *SegmentPlan) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SegmentPlan) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SegmentPlan) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.TookNs != 0 {
+		i = encodeVarintSpec(dAtA, i, uint64(m.TookNs))
+		i--
+		dAtA[i] = 0x38
+	}
+	if m.Matched != 0 {
+		i = encodeVarintSpec(dAtA, i, uint64(m.Matched))
+		i--
+		dAtA[i] = 0x30
+	}
+	if len(m.SkipReason) > 0 {
+		i -= len(m.SkipReason)
+		copy(dAtA[i:], m.SkipReason)
+		i = encodeVarintSpec(dAtA, i, uint64(len(m.SkipReason)))
+		i--
+		dAtA[i] = 0x2a
+	}
+	if m.Skipped {
+		i--
+		if m.Skipped {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.Estimated != 0 {
+		i = encodeVarintSpec(dAtA, i, uint64(m.Estimated))
+		i--
+		dAtA[i] = 0x18
+	}
+	if len(m.Plan) > 0 {
+		i -= len(m.Plan)
+		copy(dAtA[i:], m.Plan)
+		i = encodeVarintSpec(dAtA, i, uint64(len(m.Plan)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.SegmentId) > 0 {
+		i -= len(m.SegmentId)
+		copy(dAtA[i:], m.SegmentId)
+		i = encodeVarintSpec(dAtA, i, uint64(len(m.SegmentId)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *QueryExplain) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *QueryExplain) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *QueryExplain) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.TookNs != 0 {
+		i = encodeVarintSpec(dAtA, i, uint64(m.TookNs))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Segments) > 0 {
+		for iNdEx := len(m.Segments) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Segments[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintSpec(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *CountPerKV) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *CountPerKV) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *CountPerKV) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Key) > 0 {
+		i -= len(m.Key)
+		copy(dAtA[i:], m.Key)
+		i = encodeVarintSpec(dAtA, i, uint64(len(m.Key)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if m.Total != 0 {
+		i = encodeVarintSpec(dAtA, i, uint64(m.Total))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Count) > 0 {
+		for k := range m.Count {
+			v := m.Count[k]
+			baseI := i
+			i = encodeVarintSpec(dAtA, i, uint64(v))
+			i--
+			dAtA[i] = 0x10
+			i -= len(k)
+			copy(dAtA[i:], k)
+			i = encodeVarintSpec(dAtA, i, uint64(len(k)))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintSpec(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *PointPerEventType) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *PointPerEventType) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *PointPerEventType) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.EventType) > 0 {
+		i -= len(m.EventType)
+		copy(dAtA[i:], m.EventType)
+		i = encodeVarintSpec(dAtA, i, uint64(len(m.EventType)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if m.Bucket != 0 {
+		i = encodeVarintSpec(dAtA, i, uint64(m.Bucket))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.CountUnique != 0 {
+		i = encodeVarintSpec(dAtA, i, uint64(m.CountUnique))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.Count != 0 {
+		i = encodeVarintSpec(dAtA, i, uint64(m.Count))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *ChartBucketPerTime) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ChartBucketPerTime) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ChartBucketPerTime) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.PerType) > 0 {
+		for k := range m.PerType {
+			v := m.PerType[k]
+			baseI := i
+			if v != nil {
+				{
+					size, err := v.MarshalToSizedBuffer(dAtA[:i])
+					if err != nil {
+						return 0, err
+					}
+					i -= size
+					i = encodeVarintSpec(dAtA, i, uint64(size))
+				}
+				i--
+				dAtA[i] = 0x12
+			}
+			i -= len(k)
+			copy(dAtA[i:], k)
+			i = encodeVarintSpec(dAtA, i, uint64(len(k)))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintSpec(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Chart) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Chart) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Chart) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.TimeBucketSec != 0 {
+		i = encodeVarintSpec(dAtA, i, uint64(m.TimeBucketSec))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.TimeEnd != 0 {
+		i = encodeVarintSpec(dAtA, i, uint64(m.TimeEnd))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.TimeStart != 0 {
+		i = encodeVarintSpec(dAtA, i, uint64(m.TimeStart))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Buckets) > 0 {
+		for k := range m.Buckets {
+			v := m.Buckets[k]
+			baseI := i
+			if v != nil {
+				{
+					size, err := v.MarshalToSizedBuffer(dAtA[:i])
+					if err != nil {
+						return 0, err
+					}
+					i -= size
+					i = encodeVarintSpec(dAtA, i, uint64(size))
+				}
+				i--
+				dAtA[i] = 0x12
+			}
+			i = encodeVarintSpec(dAtA, i, uint64(k))
+			i--
+			dAtA[i] = 0x8
+			i = encodeVarintSpec(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *AggregateRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *AggregateRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *AggregateRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.TimeBucketSec != 0 {
+		i = encodeVarintSpec(dAtA, i, uint64(m.TimeBucketSec))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.SampleLimit != 0 {
+		i = encodeVarintSpec(dAtA, i, uint64(m.SampleLimit))
+		i--
+		dAtA[i] = 0x18
+	}
+	if len(m.Fields) > 0 {
+		for k := range m.Fields {
+			v := m.Fields[k]
+			baseI := i
+			i--
+			if v {
+				dAtA[i] = 1
+			} else {
+				dAtA[i] = 0
+			}
+			i--
+			dAtA[i] = 0x10
+			i -= len(k)
+			copy(dAtA[i:], k)
+			i = encodeVarintSpec(dAtA, i, uint64(len(k)))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintSpec(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if m.Query != nil {
+		{
+			size, err := m.Query.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintSpec(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *NumberStats) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *NumberStats) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *NumberStats) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Max != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.Max))))
+		i--
+		dAtA[i] = 0x29
+	}
+	if m.Min != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.Min))))
+		i--
+		dAtA[i] = 0x21
+	}
+	if m.Sum != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.Sum))))
+		i--
+		dAtA[i] = 0x19
+	}
+	if m.Count != 0 {
+		i = encodeVarintSpec(dAtA, i, uint64(m.Count))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Key) > 0 {
+		i -= len(m.Key)
+		copy(dAtA[i:], m.Key)
+		i = encodeVarintSpec(dAtA, i, uint64(len(m.Key)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *ShardFailure) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ShardFailure) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ShardFailure) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Error) > 0 {
+		i -= len(m.Error)
+		copy(dAtA[i:], m.Error)
+		i = encodeVarintSpec(dAtA, i, uint64(len(m.Error)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Shard) > 0 {
+		i -= len(m.Shard)
+		copy(dAtA[i:], m.Shard)
+		i = encodeVarintSpec(dAtA, i, uint64(len(m.Shard)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Aggregate) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Aggregate) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Aggregate) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Numbers) > 0 {
+		for k := range m.Numbers {
+			v := m.Numbers[k]
+			baseI := i
+			if v != nil {
+				{
+					size, err := v.MarshalToSizedBuffer(dAtA[:i])
+					if err != nil {
+						return 0, err
+					}
+					i -= size
+					i = encodeVarintSpec(dAtA, i, uint64(size))
+				}
+				i--
+				dAtA[i] = 0x12
+			}
+			i -= len(k)
+			copy(dAtA[i:], k)
+			i = encodeVarintSpec(dAtA, i, uint64(len(k)))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintSpec(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0x52
+		}
+	}
+	if len(m.FailedShards) > 0 {
+		for iNdEx := len(m.FailedShards) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.FailedShards[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintSpec(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x4a
+		}
+	}
+	if m.Chart != nil {
+		{
+			size, err := m.Chart.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintSpec(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x42
+	}
+	if len(m.Sample) > 0 {
+		for iNdEx := len(m.Sample) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Sample[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintSpec(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x3a
+		}
+	}
+	if m.Total != 0 {
+		i = encodeVarintSpec(dAtA, i, uint64(m.Total))
+		i--
+		dAtA[i] = 0x30
+	}
+	if len(m.Possible) > 0 {
+		for k := range m.Possible {
+			v := m.Possible[k]
+			baseI := i
+			i = encodeVarintSpec(dAtA, i, uint64(v))
+			i--
+			dAtA[i] = 0x10
+			i -= len(k)
+			copy(dAtA[i:], k)
+			i = encodeVarintSpec(dAtA, i, uint64(len(k)))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintSpec(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0x2a
+		}
+	}
+	if len(m.EventType) > 0 {
+		for k := range m.EventType {
+			v := m.EventType[k]
+			baseI := i
+			if v != nil {
+				{
+					size, err := v.MarshalToSizedBuffer(dAtA[:i])
+					if err != nil {
+						return 0, err
+					}
+					i -= size
+					i = encodeVarintSpec(dAtA, i, uint64(size))
+				}
+				i--
+				dAtA[i] = 0x12
+			}
+			i -= len(k)
+			copy(dAtA[i:], k)
+			i = encodeVarintSpec(dAtA, i, uint64(len(k)))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintSpec(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0x22
+		}
+	}
+	if len(m.ForeignId) > 0 {
+		for k := range m.ForeignId {
+			v := m.ForeignId[k]
+			baseI := i
+			if v != nil {
+				{
+					size, err := v.MarshalToSizedBuffer(dAtA[:i])
+					if err != nil {
+						return 0, err
+					}
+					i -= size
+					i = encodeVarintSpec(dAtA, i, uint64(size))
+				}
+				i--
+				dAtA[i] = 0x12
+			}
+			i -= len(k)
+			copy(dAtA[i:], k)
+			i = encodeVarintSpec(dAtA, i, uint64(len(k)))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintSpec(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if len(m.Count) > 0 {
+		for k := range m.Count {
+			v := m.Count[k]
+			baseI := i
+			if v != nil {
+				{
+					size, err := v.MarshalToSizedBuffer(dAtA[:i])
+					if err != nil {
+						return 0, err
+					}
+					i -= size
+					i = encodeVarintSpec(dAtA, i, uint64(size))
+				}
+				i--
+				dAtA[i] = 0x12
+			}
+			i -= len(k)
+			copy(dAtA[i:], k)
+			i = encodeVarintSpec(dAtA, i, uint64(len(k)))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintSpec(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if len(m.Search) > 0 {
+		for k := range m.Search {
+			v := m.Search[k]
+			baseI := i
+			if v != nil {
+				{
+					size, err := v.MarshalToSizedBuffer(dAtA[:i])
+					if err != nil {
+						return 0, err
+					}
+					i -= size
+					i = encodeVarintSpec(dAtA, i, uint64(size))
+				}
+				i--
+				dAtA[i] = 0x12
+			}
+			i -= len(k)
+			copy(dAtA[i:], k)
+			i = encodeVarintSpec(dAtA, i, uint64(len(k)))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintSpec(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *SearchQueryResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SearchQueryResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SearchQueryResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.FailedShards) > 0 {
+		for iNdEx := len(m.FailedShards) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.FailedShards[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintSpec(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x22
+		}
+	}
+	if m.Explain != nil {
+		{
+			size, err := m.Explain.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintSpec(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x1a
+	}
+	if m.Total != 0 {
+		i = encodeVarintSpec(dAtA, i, uint64(m.Total))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Hits) > 0 {
+		for iNdEx := len(m.Hits) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Hits[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintSpec(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Envelope) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Envelope) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Envelope) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Payload) > 0 {
+		i -= len(m.Payload)
+		copy(dAtA[i:], m.Payload)
+		i = encodeVarintSpec(dAtA, i, uint64(len(m.Payload)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.Metadata != nil {
+		{
+			size, err := m.Metadata.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintSpec(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Success) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Success) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Success) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Rejections) > 0 {
+		for iNdEx := len(m.Rejections) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Rejections[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintSpec(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if len(m.Receipts) > 0 {
+		for iNdEx := len(m.Receipts) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Receipts[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintSpec(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if m.Success {
+		i--
+		if m.Success {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Receipt) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Receipt) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Receipt) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Index != 0 {
+		i = encodeVarintSpec(dAtA, i, uint64(m.Index))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.Id != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(m.Id))
+		i--
+		dAtA[i] = 0x19
+	}
+	if m.Offset != 0 {
+		i = encodeVarintSpec(dAtA, i, uint64(m.Offset))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.Partition != 0 {
+		i = encodeVarintSpec(dAtA, i, uint64(m.Partition))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Rejection) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Rejection) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Rejection) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Reason) > 0 {
+		i -= len(m.Reason)
+		copy(dAtA[i:], m.Reason)
+		i = encodeVarintSpec(dAtA, i, uint64(len(m.Reason)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.Index != 0 {
+		i = encodeVarintSpec(dAtA, i, uint64(m.Index))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *HealthRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *HealthRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *HealthRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	return len(dAtA) - i, nil
+}
+
+func (m *SnapshotFile) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SnapshotFile) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SnapshotFile) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Sha256) > 0 {
+		i -= len(m.Sha256)
+		copy(dAtA[i:], m.Sha256)
+		i = encodeVarintSpec(dAtA, i, uint64(len(m.Sha256)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if m.SizeBytes != 0 {
+		i = encodeVarintSpec(dAtA, i, uint64(m.SizeBytes))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Name) > 0 {
+		i -= len(m.Name)
+		copy(dAtA[i:], m.Name)
+		i = encodeVarintSpec(dAtA, i, uint64(len(m.Name)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *SnapshotSegment) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SnapshotSegment) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SnapshotSegment) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Files) > 0 {
+		for iNdEx := len(m.Files) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Files[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintSpec(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if m.StartNs != 0 {
+		i = encodeVarintSpec(dAtA, i, uint64(m.StartNs))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Id) > 0 {
+		i -= len(m.Id)
+		copy(dAtA[i:], m.Id)
+		i = encodeVarintSpec(dAtA, i, uint64(len(m.Id)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *SnapshotManifest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SnapshotManifest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SnapshotManifest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Segments) > 0 {
+		for iNdEx := len(m.Segments) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Segments[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintSpec(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if m.CreatedAtNs != 0 {
+		i = encodeVarintSpec(dAtA, i, uint64(m.CreatedAtNs))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.SegmentStep != 0 {
+		i = encodeVarintSpec(dAtA, i, uint64(m.SegmentStep))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *BackupRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *BackupRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *BackupRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.ToSecond != 0 {
+		i = encodeVarintSpec(dAtA, i, uint64(m.ToSecond))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.FromSecond != 0 {
+		i = encodeVarintSpec(dAtA, i, uint64(m.FromSecond))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Destination) > 0 {
+		i -= len(m.Destination)
+		copy(dAtA[i:], m.Destination)
+		i = encodeVarintSpec(dAtA, i, uint64(len(m.Destination)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *RestoreRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *RestoreRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *RestoreRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Source) > 0 {
+		i -= len(m.Source)
+		copy(dAtA[i:], m.Source)
+		i = encodeVarintSpec(dAtA, i, uint64(len(m.Source)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *ReindexRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ReindexRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ReindexRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.StatusOnly {
+		i--
+		if m.StatusOnly {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x20
+	}
+	if len(m.Whitelist) > 0 {
+		for iNdEx := len(m.Whitelist) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Whitelist[iNdEx])
+			copy(dAtA[i:], m.Whitelist[iNdEx])
+			i = encodeVarintSpec(dAtA, i, uint64(len(m.Whitelist[iNdEx])))
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if m.ToSecond != 0 {
+		i = encodeVarintSpec(dAtA, i, uint64(m.ToSecond))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.FromSecond != 0 {
+		i = encodeVarintSpec(dAtA, i, uint64(m.FromSecond))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *ReindexStatus) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ReindexStatus) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ReindexStatus) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.FinishedAtNs != 0 {
+		i = encodeVarintSpec(dAtA, i, uint64(m.FinishedAtNs))
+		i--
+		dAtA[i] = 0x38
+	}
+	if m.StartedAtNs != 0 {
+		i = encodeVarintSpec(dAtA, i, uint64(m.StartedAtNs))
+		i--
+		dAtA[i] = 0x30
+	}
+	if len(m.Error) > 0 {
+		i -= len(m.Error)
+		copy(dAtA[i:], m.Error)
+		i = encodeVarintSpec(dAtA, i, uint64(len(m.Error)))
+		i--
+		dAtA[i] = 0x2a
+	}
+	if m.SegmentsDone != 0 {
+		i = encodeVarintSpec(dAtA, i, uint64(m.SegmentsDone))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.SegmentsTotal != 0 {
+		i = encodeVarintSpec(dAtA, i, uint64(m.SegmentsTotal))
+		i--
+		dAtA[i] = 0x18
+	}
+	if len(m.Whitelist) > 0 {
+		for iNdEx := len(m.Whitelist) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Whitelist[iNdEx])
+			copy(dAtA[i:], m.Whitelist[iNdEx])
+			i = encodeVarintSpec(dAtA, i, uint64(len(m.Whitelist[iNdEx])))
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if m.Running {
+		i--
+		if m.Running {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *SegmentStats) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SegmentStats) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SegmentStats) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.PartitionOffsets) > 0 {
+		for k := range m.PartitionOffsets {
+			v := m.PartitionOffsets[k]
+			baseI := i
+			i = encodeVarintSpec(dAtA, i, uint64(v))
+			i--
+			dAtA[i] = 0x10
+			i = encodeVarintSpec(dAtA, i, uint64(k))
+			i--
+			dAtA[i] = 0x8
+			i = encodeVarintSpec(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0x62
+		}
+	}
+	if len(m.EventTypes) > 0 {
+		for k := range m.EventTypes {
+			v := m.EventTypes[k]
+			baseI := i
+			i = encodeVarintSpec(dAtA, i, uint64(v))
+			i--
+			dAtA[i] = 0x10
+			i -= len(k)
+			copy(dAtA[i:], k)
+			i = encodeVarintSpec(dAtA, i, uint64(len(k)))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintSpec(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0x5a
+		}
+	}
+	if m.Sealed {
+		i--
+		if m.Sealed {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x50
+	}
+	if m.Terms != 0 {
+		i = encodeVarintSpec(dAtA, i, uint64(m.Terms))
+		i--
+		dAtA[i] = 0x48
+	}
+	if m.InvertedBytes != 0 {
+		i = encodeVarintSpec(dAtA, i, uint64(m.InvertedBytes))
+		i--
+		dAtA[i] = 0x40
+	}
+	if m.ForwardBytes != 0 {
+		i = encodeVarintSpec(dAtA, i, uint64(m.ForwardBytes))
+		i--
+		dAtA[i] = 0x38
+	}
+	if m.MaxCreatedAtNs != 0 {
+		i = encodeVarintSpec(dAtA, i, uint64(m.MaxCreatedAtNs))
+		i--
+		dAtA[i] = 0x30
+	}
+	if m.MinCreatedAtNs != 0 {
+		i = encodeVarintSpec(dAtA, i, uint64(m.MinCreatedAtNs))
+		i--
+		dAtA[i] = 0x28
+	}
+	if m.Docs != 0 {
+		i = encodeVarintSpec(dAtA, i, uint64(m.Docs))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.StartNs != 0 {
+		i = encodeVarintSpec(dAtA, i, uint64(m.StartNs))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.Step != 0 {
+		i = encodeVarintSpec(dAtA, i, uint64(m.Step))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Id) > 0 {
+		i -= len(m.Id)
+		copy(dAtA[i:], m.Id)
+		i = encodeVarintSpec(dAtA, i, uint64(len(m.Id)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *SegmentCatalog) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SegmentCatalog) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SegmentCatalog) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Segments) > 0 {
+		for iNdEx := len(m.Segments) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Segments[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintSpec(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *StatsRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *StatsRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *StatsRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.TopEventTypes != 0 {
+		i = encodeVarintSpec(dAtA, i, uint64(m.TopEventTypes))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.ToSecond != 0 {
+		i = encodeVarintSpec(dAtA, i, uint64(m.ToSecond))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.FromSecond != 0 {
+		i = encodeVarintSpec(dAtA, i, uint64(m.FromSecond))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *StatsResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *StatsResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *StatsResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.PartitionOffsets) > 0 {
+		for k := range m.PartitionOffsets {
+			v := m.PartitionOffsets[k]
+			baseI := i
+			i = encodeVarintSpec(dAtA, i, uint64(v))
+			i--
+			dAtA[i] = 0x10
+			i = encodeVarintSpec(dAtA, i, uint64(k))
+			i--
+			dAtA[i] = 0x8
+			i = encodeVarintSpec(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0x32
+		}
+	}
+	if len(m.FailedShards) > 0 {
+		for iNdEx := len(m.FailedShards) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.FailedShards[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintSpec(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x2a
+		}
+	}
+	if m.InvertedBytes != 0 {
+		i = encodeVarintSpec(dAtA, i, uint64(m.InvertedBytes))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.ForwardBytes != 0 {
+		i = encodeVarintSpec(dAtA, i, uint64(m.ForwardBytes))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.Docs != 0 {
+		i = encodeVarintSpec(dAtA, i, uint64(m.Docs))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Segments) > 0 {
+		for iNdEx := len(m.Segments) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Segments[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintSpec(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *ReplicationRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ReplicationRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ReplicationRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	return len(dAtA) - i, nil
+}
+
+func (m *ReplicaSegment) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ReplicaSegment) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ReplicaSegment) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Sealed {
+		i--
+		if m.Sealed {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.ForwardBytes != 0 {
+		i = encodeVarintSpec(dAtA, i, uint64(m.ForwardBytes))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Id) > 0 {
+		i -= len(m.Id)
+		copy(dAtA[i:], m.Id)
+		i = encodeVarintSpec(dAtA, i, uint64(len(m.Id)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *ReplicationManifest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ReplicationManifest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ReplicationManifest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Whitelist) > 0 {
+		for iNdEx := len(m.Whitelist) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Whitelist[iNdEx])
+			copy(dAtA[i:], m.Whitelist[iNdEx])
+			i = encodeVarintSpec(dAtA, i, uint64(len(m.Whitelist[iNdEx])))
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if len(m.Segments) > 0 {
+		for iNdEx := len(m.Segments) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Segments[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintSpec(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *ShipRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ShipRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ShipRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.MaxBytes != 0 {
+		i = encodeVarintSpec(dAtA, i, uint64(m.MaxBytes))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.Files {
+		i--
+		if m.Files {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.Offset != 0 {
+		i = encodeVarintSpec(dAtA, i, uint64(m.Offset))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.SegmentId) > 0 {
+		i -= len(m.SegmentId)
+		copy(dAtA[i:], m.SegmentId)
+		i = encodeVarintSpec(dAtA, i, uint64(len(m.SegmentId)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *ShipChunk) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ShipChunk) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ShipChunk) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.NextOffset != 0 {
+		i = encodeVarintSpec(dAtA, i, uint64(m.NextOffset))
+		i--
+		dAtA[i] = 0x28
+	}
+	if len(m.Records) > 0 {
+		for iNdEx := len(m.Records) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Records[iNdEx])
+			copy(dAtA[i:], m.Records[iNdEx])
+			i = encodeVarintSpec(dAtA, i, uint64(len(m.Records[iNdEx])))
+			i--
+			dAtA[i] = 0x22
+		}
+	}
+	if len(m.Data) > 0 {
+		i -= len(m.Data)
+		copy(dAtA[i:], m.Data)
+		i = encodeVarintSpec(dAtA, i, uint64(len(m.Data)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if m.Offset != 0 {
+		i = encodeVarintSpec(dAtA, i, uint64(m.Offset))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Name) > 0 {
+		i -= len(m.Name)
+		copy(dAtA[i:], m.Name)
+		i = encodeVarintSpec(dAtA, i, uint64(len(m.Name)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *PromoteRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *PromoteRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *PromoteRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	return len(dAtA) - i, nil
+}
+
+func (m *DeadLetter) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *DeadLetter) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *DeadLetter) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.CreatedAtNs != 0 {
+		i = encodeVarintSpec(dAtA, i, uint64(m.CreatedAtNs))
+		i--
+		dAtA[i] = 0x30
+	}
+	if len(m.Reason) > 0 {
+		i -= len(m.Reason)
+		copy(dAtA[i:], m.Reason)
+		i = encodeVarintSpec(dAtA, i, uint64(len(m.Reason)))
+		i--
+		dAtA[i] = 0x2a
+	}
+	if m.Offset != 0 {
+		i = encodeVarintSpec(dAtA, i, uint64(m.Offset))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.Partition != 0 {
+		i = encodeVarintSpec(dAtA, i, uint64(m.Partition))
+		i--
+		dAtA[i] = 0x18
+	}
+	if len(m.Topic) > 0 {
+		i -= len(m.Topic)
+		copy(dAtA[i:], m.Topic)
+		i = encodeVarintSpec(dAtA, i, uint64(len(m.Topic)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Value) > 0 {
+		i -= len(m.Value)
+		copy(dAtA[i:], m.Value)
+		i = encodeVarintSpec(dAtA, i, uint64(len(m.Value)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *EventSchema) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *EventSchema) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *EventSchema) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.MaxProperties != 0 {
+		i = encodeVarintSpec(dAtA, i, uint64(m.MaxProperties))
+		i--
+		dAtA[i] = 0x38
+	}
+	if m.MaxCount != 0 {
+		i = encodeVarintSpec(dAtA, i, uint64(m.MaxCount))
+		i--
+		dAtA[i] = 0x30
+	}
+	if m.MaxSearch != 0 {
+		i = encodeVarintSpec(dAtA, i, uint64(m.MaxSearch))
+		i--
+		dAtA[i] = 0x28
+	}
+	if len(m.ForeignTypes) > 0 {
+		for iNdEx := len(m.ForeignTypes) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.ForeignTypes[iNdEx])
+			copy(dAtA[i:], m.ForeignTypes[iNdEx])
+			i = encodeVarintSpec(dAtA, i, uint64(len(m.ForeignTypes[iNdEx])))
+			i--
+			dAtA[i] = 0x22
+		}
+	}
+	if len(m.ValueTypes) > 0 {
+		for k := range m.ValueTypes {
+			v := m.ValueTypes[k]
+			baseI := i
+			i -= len(v)
+			copy(dAtA[i:], v)
+			i = encodeVarintSpec(dAtA, i, uint64(len(v)))
+			i--
+			dAtA[i] = 0x12
+			i -= len(k)
+			copy(dAtA[i:], k)
+			i = encodeVarintSpec(dAtA, i, uint64(len(k)))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintSpec(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if len(m.RequiredKeys) > 0 {
+		for iNdEx := len(m.RequiredKeys) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.RequiredKeys[iNdEx])
+			copy(dAtA[i:], m.RequiredKeys[iNdEx])
+			i = encodeVarintSpec(dAtA, i, uint64(len(m.RequiredKeys[iNdEx])))
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if len(m.EventType) > 0 {
+		i -= len(m.EventType)
+		copy(dAtA[i:], m.EventType)
+		i = encodeVarintSpec(dAtA, i, uint64(len(m.EventType)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *SchemaRegistry) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SchemaRegistry) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SchemaRegistry) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.DryRun {
+		i--
+		if m.DryRun {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Schemas) > 0 {
+		for iNdEx := len(m.Schemas) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Schemas[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintSpec(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *SchemaRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SchemaRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SchemaRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	return len(dAtA) - i, nil
+}
+
+func (m *AlertRule) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *AlertRule) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *AlertRule) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Threshold != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.Threshold))))
+		i--
+		dAtA[i] = 0x29
+	}
+	if len(m.Kind) > 0 {
+		i -= len(m.Kind)
+		copy(dAtA[i:], m.Kind)
+		i = encodeVarintSpec(dAtA, i, uint64(len(m.Kind)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if m.WindowSec != 0 {
+		i = encodeVarintSpec(dAtA, i, uint64(m.WindowSec))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.Query != nil {
+		{
+			size, err := m.Query.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintSpec(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Name) > 0 {
+		i -= len(m.Name)
+		copy(dAtA[i:], m.Name)
+		i = encodeVarintSpec(dAtA, i, uint64(len(m.Name)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *AlertRules) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *AlertRules) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *AlertRules) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Rules) > 0 {
+		for iNdEx := len(m.Rules) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Rules[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintSpec(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *AlertState) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *AlertState) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *AlertState) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Error) > 0 {
+		i -= len(m.Error)
+		copy(dAtA[i:], m.Error)
+		i = encodeVarintSpec(dAtA, i, uint64(len(m.Error)))
+		i--
+		dAtA[i] = 0x3a
+	}
+	if m.ChangedAtNs != 0 {
+		i = encodeVarintSpec(dAtA, i, uint64(m.ChangedAtNs))
+		i--
+		dAtA[i] = 0x30
+	}
+	if m.CheckedAtNs != 0 {
+		i = encodeVarintSpec(dAtA, i, uint64(m.CheckedAtNs))
+		i--
+		dAtA[i] = 0x28
+	}
+	if m.Previous != 0 {
+		i = encodeVarintSpec(dAtA, i, uint64(m.Previous))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.Count != 0 {
+		i = encodeVarintSpec(dAtA, i, uint64(m.Count))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.Firing {
+		i--
+		if m.Firing {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.Rule != nil {
+		{
+			size, err := m.Rule.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintSpec(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *AlertEvent) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *AlertEvent) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *AlertEvent) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Message) > 0 {
+		i -= len(m.Message)
+		copy(dAtA[i:], m.Message)
+		i = encodeVarintSpec(dAtA, i, uint64(len(m.Message)))
+		i--
+		dAtA[i] = 0x32
+	}
+	if m.AtNs != 0 {
+		i = encodeVarintSpec(dAtA, i, uint64(m.AtNs))
+		i--
+		dAtA[i] = 0x28
+	}
+	if m.Previous != 0 {
+		i = encodeVarintSpec(dAtA, i, uint64(m.Previous))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.Count != 0 {
+		i = encodeVarintSpec(dAtA, i, uint64(m.Count))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.Firing {
+		i--
+		if m.Firing {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Name) > 0 {
+		i -= len(m.Name)
+		copy(dAtA[i:], m.Name)
+		i = encodeVarintSpec(dAtA, i, uint64(len(m.Name)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *AlertsRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *AlertsRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *AlertsRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Name) > 0 {
+		i -= len(m.Name)
+		copy(dAtA[i:], m.Name)
+		i = encodeVarintSpec(dAtA, i, uint64(len(m.Name)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *AlertsResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *AlertsResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *AlertsResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.History) > 0 {
+		for iNdEx := len(m.History) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.History[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintSpec(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if len(m.States) > 0 {
+		for iNdEx := len(m.States) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.States[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintSpec(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *TailRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TailRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TailRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Query != nil {
+		{
+			size, err := m.Query.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintSpec(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *TailEvent) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TailEvent) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TailEvent) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Dropped != 0 {
+		i = encodeVarintSpec(dAtA, i, uint64(m.Dropped))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.Hit != nil {
+		{
+			size, err := m.Hit.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintSpec(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func encodeVarintSpec(dAtA []byte, offset int, v uint64) int {
+	offset -= sovSpec(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func (m *KV) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Key)
+	if l > 0 {
+		n += 1 + l + sovSpec(uint64(l))
+	}
+	l = len(m.Value)
+	if l > 0 {
+		n += 1 + l + sovSpec(uint64(l))
+	}
+	return n
+}
+
+func (m *KF) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Key)
+	if l > 0 {
+		n += 1 + l + sovSpec(uint64(l))
+	}
+	if m.Value != 0 {
+		n += 9
+	}
+	return n
+}
+
+func (m *Metadata) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Search) > 0 {
+		for _, e := range m.Search {
+			l = e.Size()
+			n += 1 + l + sovSpec(uint64(l))
+		}
+	}
+	if len(m.Count) > 0 {
+		for _, e := range m.Count {
+			l = e.Size()
+			n += 1 + l + sovSpec(uint64(l))
+		}
+	}
+	if len(m.Properties) > 0 {
+		for _, e := range m.Properties {
+			l = e.Size()
+			n += 1 + l + sovSpec(uint64(l))
+		}
+	}
+	if m.CreatedAtNs != 0 {
+		n += 1 + sovSpec(uint64(m.CreatedAtNs))
+	}
+	l = len(m.EventType)
+	if l > 0 {
+		n += 1 + l + sovSpec(uint64(l))
+	}
+	l = len(m.ForeignId)
+	if l > 0 {
+		n += 1 + l + sovSpec(uint64(l))
+	}
+	l = len(m.ForeignType)
+	if l > 0 {
+		n += 1 + l + sovSpec(uint64(l))
+	}
+	if len(m.Track) > 0 {
+		for k, v := range m.Track {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovSpec(uint64(len(k))) + 1 + sovSpec(uint64(v))
+			n += mapEntrySize + 1 + sovSpec(uint64(mapEntrySize))
+		}
+	}
+	if m.Id != 0 {
+		n += 9
+	}
+	if len(m.Numbers) > 0 {
+		for _, e := range m.Numbers {
+			l = e.Size()
+			n += 1 + l + sovSpec(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *SearchableMetadata) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Search) > 0 {
+		for _, e := range m.Search {
+			l = e.Size()
+			n += 1 + l + sovSpec(uint64(l))
+		}
+	}
+	l = len(m.EventType)
+	if l > 0 {
+		n += 1 + l + sovSpec(uint64(l))
+	}
+	l = len(m.ForeignId)
+	if l > 0 {
+		n += 1 + l + sovSpec(uint64(l))
+	}
+	l = len(m.ForeignType)
+	if l > 0 {
+		n += 1 + l + sovSpec(uint64(l))
+	}
+	if len(m.Track) > 0 {
+		for k, v := range m.Track {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovSpec(uint64(len(k))) + 1 + sovSpec(uint64(v))
+			n += mapEntrySize + 1 + sovSpec(uint64(mapEntrySize))
+		}
+	}
+	return n
+}
+
+func (m *BasicMetadata) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.CreatedAtNs != 0 {
+		n += 1 + sovSpec(uint64(m.CreatedAtNs))
+	}
+	l = len(m.EventType)
+	if l > 0 {
+		n += 1 + l + sovSpec(uint64(l))
+	}
+	l = len(m.ForeignId)
+	if l > 0 {
+		n += 1 + l + sovSpec(uint64(l))
+	}
+	l = len(m.ForeignType)
+	if l > 0 {
+		n += 1 + l + sovSpec(uint64(l))
+	}
+	if m.Id != 0 {
+		n += 9
+	}
+	return n
+}
+
+func (m *CountableMetadata) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Search) > 0 {
+		for _, e := range m.Search {
+			l = e.Size()
+			n += 1 + l + sovSpec(uint64(l))
+		}
+	}
+	if len(m.Count) > 0 {
+		for _, e := range m.Count {
+			l = e.Size()
+			n += 1 + l + sovSpec(uint64(l))
+		}
+	}
+	if m.CreatedAtNs != 0 {
+		n += 1 + sovSpec(uint64(m.CreatedAtNs))
+	}
+	l = len(m.EventType)
+	if l > 0 {
+		n += 1 + l + sovSpec(uint64(l))
+	}
+	l = len(m.ForeignId)
+	if l > 0 {
+		n += 1 + l + sovSpec(uint64(l))
+	}
+	l = len(m.ForeignType)
+	if l > 0 {
+		n += 1 + l + sovSpec(uint64(l))
+	}
+	if len(m.Numbers) > 0 {
+		for _, e := range m.Numbers {
+			l = e.Size()
+			n += 1 + l + sovSpec(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *Hit) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Id != 0 {
+		n += 1 + sovSpec(uint64(m.Id))
+	}
+	if m.Score != 0 {
+		n += 5
+	}
+	if m.Metadata != nil {
+		l = m.Metadata.Size()
+		n += 1 + l + sovSpec(uint64(l))
+	}
+	return n
+}
+
+func (m *SearchQueryRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.FromSecond != 0 {
+		n += 1 + sovSpec(uint64(m.FromSecond))
+	}
+	if m.ToSecond != 0 {
+		n += 1 + sovSpec(uint64(m.ToSecond))
+	}
+	if m.Query != nil {
+		l = m.Query.Size()
+		n += 1 + l + sovSpec(uint64(l))
+	}
+	if m.Limit != 0 {
+		n += 1 + sovSpec(uint64(m.Limit))
+	}
+	if m.Explain {
+		n += 2
+	}
+	return n
+}
+
+func (m *SegmentPlan) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.SegmentId)
+	if l > 0 {
+		n += 1 + l + sovSpec(uint64(l))
+	}
+	l = len(m.Plan)
+	if l > 0 {
+		n += 1 + l + sovSpec(uint64(l))
+	}
+	if m.Estimated != 0 {
+		n += 1 + sovSpec(uint64(m.Estimated))
+	}
+	if m.Skipped {
+		n += 2
+	}
+	l = len(m.SkipReason)
+	if l > 0 {
+		n += 1 + l + sovSpec(uint64(l))
+	}
+	if m.Matched != 0 {
+		n += 1 + sovSpec(uint64(m.Matched))
+	}
+	if m.TookNs != 0 {
+		n += 1 + sovSpec(uint64(m.TookNs))
+	}
+	return n
+}
+
+func (m *QueryExplain) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Segments) > 0 {
+		for _, e := range m.Segments {
+			l = e.Size()
+			n += 1 + l + sovSpec(uint64(l))
+		}
+	}
+	if m.TookNs != 0 {
+		n += 1 + sovSpec(uint64(m.TookNs))
+	}
+	return n
+}
+
+func (m *CountPerKV) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Count) > 0 {
+		for k, v := range m.Count {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovSpec(uint64(len(k))) + 1 + sovSpec(uint64(v))
+			n += mapEntrySize + 1 + sovSpec(uint64(mapEntrySize))
+		}
+	}
+	if m.Total != 0 {
+		n += 1 + sovSpec(uint64(m.Total))
+	}
+	l = len(m.Key)
+	if l > 0 {
+		n += 1 + l + sovSpec(uint64(l))
+	}
+	return n
+}
+
+func (m *PointPerEventType) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Count != 0 {
+		n += 1 + sovSpec(uint64(m.Count))
+	}
+	if m.CountUnique != 0 {
+		n += 1 + sovSpec(uint64(m.CountUnique))
+	}
+	if m.Bucket != 0 {
+		n += 1 + sovSpec(uint64(m.Bucket))
+	}
+	l = len(m.EventType)
+	if l > 0 {
+		n += 1 + l + sovSpec(uint64(l))
+	}
+	return n
+}
+
+func (m *ChartBucketPerTime) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.PerType) > 0 {
+		for k, v := range m.PerType {
+			_ = k
+			_ = v
+			l = 0
+			if v != nil {
+				l = v.Size()
+				l += 1 + sovSpec(uint64(l))
+			}
+			mapEntrySize := 1 + len(k) + sovSpec(uint64(len(k))) + l
+			n += mapEntrySize + 1 + sovSpec(uint64(mapEntrySize))
+		}
+	}
+	return n
+}
+
+func (m *Chart) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Buckets) > 0 {
+		for k, v := range m.Buckets {
+			_ = k
+			_ = v
+			l = 0
+			if v != nil {
+				l = v.Size()
+				l += 1 + sovSpec(uint64(l))
+			}
+			mapEntrySize := 1 + sovSpec(uint64(k)) + l
+			n += mapEntrySize + 1 + sovSpec(uint64(mapEntrySize))
+		}
+	}
+	if m.TimeStart != 0 {
+		n += 1 + sovSpec(uint64(m.TimeStart))
+	}
+	if m.TimeEnd != 0 {
+		n += 1 + sovSpec(uint64(m.TimeEnd))
+	}
+	if m.TimeBucketSec != 0 {
+		n += 1 + sovSpec(uint64(m.TimeBucketSec))
+	}
+	return n
+}
+
+func (m *AggregateRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Query != nil {
+		l = m.Query.Size()
+		n += 1 + l + sovSpec(uint64(l))
+	}
+	if len(m.Fields) > 0 {
+		for k, v := range m.Fields {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovSpec(uint64(len(k))) + 1 + 1
+			n += mapEntrySize + 1 + sovSpec(uint64(mapEntrySize))
+		}
+	}
+	if m.SampleLimit != 0 {
+		n += 1 + sovSpec(uint64(m.SampleLimit))
+	}
+	if m.TimeBucketSec != 0 {
+		n += 1 + sovSpec(uint64(m.TimeBucketSec))
+	}
+	return n
+}
+
+func (m *NumberStats) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Key)
+	if l > 0 {
+		n += 1 + l + sovSpec(uint64(l))
+	}
+	if m.Count != 0 {
+		n += 1 + sovSpec(uint64(m.Count))
+	}
+	if m.Sum != 0 {
+		n += 9
+	}
+	if m.Min != 0 {
+		n += 9
+	}
+	if m.Max != 0 {
+		n += 9
+	}
+	return n
+}
+
+func (m *ShardFailure) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Shard)
+	if l > 0 {
+		n += 1 + l + sovSpec(uint64(l))
+	}
+	l = len(m.Error)
+	if l > 0 {
+		n += 1 + l + sovSpec(uint64(l))
+	}
+	return n
+}
+
+func (m *Aggregate) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Search) > 0 {
 		for k, v := range m.Search {
 			_ = k
 			_ = v
-			l = 0
-			if v != nil {
-				l = v.Size()
-				l += 1 + sovSpec(uint64(l))
+			l = 0
+			if v != nil {
+				l = v.Size()
+				l += 1 + sovSpec(uint64(l))
+			}
+			mapEntrySize := 1 + len(k) + sovSpec(uint64(len(k))) + l
+			n += mapEntrySize + 1 + sovSpec(uint64(mapEntrySize))
+		}
+	}
+	if len(m.Count) > 0 {
+		for k, v := range m.Count {
+			_ = k
+			_ = v
+			l = 0
+			if v != nil {
+				l = v.Size()
+				l += 1 + sovSpec(uint64(l))
+			}
+			mapEntrySize := 1 + len(k) + sovSpec(uint64(len(k))) + l
+			n += mapEntrySize + 1 + sovSpec(uint64(mapEntrySize))
+		}
+	}
+	if len(m.ForeignId) > 0 {
+		for k, v := range m.ForeignId {
+			_ = k
+			_ = v
+			l = 0
+			if v != nil {
+				l = v.Size()
+				l += 1 + sovSpec(uint64(l))
+			}
+			mapEntrySize := 1 + len(k) + sovSpec(uint64(len(k))) + l
+			n += mapEntrySize + 1 + sovSpec(uint64(mapEntrySize))
+		}
+	}
+	if len(m.EventType) > 0 {
+		for k, v := range m.EventType {
+			_ = k
+			_ = v
+			l = 0
+			if v != nil {
+				l = v.Size()
+				l += 1 + sovSpec(uint64(l))
+			}
+			mapEntrySize := 1 + len(k) + sovSpec(uint64(len(k))) + l
+			n += mapEntrySize + 1 + sovSpec(uint64(mapEntrySize))
+		}
+	}
+	if len(m.Possible) > 0 {
+		for k, v := range m.Possible {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovSpec(uint64(len(k))) + 1 + sovSpec(uint64(v))
+			n += mapEntrySize + 1 + sovSpec(uint64(mapEntrySize))
+		}
+	}
+	if m.Total != 0 {
+		n += 1 + sovSpec(uint64(m.Total))
+	}
+	if len(m.Sample) > 0 {
+		for _, e := range m.Sample {
+			l = e.Size()
+			n += 1 + l + sovSpec(uint64(l))
+		}
+	}
+	if m.Chart != nil {
+		l = m.Chart.Size()
+		n += 1 + l + sovSpec(uint64(l))
+	}
+	if len(m.FailedShards) > 0 {
+		for _, e := range m.FailedShards {
+			l = e.Size()
+			n += 1 + l + sovSpec(uint64(l))
+		}
+	}
+	if len(m.Numbers) > 0 {
+		for k, v := range m.Numbers {
+			_ = k
+			_ = v
+			l = 0
+			if v != nil {
+				l = v.Size()
+				l += 1 + sovSpec(uint64(l))
+			}
+			mapEntrySize := 1 + len(k) + sovSpec(uint64(len(k))) + l
+			n += mapEntrySize + 1 + sovSpec(uint64(mapEntrySize))
+		}
+	}
+	return n
+}
+
+func (m *SearchQueryResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Hits) > 0 {
+		for _, e := range m.Hits {
+			l = e.Size()
+			n += 1 + l + sovSpec(uint64(l))
+		}
+	}
+	if m.Total != 0 {
+		n += 1 + sovSpec(uint64(m.Total))
+	}
+	if m.Explain != nil {
+		l = m.Explain.Size()
+		n += 1 + l + sovSpec(uint64(l))
+	}
+	if len(m.FailedShards) > 0 {
+		for _, e := range m.FailedShards {
+			l = e.Size()
+			n += 1 + l + sovSpec(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *Envelope) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Metadata != nil {
+		l = m.Metadata.Size()
+		n += 1 + l + sovSpec(uint64(l))
+	}
+	l = len(m.Payload)
+	if l > 0 {
+		n += 1 + l + sovSpec(uint64(l))
+	}
+	return n
+}
+
+func (m *Success) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Success {
+		n += 2
+	}
+	if len(m.Receipts) > 0 {
+		for _, e := range m.Receipts {
+			l = e.Size()
+			n += 1 + l + sovSpec(uint64(l))
+		}
+	}
+	if len(m.Rejections) > 0 {
+		for _, e := range m.Rejections {
+			l = e.Size()
+			n += 1 + l + sovSpec(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *Receipt) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Partition != 0 {
+		n += 1 + sovSpec(uint64(m.Partition))
+	}
+	if m.Offset != 0 {
+		n += 1 + sovSpec(uint64(m.Offset))
+	}
+	if m.Id != 0 {
+		n += 9
+	}
+	if m.Index != 0 {
+		n += 1 + sovSpec(uint64(m.Index))
+	}
+	return n
+}
+
+func (m *Rejection) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Index != 0 {
+		n += 1 + sovSpec(uint64(m.Index))
+	}
+	l = len(m.Reason)
+	if l > 0 {
+		n += 1 + l + sovSpec(uint64(l))
+	}
+	return n
+}
+
+func (m *HealthRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	return n
+}
+
+func (m *SnapshotFile) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovSpec(uint64(l))
+	}
+	if m.SizeBytes != 0 {
+		n += 1 + sovSpec(uint64(m.SizeBytes))
+	}
+	l = len(m.Sha256)
+	if l > 0 {
+		n += 1 + l + sovSpec(uint64(l))
+	}
+	return n
+}
+
+func (m *SnapshotSegment) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Id)
+	if l > 0 {
+		n += 1 + l + sovSpec(uint64(l))
+	}
+	if m.StartNs != 0 {
+		n += 1 + sovSpec(uint64(m.StartNs))
+	}
+	if len(m.Files) > 0 {
+		for _, e := range m.Files {
+			l = e.Size()
+			n += 1 + l + sovSpec(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *SnapshotManifest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.SegmentStep != 0 {
+		n += 1 + sovSpec(uint64(m.SegmentStep))
+	}
+	if m.CreatedAtNs != 0 {
+		n += 1 + sovSpec(uint64(m.CreatedAtNs))
+	}
+	if len(m.Segments) > 0 {
+		for _, e := range m.Segments {
+			l = e.Size()
+			n += 1 + l + sovSpec(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *BackupRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Destination)
+	if l > 0 {
+		n += 1 + l + sovSpec(uint64(l))
+	}
+	if m.FromSecond != 0 {
+		n += 1 + sovSpec(uint64(m.FromSecond))
+	}
+	if m.ToSecond != 0 {
+		n += 1 + sovSpec(uint64(m.ToSecond))
+	}
+	return n
+}
+
+func (m *RestoreRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Source)
+	if l > 0 {
+		n += 1 + l + sovSpec(uint64(l))
+	}
+	return n
+}
+
+func (m *ReindexRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.FromSecond != 0 {
+		n += 1 + sovSpec(uint64(m.FromSecond))
+	}
+	if m.ToSecond != 0 {
+		n += 1 + sovSpec(uint64(m.ToSecond))
+	}
+	if len(m.Whitelist) > 0 {
+		for _, s := range m.Whitelist {
+			l = len(s)
+			n += 1 + l + sovSpec(uint64(l))
+		}
+	}
+	if m.StatusOnly {
+		n += 2
+	}
+	return n
+}
+
+func (m *ReindexStatus) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Running {
+		n += 2
+	}
+	if len(m.Whitelist) > 0 {
+		for _, s := range m.Whitelist {
+			l = len(s)
+			n += 1 + l + sovSpec(uint64(l))
+		}
+	}
+	if m.SegmentsTotal != 0 {
+		n += 1 + sovSpec(uint64(m.SegmentsTotal))
+	}
+	if m.SegmentsDone != 0 {
+		n += 1 + sovSpec(uint64(m.SegmentsDone))
+	}
+	l = len(m.Error)
+	if l > 0 {
+		n += 1 + l + sovSpec(uint64(l))
+	}
+	if m.StartedAtNs != 0 {
+		n += 1 + sovSpec(uint64(m.StartedAtNs))
+	}
+	if m.FinishedAtNs != 0 {
+		n += 1 + sovSpec(uint64(m.FinishedAtNs))
+	}
+	return n
+}
+
+func (m *SegmentStats) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Id)
+	if l > 0 {
+		n += 1 + l + sovSpec(uint64(l))
+	}
+	if m.Step != 0 {
+		n += 1 + sovSpec(uint64(m.Step))
+	}
+	if m.StartNs != 0 {
+		n += 1 + sovSpec(uint64(m.StartNs))
+	}
+	if m.Docs != 0 {
+		n += 1 + sovSpec(uint64(m.Docs))
+	}
+	if m.MinCreatedAtNs != 0 {
+		n += 1 + sovSpec(uint64(m.MinCreatedAtNs))
+	}
+	if m.MaxCreatedAtNs != 0 {
+		n += 1 + sovSpec(uint64(m.MaxCreatedAtNs))
+	}
+	if m.ForwardBytes != 0 {
+		n += 1 + sovSpec(uint64(m.ForwardBytes))
+	}
+	if m.InvertedBytes != 0 {
+		n += 1 + sovSpec(uint64(m.InvertedBytes))
+	}
+	if m.Terms != 0 {
+		n += 1 + sovSpec(uint64(m.Terms))
+	}
+	if m.Sealed {
+		n += 2
+	}
+	if len(m.EventTypes) > 0 {
+		for k, v := range m.EventTypes {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovSpec(uint64(len(k))) + 1 + sovSpec(uint64(v))
+			n += mapEntrySize + 1 + sovSpec(uint64(mapEntrySize))
+		}
+	}
+	if len(m.PartitionOffsets) > 0 {
+		for k, v := range m.PartitionOffsets {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + sovSpec(uint64(k)) + 1 + sovSpec(uint64(v))
+			n += mapEntrySize + 1 + sovSpec(uint64(mapEntrySize))
+		}
+	}
+	return n
+}
+
+func (m *SegmentCatalog) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Segments) > 0 {
+		for _, e := range m.Segments {
+			l = e.Size()
+			n += 1 + l + sovSpec(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *StatsRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.FromSecond != 0 {
+		n += 1 + sovSpec(uint64(m.FromSecond))
+	}
+	if m.ToSecond != 0 {
+		n += 1 + sovSpec(uint64(m.ToSecond))
+	}
+	if m.TopEventTypes != 0 {
+		n += 1 + sovSpec(uint64(m.TopEventTypes))
+	}
+	return n
+}
+
+func (m *StatsResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Segments) > 0 {
+		for _, e := range m.Segments {
+			l = e.Size()
+			n += 1 + l + sovSpec(uint64(l))
+		}
+	}
+	if m.Docs != 0 {
+		n += 1 + sovSpec(uint64(m.Docs))
+	}
+	if m.ForwardBytes != 0 {
+		n += 1 + sovSpec(uint64(m.ForwardBytes))
+	}
+	if m.InvertedBytes != 0 {
+		n += 1 + sovSpec(uint64(m.InvertedBytes))
+	}
+	if len(m.FailedShards) > 0 {
+		for _, e := range m.FailedShards {
+			l = e.Size()
+			n += 1 + l + sovSpec(uint64(l))
+		}
+	}
+	if len(m.PartitionOffsets) > 0 {
+		for k, v := range m.PartitionOffsets {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + sovSpec(uint64(k)) + 1 + sovSpec(uint64(v))
+			n += mapEntrySize + 1 + sovSpec(uint64(mapEntrySize))
+		}
+	}
+	return n
+}
+
+func (m *ReplicationRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	return n
+}
+
+func (m *ReplicaSegment) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Id)
+	if l > 0 {
+		n += 1 + l + sovSpec(uint64(l))
+	}
+	if m.ForwardBytes != 0 {
+		n += 1 + sovSpec(uint64(m.ForwardBytes))
+	}
+	if m.Sealed {
+		n += 2
+	}
+	return n
+}
+
+func (m *ReplicationManifest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Segments) > 0 {
+		for _, e := range m.Segments {
+			l = e.Size()
+			n += 1 + l + sovSpec(uint64(l))
+		}
+	}
+	if len(m.Whitelist) > 0 {
+		for _, s := range m.Whitelist {
+			l = len(s)
+			n += 1 + l + sovSpec(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *ShipRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.SegmentId)
+	if l > 0 {
+		n += 1 + l + sovSpec(uint64(l))
+	}
+	if m.Offset != 0 {
+		n += 1 + sovSpec(uint64(m.Offset))
+	}
+	if m.Files {
+		n += 2
+	}
+	if m.MaxBytes != 0 {
+		n += 1 + sovSpec(uint64(m.MaxBytes))
+	}
+	return n
+}
+
+func (m *ShipChunk) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovSpec(uint64(l))
+	}
+	if m.Offset != 0 {
+		n += 1 + sovSpec(uint64(m.Offset))
+	}
+	l = len(m.Data)
+	if l > 0 {
+		n += 1 + l + sovSpec(uint64(l))
+	}
+	if len(m.Records) > 0 {
+		for _, b := range m.Records {
+			l = len(b)
+			n += 1 + l + sovSpec(uint64(l))
+		}
+	}
+	if m.NextOffset != 0 {
+		n += 1 + sovSpec(uint64(m.NextOffset))
+	}
+	return n
+}
+
+func (m *PromoteRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	return n
+}
+
+func (m *DeadLetter) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Value)
+	if l > 0 {
+		n += 1 + l + sovSpec(uint64(l))
+	}
+	l = len(m.Topic)
+	if l > 0 {
+		n += 1 + l + sovSpec(uint64(l))
+	}
+	if m.Partition != 0 {
+		n += 1 + sovSpec(uint64(m.Partition))
+	}
+	if m.Offset != 0 {
+		n += 1 + sovSpec(uint64(m.Offset))
+	}
+	l = len(m.Reason)
+	if l > 0 {
+		n += 1 + l + sovSpec(uint64(l))
+	}
+	if m.CreatedAtNs != 0 {
+		n += 1 + sovSpec(uint64(m.CreatedAtNs))
+	}
+	return n
+}
+
+func (m *EventSchema) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.EventType)
+	if l > 0 {
+		n += 1 + l + sovSpec(uint64(l))
+	}
+	if len(m.RequiredKeys) > 0 {
+		for _, s := range m.RequiredKeys {
+			l = len(s)
+			n += 1 + l + sovSpec(uint64(l))
+		}
+	}
+	if len(m.ValueTypes) > 0 {
+		for k, v := range m.ValueTypes {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovSpec(uint64(len(k))) + 1 + len(v) + sovSpec(uint64(len(v)))
+			n += mapEntrySize + 1 + sovSpec(uint64(mapEntrySize))
+		}
+	}
+	if len(m.ForeignTypes) > 0 {
+		for _, s := range m.ForeignTypes {
+			l = len(s)
+			n += 1 + l + sovSpec(uint64(l))
+		}
+	}
+	if m.MaxSearch != 0 {
+		n += 1 + sovSpec(uint64(m.MaxSearch))
+	}
+	if m.MaxCount != 0 {
+		n += 1 + sovSpec(uint64(m.MaxCount))
+	}
+	if m.MaxProperties != 0 {
+		n += 1 + sovSpec(uint64(m.MaxProperties))
+	}
+	return n
+}
+
+func (m *SchemaRegistry) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Schemas) > 0 {
+		for _, e := range m.Schemas {
+			l = e.Size()
+			n += 1 + l + sovSpec(uint64(l))
+		}
+	}
+	if m.DryRun {
+		n += 2
+	}
+	return n
+}
+
+func (m *SchemaRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	return n
+}
+
+func (m *AlertRule) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovSpec(uint64(l))
+	}
+	if m.Query != nil {
+		l = m.Query.Size()
+		n += 1 + l + sovSpec(uint64(l))
+	}
+	if m.WindowSec != 0 {
+		n += 1 + sovSpec(uint64(m.WindowSec))
+	}
+	l = len(m.Kind)
+	if l > 0 {
+		n += 1 + l + sovSpec(uint64(l))
+	}
+	if m.Threshold != 0 {
+		n += 9
+	}
+	return n
+}
+
+func (m *AlertRules) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Rules) > 0 {
+		for _, e := range m.Rules {
+			l = e.Size()
+			n += 1 + l + sovSpec(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *AlertState) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Rule != nil {
+		l = m.Rule.Size()
+		n += 1 + l + sovSpec(uint64(l))
+	}
+	if m.Firing {
+		n += 2
+	}
+	if m.Count != 0 {
+		n += 1 + sovSpec(uint64(m.Count))
+	}
+	if m.Previous != 0 {
+		n += 1 + sovSpec(uint64(m.Previous))
+	}
+	if m.CheckedAtNs != 0 {
+		n += 1 + sovSpec(uint64(m.CheckedAtNs))
+	}
+	if m.ChangedAtNs != 0 {
+		n += 1 + sovSpec(uint64(m.ChangedAtNs))
+	}
+	l = len(m.Error)
+	if l > 0 {
+		n += 1 + l + sovSpec(uint64(l))
+	}
+	return n
+}
+
+func (m *AlertEvent) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovSpec(uint64(l))
+	}
+	if m.Firing {
+		n += 2
+	}
+	if m.Count != 0 {
+		n += 1 + sovSpec(uint64(m.Count))
+	}
+	if m.Previous != 0 {
+		n += 1 + sovSpec(uint64(m.Previous))
+	}
+	if m.AtNs != 0 {
+		n += 1 + sovSpec(uint64(m.AtNs))
+	}
+	l = len(m.Message)
+	if l > 0 {
+		n += 1 + l + sovSpec(uint64(l))
+	}
+	return n
+}
+
+func (m *AlertsRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovSpec(uint64(l))
+	}
+	return n
+}
+
+func (m *AlertsResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.States) > 0 {
+		for _, e := range m.States {
+			l = e.Size()
+			n += 1 + l + sovSpec(uint64(l))
+		}
+	}
+	if len(m.History) > 0 {
+		for _, e := range m.History {
+			l = e.Size()
+			n += 1 + l + sovSpec(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *TailRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Query != nil {
+		l = m.Query.Size()
+		n += 1 + l + sovSpec(uint64(l))
+	}
+	return n
+}
+
+func (m *TailEvent) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Hit != nil {
+		l = m.Hit.Size()
+		n += 1 + l + sovSpec(uint64(l))
+	}
+	if m.Dropped != 0 {
+		n += 1 + sovSpec(uint64(m.Dropped))
+	}
+	return n
+}
+
+func sovSpec(x uint64) (n int) {
+	return (math_bits.Len64(x|1) + 6) / 7
+}
+func sozSpec(x uint64) (n int) {
+	return sovSpec(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *KV) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSpec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: KV: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: KV: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Key", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Key = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Value = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSpec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *KF) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSpec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: KF: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: KF: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Key", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Key = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.Value = float64(math.Float64frombits(v))
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSpec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Metadata) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSpec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Metadata: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Metadata: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Search", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Search = append(m.Search, KV{})
+			if err := m.Search[len(m.Search)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Count", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Count = append(m.Count, KV{})
+			if err := m.Count[len(m.Count)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Properties", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Properties = append(m.Properties, KV{})
+			if err := m.Properties[len(m.Properties)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CreatedAtNs", wireType)
+			}
+			m.CreatedAtNs = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.CreatedAtNs |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EventType", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.EventType = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ForeignId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ForeignId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ForeignType", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ForeignType = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Track", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Track == nil {
+				m.Track = make(map[string]uint32)
+			}
+			var mapkey string
+			var mapvalue uint32
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowSpec
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowSpec
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthSpec
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthSpec
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowSpec
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						mapvalue |= uint32(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipSpec(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if skippy < 0 {
+						return ErrInvalidLengthSpec
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.Track[mapkey] = mapvalue
+			iNdEx = postIndex
+		case 12:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			m.Id = 0
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Id = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+		case 13:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Numbers", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Numbers = append(m.Numbers, KF{})
+			if err := m.Numbers[len(m.Numbers)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSpec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SearchableMetadata) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSpec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SearchableMetadata: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SearchableMetadata: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Search", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Search = append(m.Search, KV{})
+			if err := m.Search[len(m.Search)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EventType", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.EventType = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ForeignId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ForeignId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ForeignType", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ForeignType = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Track", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Track == nil {
+				m.Track = make(map[string]uint32)
+			}
+			var mapkey string
+			var mapvalue uint32
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowSpec
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowSpec
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthSpec
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthSpec
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowSpec
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						mapvalue |= uint32(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipSpec(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if skippy < 0 {
+						return ErrInvalidLengthSpec
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.Track[mapkey] = mapvalue
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSpec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *BasicMetadata) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSpec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: BasicMetadata: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: BasicMetadata: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CreatedAtNs", wireType)
+			}
+			m.CreatedAtNs = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.CreatedAtNs |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EventType", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.EventType = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ForeignId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ForeignId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ForeignType", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ForeignType = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 12:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			m.Id = 0
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Id = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSpec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *CountableMetadata) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSpec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: CountableMetadata: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: CountableMetadata: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Search", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Search = append(m.Search, KV{})
+			if err := m.Search[len(m.Search)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Count", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Count = append(m.Count, KV{})
+			if err := m.Count[len(m.Count)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CreatedAtNs", wireType)
+			}
+			m.CreatedAtNs = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.CreatedAtNs |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EventType", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.EventType = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ForeignId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ForeignId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ForeignType", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ForeignType = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 13:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Numbers", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Numbers = append(m.Numbers, KF{})
+			if err := m.Numbers[len(m.Numbers)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSpec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Hit) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSpec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Hit: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Hit: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			m.Id = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Id |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 5 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Score", wireType)
+			}
+			var v uint32
+			if (iNdEx + 4) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint32(encoding_binary.LittleEndian.Uint32(dAtA[iNdEx:]))
+			iNdEx += 4
+			m.Score = float32(math.Float32frombits(v))
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Metadata", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Metadata == nil {
+				m.Metadata = &Metadata{}
+			}
+			if err := m.Metadata.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSpec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SearchQueryRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSpec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SearchQueryRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SearchQueryRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FromSecond", wireType)
+			}
+			m.FromSecond = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.FromSecond |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ToSecond", wireType)
+			}
+			m.ToSecond = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ToSecond |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Query", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Query == nil {
+				m.Query = &go_query_dsl.Query{}
+			}
+			if err := m.Query.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Limit", wireType)
+			}
+			m.Limit = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Limit |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Explain", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Explain = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSpec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SegmentPlan) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSpec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SegmentPlan: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SegmentPlan: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SegmentId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.SegmentId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Plan", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Plan = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Estimated", wireType)
+			}
+			m.Estimated = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Estimated |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Skipped", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Skipped = bool(v != 0)
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SkipReason", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.SkipReason = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Matched", wireType)
+			}
+			m.Matched = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Matched |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TookNs", wireType)
+			}
+			m.TookNs = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.TookNs |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSpec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *QueryExplain) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSpec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: QueryExplain: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: QueryExplain: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Segments", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Segments = append(m.Segments, &SegmentPlan{})
+			if err := m.Segments[len(m.Segments)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TookNs", wireType)
+			}
+			m.TookNs = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.TookNs |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSpec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *CountPerKV) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSpec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: CountPerKV: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: CountPerKV: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Count", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Count == nil {
+				m.Count = make(map[string]uint32)
+			}
+			var mapkey string
+			var mapvalue uint32
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowSpec
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowSpec
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthSpec
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthSpec
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowSpec
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						mapvalue |= uint32(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipSpec(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if skippy < 0 {
+						return ErrInvalidLengthSpec
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.Count[mapkey] = mapvalue
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Total", wireType)
+			}
+			m.Total = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Total |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Key", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Key = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSpec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *PointPerEventType) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSpec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: PointPerEventType: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: PointPerEventType: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Count", wireType)
+			}
+			m.Count = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Count |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CountUnique", wireType)
+			}
+			m.CountUnique = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.CountUnique |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Bucket", wireType)
+			}
+			m.Bucket = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Bucket |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EventType", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.EventType = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSpec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ChartBucketPerTime) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSpec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ChartBucketPerTime: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ChartBucketPerTime: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PerType", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.PerType == nil {
+				m.PerType = make(map[string]*PointPerEventType)
+			}
+			var mapkey string
+			var mapvalue *PointPerEventType
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowSpec
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowSpec
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthSpec
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthSpec
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var mapmsglen int
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowSpec
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						mapmsglen |= int(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					if mapmsglen < 0 {
+						return ErrInvalidLengthSpec
+					}
+					postmsgIndex := iNdEx + mapmsglen
+					if postmsgIndex < 0 {
+						return ErrInvalidLengthSpec
+					}
+					if postmsgIndex > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = &PointPerEventType{}
+					if err := mapvalue.Unmarshal(dAtA[iNdEx:postmsgIndex]); err != nil {
+						return err
+					}
+					iNdEx = postmsgIndex
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipSpec(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if skippy < 0 {
+						return ErrInvalidLengthSpec
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.PerType[mapkey] = mapvalue
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSpec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Chart) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSpec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Chart: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Chart: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Buckets", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Buckets == nil {
+				m.Buckets = make(map[uint32]*ChartBucketPerTime)
+			}
+			var mapkey uint32
+			var mapvalue *ChartBucketPerTime
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowSpec
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowSpec
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						mapkey |= uint32(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+				} else if fieldNum == 2 {
+					var mapmsglen int
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowSpec
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						mapmsglen |= int(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					if mapmsglen < 0 {
+						return ErrInvalidLengthSpec
+					}
+					postmsgIndex := iNdEx + mapmsglen
+					if postmsgIndex < 0 {
+						return ErrInvalidLengthSpec
+					}
+					if postmsgIndex > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = &ChartBucketPerTime{}
+					if err := mapvalue.Unmarshal(dAtA[iNdEx:postmsgIndex]); err != nil {
+						return err
+					}
+					iNdEx = postmsgIndex
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipSpec(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if skippy < 0 {
+						return ErrInvalidLengthSpec
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.Buckets[mapkey] = mapvalue
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TimeStart", wireType)
+			}
+			m.TimeStart = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.TimeStart |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TimeEnd", wireType)
+			}
+			m.TimeEnd = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.TimeEnd |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TimeBucketSec", wireType)
+			}
+			m.TimeBucketSec = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.TimeBucketSec |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSpec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *AggregateRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSpec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: AggregateRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: AggregateRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Query", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Query == nil {
+				m.Query = &SearchQueryRequest{}
+			}
+			if err := m.Query.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Fields", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Fields == nil {
+				m.Fields = make(map[string]bool)
+			}
+			var mapkey string
+			var mapvalue bool
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowSpec
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowSpec
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthSpec
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthSpec
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var mapvaluetemp int
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowSpec
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						mapvaluetemp |= int(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					mapvalue = bool(mapvaluetemp != 0)
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipSpec(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if skippy < 0 {
+						return ErrInvalidLengthSpec
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.Fields[mapkey] = mapvalue
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SampleLimit", wireType)
+			}
+			m.SampleLimit = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SampleLimit |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TimeBucketSec", wireType)
+			}
+			m.TimeBucketSec = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.TimeBucketSec |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSpec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *NumberStats) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSpec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: NumberStats: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: NumberStats: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Key", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Key = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Count", wireType)
+			}
+			m.Count = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Count |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Sum", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.Sum = float64(math.Float64frombits(v))
+		case 4:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Min", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.Min = float64(math.Float64frombits(v))
+		case 5:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Max", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.Max = float64(math.Float64frombits(v))
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSpec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ShardFailure) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSpec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ShardFailure: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ShardFailure: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Shard", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Shard = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Error", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Error = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSpec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Aggregate) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSpec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Aggregate: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Aggregate: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Search", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Search == nil {
+				m.Search = make(map[string]*CountPerKV)
+			}
+			var mapkey string
+			var mapvalue *CountPerKV
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowSpec
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowSpec
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthSpec
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthSpec
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var mapmsglen int
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowSpec
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						mapmsglen |= int(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					if mapmsglen < 0 {
+						return ErrInvalidLengthSpec
+					}
+					postmsgIndex := iNdEx + mapmsglen
+					if postmsgIndex < 0 {
+						return ErrInvalidLengthSpec
+					}
+					if postmsgIndex > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = &CountPerKV{}
+					if err := mapvalue.Unmarshal(dAtA[iNdEx:postmsgIndex]); err != nil {
+						return err
+					}
+					iNdEx = postmsgIndex
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipSpec(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if skippy < 0 {
+						return ErrInvalidLengthSpec
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.Search[mapkey] = mapvalue
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Count", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Count == nil {
+				m.Count = make(map[string]*CountPerKV)
+			}
+			var mapkey string
+			var mapvalue *CountPerKV
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowSpec
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowSpec
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthSpec
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthSpec
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var mapmsglen int
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowSpec
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						mapmsglen |= int(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					if mapmsglen < 0 {
+						return ErrInvalidLengthSpec
+					}
+					postmsgIndex := iNdEx + mapmsglen
+					if postmsgIndex < 0 {
+						return ErrInvalidLengthSpec
+					}
+					if postmsgIndex > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = &CountPerKV{}
+					if err := mapvalue.Unmarshal(dAtA[iNdEx:postmsgIndex]); err != nil {
+						return err
+					}
+					iNdEx = postmsgIndex
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipSpec(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if skippy < 0 {
+						return ErrInvalidLengthSpec
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.Count[mapkey] = mapvalue
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ForeignId", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.ForeignId == nil {
+				m.ForeignId = make(map[string]*CountPerKV)
+			}
+			var mapkey string
+			var mapvalue *CountPerKV
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowSpec
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowSpec
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthSpec
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthSpec
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var mapmsglen int
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowSpec
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						mapmsglen |= int(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					if mapmsglen < 0 {
+						return ErrInvalidLengthSpec
+					}
+					postmsgIndex := iNdEx + mapmsglen
+					if postmsgIndex < 0 {
+						return ErrInvalidLengthSpec
+					}
+					if postmsgIndex > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = &CountPerKV{}
+					if err := mapvalue.Unmarshal(dAtA[iNdEx:postmsgIndex]); err != nil {
+						return err
+					}
+					iNdEx = postmsgIndex
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipSpec(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if skippy < 0 {
+						return ErrInvalidLengthSpec
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.ForeignId[mapkey] = mapvalue
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EventType", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.EventType == nil {
+				m.EventType = make(map[string]*CountPerKV)
+			}
+			var mapkey string
+			var mapvalue *CountPerKV
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowSpec
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowSpec
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthSpec
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthSpec
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var mapmsglen int
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowSpec
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						mapmsglen |= int(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					if mapmsglen < 0 {
+						return ErrInvalidLengthSpec
+					}
+					postmsgIndex := iNdEx + mapmsglen
+					if postmsgIndex < 0 {
+						return ErrInvalidLengthSpec
+					}
+					if postmsgIndex > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = &CountPerKV{}
+					if err := mapvalue.Unmarshal(dAtA[iNdEx:postmsgIndex]); err != nil {
+						return err
+					}
+					iNdEx = postmsgIndex
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipSpec(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if skippy < 0 {
+						return ErrInvalidLengthSpec
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.EventType[mapkey] = mapvalue
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Possible", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Possible == nil {
+				m.Possible = make(map[string]uint32)
+			}
+			var mapkey string
+			var mapvalue uint32
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowSpec
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowSpec
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthSpec
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthSpec
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowSpec
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						mapvalue |= uint32(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipSpec(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if skippy < 0 {
+						return ErrInvalidLengthSpec
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.Possible[mapkey] = mapvalue
+			iNdEx = postIndex
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Total", wireType)
+			}
+			m.Total = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Total |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Sample", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Sample = append(m.Sample, &Hit{})
+			if err := m.Sample[len(m.Sample)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Chart", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Chart == nil {
+				m.Chart = &Chart{}
+			}
+			if err := m.Chart.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FailedShards", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.FailedShards = append(m.FailedShards, &ShardFailure{})
+			if err := m.FailedShards[len(m.FailedShards)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Numbers", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Numbers == nil {
+				m.Numbers = make(map[string]*NumberStats)
+			}
+			var mapkey string
+			var mapvalue *NumberStats
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowSpec
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowSpec
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthSpec
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthSpec
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var mapmsglen int
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowSpec
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						mapmsglen |= int(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					if mapmsglen < 0 {
+						return ErrInvalidLengthSpec
+					}
+					postmsgIndex := iNdEx + mapmsglen
+					if postmsgIndex < 0 {
+						return ErrInvalidLengthSpec
+					}
+					if postmsgIndex > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = &NumberStats{}
+					if err := mapvalue.Unmarshal(dAtA[iNdEx:postmsgIndex]); err != nil {
+						return err
+					}
+					iNdEx = postmsgIndex
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipSpec(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if skippy < 0 {
+						return ErrInvalidLengthSpec
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.Numbers[mapkey] = mapvalue
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSpec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SearchQueryResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSpec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SearchQueryResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SearchQueryResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Hits", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Hits = append(m.Hits, &Hit{})
+			if err := m.Hits[len(m.Hits)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Total", wireType)
+			}
+			m.Total = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Total |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Explain", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Explain == nil {
+				m.Explain = &QueryExplain{}
+			}
+			if err := m.Explain.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FailedShards", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.FailedShards = append(m.FailedShards, &ShardFailure{})
+			if err := m.FailedShards[len(m.FailedShards)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSpec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Envelope) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSpec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Envelope: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Envelope: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Metadata", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Metadata == nil {
+				m.Metadata = &Metadata{}
+			}
+			if err := m.Metadata.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Payload", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Payload = append(m.Payload[:0], dAtA[iNdEx:postIndex]...)
+			if m.Payload == nil {
+				m.Payload = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSpec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Success) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSpec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Success: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Success: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Success", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Success = bool(v != 0)
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Receipts", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Receipts = append(m.Receipts, &Receipt{})
+			if err := m.Receipts[len(m.Receipts)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Rejections", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Rejections = append(m.Rejections, &Rejection{})
+			if err := m.Rejections[len(m.Rejections)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSpec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Receipt) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSpec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Receipt: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Receipt: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Partition", wireType)
+			}
+			m.Partition = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Partition |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Offset", wireType)
+			}
+			m.Offset = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Offset |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			m.Id = 0
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Id = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Index", wireType)
+			}
+			m.Index = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Index |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSpec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Rejection) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSpec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Rejection: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Rejection: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Index", wireType)
+			}
+			m.Index = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Index |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Reason", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Reason = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSpec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *HealthRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSpec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: HealthRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: HealthRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSpec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SnapshotFile) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSpec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SnapshotFile: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SnapshotFile: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SizeBytes", wireType)
+			}
+			m.SizeBytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SizeBytes |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Sha256", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Sha256 = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSpec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SnapshotSegment) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSpec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SnapshotSegment: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SnapshotSegment: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Id = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StartNs", wireType)
+			}
+			m.StartNs = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.StartNs |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Files", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Files = append(m.Files, &SnapshotFile{})
+			if err := m.Files[len(m.Files)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSpec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SnapshotManifest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSpec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SnapshotManifest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SnapshotManifest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SegmentStep", wireType)
+			}
+			m.SegmentStep = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SegmentStep |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CreatedAtNs", wireType)
+			}
+			m.CreatedAtNs = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.CreatedAtNs |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Segments", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Segments = append(m.Segments, &SnapshotSegment{})
+			if err := m.Segments[len(m.Segments)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSpec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *BackupRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSpec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: BackupRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: BackupRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Destination", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Destination = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FromSecond", wireType)
+			}
+			m.FromSecond = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.FromSecond |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ToSecond", wireType)
+			}
+			m.ToSecond = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ToSecond |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSpec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *RestoreRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSpec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: RestoreRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: RestoreRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Source", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Source = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSpec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ReindexRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSpec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ReindexRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ReindexRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FromSecond", wireType)
+			}
+			m.FromSecond = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.FromSecond |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ToSecond", wireType)
+			}
+			m.ToSecond = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ToSecond |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Whitelist", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			mapEntrySize := 1 + len(k) + sovSpec(uint64(len(k))) + l
-			n += mapEntrySize + 1 + sovSpec(uint64(mapEntrySize))
-		}
-	}
-	if len(m.Count) > 0 {
-		for k, v := range m.Count {
-			_ = k
-			_ = v
-			l = 0
-			if v != nil {
-				l = v.Size()
-				l += 1 + sovSpec(uint64(l))
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSpec
 			}
-			mapEntrySize := 1 + len(k) + sovSpec(uint64(len(k))) + l
-			n += mapEntrySize + 1 + sovSpec(uint64(mapEntrySize))
-		}
-	}
-	if len(m.ForeignId) > 0 {
-		for k, v := range m.ForeignId {
-			_ = k
-			_ = v
-			l = 0
-			if v != nil {
-				l = v.Size()
-				l += 1 + sovSpec(uint64(l))
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
 			}
-			mapEntrySize := 1 + len(k) + sovSpec(uint64(len(k))) + l
-			n += mapEntrySize + 1 + sovSpec(uint64(mapEntrySize))
-		}
-	}
-	if len(m.EventType) > 0 {
-		for k, v := range m.EventType {
-			_ = k
-			_ = v
-			l = 0
-			if v != nil {
-				l = v.Size()
-				l += 1 + sovSpec(uint64(l))
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
 			}
-			mapEntrySize := 1 + len(k) + sovSpec(uint64(len(k))) + l
-			n += mapEntrySize + 1 + sovSpec(uint64(mapEntrySize))
-		}
-	}
-	if len(m.Possible) > 0 {
-		for k, v := range m.Possible {
-			_ = k
-			_ = v
-			mapEntrySize := 1 + len(k) + sovSpec(uint64(len(k))) + 1 + sovSpec(uint64(v))
-			n += mapEntrySize + 1 + sovSpec(uint64(mapEntrySize))
-		}
-	}
-	if m.Total != 0 {
-		n += 1 + sovSpec(uint64(m.Total))
-	}
-	if len(m.Sample) > 0 {
-		for _, e := range m.Sample {
-			l = e.Size()
-			n += 1 + l + sovSpec(uint64(l))
-		}
-	}
-	if m.Chart != nil {
-		l = m.Chart.Size()
-		n += 1 + l + sovSpec(uint64(l))
-	}
-	return n
-}
-
-func (m *SearchQueryResponse) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	if len(m.Hits) > 0 {
-		for _, e := range m.Hits {
-			l = e.Size()
-			n += 1 + l + sovSpec(uint64(l))
+			m.Whitelist = append(m.Whitelist, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StatusOnly", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.StatusOnly = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSpec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
 		}
 	}
-	if m.Total != 0 {
-		n += 1 + sovSpec(uint64(m.Total))
-	}
-	return n
-}
-
-func (m *Envelope) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	if m.Metadata != nil {
-		l = m.Metadata.Size()
-		n += 1 + l + sovSpec(uint64(l))
-	}
-	l = len(m.Payload)
-	if l > 0 {
-		n += 1 + l + sovSpec(uint64(l))
-	}
-	return n
-}
-
-func (m *Success) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	if m.Success {
-		n += 2
-	}
-	return n
-}
 
-func (m *HealthRequest) Size() (n int) {
-	if m == nil {
-		return 0
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
 	}
-	var l int
-	_ = l
-	return n
-}
-
-func sovSpec(x uint64) (n int) {
-	return (math_bits.Len64(x|1) + 6) / 7
-}
-func sozSpec(x uint64) (n int) {
-	return sovSpec(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+	return nil
 }
-func (m *KV) Unmarshal(dAtA []byte) error {
+func (m *ReindexStatus) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -3378,15 +14871,105 @@ func (m *KV) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: KV: wiretype end group for non-group")
+			return fmt.Errorf("proto: ReindexStatus: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: KV: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: ReindexStatus: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Running", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Running = bool(v != 0)
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Whitelist", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Whitelist = append(m.Whitelist, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SegmentsTotal", wireType)
+			}
+			m.SegmentsTotal = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SegmentsTotal |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SegmentsDone", wireType)
+			}
+			m.SegmentsDone = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SegmentsDone |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Key", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Error", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -3414,13 +14997,13 @@ func (m *KV) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Key = string(dAtA[iNdEx:postIndex])
+			m.Error = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 2:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StartedAtNs", wireType)
 			}
-			var stringLen uint64
+			m.StartedAtNs = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSpec
@@ -3430,24 +15013,30 @@ func (m *KV) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				m.StartedAtNs |= int64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthSpec
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthSpec
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FinishedAtNs", wireType)
 			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
+			m.FinishedAtNs = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.FinishedAtNs |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			m.Value = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipSpec(dAtA[iNdEx:])
@@ -3472,7 +15061,7 @@ func (m *KV) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *KF) Unmarshal(dAtA []byte) error {
+func (m *SegmentStats) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -3495,15 +15084,15 @@ func (m *KF) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: KF: wiretype end group for non-group")
+			return fmt.Errorf("proto: SegmentStats: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: KF: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: SegmentStats: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Key", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -3531,77 +15120,13 @@ func (m *KF) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Key = string(dAtA[iNdEx:postIndex])
+			m.Id = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 2:
-			if wireType != 1 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
-			}
-			var v uint64
-			if (iNdEx + 8) > l {
-				return io.ErrUnexpectedEOF
-			}
-			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
-			iNdEx += 8
-			m.Value = float64(math.Float64frombits(v))
-		default:
-			iNdEx = preIndex
-			skippy, err := skipSpec(dAtA[iNdEx:])
-			if err != nil {
-				return err
-			}
-			if skippy < 0 {
-				return ErrInvalidLengthSpec
-			}
-			if (iNdEx + skippy) < 0 {
-				return ErrInvalidLengthSpec
-			}
-			if (iNdEx + skippy) > l {
-				return io.ErrUnexpectedEOF
-			}
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *Metadata) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowSpec
-			}
-			if iNdEx >= l {
-				return io.ErrUnexpectedEOF
-			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= uint64(b&0x7F) << shift
-			if b < 0x80 {
-				break
-			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: Metadata: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Metadata: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Search", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Step", wireType)
 			}
-			var msglen int
+			m.Step = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSpec
@@ -3611,31 +15136,16 @@ func (m *Metadata) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				m.Step |= int64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthSpec
-			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
-				return ErrInvalidLengthSpec
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Search = append(m.Search, KV{})
-			if err := m.Search[len(m.Search)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			iNdEx = postIndex
-		case 2:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Count", wireType)
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StartNs", wireType)
 			}
-			var msglen int
+			m.StartNs = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSpec
@@ -3645,31 +15155,16 @@ func (m *Metadata) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				m.StartNs |= int64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthSpec
-			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
-				return ErrInvalidLengthSpec
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Count = append(m.Count, KV{})
-			if err := m.Count[len(m.Count)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			iNdEx = postIndex
-		case 3:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Properties", wireType)
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Docs", wireType)
 			}
-			var msglen int
+			m.Docs = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSpec
@@ -3679,31 +15174,35 @@ func (m *Metadata) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				m.Docs |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthSpec
-			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
-				return ErrInvalidLengthSpec
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MinCreatedAtNs", wireType)
 			}
-			m.Properties = append(m.Properties, KV{})
-			if err := m.Properties[len(m.Properties)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			m.MinCreatedAtNs = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MinCreatedAtNs |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			iNdEx = postIndex
-		case 5:
+		case 6:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field CreatedAtNs", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxCreatedAtNs", wireType)
 			}
-			m.CreatedAtNs = 0
+			m.MaxCreatedAtNs = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSpec
@@ -3713,16 +15212,16 @@ func (m *Metadata) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.CreatedAtNs |= int64(b&0x7F) << shift
+				m.MaxCreatedAtNs |= int64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
 		case 7:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field EventType", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ForwardBytes", wireType)
 			}
-			var stringLen uint64
+			m.ForwardBytes = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSpec
@@ -3732,29 +15231,35 @@ func (m *Metadata) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				m.ForwardBytes |= int64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthSpec
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthSpec
+		case 8:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field InvertedBytes", wireType)
 			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
+			m.InvertedBytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.InvertedBytes |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			m.EventType = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
 		case 9:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ForeignId", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Terms", wireType)
 			}
-			var stringLen uint64
+			m.Terms = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSpec
@@ -3764,29 +15269,16 @@ func (m *Metadata) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				m.Terms |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthSpec
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthSpec
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.ForeignId = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
 		case 10:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ForeignType", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Sealed", wireType)
 			}
-			var stringLen uint64
+			var v int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSpec
@@ -3796,27 +15288,15 @@ func (m *Metadata) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				v |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
-				}
-			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthSpec
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthSpec
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
+				}
 			}
-			m.ForeignType = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
+			m.Sealed = bool(v != 0)
 		case 11:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Track", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field EventTypes", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -3843,11 +15323,11 @@ func (m *Metadata) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Track == nil {
-				m.Track = make(map[string]uint32)
+			if m.EventTypes == nil {
+				m.EventTypes = make(map[string]uint64)
 			}
 			var mapkey string
-			var mapvalue uint32
+			var mapvalue uint64
 			for iNdEx < postIndex {
 				entryPreIndex := iNdEx
 				var wire uint64
@@ -3905,7 +15385,106 @@ func (m *Metadata) Unmarshal(dAtA []byte) error {
 						}
 						b := dAtA[iNdEx]
 						iNdEx++
-						mapvalue |= uint32(b&0x7F) << shift
+						mapvalue |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipSpec(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if skippy < 0 {
+						return ErrInvalidLengthSpec
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.EventTypes[mapkey] = mapvalue
+			iNdEx = postIndex
+		case 12:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PartitionOffsets", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.PartitionOffsets == nil {
+				m.PartitionOffsets = make(map[int32]int64)
+			}
+			var mapkey int32
+			var mapvalue int64
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowSpec
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowSpec
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						mapkey |= int32(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+				} else if fieldNum == 2 {
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowSpec
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						mapvalue |= int64(b&0x7F) << shift
 						if b < 0x80 {
 							break
 						}
@@ -3925,18 +15504,205 @@ func (m *Metadata) Unmarshal(dAtA []byte) error {
 					iNdEx += skippy
 				}
 			}
-			m.Track[mapkey] = mapvalue
-			iNdEx = postIndex
-		case 12:
-			if wireType != 1 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			m.PartitionOffsets[mapkey] = mapvalue
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSpec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SegmentCatalog) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSpec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SegmentCatalog: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SegmentCatalog: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Segments", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Segments = append(m.Segments, &SegmentStats{})
+			if err := m.Segments[len(m.Segments)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSpec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *StatsRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSpec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: StatsRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: StatsRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FromSecond", wireType)
+			}
+			m.FromSecond = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.FromSecond |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ToSecond", wireType)
+			}
+			m.ToSecond = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ToSecond |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TopEventTypes", wireType)
 			}
-			m.Id = 0
-			if (iNdEx + 8) > l {
-				return io.ErrUnexpectedEOF
+			m.TopEventTypes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.TopEventTypes |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			m.Id = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
-			iNdEx += 8
 		default:
 			iNdEx = preIndex
 			skippy, err := skipSpec(dAtA[iNdEx:])
@@ -3961,7 +15727,7 @@ func (m *Metadata) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *SearchableMetadata) Unmarshal(dAtA []byte) error {
+func (m *StatsResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -3984,15 +15750,15 @@ func (m *SearchableMetadata) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: SearchableMetadata: wiretype end group for non-group")
+			return fmt.Errorf("proto: StatsResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: SearchableMetadata: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: StatsResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Search", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Segments", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -4019,16 +15785,16 @@ func (m *SearchableMetadata) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Search = append(m.Search, KV{})
-			if err := m.Search[len(m.Search)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.Segments = append(m.Segments, &SegmentStats{})
+			if err := m.Segments[len(m.Segments)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
-		case 7:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field EventType", wireType)
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Docs", wireType)
 			}
-			var stringLen uint64
+			m.Docs = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSpec
@@ -4038,29 +15804,16 @@ func (m *SearchableMetadata) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				m.Docs |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthSpec
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthSpec
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.EventType = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		case 9:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ForeignId", wireType)
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ForwardBytes", wireType)
 			}
-			var stringLen uint64
+			m.ForwardBytes = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSpec
@@ -4070,29 +15823,35 @@ func (m *SearchableMetadata) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				m.ForwardBytes |= int64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthSpec
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthSpec
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field InvertedBytes", wireType)
 			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
+			m.InvertedBytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.InvertedBytes |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			m.ForeignId = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		case 10:
+		case 5:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ForeignType", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field FailedShards", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSpec
@@ -4102,27 +15861,29 @@ func (m *SearchableMetadata) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthSpec
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthSpec
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.ForeignType = string(dAtA[iNdEx:postIndex])
+			m.FailedShards = append(m.FailedShards, &ShardFailure{})
+			if err := m.FailedShards[len(m.FailedShards)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
-		case 11:
+		case 6:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Track", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field PartitionOffsets", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -4149,11 +15910,11 @@ func (m *SearchableMetadata) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Track == nil {
-				m.Track = make(map[string]uint32)
+			if m.PartitionOffsets == nil {
+				m.PartitionOffsets = make(map[int32]int64)
 			}
-			var mapkey string
-			var mapvalue uint32
+			var mapkey int32
+			var mapvalue int64
 			for iNdEx < postIndex {
 				entryPreIndex := iNdEx
 				var wire uint64
@@ -4173,7 +15934,6 @@ func (m *SearchableMetadata) Unmarshal(dAtA []byte) error {
 				}
 				fieldNum := int32(wire >> 3)
 				if fieldNum == 1 {
-					var stringLenmapkey uint64
 					for shift := uint(0); ; shift += 7 {
 						if shift >= 64 {
 							return ErrIntOverflowSpec
@@ -4183,24 +15943,11 @@ func (m *SearchableMetadata) Unmarshal(dAtA []byte) error {
 						}
 						b := dAtA[iNdEx]
 						iNdEx++
-						stringLenmapkey |= uint64(b&0x7F) << shift
+						mapkey |= int32(b&0x7F) << shift
 						if b < 0x80 {
 							break
 						}
 					}
-					intStringLenmapkey := int(stringLenmapkey)
-					if intStringLenmapkey < 0 {
-						return ErrInvalidLengthSpec
-					}
-					postStringIndexmapkey := iNdEx + intStringLenmapkey
-					if postStringIndexmapkey < 0 {
-						return ErrInvalidLengthSpec
-					}
-					if postStringIndexmapkey > l {
-						return io.ErrUnexpectedEOF
-					}
-					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
-					iNdEx = postStringIndexmapkey
 				} else if fieldNum == 2 {
 					for shift := uint(0); ; shift += 7 {
 						if shift >= 64 {
@@ -4211,7 +15958,7 @@ func (m *SearchableMetadata) Unmarshal(dAtA []byte) error {
 						}
 						b := dAtA[iNdEx]
 						iNdEx++
-						mapvalue |= uint32(b&0x7F) << shift
+						mapvalue |= int64(b&0x7F) << shift
 						if b < 0x80 {
 							break
 						}
@@ -4231,7 +15978,7 @@ func (m *SearchableMetadata) Unmarshal(dAtA []byte) error {
 					iNdEx += skippy
 				}
 			}
-			m.Track[mapkey] = mapvalue
+			m.PartitionOffsets[mapkey] = mapvalue
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -4257,7 +16004,7 @@ func (m *SearchableMetadata) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *BasicMetadata) Unmarshal(dAtA []byte) error {
+func (m *ReplicationRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -4280,127 +16027,12 @@ func (m *BasicMetadata) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: BasicMetadata: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: BasicMetadata: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 5:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field CreatedAtNs", wireType)
-			}
-			m.CreatedAtNs = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowSpec
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.CreatedAtNs |= int64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 7:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field EventType", wireType)
-			}
-			var stringLen uint64
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowSpec
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthSpec
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthSpec
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.EventType = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		case 9:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ForeignId", wireType)
-			}
-			var stringLen uint64
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowSpec
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthSpec
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthSpec
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.ForeignId = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		case 10:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ForeignType", wireType)
-			}
-			var stringLen uint64
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowSpec
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthSpec
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthSpec
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.ForeignType = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
+			return fmt.Errorf("proto: ReplicationRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ReplicationRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
 		default:
 			iNdEx = preIndex
 			skippy, err := skipSpec(dAtA[iNdEx:])
@@ -4425,7 +16057,7 @@ func (m *BasicMetadata) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *CountableMetadata) Unmarshal(dAtA []byte) error {
+func (m *ReplicaSegment) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -4448,17 +16080,17 @@ func (m *CountableMetadata) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: CountableMetadata: wiretype end group for non-group")
+			return fmt.Errorf("proto: ReplicaSegment: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: CountableMetadata: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: ReplicaSegment: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Search", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSpec
@@ -4468,31 +16100,29 @@ func (m *CountableMetadata) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthSpec
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthSpec
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Search = append(m.Search, KV{})
-			if err := m.Search[len(m.Search)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.Id = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 2:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Count", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ForwardBytes", wireType)
 			}
-			var msglen int
+			m.ForwardBytes = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSpec
@@ -4502,31 +16132,16 @@ func (m *CountableMetadata) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				m.ForwardBytes |= int64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthSpec
-			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
-				return ErrInvalidLengthSpec
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Count = append(m.Count, KV{})
-			if err := m.Count[len(m.Count)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			iNdEx = postIndex
-		case 5:
+		case 3:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field CreatedAtNs", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Sealed", wireType)
 			}
-			m.CreatedAtNs = 0
+			var v int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSpec
@@ -4536,48 +16151,70 @@ func (m *CountableMetadata) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.CreatedAtNs |= int64(b&0x7F) << shift
+				v |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 7:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field EventType", wireType)
-			}
-			var stringLen uint64
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowSpec
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
+			m.Sealed = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSpec(dAtA[iNdEx:])
+			if err != nil {
+				return err
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if skippy < 0 {
 				return ErrInvalidLengthSpec
 			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
+			if (iNdEx + skippy) < 0 {
 				return ErrInvalidLengthSpec
 			}
-			if postIndex > l {
+			if (iNdEx + skippy) > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.EventType = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		case 9:
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ReplicationManifest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSpec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ReplicationManifest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ReplicationManifest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ForeignId", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Segments", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSpec
@@ -4587,27 +16224,29 @@ func (m *CountableMetadata) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthSpec
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthSpec
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.ForeignId = string(dAtA[iNdEx:postIndex])
+			m.Segments = append(m.Segments, &ReplicaSegment{})
+			if err := m.Segments[len(m.Segments)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
-		case 10:
+		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ForeignType", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Whitelist", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -4635,7 +16274,7 @@ func (m *CountableMetadata) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.ForeignType = string(dAtA[iNdEx:postIndex])
+			m.Whitelist = append(m.Whitelist, string(dAtA[iNdEx:postIndex]))
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -4661,7 +16300,7 @@ func (m *CountableMetadata) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Hit) Unmarshal(dAtA []byte) error {
+func (m *ShipRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -4684,17 +16323,17 @@ func (m *Hit) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Hit: wiretype end group for non-group")
+			return fmt.Errorf("proto: ShipRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Hit: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: ShipRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SegmentId", wireType)
 			}
-			m.Id = 0
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSpec
@@ -4704,27 +16343,29 @@ func (m *Hit) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Id |= uint64(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 2:
-			if wireType != 5 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Score", wireType)
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSpec
 			}
-			var v uint32
-			if (iNdEx + 4) > l {
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			v = uint32(encoding_binary.LittleEndian.Uint32(dAtA[iNdEx:]))
-			iNdEx += 4
-			m.Score = float32(math.Float32frombits(v))
-		case 3:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Metadata", wireType)
+			m.SegmentId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Offset", wireType)
 			}
-			var msglen int
+			m.Offset = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSpec
@@ -4734,28 +16375,50 @@ func (m *Hit) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				m.Offset |= int64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthSpec
-			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
-				return ErrInvalidLengthSpec
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Files", wireType)
 			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			if m.Metadata == nil {
-				m.Metadata = &Metadata{}
+			m.Files = bool(v != 0)
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxBytes", wireType)
 			}
-			if err := m.Metadata.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			m.MaxBytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxBytes |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipSpec(dAtA[iNdEx:])
@@ -4780,7 +16443,7 @@ func (m *Hit) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *SearchQueryRequest) Unmarshal(dAtA []byte) error {
+func (m *ShipChunk) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -4803,17 +16466,17 @@ func (m *SearchQueryRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: SearchQueryRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: ShipChunk: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: SearchQueryRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: ShipChunk: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field FromSecond", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
 			}
-			m.FromSecond = 0
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSpec
@@ -4823,16 +16486,29 @@ func (m *SearchQueryRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.FromSecond |= uint32(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		case 2:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ToSecond", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Offset", wireType)
 			}
-			m.ToSecond = 0
+			m.Offset = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSpec
@@ -4842,16 +16518,50 @@ func (m *SearchQueryRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.ToSecond |= uint32(b&0x7F) << shift
+				m.Offset |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Data", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 3:
+			if byteLen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Data = append(m.Data[:0], dAtA[iNdEx:postIndex]...)
+			if m.Data == nil {
+				m.Data = []byte{}
+			}
+			iNdEx = postIndex
+		case 4:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Query", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Records", wireType)
 			}
-			var msglen int
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSpec
@@ -4861,33 +16571,29 @@ func (m *SearchQueryRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				byteLen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			if byteLen < 0 {
 				return ErrInvalidLengthSpec
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + byteLen
 			if postIndex < 0 {
 				return ErrInvalidLengthSpec
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Query == nil {
-				m.Query = &go_query_dsl.Query{}
-			}
-			if err := m.Query.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.Records = append(m.Records, make([]byte, postIndex-iNdEx))
+			copy(m.Records[len(m.Records)-1], dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 4:
+		case 5:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Limit", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field NextOffset", wireType)
 			}
-			m.Limit = 0
+			m.NextOffset = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSpec
@@ -4897,7 +16603,7 @@ func (m *SearchQueryRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Limit |= int32(b&0x7F) << shift
+				m.NextOffset |= int64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -4926,7 +16632,7 @@ func (m *SearchQueryRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *CountPerKV) Unmarshal(dAtA []byte) error {
+func (m *PromoteRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -4949,176 +16655,12 @@ func (m *CountPerKV) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: CountPerKV: wiretype end group for non-group")
+			return fmt.Errorf("proto: PromoteRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: CountPerKV: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: PromoteRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
-		case 1:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Count", wireType)
-			}
-			var msglen int
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowSpec
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				msglen |= int(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			if msglen < 0 {
-				return ErrInvalidLengthSpec
-			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
-				return ErrInvalidLengthSpec
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			if m.Count == nil {
-				m.Count = make(map[string]uint32)
-			}
-			var mapkey string
-			var mapvalue uint32
-			for iNdEx < postIndex {
-				entryPreIndex := iNdEx
-				var wire uint64
-				for shift := uint(0); ; shift += 7 {
-					if shift >= 64 {
-						return ErrIntOverflowSpec
-					}
-					if iNdEx >= l {
-						return io.ErrUnexpectedEOF
-					}
-					b := dAtA[iNdEx]
-					iNdEx++
-					wire |= uint64(b&0x7F) << shift
-					if b < 0x80 {
-						break
-					}
-				}
-				fieldNum := int32(wire >> 3)
-				if fieldNum == 1 {
-					var stringLenmapkey uint64
-					for shift := uint(0); ; shift += 7 {
-						if shift >= 64 {
-							return ErrIntOverflowSpec
-						}
-						if iNdEx >= l {
-							return io.ErrUnexpectedEOF
-						}
-						b := dAtA[iNdEx]
-						iNdEx++
-						stringLenmapkey |= uint64(b&0x7F) << shift
-						if b < 0x80 {
-							break
-						}
-					}
-					intStringLenmapkey := int(stringLenmapkey)
-					if intStringLenmapkey < 0 {
-						return ErrInvalidLengthSpec
-					}
-					postStringIndexmapkey := iNdEx + intStringLenmapkey
-					if postStringIndexmapkey < 0 {
-						return ErrInvalidLengthSpec
-					}
-					if postStringIndexmapkey > l {
-						return io.ErrUnexpectedEOF
-					}
-					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
-					iNdEx = postStringIndexmapkey
-				} else if fieldNum == 2 {
-					for shift := uint(0); ; shift += 7 {
-						if shift >= 64 {
-							return ErrIntOverflowSpec
-						}
-						if iNdEx >= l {
-							return io.ErrUnexpectedEOF
-						}
-						b := dAtA[iNdEx]
-						iNdEx++
-						mapvalue |= uint32(b&0x7F) << shift
-						if b < 0x80 {
-							break
-						}
-					}
-				} else {
-					iNdEx = entryPreIndex
-					skippy, err := skipSpec(dAtA[iNdEx:])
-					if err != nil {
-						return err
-					}
-					if skippy < 0 {
-						return ErrInvalidLengthSpec
-					}
-					if (iNdEx + skippy) > postIndex {
-						return io.ErrUnexpectedEOF
-					}
-					iNdEx += skippy
-				}
-			}
-			m.Count[mapkey] = mapvalue
-			iNdEx = postIndex
-		case 2:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Total", wireType)
-			}
-			m.Total = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowSpec
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.Total |= uint32(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 3:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Key", wireType)
-			}
-			var stringLen uint64
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowSpec
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthSpec
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthSpec
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Key = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipSpec(dAtA[iNdEx:])
@@ -5143,7 +16685,7 @@ func (m *CountPerKV) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *PointPerEventType) Unmarshal(dAtA []byte) error {
+func (m *DeadLetter) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -5166,17 +16708,17 @@ func (m *PointPerEventType) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: PointPerEventType: wiretype end group for non-group")
+			return fmt.Errorf("proto: DeadLetter: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: PointPerEventType: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: DeadLetter: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Count", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
 			}
-			m.Count = 0
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSpec
@@ -5186,16 +16728,63 @@ func (m *PointPerEventType) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Count |= uint32(b&0x7F) << shift
+				byteLen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			if byteLen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Value = append(m.Value[:0], dAtA[iNdEx:postIndex]...)
+			if m.Value == nil {
+				m.Value = []byte{}
+			}
+			iNdEx = postIndex
 		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Topic", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Topic = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field CountUnique", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Partition", wireType)
 			}
-			m.CountUnique = 0
+			m.Partition = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSpec
@@ -5205,16 +16794,16 @@ func (m *PointPerEventType) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.CountUnique |= uint32(b&0x7F) << shift
+				m.Partition |= int32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 3:
+		case 4:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Bucket", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Offset", wireType)
 			}
-			m.Bucket = 0
+			m.Offset = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSpec
@@ -5224,14 +16813,14 @@ func (m *PointPerEventType) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Bucket |= uint32(b&0x7F) << shift
+				m.Offset |= int64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 4:
+		case 5:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field EventType", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Reason", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -5259,8 +16848,27 @@ func (m *PointPerEventType) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.EventType = string(dAtA[iNdEx:postIndex])
+			m.Reason = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CreatedAtNs", wireType)
+			}
+			m.CreatedAtNs = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.CreatedAtNs |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipSpec(dAtA[iNdEx:])
@@ -5285,7 +16893,7 @@ func (m *PointPerEventType) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *ChartBucketPerTime) Unmarshal(dAtA []byte) error {
+func (m *EventSchema) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -5308,17 +16916,17 @@ func (m *ChartBucketPerTime) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: ChartBucketPerTime: wiretype end group for non-group")
+			return fmt.Errorf("proto: EventSchema: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: ChartBucketPerTime: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: EventSchema: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field PerType", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field EventType", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSpec
@@ -5328,177 +16936,59 @@ func (m *ChartBucketPerTime) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthSpec
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthSpec
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.PerType == nil {
-				m.PerType = make(map[string]*PointPerEventType)
+			m.EventType = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RequiredKeys", wireType)
 			}
-			var mapkey string
-			var mapvalue *PointPerEventType
-			for iNdEx < postIndex {
-				entryPreIndex := iNdEx
-				var wire uint64
-				for shift := uint(0); ; shift += 7 {
-					if shift >= 64 {
-						return ErrIntOverflowSpec
-					}
-					if iNdEx >= l {
-						return io.ErrUnexpectedEOF
-					}
-					b := dAtA[iNdEx]
-					iNdEx++
-					wire |= uint64(b&0x7F) << shift
-					if b < 0x80 {
-						break
-					}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
 				}
-				fieldNum := int32(wire >> 3)
-				if fieldNum == 1 {
-					var stringLenmapkey uint64
-					for shift := uint(0); ; shift += 7 {
-						if shift >= 64 {
-							return ErrIntOverflowSpec
-						}
-						if iNdEx >= l {
-							return io.ErrUnexpectedEOF
-						}
-						b := dAtA[iNdEx]
-						iNdEx++
-						stringLenmapkey |= uint64(b&0x7F) << shift
-						if b < 0x80 {
-							break
-						}
-					}
-					intStringLenmapkey := int(stringLenmapkey)
-					if intStringLenmapkey < 0 {
-						return ErrInvalidLengthSpec
-					}
-					postStringIndexmapkey := iNdEx + intStringLenmapkey
-					if postStringIndexmapkey < 0 {
-						return ErrInvalidLengthSpec
-					}
-					if postStringIndexmapkey > l {
-						return io.ErrUnexpectedEOF
-					}
-					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
-					iNdEx = postStringIndexmapkey
-				} else if fieldNum == 2 {
-					var mapmsglen int
-					for shift := uint(0); ; shift += 7 {
-						if shift >= 64 {
-							return ErrIntOverflowSpec
-						}
-						if iNdEx >= l {
-							return io.ErrUnexpectedEOF
-						}
-						b := dAtA[iNdEx]
-						iNdEx++
-						mapmsglen |= int(b&0x7F) << shift
-						if b < 0x80 {
-							break
-						}
-					}
-					if mapmsglen < 0 {
-						return ErrInvalidLengthSpec
-					}
-					postmsgIndex := iNdEx + mapmsglen
-					if postmsgIndex < 0 {
-						return ErrInvalidLengthSpec
-					}
-					if postmsgIndex > l {
-						return io.ErrUnexpectedEOF
-					}
-					mapvalue = &PointPerEventType{}
-					if err := mapvalue.Unmarshal(dAtA[iNdEx:postmsgIndex]); err != nil {
-						return err
-					}
-					iNdEx = postmsgIndex
-				} else {
-					iNdEx = entryPreIndex
-					skippy, err := skipSpec(dAtA[iNdEx:])
-					if err != nil {
-						return err
-					}
-					if skippy < 0 {
-						return ErrInvalidLengthSpec
-					}
-					if (iNdEx + skippy) > postIndex {
-						return io.ErrUnexpectedEOF
-					}
-					iNdEx += skippy
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
 				}
 			}
-			m.PerType[mapkey] = mapvalue
-			iNdEx = postIndex
-		default:
-			iNdEx = preIndex
-			skippy, err := skipSpec(dAtA[iNdEx:])
-			if err != nil {
-				return err
-			}
-			if skippy < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthSpec
 			}
-			if (iNdEx + skippy) < 0 {
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
 				return ErrInvalidLengthSpec
 			}
-			if (iNdEx + skippy) > l {
-				return io.ErrUnexpectedEOF
-			}
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *Chart) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowSpec
-			}
-			if iNdEx >= l {
+			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= uint64(b&0x7F) << shift
-			if b < 0x80 {
-				break
-			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: Chart: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Chart: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
+			m.RequiredKeys = append(m.RequiredKeys, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Buckets", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ValueTypes", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -5525,11 +17015,11 @@ func (m *Chart) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Buckets == nil {
-				m.Buckets = make(map[uint32]*ChartBucketPerTime)
+			if m.ValueTypes == nil {
+				m.ValueTypes = make(map[string]string)
 			}
-			var mapkey uint32
-			var mapvalue *ChartBucketPerTime
+			var mapkey string
+			var mapvalue string
 			for iNdEx < postIndex {
 				entryPreIndex := iNdEx
 				var wire uint64
@@ -5549,6 +17039,7 @@ func (m *Chart) Unmarshal(dAtA []byte) error {
 				}
 				fieldNum := int32(wire >> 3)
 				if fieldNum == 1 {
+					var stringLenmapkey uint64
 					for shift := uint(0); ; shift += 7 {
 						if shift >= 64 {
 							return ErrIntOverflowSpec
@@ -5558,13 +17049,26 @@ func (m *Chart) Unmarshal(dAtA []byte) error {
 						}
 						b := dAtA[iNdEx]
 						iNdEx++
-						mapkey |= uint32(b&0x7F) << shift
+						stringLenmapkey |= uint64(b&0x7F) << shift
 						if b < 0x80 {
 							break
 						}
 					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthSpec
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthSpec
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
 				} else if fieldNum == 2 {
-					var mapmsglen int
+					var stringLenmapvalue uint64
 					for shift := uint(0); ; shift += 7 {
 						if shift >= 64 {
 							return ErrIntOverflowSpec
@@ -5574,26 +17078,24 @@ func (m *Chart) Unmarshal(dAtA []byte) error {
 						}
 						b := dAtA[iNdEx]
 						iNdEx++
-						mapmsglen |= int(b&0x7F) << shift
+						stringLenmapvalue |= uint64(b&0x7F) << shift
 						if b < 0x80 {
 							break
 						}
 					}
-					if mapmsglen < 0 {
+					intStringLenmapvalue := int(stringLenmapvalue)
+					if intStringLenmapvalue < 0 {
 						return ErrInvalidLengthSpec
 					}
-					postmsgIndex := iNdEx + mapmsglen
-					if postmsgIndex < 0 {
+					postStringIndexmapvalue := iNdEx + intStringLenmapvalue
+					if postStringIndexmapvalue < 0 {
 						return ErrInvalidLengthSpec
 					}
-					if postmsgIndex > l {
+					if postStringIndexmapvalue > l {
 						return io.ErrUnexpectedEOF
 					}
-					mapvalue = &ChartBucketPerTime{}
-					if err := mapvalue.Unmarshal(dAtA[iNdEx:postmsgIndex]); err != nil {
-						return err
-					}
-					iNdEx = postmsgIndex
+					mapvalue = string(dAtA[iNdEx:postStringIndexmapvalue])
+					iNdEx = postStringIndexmapvalue
 				} else {
 					iNdEx = entryPreIndex
 					skippy, err := skipSpec(dAtA[iNdEx:])
@@ -5609,13 +17111,45 @@ func (m *Chart) Unmarshal(dAtA []byte) error {
 					iNdEx += skippy
 				}
 			}
-			m.Buckets[mapkey] = mapvalue
+			m.ValueTypes[mapkey] = mapvalue
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ForeignTypes", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ForeignTypes = append(m.ForeignTypes, string(dAtA[iNdEx:postIndex]))
 			iNdEx = postIndex
-		case 2:
+		case 5:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field TimeStart", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxSearch", wireType)
 			}
-			m.TimeStart = 0
+			m.MaxSearch = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSpec
@@ -5625,16 +17159,16 @@ func (m *Chart) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.TimeStart |= uint32(b&0x7F) << shift
+				m.MaxSearch |= int32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 3:
+		case 6:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field TimeEnd", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxCount", wireType)
 			}
-			m.TimeEnd = 0
+			m.MaxCount = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSpec
@@ -5644,16 +17178,16 @@ func (m *Chart) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.TimeEnd |= uint32(b&0x7F) << shift
+				m.MaxCount |= int32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 4:
+		case 7:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field TimeBucketSec", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxProperties", wireType)
 			}
-			m.TimeBucketSec = 0
+			m.MaxProperties = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSpec
@@ -5663,7 +17197,7 @@ func (m *Chart) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.TimeBucketSec |= uint32(b&0x7F) << shift
+				m.MaxProperties |= int32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -5692,7 +17226,7 @@ func (m *Chart) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *AggregateRequest) Unmarshal(dAtA []byte) error {
+func (m *SchemaRegistry) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -5715,15 +17249,15 @@ func (m *AggregateRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: AggregateRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: SchemaRegistry: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: AggregateRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: SchemaRegistry: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Query", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Schemas", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -5750,18 +17284,142 @@ func (m *AggregateRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Query == nil {
-				m.Query = &SearchQueryRequest{}
-			}
-			if err := m.Query.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.Schemas = append(m.Schemas, &EventSchema{})
+			if err := m.Schemas[len(m.Schemas)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
 		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DryRun", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.DryRun = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSpec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SchemaRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSpec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SchemaRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SchemaRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSpec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *AlertRule) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSpec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: AlertRule: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: AlertRule: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Fields", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSpec
@@ -5771,112 +17429,65 @@ func (m *AggregateRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthSpec
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthSpec
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Fields == nil {
-				m.Fields = make(map[string]bool)
+			m.Name = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Query", wireType)
 			}
-			var mapkey string
-			var mapvalue bool
-			for iNdEx < postIndex {
-				entryPreIndex := iNdEx
-				var wire uint64
-				for shift := uint(0); ; shift += 7 {
-					if shift >= 64 {
-						return ErrIntOverflowSpec
-					}
-					if iNdEx >= l {
-						return io.ErrUnexpectedEOF
-					}
-					b := dAtA[iNdEx]
-					iNdEx++
-					wire |= uint64(b&0x7F) << shift
-					if b < 0x80 {
-						break
-					}
-				}
-				fieldNum := int32(wire >> 3)
-				if fieldNum == 1 {
-					var stringLenmapkey uint64
-					for shift := uint(0); ; shift += 7 {
-						if shift >= 64 {
-							return ErrIntOverflowSpec
-						}
-						if iNdEx >= l {
-							return io.ErrUnexpectedEOF
-						}
-						b := dAtA[iNdEx]
-						iNdEx++
-						stringLenmapkey |= uint64(b&0x7F) << shift
-						if b < 0x80 {
-							break
-						}
-					}
-					intStringLenmapkey := int(stringLenmapkey)
-					if intStringLenmapkey < 0 {
-						return ErrInvalidLengthSpec
-					}
-					postStringIndexmapkey := iNdEx + intStringLenmapkey
-					if postStringIndexmapkey < 0 {
-						return ErrInvalidLengthSpec
-					}
-					if postStringIndexmapkey > l {
-						return io.ErrUnexpectedEOF
-					}
-					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
-					iNdEx = postStringIndexmapkey
-				} else if fieldNum == 2 {
-					var mapvaluetemp int
-					for shift := uint(0); ; shift += 7 {
-						if shift >= 64 {
-							return ErrIntOverflowSpec
-						}
-						if iNdEx >= l {
-							return io.ErrUnexpectedEOF
-						}
-						b := dAtA[iNdEx]
-						iNdEx++
-						mapvaluetemp |= int(b&0x7F) << shift
-						if b < 0x80 {
-							break
-						}
-					}
-					mapvalue = bool(mapvaluetemp != 0)
-				} else {
-					iNdEx = entryPreIndex
-					skippy, err := skipSpec(dAtA[iNdEx:])
-					if err != nil {
-						return err
-					}
-					if skippy < 0 {
-						return ErrInvalidLengthSpec
-					}
-					if (iNdEx + skippy) > postIndex {
-						return io.ErrUnexpectedEOF
-					}
-					iNdEx += skippy
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
 				}
 			}
-			m.Fields[mapkey] = mapvalue
+			if msglen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Query == nil {
+				m.Query = &go_query_dsl.Query{}
+			}
+			if err := m.Query.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
 		case 3:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field SampleLimit", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field WindowSec", wireType)
 			}
-			m.SampleLimit = 0
+			m.WindowSec = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSpec
@@ -5886,16 +17497,16 @@ func (m *AggregateRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.SampleLimit |= int32(b&0x7F) << shift
+				m.WindowSec |= uint32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
 		case 4:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field TimeBucketSec", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Kind", wireType)
 			}
-			m.TimeBucketSec = 0
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSpec
@@ -5905,11 +17516,35 @@ func (m *AggregateRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.TimeBucketSec |= uint32(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Kind = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Threshold", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.Threshold = float64(math.Float64frombits(v))
 		default:
 			iNdEx = preIndex
 			skippy, err := skipSpec(dAtA[iNdEx:])
@@ -5934,7 +17569,7 @@ func (m *AggregateRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Aggregate) Unmarshal(dAtA []byte) error {
+func (m *AlertRules) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -5957,15 +17592,15 @@ func (m *Aggregate) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Aggregate: wiretype end group for non-group")
+			return fmt.Errorf("proto: AlertRules: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Aggregate: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: AlertRules: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Search", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Rules", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -5992,111 +17627,125 @@ func (m *Aggregate) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Search == nil {
-				m.Search = make(map[string]*CountPerKV)
+			m.Rules = append(m.Rules, &AlertRule{})
+			if err := m.Rules[len(m.Rules)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
 			}
-			var mapkey string
-			var mapvalue *CountPerKV
-			for iNdEx < postIndex {
-				entryPreIndex := iNdEx
-				var wire uint64
-				for shift := uint(0); ; shift += 7 {
-					if shift >= 64 {
-						return ErrIntOverflowSpec
-					}
-					if iNdEx >= l {
-						return io.ErrUnexpectedEOF
-					}
-					b := dAtA[iNdEx]
-					iNdEx++
-					wire |= uint64(b&0x7F) << shift
-					if b < 0x80 {
-						break
-					}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSpec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *AlertState) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSpec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: AlertState: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: AlertState: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Rule", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
 				}
-				fieldNum := int32(wire >> 3)
-				if fieldNum == 1 {
-					var stringLenmapkey uint64
-					for shift := uint(0); ; shift += 7 {
-						if shift >= 64 {
-							return ErrIntOverflowSpec
-						}
-						if iNdEx >= l {
-							return io.ErrUnexpectedEOF
-						}
-						b := dAtA[iNdEx]
-						iNdEx++
-						stringLenmapkey |= uint64(b&0x7F) << shift
-						if b < 0x80 {
-							break
-						}
-					}
-					intStringLenmapkey := int(stringLenmapkey)
-					if intStringLenmapkey < 0 {
-						return ErrInvalidLengthSpec
-					}
-					postStringIndexmapkey := iNdEx + intStringLenmapkey
-					if postStringIndexmapkey < 0 {
-						return ErrInvalidLengthSpec
-					}
-					if postStringIndexmapkey > l {
-						return io.ErrUnexpectedEOF
-					}
-					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
-					iNdEx = postStringIndexmapkey
-				} else if fieldNum == 2 {
-					var mapmsglen int
-					for shift := uint(0); ; shift += 7 {
-						if shift >= 64 {
-							return ErrIntOverflowSpec
-						}
-						if iNdEx >= l {
-							return io.ErrUnexpectedEOF
-						}
-						b := dAtA[iNdEx]
-						iNdEx++
-						mapmsglen |= int(b&0x7F) << shift
-						if b < 0x80 {
-							break
-						}
-					}
-					if mapmsglen < 0 {
-						return ErrInvalidLengthSpec
-					}
-					postmsgIndex := iNdEx + mapmsglen
-					if postmsgIndex < 0 {
-						return ErrInvalidLengthSpec
-					}
-					if postmsgIndex > l {
-						return io.ErrUnexpectedEOF
-					}
-					mapvalue = &CountPerKV{}
-					if err := mapvalue.Unmarshal(dAtA[iNdEx:postmsgIndex]); err != nil {
-						return err
-					}
-					iNdEx = postmsgIndex
-				} else {
-					iNdEx = entryPreIndex
-					skippy, err := skipSpec(dAtA[iNdEx:])
-					if err != nil {
-						return err
-					}
-					if skippy < 0 {
-						return ErrInvalidLengthSpec
-					}
-					if (iNdEx + skippy) > postIndex {
-						return io.ErrUnexpectedEOF
-					}
-					iNdEx += skippy
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Rule == nil {
+				m.Rule = &AlertRule{}
+			}
+			if err := m.Rule.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Firing", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
 				}
 			}
-			m.Search[mapkey] = mapvalue
-			iNdEx = postIndex
-		case 2:
-			if wireType != 2 {
+			m.Firing = bool(v != 0)
+		case 3:
+			if wireType != 0 {
 				return fmt.Errorf("proto: wrong wireType = %d for field Count", wireType)
 			}
-			var msglen int
+			m.Count = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSpec
@@ -6106,126 +17755,35 @@ func (m *Aggregate) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				m.Count |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthSpec
-			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
-				return ErrInvalidLengthSpec
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			if m.Count == nil {
-				m.Count = make(map[string]*CountPerKV)
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Previous", wireType)
 			}
-			var mapkey string
-			var mapvalue *CountPerKV
-			for iNdEx < postIndex {
-				entryPreIndex := iNdEx
-				var wire uint64
-				for shift := uint(0); ; shift += 7 {
-					if shift >= 64 {
-						return ErrIntOverflowSpec
-					}
-					if iNdEx >= l {
-						return io.ErrUnexpectedEOF
-					}
-					b := dAtA[iNdEx]
-					iNdEx++
-					wire |= uint64(b&0x7F) << shift
-					if b < 0x80 {
-						break
-					}
+			m.Previous = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
 				}
-				fieldNum := int32(wire >> 3)
-				if fieldNum == 1 {
-					var stringLenmapkey uint64
-					for shift := uint(0); ; shift += 7 {
-						if shift >= 64 {
-							return ErrIntOverflowSpec
-						}
-						if iNdEx >= l {
-							return io.ErrUnexpectedEOF
-						}
-						b := dAtA[iNdEx]
-						iNdEx++
-						stringLenmapkey |= uint64(b&0x7F) << shift
-						if b < 0x80 {
-							break
-						}
-					}
-					intStringLenmapkey := int(stringLenmapkey)
-					if intStringLenmapkey < 0 {
-						return ErrInvalidLengthSpec
-					}
-					postStringIndexmapkey := iNdEx + intStringLenmapkey
-					if postStringIndexmapkey < 0 {
-						return ErrInvalidLengthSpec
-					}
-					if postStringIndexmapkey > l {
-						return io.ErrUnexpectedEOF
-					}
-					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
-					iNdEx = postStringIndexmapkey
-				} else if fieldNum == 2 {
-					var mapmsglen int
-					for shift := uint(0); ; shift += 7 {
-						if shift >= 64 {
-							return ErrIntOverflowSpec
-						}
-						if iNdEx >= l {
-							return io.ErrUnexpectedEOF
-						}
-						b := dAtA[iNdEx]
-						iNdEx++
-						mapmsglen |= int(b&0x7F) << shift
-						if b < 0x80 {
-							break
-						}
-					}
-					if mapmsglen < 0 {
-						return ErrInvalidLengthSpec
-					}
-					postmsgIndex := iNdEx + mapmsglen
-					if postmsgIndex < 0 {
-						return ErrInvalidLengthSpec
-					}
-					if postmsgIndex > l {
-						return io.ErrUnexpectedEOF
-					}
-					mapvalue = &CountPerKV{}
-					if err := mapvalue.Unmarshal(dAtA[iNdEx:postmsgIndex]); err != nil {
-						return err
-					}
-					iNdEx = postmsgIndex
-				} else {
-					iNdEx = entryPreIndex
-					skippy, err := skipSpec(dAtA[iNdEx:])
-					if err != nil {
-						return err
-					}
-					if skippy < 0 {
-						return ErrInvalidLengthSpec
-					}
-					if (iNdEx + skippy) > postIndex {
-						return io.ErrUnexpectedEOF
-					}
-					iNdEx += skippy
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Previous |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
 				}
 			}
-			m.Count[mapkey] = mapvalue
-			iNdEx = postIndex
-		case 3:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ForeignId", wireType)
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CheckedAtNs", wireType)
 			}
-			var msglen int
+			m.CheckedAtNs = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSpec
@@ -6235,126 +17793,35 @@ func (m *Aggregate) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				m.CheckedAtNs |= int64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthSpec
-			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
-				return ErrInvalidLengthSpec
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			if m.ForeignId == nil {
-				m.ForeignId = make(map[string]*CountPerKV)
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ChangedAtNs", wireType)
 			}
-			var mapkey string
-			var mapvalue *CountPerKV
-			for iNdEx < postIndex {
-				entryPreIndex := iNdEx
-				var wire uint64
-				for shift := uint(0); ; shift += 7 {
-					if shift >= 64 {
-						return ErrIntOverflowSpec
-					}
-					if iNdEx >= l {
-						return io.ErrUnexpectedEOF
-					}
-					b := dAtA[iNdEx]
-					iNdEx++
-					wire |= uint64(b&0x7F) << shift
-					if b < 0x80 {
-						break
-					}
+			m.ChangedAtNs = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
 				}
-				fieldNum := int32(wire >> 3)
-				if fieldNum == 1 {
-					var stringLenmapkey uint64
-					for shift := uint(0); ; shift += 7 {
-						if shift >= 64 {
-							return ErrIntOverflowSpec
-						}
-						if iNdEx >= l {
-							return io.ErrUnexpectedEOF
-						}
-						b := dAtA[iNdEx]
-						iNdEx++
-						stringLenmapkey |= uint64(b&0x7F) << shift
-						if b < 0x80 {
-							break
-						}
-					}
-					intStringLenmapkey := int(stringLenmapkey)
-					if intStringLenmapkey < 0 {
-						return ErrInvalidLengthSpec
-					}
-					postStringIndexmapkey := iNdEx + intStringLenmapkey
-					if postStringIndexmapkey < 0 {
-						return ErrInvalidLengthSpec
-					}
-					if postStringIndexmapkey > l {
-						return io.ErrUnexpectedEOF
-					}
-					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
-					iNdEx = postStringIndexmapkey
-				} else if fieldNum == 2 {
-					var mapmsglen int
-					for shift := uint(0); ; shift += 7 {
-						if shift >= 64 {
-							return ErrIntOverflowSpec
-						}
-						if iNdEx >= l {
-							return io.ErrUnexpectedEOF
-						}
-						b := dAtA[iNdEx]
-						iNdEx++
-						mapmsglen |= int(b&0x7F) << shift
-						if b < 0x80 {
-							break
-						}
-					}
-					if mapmsglen < 0 {
-						return ErrInvalidLengthSpec
-					}
-					postmsgIndex := iNdEx + mapmsglen
-					if postmsgIndex < 0 {
-						return ErrInvalidLengthSpec
-					}
-					if postmsgIndex > l {
-						return io.ErrUnexpectedEOF
-					}
-					mapvalue = &CountPerKV{}
-					if err := mapvalue.Unmarshal(dAtA[iNdEx:postmsgIndex]); err != nil {
-						return err
-					}
-					iNdEx = postmsgIndex
-				} else {
-					iNdEx = entryPreIndex
-					skippy, err := skipSpec(dAtA[iNdEx:])
-					if err != nil {
-						return err
-					}
-					if skippy < 0 {
-						return ErrInvalidLengthSpec
-					}
-					if (iNdEx + skippy) > postIndex {
-						return io.ErrUnexpectedEOF
-					}
-					iNdEx += skippy
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ChangedAtNs |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
 				}
 			}
-			m.ForeignId[mapkey] = mapvalue
-			iNdEx = postIndex
-		case 4:
+		case 7:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field EventType", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Error", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSpec
@@ -6364,126 +17831,82 @@ func (m *Aggregate) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthSpec
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthSpec
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.EventType == nil {
-				m.EventType = make(map[string]*CountPerKV)
+			m.Error = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSpec(dAtA[iNdEx:])
+			if err != nil {
+				return err
 			}
-			var mapkey string
-			var mapvalue *CountPerKV
-			for iNdEx < postIndex {
-				entryPreIndex := iNdEx
-				var wire uint64
-				for shift := uint(0); ; shift += 7 {
-					if shift >= 64 {
-						return ErrIntOverflowSpec
-					}
-					if iNdEx >= l {
-						return io.ErrUnexpectedEOF
-					}
-					b := dAtA[iNdEx]
-					iNdEx++
-					wire |= uint64(b&0x7F) << shift
-					if b < 0x80 {
-						break
-					}
-				}
-				fieldNum := int32(wire >> 3)
-				if fieldNum == 1 {
-					var stringLenmapkey uint64
-					for shift := uint(0); ; shift += 7 {
-						if shift >= 64 {
-							return ErrIntOverflowSpec
-						}
-						if iNdEx >= l {
-							return io.ErrUnexpectedEOF
-						}
-						b := dAtA[iNdEx]
-						iNdEx++
-						stringLenmapkey |= uint64(b&0x7F) << shift
-						if b < 0x80 {
-							break
-						}
-					}
-					intStringLenmapkey := int(stringLenmapkey)
-					if intStringLenmapkey < 0 {
-						return ErrInvalidLengthSpec
-					}
-					postStringIndexmapkey := iNdEx + intStringLenmapkey
-					if postStringIndexmapkey < 0 {
-						return ErrInvalidLengthSpec
-					}
-					if postStringIndexmapkey > l {
-						return io.ErrUnexpectedEOF
-					}
-					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
-					iNdEx = postStringIndexmapkey
-				} else if fieldNum == 2 {
-					var mapmsglen int
-					for shift := uint(0); ; shift += 7 {
-						if shift >= 64 {
-							return ErrIntOverflowSpec
-						}
-						if iNdEx >= l {
-							return io.ErrUnexpectedEOF
-						}
-						b := dAtA[iNdEx]
-						iNdEx++
-						mapmsglen |= int(b&0x7F) << shift
-						if b < 0x80 {
-							break
-						}
-					}
-					if mapmsglen < 0 {
-						return ErrInvalidLengthSpec
-					}
-					postmsgIndex := iNdEx + mapmsglen
-					if postmsgIndex < 0 {
-						return ErrInvalidLengthSpec
-					}
-					if postmsgIndex > l {
-						return io.ErrUnexpectedEOF
-					}
-					mapvalue = &CountPerKV{}
-					if err := mapvalue.Unmarshal(dAtA[iNdEx:postmsgIndex]); err != nil {
-						return err
-					}
-					iNdEx = postmsgIndex
-				} else {
-					iNdEx = entryPreIndex
-					skippy, err := skipSpec(dAtA[iNdEx:])
-					if err != nil {
-						return err
-					}
-					if skippy < 0 {
-						return ErrInvalidLengthSpec
-					}
-					if (iNdEx + skippy) > postIndex {
-						return io.ErrUnexpectedEOF
-					}
-					iNdEx += skippy
-				}
+			if skippy < 0 {
+				return ErrInvalidLengthSpec
 			}
-			m.EventType[mapkey] = mapvalue
-			iNdEx = postIndex
-		case 5:
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *AlertEvent) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSpec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: AlertEvent: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: AlertEvent: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Possible", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSpec
@@ -6492,111 +17915,30 @@ func (m *Aggregate) Unmarshal(dAtA []byte) error {
 					return io.ErrUnexpectedEOF
 				}
 				b := dAtA[iNdEx]
-				iNdEx++
-				msglen |= int(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			if msglen < 0 {
-				return ErrInvalidLengthSpec
-			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
-				return ErrInvalidLengthSpec
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			if m.Possible == nil {
-				m.Possible = make(map[string]uint32)
-			}
-			var mapkey string
-			var mapvalue uint32
-			for iNdEx < postIndex {
-				entryPreIndex := iNdEx
-				var wire uint64
-				for shift := uint(0); ; shift += 7 {
-					if shift >= 64 {
-						return ErrIntOverflowSpec
-					}
-					if iNdEx >= l {
-						return io.ErrUnexpectedEOF
-					}
-					b := dAtA[iNdEx]
-					iNdEx++
-					wire |= uint64(b&0x7F) << shift
-					if b < 0x80 {
-						break
-					}
-				}
-				fieldNum := int32(wire >> 3)
-				if fieldNum == 1 {
-					var stringLenmapkey uint64
-					for shift := uint(0); ; shift += 7 {
-						if shift >= 64 {
-							return ErrIntOverflowSpec
-						}
-						if iNdEx >= l {
-							return io.ErrUnexpectedEOF
-						}
-						b := dAtA[iNdEx]
-						iNdEx++
-						stringLenmapkey |= uint64(b&0x7F) << shift
-						if b < 0x80 {
-							break
-						}
-					}
-					intStringLenmapkey := int(stringLenmapkey)
-					if intStringLenmapkey < 0 {
-						return ErrInvalidLengthSpec
-					}
-					postStringIndexmapkey := iNdEx + intStringLenmapkey
-					if postStringIndexmapkey < 0 {
-						return ErrInvalidLengthSpec
-					}
-					if postStringIndexmapkey > l {
-						return io.ErrUnexpectedEOF
-					}
-					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
-					iNdEx = postStringIndexmapkey
-				} else if fieldNum == 2 {
-					for shift := uint(0); ; shift += 7 {
-						if shift >= 64 {
-							return ErrIntOverflowSpec
-						}
-						if iNdEx >= l {
-							return io.ErrUnexpectedEOF
-						}
-						b := dAtA[iNdEx]
-						iNdEx++
-						mapvalue |= uint32(b&0x7F) << shift
-						if b < 0x80 {
-							break
-						}
-					}
-				} else {
-					iNdEx = entryPreIndex
-					skippy, err := skipSpec(dAtA[iNdEx:])
-					if err != nil {
-						return err
-					}
-					if skippy < 0 {
-						return ErrInvalidLengthSpec
-					}
-					if (iNdEx + skippy) > postIndex {
-						return io.ErrUnexpectedEOF
-					}
-					iNdEx += skippy
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
 				}
 			}
-			m.Possible[mapkey] = mapvalue
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 6:
+		case 2:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Total", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Firing", wireType)
 			}
-			m.Total = 0
+			var v int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSpec
@@ -6606,16 +17948,17 @@ func (m *Aggregate) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Total |= uint32(b&0x7F) << shift
+				v |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 7:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Sample", wireType)
+			m.Firing = bool(v != 0)
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Count", wireType)
 			}
-			var msglen int
+			m.Count = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSpec
@@ -6625,31 +17968,54 @@ func (m *Aggregate) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				m.Count |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthSpec
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Previous", wireType)
 			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
-				return ErrInvalidLengthSpec
+			m.Previous = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Previous |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AtNs", wireType)
 			}
-			m.Sample = append(m.Sample, &Hit{})
-			if err := m.Sample[len(m.Sample)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			m.AtNs = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.AtNs |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			iNdEx = postIndex
-		case 8:
+		case 6:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Chart", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Message", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSpec
@@ -6659,27 +18025,23 @@ func (m *Aggregate) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthSpec
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthSpec
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Chart == nil {
-				m.Chart = &Chart{}
-			}
-			if err := m.Chart.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.Message = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -6705,7 +18067,7 @@ func (m *Aggregate) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *SearchQueryResponse) Unmarshal(dAtA []byte) error {
+func (m *AlertsRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -6728,17 +18090,17 @@ func (m *SearchQueryResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: SearchQueryResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: AlertsRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: SearchQueryResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: AlertsRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Hits", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSpec
@@ -6748,45 +18110,24 @@ func (m *SearchQueryResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthSpec
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthSpec
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Hits = append(m.Hits, &Hit{})
-			if err := m.Hits[len(m.Hits)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.Name = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 2:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Total", wireType)
-			}
-			m.Total = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowSpec
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.Total |= uint64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipSpec(dAtA[iNdEx:])
@@ -6811,7 +18152,7 @@ func (m *SearchQueryResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Envelope) Unmarshal(dAtA []byte) error {
+func (m *AlertsResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -6834,15 +18175,15 @@ func (m *Envelope) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Envelope: wiretype end group for non-group")
+			return fmt.Errorf("proto: AlertsResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Envelope: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: AlertsResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Metadata", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field States", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -6869,18 +18210,16 @@ func (m *Envelope) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Metadata == nil {
-				m.Metadata = &Metadata{}
-			}
-			if err := m.Metadata.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.States = append(m.States, &AlertState{})
+			if err := m.States[len(m.States)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Payload", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field History", wireType)
 			}
-			var byteLen int
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSpec
@@ -6890,24 +18229,24 @@ func (m *Envelope) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= int(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthSpec
 			}
-			postIndex := iNdEx + byteLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthSpec
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Payload = append(m.Payload[:0], dAtA[iNdEx:postIndex]...)
-			if m.Payload == nil {
-				m.Payload = []byte{}
+			m.History = append(m.History, &AlertEvent{})
+			if err := m.History[len(m.History)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
 			}
 			iNdEx = postIndex
 		default:
@@ -6934,7 +18273,7 @@ func (m *Envelope) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Success) Unmarshal(dAtA []byte) error {
+func (m *TailRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -6957,17 +18296,17 @@ func (m *Success) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Success: wiretype end group for non-group")
+			return fmt.Errorf("proto: TailRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Success: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: TailRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Success", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Query", wireType)
 			}
-			var v int
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSpec
@@ -6977,12 +18316,28 @@ func (m *Success) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				v |= int(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			m.Success = bool(v != 0)
+			if msglen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Query == nil {
+				m.Query = &go_query_dsl.Query{}
+			}
+			if err := m.Query.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipSpec(dAtA[iNdEx:])
@@ -7007,7 +18362,7 @@ func (m *Success) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *HealthRequest) Unmarshal(dAtA []byte) error {
+func (m *TailEvent) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -7030,12 +18385,67 @@ func (m *HealthRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: HealthRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: TailEvent: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: HealthRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: TailEvent: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Hit", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSpec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthSpec
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Hit == nil {
+				m.Hit = &Hit{}
+			}
+			if err := m.Hit.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Dropped", wireType)
+			}
+			m.Dropped = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSpec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Dropped |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipSpec(dAtA[iNdEx:])
@@ -7063,6 +18473,7 @@ func (m *HealthRequest) Unmarshal(dAtA []byte) error {
 func skipSpec(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
+	depth := 0
 	for iNdEx < l {
 		var wire uint64
 		for shift := uint(0); ; shift += 7 {
@@ -7094,10 +18505,8 @@ func skipSpec(dAtA []byte) (n int, err error) {
 					break
 				}
 			}
-			return iNdEx, nil
 		case 1:
 			iNdEx += 8
-			return iNdEx, nil
 		case 2:
 			var length int
 			for shift := uint(0); ; shift += 7 {
@@ -7118,55 +18527,30 @@ func skipSpec(dAtA []byte) (n int, err error) {
 				return 0, ErrInvalidLengthSpec
 			}
 			iNdEx += length
-			if iNdEx < 0 {
-				return 0, ErrInvalidLengthSpec
-			}
-			return iNdEx, nil
 		case 3:
-			for {
-				var innerWire uint64
-				var start int = iNdEx
-				for shift := uint(0); ; shift += 7 {
-					if shift >= 64 {
-						return 0, ErrIntOverflowSpec
-					}
-					if iNdEx >= l {
-						return 0, io.ErrUnexpectedEOF
-					}
-					b := dAtA[iNdEx]
-					iNdEx++
-					innerWire |= (uint64(b) & 0x7F) << shift
-					if b < 0x80 {
-						break
-					}
-				}
-				innerWireType := int(innerWire & 0x7)
-				if innerWireType == 4 {
-					break
-				}
-				next, err := skipSpec(dAtA[start:])
-				if err != nil {
-					return 0, err
-				}
-				iNdEx = start + next
-				if iNdEx < 0 {
-					return 0, ErrInvalidLengthSpec
-				}
-			}
-			return iNdEx, nil
+			depth++
 		case 4:
-			return iNdEx, nil
+			if depth == 0 {
+				return 0, ErrUnexpectedEndOfGroupSpec
+			}
+			depth--
 		case 5:
 			iNdEx += 4
-			return iNdEx, nil
 		default:
 			return 0, fmt.Errorf("proto: illegal wireType %d", wireType)
 		}
+		if iNdEx < 0 {
+			return 0, ErrInvalidLengthSpec
+		}
+		if depth == 0 {
+			return iNdEx, nil
+		}
 	}
-	panic("unreachable")
+	return 0, io.ErrUnexpectedEOF
 }
 
 var (
-	ErrInvalidLengthSpec = fmt.Errorf("proto: negative length found during unmarshaling")
-	ErrIntOverflowSpec   = fmt.Errorf("proto: integer overflow")
+	ErrInvalidLengthSpec        = fmt.Errorf("proto: negative length found during unmarshaling")
+	ErrIntOverflowSpec          = fmt.Errorf("proto: integer overflow")
+	ErrUnexpectedEndOfGroupSpec = fmt.Errorf("proto: unexpected end of group")
 )
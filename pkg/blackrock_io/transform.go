@@ -13,7 +13,6 @@ import (
 
 	"github.com/mssola/user_agent"
 	"github.com/oschwald/geoip2-golang"
-	"github.com/rekki/blackrock/pkg/depths"
 	"github.com/tomasen/realip"
 )
 
@@ -78,15 +77,29 @@ type JsonFrame struct {
 }
 
 func Transform(m map[string]interface{}, expand bool) ([]KV, error) {
-	out := []KV{}
-	flatten, err := depths.Flatten(m, "", depths.DotStyle)
-	if err != nil {
-		return nil, err
-	}
-	hasID := func(s string) bool {
-		return strings.HasSuffix(s, "_id") || strings.HasSuffix(s, "_ids") || strings.HasSuffix(s, "_code")
+	return TransformWithRules(m, expand, &DefaultFlattenRules)
+}
+
+func TransformWithRules(m map[string]interface{}, expand bool, rules *FlattenRules) ([]KV, error) {
+	out, _, err := transform(m, expand, rules, false)
+	return out, err
+}
+
+// TransformTyped is TransformWithRules that also returns the numeric values
+// with their type kept, only the json numbers are there, not "1.5"
+func TransformTyped(m map[string]interface{}, expand bool, rules *FlattenRules) ([]KV, []KF, error) {
+	return transform(m, expand, rules, true)
+}
+
+func transform(m map[string]interface{}, expand bool, rules *FlattenRules, typed bool) ([]KV, []KF, error) {
+	var numbers *[]KF
+	if typed {
+		numbers = &[]KF{}
 	}
-	for k, v := range flatten {
+	flatten := rules.flatten([]KV{}, numbers, m, "", 0)
+	out := make([]KV, 0, len(flatten))
+	for _, kv := range flatten {
+		k, v := kv.Key, kv.Value
 		if expand {
 
 			// a lot of CoC here, path like example.restaurant_id.92e2e4af-f833-492e-9ade-f797bbaa80fd.updated = true
@@ -97,7 +110,7 @@ func Transform(m map[string]interface{}, expand bool) ([]KV, error) {
 				if len(splitted) > 1 {
 					for i := 0; i < len(splitted)-1; i++ {
 						p := splitted[i]
-						if hasID(p) {
+						if rules.expandable(p) {
 							k = strings.Join(splitted[:i+1], ".")
 							v = strings.Join(splitted[i+1:], ".")
 
@@ -107,10 +120,18 @@ func Transform(m map[string]interface{}, expand bool) ([]KV, error) {
 				}
 			}
 		}
-		out = append(out, KV{Key: k, Value: v})
+		out = append(out, KV{Key: cut(k, rules.MaxKeyLength), Value: cut(v, rules.MaxValueLength)})
 	}
 
-	return out, nil
+	if numbers == nil {
+		return out, nil, nil
+	}
+	return out, *numbers, nil
+}
+
+// isDecoration is true for the search keys Decorate adds
+func isDecoration(key string) bool {
+	return key == "ip" || strings.HasPrefix(key, "geoip_") || strings.HasPrefix(key, "ua_")
 }
 
 func Decorate(g *geoip2.Reader, r *http.Request, e *Envelope) error {
@@ -156,7 +177,9 @@ func Decorate(g *geoip2.Reader, r *http.Request, e *Envelope) error {
 	return nil
 }
 
-func DecodeAndFlatten(body io.Reader) (*Envelope, error) {
+// DecodeAndFlatten decodes one JsonFrame, the flatten rules are picked by
+// its event_type, a nil config means the default rules
+func DecodeAndFlatten(body io.Reader, config *FlattenConfig) (*Envelope, error) {
 	var metadata JsonFrame
 	data, err := ioutil.ReadAll(body)
 	if err != nil {
@@ -168,28 +191,40 @@ func DecodeAndFlatten(body io.Reader) (*Envelope, error) {
 		return nil, err
 	}
 
+	return FlattenFrame(&metadata, config)
+}
+
+func FlattenFrame(metadata *JsonFrame, config *FlattenConfig) (*Envelope, error) {
+	rules := config.For(metadata.EventType)
+
+	var err error
 	search := []KV{}
 	if metadata.Search != nil {
-		search, err = Transform(metadata.Search, true)
+		search, err = TransformWithRules(metadata.Search, true, rules)
 		if err != nil {
 			return nil, err
 		}
 	}
 
+	numbers := []KF{}
 	count := []KV{}
 	if metadata.Count != nil {
-		count, err = Transform(metadata.Count, true)
+		var kf []KF
+		count, kf, err = TransformTyped(metadata.Count, true, rules)
 		if err != nil {
 			return nil, err
 		}
+		numbers = append(numbers, kf...)
 	}
 
 	properties := []KV{}
 	if metadata.Properties != nil {
-		properties, err = Transform(metadata.Properties, true)
+		var kf []KF
+		properties, kf, err = TransformTyped(metadata.Properties, true, rules)
 		if err != nil {
 			return nil, err
 		}
+		numbers = append(numbers, kf...)
 	}
 
 	converted := Envelope{
@@ -197,6 +232,7 @@ func DecodeAndFlatten(body io.Reader) (*Envelope, error) {
 			Search:      search,
 			Count:       count,
 			Properties:  properties,
+			Numbers:     numbers,
 			CreatedAtNs: metadata.CreatedAtNs,
 			EventType:   metadata.EventType,
 			ForeignId:   metadata.ForeignId,
@@ -243,9 +279,9 @@ func ToString(v interface{}) string {
 	case uint16:
 		value = fmt.Sprintf("%d", v.(uint16))
 	case float32:
-		value = strconv.FormatFloat(float64(v.(float32)), 'f', 0, 64)
+		value = strconv.FormatFloat(float64(v.(float32)), 'f', -1, 32)
 	case float64:
-		value = strconv.FormatFloat(v.(float64), 'f', 0, 64)
+		value = strconv.FormatFloat(v.(float64), 'f', -1, 64)
 	case nil:
 		value = "nil"
 	default:
@@ -255,6 +291,31 @@ func ToString(v interface{}) string {
 	return value
 }
 
+// ToFloat returns the value of the numeric types
+func ToFloat(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case int16:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case uint16:
+		return float64(v), true
+	}
+	return 0, false
+}
+
 func ToKV(key string, v interface{}) KV {
 	value := ToString(v)
 	return KV{Key: key, Value: value}
@@ -4,9 +4,11 @@ import (
 	"errors"
 )
 
+var errMissingMetadata = errors.New("need metadata key")
+
 func ValidateEnvelope(envelope *Envelope) error {
 	if envelope.Metadata == nil {
-		return errors.New("need metadata key")
+		return errMissingMetadata
 	}
 	if envelope.Metadata.ForeignId == "" {
 		return errors.New("need foreign_id in metadata")
@@ -0,0 +1,191 @@
+package certs
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+var errKeyWithoutCert = errors.New("-tls-cert and -tls-key go together")
+var errClientKeyWithoutCert = errors.New("-tls-client-cert and -tls-client-key go together")
+var errClientCAWithoutCert = errors.New("-tls-client-ca needs -tls-cert")
+var errNotOwnCertificate = errors.New("not our own certificate")
+
+// Flags are the tls flags of a command, the listeners use tls when there is
+// a certificate and the dials when there is a ca or a client certificate
+type Flags struct {
+	Cert       *string
+	Key        *string
+	ClientCA   *string
+	CA         *string
+	ClientCert *string
+	ClientKey  *string
+	ServerName *string
+}
+
+func AddFlags(fs *flag.FlagSet) *Flags {
+	return &Flags{
+		Cert:       fs.String("tls-cert", "", "pem certificate of the grpc and http listeners, nothing means plaintext"),
+		Key:        fs.String("tls-key", "", "pem key of -tls-cert"),
+		ClientCA:   fs.String("tls-client-ca", "", "pem ca the clients certificates must be signed by (mtls), nothing means no client certificates"),
+		CA:         fs.String("tls-ca", "", "pem ca to verify the servers we connect to, nothing means the system ones, if neither this nor -tls-client-cert is set the connections are plaintext"),
+		ClientCert: fs.String("tls-client-cert", "", "pem certificate we present when connecting, for servers with -tls-client-ca"),
+		ClientKey:  fs.String("tls-client-key", "", "pem key of -tls-client-cert"),
+		ServerName: fs.String("tls-server-name", "", "name to verify in the servers certificates instead of the host we connect to"),
+	}
+}
+
+func readPool(fn string) (*x509.CertPool, error) {
+	data, err := ioutil.ReadFile(fn)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no certificates in %s", fn)
+	}
+	return pool, nil
+}
+
+// Server is the config of the listeners, nil means plaintext
+func (f *Flags) Server() (*tls.Config, error) {
+	if (*f.Cert == "") != (*f.Key == "") {
+		return nil, errKeyWithoutCert
+	}
+	if *f.Cert == "" {
+		if *f.ClientCA != "" {
+			return nil, errClientCAWithoutCert
+		}
+		return nil, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(*f.Cert, *f.Key)
+	if err != nil {
+		return nil, err
+	}
+	c := &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}
+	if *f.ClientCA != "" {
+		c.ClientCAs, err = readPool(*f.ClientCA)
+		if err != nil {
+			return nil, err
+		}
+		c.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return c, nil
+}
+
+// Client is the config of the connections we make, nil means plaintext
+func (f *Flags) Client() (*tls.Config, error) {
+	if (*f.ClientCert == "") != (*f.ClientKey == "") {
+		return nil, errClientKeyWithoutCert
+	}
+	if *f.CA == "" && *f.ClientCert == "" {
+		return nil, nil
+	}
+
+	c := &tls.Config{ServerName: *f.ServerName, MinVersion: tls.VersionTLS12}
+	if *f.CA != "" {
+		pool, err := readPool(*f.CA)
+		if err != nil {
+			return nil, err
+		}
+		c.RootCAs = pool
+	}
+	if *f.ClientCert != "" {
+		cert, err := tls.LoadX509KeyPair(*f.ClientCert, *f.ClientKey)
+		if err != nil {
+			return nil, err
+		}
+		c.Certificates = []tls.Certificate{cert}
+	}
+	return c, nil
+}
+
+// Local is the config to connect to our own listener, like the http gateway
+// does, it accepts only our certificate whatever the names in it are. The
+// client certificate is -tls-client-cert or the server one, so with
+// -tls-client-ca it needs the client auth usage.
+func (f *Flags) Local() (*tls.Config, error) {
+	server, err := f.Server()
+	if server == nil || err != nil {
+		return nil, err
+	}
+
+	own := server.Certificates[0].Certificate[0]
+	c := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: true,
+		VerifyPeerCertificate: func(raw [][]byte, _ [][]*x509.Certificate) error {
+			if len(raw) == 0 || !bytes.Equal(raw[0], own) {
+				return errNotOwnCertificate
+			}
+			return nil
+		},
+	}
+
+	if *f.ClientCert != "" {
+		client, err := f.Client()
+		if err != nil {
+			return nil, err
+		}
+		c.Certificates = client.Certificates
+	} else if server.ClientCAs != nil {
+		c.Certificates = server.Certificates
+	}
+	return c, nil
+}
+
+func (f *Flags) ServerOptions() ([]grpc.ServerOption, error) {
+	c, err := f.Server()
+	if c == nil || err != nil {
+		return []grpc.ServerOption{}, err
+	}
+	return []grpc.ServerOption{grpc.Creds(credentials.NewTLS(c))}, nil
+}
+
+func dialOptions(c *tls.Config, err error) ([]grpc.DialOption, error) {
+	if err != nil {
+		return nil, err
+	}
+	if c == nil {
+		return []grpc.DialOption{grpc.WithInsecure()}, nil
+	}
+	return []grpc.DialOption{grpc.WithTransportCredentials(credentials.NewTLS(c))}, nil
+}
+
+// DialOptions replace grpc.WithInsecure()
+func (f *Flags) DialOptions() ([]grpc.DialOption, error) {
+	return dialOptions(f.Client())
+}
+
+// LocalDialOptions are the DialOptions to connect to our own grpc listener
+func (f *Flags) LocalDialOptions() ([]grpc.DialOption, error) {
+	return dialOptions(f.Local())
+}
+
+// ListenAndServe is http.ListenAndServe with the Server config
+func (f *Flags) ListenAndServe(addr string, h http.Handler) error {
+	c, err := f.Server()
+	if err != nil {
+		return err
+	}
+	if c == nil {
+		return http.ListenAndServe(addr, h)
+	}
+
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	s := &http.Server{Addr: addr, Handler: h, TLSConfig: c}
+	return s.ServeTLS(lis, "", "")
+}
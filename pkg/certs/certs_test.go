@@ -0,0 +1,225 @@
+package certs
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"flag"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	spec "github.com/rekki/blackrock/pkg/blackrock_io"
+	"google.golang.org/grpc"
+)
+
+type pair struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+// generate writes name.pem and name.key signed by parent, or self signed
+func generate(t *testing.T, dir, name string, parent *pair, usage []x509.ExtKeyUsage) *pair {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  usage,
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	signer := &pair{cert: template, key: key}
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage |= x509.KeyUsageCertSign
+	} else {
+		signer = parent
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, signer.cert, &key.PublicKey, signer.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = ioutil.WriteFile(path.Join(dir, name+".pem"), pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ioutil.WriteFile(path.Join(dir, name+".key"), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &pair{cert: cert, key: key}
+}
+
+func parse(t *testing.T, args ...string) *Flags {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	f := AddFlags(fs)
+	err := fs.Parse(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+type healthServer struct {
+	spec.UnimplementedSearchServer
+}
+
+func (h *healthServer) SayHealth(context.Context, *spec.HealthRequest) (*spec.Success, error) {
+	return &spec.Success{Success: true}, nil
+}
+
+func TestCerts(t *testing.T) {
+	dir, err := ioutil.TempDir("", "certs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	ca := generate(t, dir, "ca", nil, nil)
+	generate(t, dir, "server", ca, []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth})
+	generate(t, dir, "client", ca, []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth})
+	other := generate(t, dir, "other-ca", nil, nil)
+	generate(t, dir, "intruder", other, []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth})
+	p := func(name string) string {
+		return path.Join(dir, name)
+	}
+
+	server := parse(t, "-tls-cert", p("server.pem"), "-tls-key", p("server.key"), "-tls-client-ca", p("ca.pem"))
+	opts, err := server.ServerOptions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	grpcServer := grpc.NewServer(opts...)
+	spec.RegisterSearchServer(grpcServer, &healthServer{})
+	go func() {
+		_ = grpcServer.Serve(lis)
+	}()
+	defer grpcServer.Stop()
+
+	health := func(f *Flags, local bool) error {
+		var opts []grpc.DialOption
+		if local {
+			opts, err = f.LocalDialOptions()
+		} else {
+			opts, err = f.DialOptions()
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		conn, err := grpc.DialContext(ctx, lis.Addr().String(), opts...)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		_, err = spec.NewSearchClient(conn).SayHealth(ctx, &spec.HealthRequest{})
+		return err
+	}
+
+	err = health(parse(t, "-tls-ca", p("ca.pem"), "-tls-client-cert", p("client.pem"), "-tls-client-key", p("client.key")), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = health(server, true)
+	if err != nil {
+		t.Fatalf("expected the local dial to work, got %v", err)
+	}
+	for _, f := range []*Flags{
+		parse(t),
+		parse(t, "-tls-ca", p("ca.pem")),
+		parse(t, "-tls-ca", p("ca.pem"), "-tls-client-cert", p("intruder.pem"), "-tls-client-key", p("intruder.key")),
+		parse(t, "-tls-ca", p("other-ca.pem"), "-tls-client-cert", p("client.pem"), "-tls-client-key", p("client.key")),
+	} {
+		if health(f, false) == nil {
+			t.Fatalf("expected failure with %s %s", *f.CA, *f.ClientCert)
+		}
+	}
+
+	httpAddr := freeAddr(t)
+	go func() {
+		_ = server.ListenAndServe(httpAddr, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("OK"))
+		}))
+	}()
+	for i := 0; i < 100; i++ {
+		conn, err := net.Dial("tcp", httpAddr)
+		if err == nil {
+			conn.Close()
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	get := func(f *Flags) error {
+		c, err := f.Client()
+		if err != nil {
+			t.Fatal(err)
+		}
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: c}, Timeout: 5 * time.Second}
+		res, err := client.Get("https://" + httpAddr + "/")
+		if err != nil {
+			return err
+		}
+		res.Body.Close()
+		return nil
+	}
+	err = get(parse(t, "-tls-ca", p("ca.pem"), "-tls-client-cert", p("client.pem"), "-tls-client-key", p("client.key")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if get(parse(t, "-tls-ca", p("ca.pem"))) == nil {
+		t.Fatal("expected failure without a client certificate")
+	}
+
+	for _, args := range [][]string{
+		{"-tls-cert", p("server.pem")},
+		{"-tls-client-ca", p("ca.pem")},
+		{"-tls-cert", p("server.pem"), "-tls-key", p("server.key"), "-tls-client-ca", p("server.key")},
+	} {
+		if _, err := parse(t, args...).Server(); err == nil {
+			t.Fatalf("expected error for %v", args)
+		}
+	}
+	if _, err := parse(t, "-tls-client-cert", p("client.pem")).Client(); err != errClientKeyWithoutCert {
+		t.Fatalf("expected error got %v", err)
+	}
+}
+
+func freeAddr(t *testing.T) string {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lis.Close()
+	return lis.Addr().String()
+}
@@ -0,0 +1,243 @@
+package deadletter
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	spec "github.com/rekki/blackrock/pkg/blackrock_io"
+	pen "github.com/rekki/go-pen"
+	"github.com/segmentio/kafka-go"
+)
+
+var errMissingSink = errors.New("dead letter sink must be file:<path> or kafka:<topic>")
+
+// Sink stores the messages the consumer could not ingest, Write returns
+// only after the dead letter is durable, so the offset can be committed
+type Sink interface {
+	Write(*spec.DeadLetter) error
+	Close() error
+}
+
+// NewSink parses file:<path> or kafka:<topic>
+func NewSink(s string, kafkaServers string) (Sink, error) {
+	switch {
+	case strings.HasPrefix(s, "file:"):
+		return NewFileSink(strings.TrimPrefix(s, "file:"))
+	case strings.HasPrefix(s, "kafka:"):
+		return NewKafkaSink(kafkaServers, strings.TrimPrefix(s, "kafka:")), nil
+	}
+	return nil, errMissingSink
+}
+
+type fileSink struct {
+	writer *pen.Writer
+	sync.Mutex
+}
+
+// NewFileSink appends the dead letters to a local pen file
+func NewFileSink(filename string) (Sink, error) {
+	w, err := pen.NewWriter(filename)
+	if err != nil {
+		return nil, err
+	}
+	return &fileSink{writer: w}, nil
+}
+
+func (s *fileSink) Write(d *spec.DeadLetter) error {
+	if d.CreatedAtNs == 0 {
+		d.CreatedAtNs = time.Now().UnixNano()
+	}
+	encoded, err := proto.Marshal(d)
+	if err != nil {
+		return err
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	_, _, err = s.writer.Append(encoded)
+	if err != nil {
+		return err
+	}
+	return s.writer.Sync()
+}
+
+func (s *fileSink) Close() error {
+	return s.writer.Close()
+}
+
+type kafkaSink struct {
+	writer *kafka.Writer
+}
+
+// NewKafkaSink writes the dead letters to a topic, one message per dead
+// letter
+func NewKafkaSink(kafkaServers string, topic string) Sink {
+	return &kafkaSink{
+		writer: kafka.NewWriter(kafka.WriterConfig{
+			Brokers:  strings.Split(kafkaServers, ","),
+			Topic:    topic,
+			Balancer: &kafka.LeastBytes{},
+		}),
+	}
+}
+
+func (s *kafkaSink) Write(d *spec.DeadLetter) error {
+	if d.CreatedAtNs == 0 {
+		d.CreatedAtNs = time.Now().UnixNano()
+	}
+	encoded, err := proto.Marshal(d)
+	if err != nil {
+		return err
+	}
+	return s.writer.WriteMessages(context.Background(), kafka.Message{Value: encoded})
+}
+
+func (s *kafkaSink) Close() error {
+	return s.writer.Close()
+}
+
+// Read reads the dead letters from file:<path> or kafka:<topic>
+func Read(s string, kafkaServers string, cb func(*spec.DeadLetter) error) error {
+	switch {
+	case strings.HasPrefix(s, "file:"):
+		return ReadFile(strings.TrimPrefix(s, "file:"), cb)
+	case strings.HasPrefix(s, "kafka:"):
+		return ReadTopic(kafkaServers, strings.TrimPrefix(s, "kafka:"), DefaultIdle, cb)
+	}
+	return errMissingSink
+}
+
+// ReadFile calls cb for every dead letter in the file
+func ReadFile(filename string, cb func(*spec.DeadLetter) error) error {
+	return ReadFileFrom(filename, 0, func(d *spec.DeadLetter, next uint32) error {
+		return cb(d)
+	})
+}
+
+// ReadFileFrom calls cb for every dead letter in the file starting at
+// offset, next is the offset after the dead letter so the caller can store
+// it and resume from there
+func ReadFileFrom(filename string, offset uint32, cb func(d *spec.DeadLetter, next uint32) error) error {
+	f, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	for int64(offset)*int64(pen.PAD) < info.Size() {
+		data, next, err := pen.ReadFromReader(f, offset, 16)
+		if err == io.EOF {
+			// torn write at the end
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		d := &spec.DeadLetter{}
+		err = proto.Unmarshal(data, d)
+		if err != nil {
+			return err
+		}
+
+		err = cb(d, next)
+		if err != nil {
+			return err
+		}
+		offset = next
+	}
+	return nil
+}
+
+// DefaultIdle is how long Read waits for new dead letters in a topic before
+// it considers it done
+const DefaultIdle = 5 * time.Second
+
+// ReadTopic calls cb for every dead letter in all partitions of the topic,
+// until there is nothing new for idle
+func ReadTopic(kafkaServers string, topic string, idle time.Duration, cb func(*spec.DeadLetter) error) error {
+	return ReadTopicFrom(kafkaServers, topic, idle, func(partition int) (int64, error) {
+		return 0, nil
+	}, func(d *spec.DeadLetter, partition int, next int64) error {
+		return cb(d)
+	})
+}
+
+// ReadTopicFrom is ReadTopic starting every partition at the offset
+// returned by start, 0 or less is the first offset, next is the offset
+// after the dead letter in its partition so the caller can store it and
+// resume from there
+func ReadTopicFrom(kafkaServers string, topic string, idle time.Duration, start func(partition int) (int64, error), cb func(d *spec.DeadLetter, partition int, next int64) error) error {
+	conn, err := kafka.Dial("tcp", strings.Split(kafkaServers, ",")[0])
+	if err != nil {
+		return err
+	}
+	partitions, err := conn.ReadPartitions(topic)
+	conn.Close()
+	if err != nil {
+		return err
+	}
+
+	for _, p := range partitions {
+		r := kafka.NewReader(kafka.ReaderConfig{
+			Brokers:   strings.Split(kafkaServers, ","),
+			Topic:     topic,
+			Partition: p.ID,
+			MaxWait:   idle,
+		})
+
+		err = func() error {
+			defer r.Close()
+			offset, err := start(p.ID)
+			if err != nil {
+				return err
+			}
+			if offset <= 0 {
+				offset = kafka.FirstOffset
+			}
+			err = r.SetOffset(offset)
+			if err != nil {
+				return err
+			}
+
+			for {
+				ctx, cancel := context.WithTimeout(context.Background(), idle)
+				m, err := r.ReadMessage(ctx)
+				cancel()
+				if err == context.DeadlineExceeded {
+					return nil
+				}
+				if err != nil {
+					return err
+				}
+
+				d := &spec.DeadLetter{}
+				err = proto.Unmarshal(m.Value, d)
+				if err != nil {
+					return err
+				}
+
+				err = cb(d, p.ID, m.Offset+1)
+				if err != nil {
+					return err
+				}
+			}
+		}()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
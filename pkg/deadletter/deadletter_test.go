@@ -0,0 +1,91 @@
+package deadletter
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	spec "github.com/rekki/blackrock/pkg/blackrock_io"
+)
+
+func TestFileSink(t *testing.T) {
+	dir, err := ioutil.TempDir("", "deadletter")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	fn := path.Join(dir, "dead_letters.bin")
+	sink, err := NewSink("file:"+fn, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 10; i++ {
+		err = sink.Write(&spec.DeadLetter{Value: []byte(fmt.Sprintf("value %d", i)), Partition: 1, Offset: int64(i), Reason: "bad"})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	sink.Close()
+
+	// reopening appends
+	sink, err = NewFileSink(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = sink.Write(&spec.DeadLetter{Value: []byte("value 10"), Offset: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sink.Close()
+
+	read := []*spec.DeadLetter{}
+	err = Read("file:"+fn, "", func(d *spec.DeadLetter) error {
+		read = append(read, d)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(read) != 11 {
+		t.Fatalf("expected 11 got %d", len(read))
+	}
+	for i, d := range read {
+		if d.Offset != int64(i) || string(d.Value) != fmt.Sprintf("value %d", i) || d.CreatedAtNs == 0 {
+			t.Fatalf("unexpected dead letter %+v", d)
+		}
+	}
+
+	// resume after the 5th dead letter
+	resume := uint32(0)
+	err = ReadFileFrom(fn, 0, func(d *spec.DeadLetter, next uint32) error {
+		if d.Offset == 4 {
+			resume = next
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	read = read[:0]
+	err = ReadFileFrom(fn, resume, func(d *spec.DeadLetter, next uint32) error {
+		read = append(read, d)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(read) != 6 || read[0].Offset != 5 || read[5].Offset != 10 {
+		t.Fatalf("unexpected resume %+v", read)
+	}
+
+	_, err = NewSink("nope", "")
+	if err != errMissingSink {
+		t.Fatalf("expected error")
+	}
+}
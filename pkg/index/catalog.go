@@ -0,0 +1,182 @@
+package index
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	spec "github.com/rekki/blackrock/pkg/blackrock_io"
+	. "github.com/rekki/blackrock/pkg/logger"
+)
+
+var errBadSegmentId = errors.New("segment id must be step/id")
+
+// segmentInfo is the segment in root/<step>/<id>, it covers the events
+// created in [id * step, (id + 1) * step) seconds
+type segmentInfo struct {
+	Id      string
+	Step    int64
+	StartNs int64
+	stats   *spec.SegmentStats
+}
+
+func newSegmentInfo(step int64, ns int64) *segmentInfo {
+	n := ns / 1000000000 / step
+	s := &segmentInfo{Id: fmt.Sprintf("%d/%d", step, n), Step: step, StartNs: n * step * 1000000000}
+	s.stats = newSegmentStats(s)
+	return s
+}
+
+func parseSegmentId(id string) (*segmentInfo, error) {
+	splitted := strings.Split(id, "/")
+	if len(splitted) != 2 {
+		return nil, errBadSegmentId
+	}
+
+	step, err := strconv.ParseInt(splitted[0], 10, 64)
+	if err != nil || step <= 0 {
+		return nil, errBadSegmentId
+	}
+
+	n, err := strconv.ParseInt(splitted[1], 10, 64)
+	if err != nil || n < 0 {
+		return nil, errBadSegmentId
+	}
+
+	s := &segmentInfo{Id: id, Step: step, StartNs: n * step * 1000000000}
+	s.stats = newSegmentStats(s)
+	return s, nil
+}
+
+func (s *segmentInfo) EndNs() int64 {
+	return s.StartNs + s.Step*1000000000
+}
+
+func (s *segmentInfo) overlaps(fromNs, toNs int64) bool {
+	return s.StartNs < toNs && fromNs < s.EndNs()
+}
+
+// catalog maps time ranges to the segments that cover them, normally there
+// is only one segment for any point in time, but if -segment-step is changed
+// segments with different steps can overlap, we read all of them and write
+// in the biggest one.
+//
+// It is guarded by the SearchIndex lock.
+type catalog struct {
+	segments []*segmentInfo
+}
+
+func loadCatalog(root string) (*catalog, error) {
+	c := &catalog{}
+	steps, err := ioutil.ReadDir(root)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, step := range steps {
+		if !step.IsDir() {
+			continue
+		}
+		if _, err := strconv.ParseInt(step.Name(), 10, 64); err != nil {
+			continue
+		}
+
+		ids, err := ioutil.ReadDir(path.Join(root, step.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, id := range ids {
+			if !id.IsDir() {
+				continue
+			}
+
+			s, err := parseSegmentId(step.Name() + "/" + id.Name())
+			if err != nil {
+				Log.Infof("skipping: %s/%s, cant parse segment id", step.Name(), id.Name())
+				continue
+			}
+			c.add(s)
+		}
+	}
+	return c, nil
+}
+
+func (c *catalog) add(s *segmentInfo) {
+	c.segments = append(c.segments, s)
+	sort.Slice(c.segments, func(i, j int) bool {
+		a, b := c.segments[i], c.segments[j]
+		if a.StartNs == b.StartNs {
+			return a.Step > b.Step
+		}
+		return a.StartNs < b.StartNs
+	})
+}
+
+func (c *catalog) remove(id string) {
+	for i, s := range c.segments {
+		if s.Id == id {
+			c.segments = append(c.segments[:i], c.segments[i+1:]...)
+			return
+		}
+	}
+}
+
+func (c *catalog) get(id string) *segmentInfo {
+	for _, s := range c.segments {
+		if s.Id == id {
+			return s
+		}
+	}
+	return nil
+}
+
+// covering returns the biggest segment that covers ns, or nil
+func (c *catalog) covering(ns int64) *segmentInfo {
+	var out *segmentInfo
+	for _, s := range c.segments {
+		if s.StartNs > ns {
+			break
+		}
+		if ns < s.EndNs() && (out == nil || s.Step > out.Step) {
+			out = s
+		}
+	}
+	return out
+}
+
+// between returns the segments overlapping [fromNs, toNs)
+func (c *catalog) between(fromNs, toNs int64) []*segmentInfo {
+	out := []*segmentInfo{}
+	for _, s := range c.segments {
+		if s.StartNs >= toNs {
+			break
+		}
+		if s.overlaps(fromNs, toNs) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func (c *catalog) all() []*segmentInfo {
+	out := make([]*segmentInfo, len(c.segments))
+	copy(out, c.segments)
+	return out
+}
+
+func removeSegmentDir(root string, id string) error {
+	err := os.RemoveAll(path.Join(root, id))
+	if err != nil {
+		return err
+	}
+
+	// fails if other segments are still there
+	_ = os.Remove(path.Dir(path.Join(root, id)))
+	return nil
+}
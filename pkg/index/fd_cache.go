@@ -0,0 +1,56 @@
+package index
+
+import (
+	"os"
+	"path"
+	"sync"
+)
+
+// same as dsl.FDCache, but Close() also forgets the closed descriptors, the
+// segments share one cache and we close it every time files are swapped
+// under them, dsl.FDCache would keep returning the closed files after that
+type fdCache struct {
+	fds       map[string]*os.File
+	maxOpenFD int
+	sync.Mutex
+}
+
+func newFDCache(n int) *fdCache {
+	return &fdCache{maxOpenFD: n, fds: map[string]*os.File{}}
+}
+
+func (x *fdCache) ComputeIfAbsent(fn string, c func(fn string) (*os.File, error)) (*os.File, error) {
+	x.Lock()
+	defer x.Unlock()
+
+	f, ok := x.fds[fn]
+	if ok {
+		return f, nil
+	}
+
+	_ = os.MkdirAll(path.Dir(fn), 0700)
+	f, err := c(fn)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(x.fds) > x.maxOpenFD {
+		x.closeAll()
+	}
+	x.fds[fn] = f
+	return f, nil
+}
+
+func (x *fdCache) Close() {
+	x.Lock()
+	defer x.Unlock()
+
+	x.closeAll()
+}
+
+func (x *fdCache) closeAll() {
+	for _, fd := range x.fds {
+		_ = fd.Close()
+	}
+	x.fds = map[string]*os.File{}
+}
@@ -0,0 +1,309 @@
+package index
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/gogo/protobuf/proto"
+	spec "github.com/rekki/blackrock/pkg/blackrock_io"
+	pen "github.com/rekki/go-pen"
+	dsl "github.com/rekki/go-query/util/index"
+)
+
+const matchAllTerm = "blackrock:match_all"
+
+var errSegmentNotFound = errors.New("segment not found")
+
+type SegmentCheck struct {
+	Id                  string
+	Records             int
+	CorruptedRegions    int
+	Undecodable         int
+	PostingFiles        int
+	Postings            int
+	DanglingPostings    int
+	MissingFromMatchAll int
+	TornPostings        []string
+	UnsortedPostings    []string
+	OrphanTerms         []string
+}
+
+func (c *SegmentCheck) Ok() bool {
+	return c.CorruptedRegions == 0 &&
+		c.Undecodable == 0 &&
+		c.DanglingPostings == 0 &&
+		c.MissingFromMatchAll == 0 &&
+		len(c.TornPostings) == 0 &&
+		len(c.UnsortedPostings) == 0
+}
+
+// Check verifies that every forward record in the segments overlapping
+// [from, to] decodes as Metadata and that the postings point only to
+// existing records, it only reads so it is safe to run on a live index,
+// but records that are being written at the same time can show up as
+// corrupted
+func (m *SearchIndex) Check(from, to uint32) ([]*SegmentCheck, error) {
+	m.maintenance.Lock()
+	defer m.maintenance.Unlock()
+
+	out := []*SegmentCheck{}
+	for _, s := range m.listSegments(from, to) {
+		c, err := checkSegment(path.Join(m.root, s.Id))
+		if err != nil {
+			return nil, err
+		}
+		c.Id = s.Id
+		out = append(out, c)
+	}
+	return out, nil
+}
+
+// RebuildInverted throws away the inverted index of the segment and creates
+// it again from main.bin
+func (m *SearchIndex) RebuildInverted(segmentId string) error {
+	m.maintenance.Lock()
+	defer m.maintenance.Unlock()
+
+	m.RLock()
+	whitelist := m.whitelist
+	s := m.catalog.get(segmentId)
+	m.RUnlock()
+
+	if s == nil {
+		return errSegmentNotFound
+	}
+
+	return m.rebuildInverted(s, whitelist)
+}
+
+// rebuildInverted must be called with the maintenance lock held
+func (m *SearchIndex) rebuildInverted(s *segmentInfo, whitelist map[string]bool) error {
+	root := path.Join(m.root, s.Id)
+	inv := path.Join(root, "inv")
+	rebuild := path.Join(root, "inv.rebuild")
+	old := path.Join(root, "inv.old")
+
+	f, err := os.Open(path.Join(root, "main.bin"))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	err = os.RemoveAll(rebuild)
+	if err != nil {
+		return err
+	}
+
+	fdc := newFDCache(m.fdCache.maxOpenFD)
+	dir := dsl.NewDirIndex(rebuild, fdc, nil)
+	index := func(did int32, data []byte) error {
+		meta := &spec.Metadata{}
+		err := proto.Unmarshal(data, meta)
+		if err != nil {
+			// nothing we can do, the record can not be searched anyway
+			return nil
+		}
+		return dir.Index(dsl.DocumentWithID(toIndexable(did, meta, whitelist)))
+	}
+
+	// most of the work is done without holding the lock, then we catch up
+	// with whatever was ingested in the meantime and swap the directories
+	next, _, err := scanForward(f, 0, index)
+	if err != nil {
+		fdc.Close()
+		return err
+	}
+
+	m.Lock()
+	defer m.Unlock()
+
+	_, _, err = scanForward(f, next, index)
+	fdc.Close()
+	if err != nil {
+		return err
+	}
+
+	m.closeSegments()
+
+	err = os.RemoveAll(old)
+	if err != nil {
+		return err
+	}
+
+	_, err = os.Stat(inv)
+	if err == nil {
+		err = os.Rename(inv, old)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = os.MkdirAll(rebuild, 0700)
+	if err != nil {
+		return err
+	}
+
+	err = os.Rename(rebuild, inv)
+	if err != nil {
+		return err
+	}
+
+	// the term count changed
+	s.stats.Sealed = false
+
+	return os.RemoveAll(old)
+}
+
+// scanForward calls cb for every record with valid checksum starting at
+// offset, it returns the offset after the last valid record and the number
+// of corrupted regions that were skipped
+func scanForward(f *os.File, offset uint32, cb func(int32, []byte) error) (uint32, int, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	next := offset
+	corrupted := 0
+	inCorrupted := false
+	for int64(offset)*int64(pen.PAD) < info.Size() {
+		data, n, err := pen.ReadFromReader(f, offset, 16)
+		if err == pen.EBADSLT {
+			if !inCorrupted {
+				corrupted++
+				inCorrupted = true
+			}
+			offset++
+			continue
+		}
+
+		if err == io.EOF {
+			// torn write at the end of the file
+			if !inCorrupted {
+				corrupted++
+			}
+			break
+		}
+
+		if err != nil {
+			return next, corrupted, err
+		}
+
+		inCorrupted = false
+		err = cb(int32(offset), data)
+		if err != nil {
+			return next, corrupted, err
+		}
+
+		offset = n
+		next = n
+	}
+
+	return next, corrupted, nil
+}
+
+func checkSegment(root string) (*SegmentCheck, error) {
+	c := &SegmentCheck{}
+	valid := map[int32]bool{}
+
+	f, err := os.Open(path.Join(root, "main.bin"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	_, c.CorruptedRegions, err = scanForward(f, 0, func(did int32, data []byte) error {
+		meta := spec.Metadata{}
+		err := proto.Unmarshal(data, &meta)
+		if err != nil {
+			c.Undecodable++
+			return nil
+		}
+		c.Records++
+		valid[did] = true
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	inv := path.Join(root, "inv")
+	matchAll := map[int32]bool{}
+	err = filepath.Walk(inv, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			if p == inv && os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(inv, p)
+		if err != nil {
+			return err
+		}
+
+		// inv/field/last_char/term
+		splitted := strings.Split(filepath.ToSlash(rel), "/")
+		term := splitted[0] + ":" + splitted[len(splitted)-1]
+
+		data, err := ioutil.ReadFile(p)
+		if err != nil {
+			return err
+		}
+
+		c.PostingFiles++
+		if len(data)%4 != 0 {
+			c.TornPostings = append(c.TornPostings, term)
+		}
+
+		n := len(data) / 4
+		dangling := 0
+		sorted := true
+		prev := int32(-1)
+		for i := 0; i < n; i++ {
+			did := int32(binary.LittleEndian.Uint32(data[i*4:]))
+			if did < prev {
+				sorted = false
+			}
+			prev = did
+
+			if !valid[did] {
+				dangling++
+			}
+			if term == matchAllTerm {
+				matchAll[did] = true
+			}
+		}
+
+		c.Postings += n
+		c.DanglingPostings += dangling
+		if !sorted {
+			c.UnsortedPostings = append(c.UnsortedPostings, term)
+		}
+		if n > 0 && dangling == n {
+			c.OrphanTerms = append(c.OrphanTerms, term)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for did := range valid {
+		if !matchAll[did] {
+			c.MissingFromMatchAll++
+		}
+	}
+
+	return c, nil
+}
@@ -0,0 +1,117 @@
+package index
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	spec "github.com/rekki/blackrock/pkg/blackrock_io"
+	"github.com/rekki/go-query/util/go_query_dsl"
+)
+
+func TestCheckAndRebuild(t *testing.T) {
+	root, err := ioutil.TempDir("", "si")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	si := NewSearchIndex(root, 10, 3600, false, map[string]bool{})
+	defer si.Close()
+
+	query := &spec.SearchQueryRequest{FromSecond: 1, ToSecond: 7200, Query: &go_query_dsl.Query{Field: "blackrock", Value: "match_all"}}
+	inserted := 100
+	for i := 0; i < inserted; i++ {
+		err = si.Ingest(RandomEnvelope(1))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	checks, err := si.Check(0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(checks) != 1 || !checks[0].Ok() || checks[0].Records != inserted {
+		t.Fatalf("unexpected check %+v", checks)
+	}
+
+	segment := path.Join(si.root, checks[0].Id)
+	err = os.RemoveAll(path.Join(segment, "inv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checks, err = si.Check(0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if checks[0].Ok() || checks[0].MissingFromMatchAll != inserted {
+		t.Fatalf("expected %d missing, got %+v", inserted, checks[0])
+	}
+
+	err = si.RebuildInverted(checks[0].Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checks, err = si.Check(0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !checks[0].Ok() {
+		t.Fatalf("expected ok after rebuild, got %+v", checks[0])
+	}
+
+	if n := countMatching(t, si, query); n != inserted {
+		t.Fatalf("expected %d got %d", inserted, n)
+	}
+
+	// corrupt the second record
+	f, err := os.OpenFile(path.Join(segment, "main.bin"), os.O_WRONLY, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = f.WriteAt([]byte("broken"), 64+8)
+	f.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checks, err = si.Check(0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := checks[0]
+	if c.Ok() || c.CorruptedRegions != 1 || c.DanglingPostings == 0 || c.Records != inserted-1 {
+		t.Fatalf("expected corruption, got %+v", c)
+	}
+
+	err = si.RebuildInverted(c.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checks, err = si.Check(0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c = checks[0]
+	if c.DanglingPostings != 0 || c.MissingFromMatchAll != 0 || len(c.OrphanTerms) != 0 {
+		t.Fatalf("expected clean postings, got %+v", c)
+	}
+
+	if n := countMatching(t, si, query); n != inserted-1 {
+		t.Fatalf("expected %d got %d", inserted-1, n)
+	}
+
+	// still writable
+	err = si.Ingest(RandomEnvelope(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := countMatching(t, si, query); n != inserted {
+		t.Fatalf("expected %d got %d", inserted, n)
+	}
+}
@@ -2,12 +2,9 @@ package index
 
 import (
 	"errors"
-	"fmt"
-	"io/ioutil"
+	"math"
 	"os"
 	"path"
-	"sort"
-	"strconv"
 	"sync"
 	"time"
 
@@ -24,22 +21,59 @@ type SearchIndex struct {
 	whitelist          map[string]bool
 	SegmentStep        int64
 	enableSegmentCache bool
-	fdCache            dsl.FileDescriptorCache
+	fdCache            *fdCache
+	catalog            *catalog
+	reindex            reindexJob
+	tails              tails
+
+	// merges, rebuilds and restores move segment directories around, only
+	// one of them can run at a time
+	maintenance sync.Mutex
 	sync.RWMutex
 }
 
+// NewSearchIndex opens the segments in root/<step>/<id>, segmentStep is used
+// only for new segments, existing segments of any step are still searchable
 func NewSearchIndex(root string, nOpenFD int, segmentStep int64, enableSegmentCache bool, whitelist map[string]bool) *SearchIndex {
-	root = path.Join(root, fmt.Sprintf("%d", segmentStep))
+	m, err := OpenSearchIndex(root, nOpenFD, segmentStep, enableSegmentCache, whitelist)
+	if err != nil {
+		Log.Fatal(err)
+	}
+	return m
+}
 
+// OpenSearchIndex is NewSearchIndex that returns the error instead of
+// exiting, e.g. when one of many indexes can not be opened
+func OpenSearchIndex(root string, nOpenFD int, segmentStep int64, enableSegmentCache bool, whitelist map[string]bool) (*SearchIndex, error) {
 	err := os.MkdirAll(root, 0700)
 	if err != nil {
-		Log.Fatal(err)
+		return nil, err
+	}
+
+	err = recoverMerges(root)
+	if err != nil {
+		return nil, err
 	}
 
-	fdc := dsl.NewFDCache(nOpenFD)
-	m := &SearchIndex{root: root, fdCache: fdc, Segments: map[string]*Segment{}, SegmentStep: segmentStep, enableSegmentCache: enableSegmentCache, whitelist: whitelist}
+	c, err := loadCatalog(root)
+	if err != nil {
+		return nil, err
+	}
 
-	return m
+	err = finishMerges(root, c)
+	if err != nil {
+		return nil, err
+	}
+
+	err = loadStats(root, c)
+	if err != nil {
+		return nil, err
+	}
+
+	fdc := newFDCache(nOpenFD)
+	m := &SearchIndex{root: root, fdCache: fdc, catalog: c, Segments: map[string]*Segment{}, SegmentStep: segmentStep, enableSegmentCache: enableSegmentCache, whitelist: whitelist}
+
+	return m, nil
 }
 
 func (m *SearchIndex) Ingest(envelope *spec.Envelope) error {
@@ -48,10 +82,22 @@ func (m *SearchIndex) Ingest(envelope *spec.Envelope) error {
 		return err
 	}
 
-	err = m.holdWrite(envelope.Metadata.CreatedAtNs, func(segment *Segment) error {
-		return segment.Ingest(envelope)
+	var whitelist map[string]bool
+	err = m.holdWrite(envelope.Metadata.CreatedAtNs, func(segment *Segment, info *segmentInfo) error {
+		err := segment.Ingest(envelope)
+		if err != nil {
+			return err
+		}
+		addToStats(info.stats, envelope.Metadata.CreatedAtNs, envelope.Metadata.EventType, envelope.Metadata.Id)
+		whitelist = segment.whitelist
+		return nil
 	})
-	return err
+	if err != nil {
+		return err
+	}
+
+	m.publish(envelope.Metadata, whitelist)
+	return nil
 }
 
 func (m *SearchIndex) Close() {
@@ -59,125 +105,220 @@ func (m *SearchIndex) Close() {
 	m.Lock()
 	defer m.Unlock()
 
+	err := m.saveCatalog()
+	if err != nil {
+		Log.Warnf("failed to save the catalog, err: %s", err.Error())
+	}
+
 	for k, s := range m.Segments {
 		s.Close()
 		delete(m.Segments, k)
 	}
 }
-func (m *SearchIndex) toSegmentId(ns int64) string {
-	s := ns / 1000000000
-	d := s / m.SegmentStep
 
-	return fmt.Sprintf("%d", d)
+// ListSegments returns the start of every segment
+func (m *SearchIndex) ListSegments() ([]int64, error) {
+	m.RLock()
+	defer m.RUnlock()
+
+	out := []int64{}
+	for _, s := range m.catalog.segments {
+		out = append(out, s.StartNs)
+	}
+	return out, nil
 }
 
-func (m *SearchIndex) ListSegments() ([]int64, error) {
-	days, err := ioutil.ReadDir(m.root)
-	if err != nil {
-		return nil, err
+// listSegments returns the segments overlapping [from, to] seconds, 0 means
+// no bound
+func (m *SearchIndex) listSegments(from, to uint32) []*segmentInfo {
+	m.RLock()
+	defer m.RUnlock()
+
+	return m.listSegmentsLocked(from, to)
+}
+
+func (m *SearchIndex) listSegmentsLocked(from, to uint32) []*segmentInfo {
+	fromNs := int64(from) * 1000000000
+	toNs := int64(math.MaxInt64)
+	if to != 0 {
+		toNs = (int64(to) + 1) * 1000000000
 	}
-	todo := []int64{}
-	for _, day := range days {
-		if !day.IsDir() {
-			Log.Infof("skipping: %s", day.Name())
-			continue
-		}
-		id, err := strconv.ParseInt(day.Name(), 10, 64)
-		if err != nil {
-			Log.Infof("skipping: %s, cant parse step", day.Name())
-			continue
-		}
-		todo = append(todo, id*m.SegmentStep*1000000000)
+
+	return m.catalog.between(fromNs, toNs)
+}
+
+// closeSegments must be called with the write lock held, the segments
+// share the file descriptor cache, so all of them go together and are
+// lazily loaded again on the next read or write
+func (m *SearchIndex) closeSegments() {
+	for k, s := range m.Segments {
+		s.Close()
+		delete(m.Segments, k)
 	}
-	sort.Slice(todo, func(i, j int) bool {
-		return todo[i] < todo[j]
-	})
-	return todo, nil
+	m.fdCache.Close()
 }
 
 func (m *SearchIndex) LookupSingleSegment(ns int64) *Segment {
-	segmentId := m.toSegmentId(ns)
 	m.RLock()
 	defer m.RUnlock()
 
-	return m.Segments[segmentId]
+	s := m.catalog.covering(ns)
+	if s == nil {
+		return nil
+	}
+	return m.Segments[s.Id]
 }
 
-func (m *SearchIndex) loadSegmentFromDisk(segmentId string) (*Segment, error) {
+func (m *SearchIndex) loadSegmentFromDisk(segmentId string, whitelist map[string]bool) (*Segment, error) {
 	p := path.Join(m.root, segmentId)
-	segment, err := NewSegment(p, m.fdCache, m.enableSegmentCache, m.whitelist)
+	segment, err := NewSegment(p, m.fdCache, m.enableSegmentCache, whitelist)
 	if err != nil {
 		return nil, err
 	}
 	return segment, nil
 }
 
-func (m *SearchIndex) holdRead(step int64, cb func(s *Segment) error) error {
-	segmentId := m.toSegmentId(step)
+var errSegmentGone = errors.New("segment was merged or removed")
 
+func (m *SearchIndex) holdRead(segmentId string, cb func(s *Segment) error) error {
 	m.RLock()
 
-	var err error
+	for {
+		segment, ok := m.Segments[segmentId]
+		if ok {
+			err := cb(segment)
+			m.RUnlock()
+			return err
+		}
+
+		if m.catalog.get(segmentId) == nil {
+			m.RUnlock()
+			return errSegmentGone
+		}
 
-	segment, ok := m.Segments[segmentId]
-	if !ok {
 		m.RUnlock()
 
-		// RACE (multiple load)
+		// loading under the write lock, otherwise the segment could be
+		// merged away while we are creating its files again
+		m.Lock()
+		var err error
+		_, ok = m.Segments[segmentId]
+		if !ok && m.catalog.get(segmentId) != nil {
+			segment, err = m.loadSegmentFromDisk(segmentId, m.whitelist)
+			if err == nil {
+				m.Segments[segmentId] = segment
+			}
+		}
+		m.Unlock()
 
-		segment, err = m.loadSegmentFromDisk(segmentId)
 		if err != nil {
 			return err
 		}
 
-		m.Lock()
-
-		overriden, ok := m.Segments[segmentId]
-		if ok {
-			segment.Close()
-			segment = overriden
-		} else {
-			m.Segments[segmentId] = segment
-		}
-
-		m.Unlock()
-
+		// it could be closed again before we get the read lock, so check
+		// again
 		m.RLock()
 	}
-
-	err = cb(segment)
-	m.RUnlock()
-	return err
 }
 
-func (m *SearchIndex) holdWrite(step int64, cb func(s *Segment) error) error {
-	segmentId := m.toSegmentId(step)
+func (m *SearchIndex) holdWrite(ns int64, cb func(s *Segment, info *segmentInfo) error) error {
 	m.Lock()
 	defer m.Unlock()
+
+	info := m.catalog.covering(ns)
+	isNew := info == nil
+	if isNew {
+		info = newSegmentInfo(m.SegmentStep, ns)
+	}
+
 	var err error
-	segment, ok := m.Segments[segmentId]
+	segment, ok := m.Segments[info.Id]
 	if !ok {
-		segment, err = m.loadSegmentFromDisk(segmentId)
+		segment, err = m.loadSegmentFromDisk(info.Id, m.whitelist)
 		if err != nil {
 			return err
 		}
-		m.Segments[segmentId] = segment
+		m.Segments[info.Id] = segment
+	}
+
+	if isNew {
+		m.catalog.add(info)
 	}
 
-	return cb(segment)
+	return cb(segment, info)
 }
 
 var errBadRequest = errors.New("missing Query")
 
+type segmentRange struct {
+	segment *segmentInfo
+	fromNs  int64
+	toNs    int64
+	empty   bool
+}
+
 func (m *SearchIndex) ForEach(qr *spec.SearchQueryRequest, limit uint32, cb func(*Segment, int32, float32) error) error {
+	_, err := m.ForEachExplain(qr, limit, cb)
+	return err
+}
+
+// ForEachExplain is ForEach that also returns how the query was planned and
+// executed in every segment, the explain is nil unless qr.Explain is set
+func (m *SearchIndex) ForEachExplain(qr *spec.SearchQueryRequest, limit uint32, cb func(*Segment, int32, float32) error) (*spec.QueryExplain, error) {
+	start := time.Now()
+	var explain *spec.QueryExplain
+	if qr.Explain {
+		explain = &spec.QueryExplain{}
+	}
+
 	steps := m.ExpandFromTo(qr.FromSecond, qr.ToSecond)
 	if qr.Query == nil {
-		return errBadRequest
+		return nil, errBadRequest
+	}
+	if len(steps) == 0 {
+		return explain, nil
 	}
 
-	for _, step := range steps {
-		err := m.holdRead(step, func(segment *Segment) error {
-			query, err := dsl.Parse(qr.Query, func(k, v string) iq.Query {
+	fromNs := steps[0]
+	toNs := steps[len(steps)-1] + m.SegmentStep*1000000000
+
+	todo := []segmentRange{}
+	between := func(fromNs, toNs int64) {
+		m.RLock()
+		for _, s := range m.catalog.between(fromNs, toNs) {
+			todo = append(todo, segmentRange{segment: s, fromNs: fromNs, toNs: toNs, empty: !hasEventsBetween(s, fromNs, toNs)})
+		}
+		m.RUnlock()
+	}
+	between(fromNs, toNs)
+
+	for i := 0; i < len(todo); i++ {
+		r := todo[i]
+		sp := &spec.SegmentPlan{SegmentId: r.segment.Id}
+		if r.empty {
+			sp.Skipped = true
+			sp.SkipReason = "no events in range"
+			if explain != nil {
+				explain.Segments = append(explain.Segments, sp)
+			}
+			continue
+		}
+
+		segmentStart := time.Now()
+
+		// merged segments can have events outside of the requested range
+		filter := r.segment.StartNs < r.fromNs || r.segment.EndNs() > r.toNs
+		err := m.holdRead(r.segment.Id, func(segment *Segment) error {
+			planned, estimated, text := planQuery(qr.Query, segment.docFrequency)
+			sp.Plan = text
+			sp.Estimated = estimated
+			if estimated == 0 {
+				sp.Skipped = true
+				sp.SkipReason = "required term without postings"
+				return nil
+			}
+
+			query, err := dsl.Parse(planned, func(k, v string) iq.Query {
 				if len(k) == 0 || len(v) == 0 {
 					return iq.Term(1, k+":"+v, []int32{})
 				}
@@ -192,9 +333,19 @@ func (m *SearchIndex) ForEach(qr *spec.SearchQueryRequest, limit uint32, cb func
 				return err
 			}
 
+			basic := spec.BasicMetadata{}
 			// no need to lock the segment after that because its used only to get data from the forward index
 			for query.Next() != iq.NO_MORE {
 				did := query.GetDocId()
+				if filter {
+					err = segment.ReadForwardDecode(did, &basic)
+					if err != nil || basic.CreatedAtNs < r.fromNs || basic.CreatedAtNs >= r.toNs {
+						// undecodable records can not be returned either
+						continue
+					}
+				}
+
+				sp.Matched++
 				score := query.Score()
 				err = cb(segment, did, score)
 				if err != nil {
@@ -209,12 +360,28 @@ func (m *SearchIndex) ForEach(qr *spec.SearchQueryRequest, limit uint32, cb func
 			}
 			return nil
 		})
+
+		if err == errSegmentGone {
+			// merged while we were searching, its events are now in the
+			// segment that covers it
+			between(max64(r.fromNs, r.segment.StartNs), min64(r.toNs, r.segment.EndNs()))
+			continue
+		}
+
 		if err != nil {
-			return err
+			return nil, err
+		}
+
+		sp.TookNs = time.Since(segmentStart).Nanoseconds()
+		if explain != nil {
+			explain.Segments = append(explain.Segments, sp)
 		}
 	}
 
-	return nil
+	if explain != nil {
+		explain.TookNs = time.Since(start).Nanoseconds()
+	}
+	return explain, nil
 }
 
 func (m *SearchIndex) ExpandFromTo(from uint32, to uint32) []int64 {
@@ -234,3 +401,22 @@ func (m *SearchIndex) ExpandFromTo(from uint32, to uint32) []int64 {
 	}
 	return out
 }
+
+// must be called with the read lock held
+func hasEventsBetween(s *segmentInfo, fromNs, toNs int64) bool {
+	return s.stats.Docs > 0 && s.stats.MaxCreatedAtNs >= fromNs && s.stats.MinCreatedAtNs < toNs
+}
+
+func min64(a, b int64) int64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max64(a, b int64) int64 {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,370 @@
+package index
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	spec "github.com/rekki/blackrock/pkg/blackrock_io"
+	. "github.com/rekki/blackrock/pkg/logger"
+)
+
+const mergedFromName = "merged_from"
+
+var errBadTier = errors.New("tier must be step:age, e.g. 86400:48h")
+
+// Tier merges the segments that ended more than Age ago into segments of
+// Step seconds
+type Tier struct {
+	Step int64
+	Age  time.Duration
+}
+
+// ParseTiers parses csv list of step:age, e.g. 86400:48h,604800:720h keeps
+// the last two days in the small segments, the last month in day segments
+// and everything older in week segments
+func ParseTiers(s string) ([]Tier, error) {
+	out := []Tier{}
+	for _, t := range strings.Split(s, ",") {
+		if len(t) == 0 {
+			continue
+		}
+
+		splitted := strings.Split(t, ":")
+		if len(splitted) != 2 {
+			return nil, errBadTier
+		}
+
+		step, err := strconv.ParseInt(splitted[0], 10, 64)
+		if err != nil || step <= 0 {
+			return nil, errBadTier
+		}
+
+		age, err := time.ParseDuration(splitted[1])
+		if err != nil {
+			return nil, errBadTier
+		}
+
+		out = append(out, Tier{Step: step, Age: age})
+	}
+	return out, nil
+}
+
+// Merge merges the small segments into the tier segments, a tier segment is
+// created only when its whole range is older than the tier age, and only
+// segments that fit in it are merged, so a segment step that does not
+// divide the tier step is never merged. If the tier segment already exists
+// (restored from backup for example) it is merged with the small ones.
+//
+// The tiers are applied in order, so they must be sorted by step.
+func (m *SearchIndex) Merge(tiers []Tier, now time.Time) error {
+	m.maintenance.Lock()
+	defer m.maintenance.Unlock()
+
+	for _, tier := range tiers {
+		cutoff := now.Add(-tier.Age).UnixNano()
+		targets := map[string]*segmentInfo{}
+		sources := map[string][]*segmentInfo{}
+
+		m.RLock()
+		for _, s := range m.catalog.segments {
+			target := newSegmentInfo(tier.Step, s.StartNs)
+			if s.Step >= tier.Step || s.EndNs() > target.EndNs() || target.EndNs() > cutoff {
+				continue
+			}
+
+			targets[target.Id] = target
+			sources[target.Id] = append(sources[target.Id], s)
+		}
+
+		for id := range targets {
+			existing := m.catalog.get(id)
+			if existing != nil {
+				sources[id] = append([]*segmentInfo{existing}, sources[id]...)
+			}
+		}
+		m.RUnlock()
+
+		ids := []string{}
+		for id := range targets {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+
+		for _, id := range ids {
+			err := m.mergeSegments(targets[id], sources[id])
+			if err != nil {
+				return err
+			}
+			Log.Infof("merged %d segments into %s", len(sources[id]), id)
+		}
+	}
+
+	return nil
+}
+
+// mergeSegments must be called with the maintenance lock held
+func (m *SearchIndex) mergeSegments(target *segmentInfo, sources []*segmentInfo) error {
+	dst := path.Join(m.root, target.Id)
+	merging := dst + ".merging"
+	old := dst + ".old"
+
+	err := os.RemoveAll(merging)
+	if err != nil {
+		return err
+	}
+
+	m.RLock()
+	whitelist := m.whitelist
+	m.RUnlock()
+
+	merged, err := NewSegment(merging, newFDCache(m.fdCache.maxOpenFD), false, whitelist)
+	if err != nil {
+		return err
+	}
+
+	stats := newSegmentStats(target)
+	files := []*os.File{}
+	defer func() {
+		for _, f := range files {
+			f.Close()
+		}
+	}()
+
+	add := func(did int32, data []byte) error {
+		meta := &spec.Metadata{}
+		err := proto.Unmarshal(data, meta)
+		if err != nil {
+			// nothing we can do, the record can not be searched anyway
+			return nil
+		}
+		err = merged.ingestEncoded(data, meta)
+		if err != nil {
+			return err
+		}
+		addToStats(stats, meta.CreatedAtNs, meta.EventType, meta.Id)
+		return nil
+	}
+
+	// same as rebuildInverted, copy most of it without the lock, and then
+	// catch up with the late events
+	offsets := []uint32{}
+	for _, s := range sources {
+		f, err := os.Open(path.Join(m.root, s.Id, "main.bin"))
+		if err != nil {
+			merged.Close()
+			return err
+		}
+		files = append(files, f)
+
+		next, _, err := scanForward(f, 0, add)
+		if err != nil {
+			merged.Close()
+			return err
+		}
+		offsets = append(offsets, next)
+	}
+
+	m.Lock()
+	defer m.Unlock()
+
+	for i, f := range files {
+		_, _, err = scanForward(f, offsets[i], add)
+		if err != nil {
+			merged.Close()
+			return err
+		}
+	}
+	merged.Close()
+
+	// if we crash after the swap, the sources are removed on startup
+	ids := []string{}
+	for _, s := range sources {
+		ids = append(ids, s.Id)
+	}
+	err = ioutil.WriteFile(path.Join(merging, mergedFromName), []byte(strings.Join(ids, "\n")), 0600)
+	if err != nil {
+		return err
+	}
+
+	m.closeSegments()
+
+	err = os.RemoveAll(old)
+	if err != nil {
+		return err
+	}
+
+	_, err = os.Stat(dst)
+	if err == nil {
+		err = os.Rename(dst, old)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = os.Rename(merging, dst)
+	if err != nil {
+		return err
+	}
+
+	info := m.catalog.get(target.Id)
+	if info == nil {
+		info = target
+		m.catalog.add(info)
+	}
+	info.stats = stats
+
+	for _, s := range sources {
+		if s.Id == target.Id {
+			continue
+		}
+
+		m.catalog.remove(s.Id)
+		err = removeSegmentDir(m.root, s.Id)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = os.Remove(path.Join(dst, mergedFromName))
+	if err != nil {
+		return err
+	}
+
+	err = os.RemoveAll(old)
+	if err != nil {
+		return err
+	}
+
+	return m.saveCatalog()
+}
+
+// recoverMerges puts back the <id>.merging and <id>.old directories of a
+// merge that was interrupted during the swap, they are not in the catalog.
+// A merging segment with merged_from is complete and is swapped in, then
+// finishMerges removes its sources, otherwise it is removed and <id>.old
+// is renamed back.
+func recoverMerges(root string) error {
+	steps, err := ioutil.ReadDir(root)
+	if err != nil {
+		return err
+	}
+
+	for _, step := range steps {
+		if !step.IsDir() {
+			continue
+		}
+		if _, err := strconv.ParseInt(step.Name(), 10, 64); err != nil {
+			continue
+		}
+
+		dir := path.Join(root, step.Name())
+		ids, err := ioutil.ReadDir(dir)
+		if err != nil {
+			return err
+		}
+
+		for _, id := range ids {
+			if !id.IsDir() || !strings.HasSuffix(id.Name(), ".merging") {
+				continue
+			}
+			merging := path.Join(dir, id.Name())
+			dst := strings.TrimSuffix(merging, ".merging")
+
+			_, err := os.Stat(path.Join(merging, mergedFromName))
+			if os.IsNotExist(err) {
+				Log.Warnf("removing %s, the merge did not finish", merging)
+				err = os.RemoveAll(merging)
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			if err != nil {
+				return err
+			}
+
+			Log.Warnf("swapping in %s, the merge finished before the swap", merging)
+			_, err = os.Stat(dst)
+			if err == nil {
+				err = os.RemoveAll(dst + ".old")
+				if err != nil {
+					return err
+				}
+				err = os.Rename(dst, dst+".old")
+			}
+			if err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			err = os.Rename(merging, dst)
+			if err != nil {
+				return err
+			}
+		}
+
+		// read again, some were just renamed
+		ids, err = ioutil.ReadDir(dir)
+		if err != nil {
+			return err
+		}
+		for _, id := range ids {
+			if !id.IsDir() || !strings.HasSuffix(id.Name(), ".old") {
+				continue
+			}
+			old := path.Join(dir, id.Name())
+			dst := strings.TrimSuffix(old, ".old")
+
+			_, err := os.Stat(dst)
+			if err == nil {
+				err = os.RemoveAll(old)
+			} else if os.IsNotExist(err) {
+				Log.Warnf("renaming %s back, the merge did not finish", old)
+				err = os.Rename(old, dst)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// finishMerges removes the sources of merges that were interrupted after
+// the merged segment was swapped in
+func finishMerges(root string, c *catalog) error {
+	for _, s := range c.all() {
+		fn := path.Join(root, s.Id, mergedFromName)
+		data, err := ioutil.ReadFile(fn)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		for _, id := range strings.Split(string(data), "\n") {
+			if id == s.Id || c.get(id) == nil {
+				continue
+			}
+
+			Log.Warnf("removing %s, it was already merged into %s", id, s.Id)
+			c.remove(id)
+			err = removeSegmentDir(root, id)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = os.Remove(fn)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,239 @@
+package index
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	spec "github.com/rekki/blackrock/pkg/blackrock_io"
+	"github.com/rekki/go-query/util/go_query_dsl"
+)
+
+func TestParseTiers(t *testing.T) {
+	tiers, err := ParseTiers("86400:48h,604800:720h")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tiers) != 2 || tiers[0].Step != 86400 || tiers[0].Age != 48*time.Hour || tiers[1].Step != 604800 {
+		t.Fatalf("unexpected tiers %+v", tiers)
+	}
+
+	for _, bad := range []string{"86400", "x:1h", "86400:x", "0:1h"} {
+		_, err = ParseTiers(bad)
+		if err != errBadTier {
+			t.Fatalf("%s: expected errBadTier, got %v", bad, err)
+		}
+	}
+}
+
+func TestMerge(t *testing.T) {
+	root, err := ioutil.TempDir("", "si")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	si := NewSearchIndex(root, 10, 3600, false, map[string]bool{})
+
+	// one event per hour for two days
+	hours := 48
+	for i := 0; i < hours; i++ {
+		err = si.Ingest(RandomEnvelope(int64(i)*3600*1e9 + 1))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	query := func(from, to uint32) *spec.SearchQueryRequest {
+		return &spec.SearchQueryRequest{FromSecond: from, ToSecond: to, Query: &go_query_dsl.Query{Field: "blackrock", Value: "match_all"}}
+	}
+
+	segments, _ := si.ListSegments()
+	if len(segments) != hours {
+		t.Fatalf("expected %d segments got %d", hours, len(segments))
+	}
+
+	// the second day is not old enough
+	now := time.Unix(2*86400, 0).Add(time.Hour)
+	err = si.Merge([]Tier{{Step: 86400, Age: 2 * time.Hour}}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	segments, _ = si.ListSegments()
+	if len(segments) != 1+24 {
+		t.Fatalf("expected %d segments got %d", 1+24, len(segments))
+	}
+
+	if n := countMatching(t, si, query(1, uint32(hours*3600))); n != hours {
+		t.Fatalf("expected %d got %d", hours, n)
+	}
+
+	// only the requested hours from the merged segment
+	if n := countMatching(t, si, query(3600*5, 3600*6)); n != 2 {
+		t.Fatalf("expected 2 got %d", n)
+	}
+
+	// late event goes to the merged segment
+	err = si.Ingest(RandomEnvelope(3600*5*1e9 + 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	segments, _ = si.ListSegments()
+	if len(segments) != 1+24 {
+		t.Fatalf("expected %d segments got %d", 1+24, len(segments))
+	}
+	if n := countMatching(t, si, query(3600*5, 3600*5)); n != 2 {
+		t.Fatalf("expected 2 got %d", n)
+	}
+
+	err = si.Merge([]Tier{{Step: 86400, Age: time.Hour}, {Step: 7 * 86400, Age: time.Hour}}, time.Unix(30*86400, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	si.Close()
+
+	si = NewSearchIndex(root, 10, 60, false, map[string]bool{})
+	defer si.Close()
+
+	segments, _ = si.ListSegments()
+	if len(segments) != 1 {
+		t.Fatalf("expected 1 segment got %d", len(segments))
+	}
+	if n := countMatching(t, si, query(1, uint32(hours*3600))); n != hours+1 {
+		t.Fatalf("expected %d got %d", hours+1, n)
+	}
+
+	checks, err := si.Check(0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(checks) != 1 || checks[0].Id != "604800/0" || !checks[0].Ok() || checks[0].Records != hours+1 {
+		t.Fatalf("unexpected check %+v", checks)
+	}
+}
+
+func TestMergeRecover(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "si")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+
+	day := RandomEnvelope(1)
+	hour := RandomEnvelope(5*3600*1e9 + 1)
+
+	// builds a segment with the envelopes and moves it to root/name
+	segment := func(step int64, root string, name string, envelopes ...*spec.Envelope) {
+		dir, err := ioutil.TempDir(tmp, "segment")
+		if err != nil {
+			t.Fatal(err)
+		}
+		si := NewSearchIndex(dir, 10, step, false, map[string]bool{})
+		for _, e := range envelopes {
+			err = si.Ingest(proto.Clone(e).(*spec.Envelope))
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		segments := si.catalog.all()
+		si.Close()
+
+		err = os.MkdirAll(path.Dir(path.Join(root, name)), 0700)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = os.Rename(path.Join(dir, segments[0].Id), path.Join(root, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	query := &spec.SearchQueryRequest{FromSecond: 1, ToSecond: 86400, Query: &go_query_dsl.Query{Field: "blackrock", Value: "match_all"}}
+	check := func(root string, expected []string) {
+		si := NewSearchIndex(root, 10, 3600, false, map[string]bool{})
+		defer si.Close()
+
+		ids := []string{}
+		for _, s := range si.catalog.all() {
+			ids = append(ids, s.Id)
+		}
+		if strings.Join(ids, ",") != strings.Join(expected, ",") {
+			t.Fatalf("expected segments %v got %v", expected, ids)
+		}
+		if n := countMatching(t, si, query); n != 2 {
+			t.Fatalf("expected 2 got %d", n)
+		}
+		for _, leftover := range []string{"86400/0.old", "86400/0.merging"} {
+			if _, err := os.Stat(path.Join(root, leftover)); !os.IsNotExist(err) {
+				t.Fatalf("expected %s to be gone, err: %v", leftover, err)
+			}
+		}
+	}
+
+	// crashed after the target was renamed to .old, the merged segment is
+	// complete
+	root := path.Join(tmp, "forward")
+	segment(86400, root, "86400/0.old", day)
+	segment(3600, root, "3600/5", hour)
+	segment(86400, root, "86400/0.merging", day, hour)
+	err = ioutil.WriteFile(path.Join(root, "86400/0.merging", mergedFromName), []byte("86400/0\n3600/5"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check(root, []string{"86400/0"})
+
+	// crashed while merging, the sources stay
+	root = path.Join(tmp, "back")
+	segment(86400, root, "86400/0.old", day)
+	segment(3600, root, "3600/5", hour)
+	segment(86400, root, "86400/0.merging", day)
+	check(root, []string{"86400/0", "3600/5"})
+}
+
+func TestMergedFilterUndecodable(t *testing.T) {
+	root, err := ioutil.TempDir("", "si")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	si := NewSearchIndex(root, 10, 86400, false, map[string]bool{})
+	for _, hour := range []int64{1, 5} {
+		err = si.Ingest(RandomEnvelope(hour*3600*1e9 + 1))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	si.Close()
+
+	// the day segment is filtered when searching by hour
+	si = NewSearchIndex(root, 10, 3600, false, map[string]bool{})
+	defer si.Close()
+
+	// indexed at hour 5, but the forward record is garbage
+	meta := RandomEnvelope(5*3600*1e9 + 2).Metadata
+	err = si.holdRead("86400/0", func(s *Segment) error {
+		return s.ingestEncoded([]byte{0xff, 0xff, 0xff}, meta)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	query := &spec.SearchQueryRequest{FromSecond: 3600, ToSecond: 2 * 3600, Query: &go_query_dsl.Query{Field: "blackrock", Value: "match_all"}}
+	matching := []int32{}
+	err = si.ForEach(query, 0, func(s *Segment, did int32, score float32) error {
+		matching = append(matching, did)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matching) != 1 || matching[0] != 0 {
+		t.Fatalf("expected only the first document got %v", matching)
+	}
+}
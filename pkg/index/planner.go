@@ -0,0 +1,144 @@
+package index
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/rekki/go-query/util/common"
+	"github.com/rekki/go-query/util/go_query_dsl"
+	dsl "github.com/rekki/go-query/util/index"
+)
+
+// planQuery rewrites the query for one segment using the document frequency
+// of its terms, AND clauses are sorted by selectivity, OR clauses without
+// postings are removed, and if a required clause has no postings the
+// estimate is 0 and the segment can be skipped without reading any posting
+// list.
+//
+// The estimate is an upper bound of the matches, min of the clauses for AND
+// and sum for OR, invalid queries are returned as they are with unknown
+// estimate so dsl.Parse can complain about them.
+func planQuery(q *go_query_dsl.Query, df func(field, value string) uint64) (*go_query_dsl.Query, uint64, string) {
+	unknown := uint64(math.MaxUint64)
+	if q == nil {
+		return q, unknown, "invalid"
+	}
+
+	switch q.Type {
+	case go_query_dsl.Query_TERM:
+		if q.Field == "" || q.Not != nil || len(q.Queries) != 0 {
+			return q, unknown, "invalid"
+		}
+		n := df(q.Field, q.Value)
+		return q, n, fmt.Sprintf("%s:%s[%d]", q.Field, q.Value, n)
+
+	case go_query_dsl.Query_AND:
+		if len(q.Queries) == 0 {
+			return q, unknown, "invalid"
+		}
+
+		type planned struct {
+			query     *go_query_dsl.Query
+			estimated uint64
+			text      string
+		}
+
+		children := []planned{}
+		for _, sub := range q.Queries {
+			p, n, text := planQuery(sub, df)
+			if n == 0 {
+				return nil, 0, fmt.Sprintf("AND(%s)[0]", text)
+			}
+			children = append(children, planned{p, n, text})
+		}
+
+		sort.SliceStable(children, func(i, j int) bool {
+			return children[i].estimated < children[j].estimated
+		})
+
+		out := &go_query_dsl.Query{Type: q.Type, Boost: q.Boost}
+		estimated := unknown
+		texts := []string{}
+		for _, c := range children {
+			out.Queries = append(out.Queries, c.query)
+			texts = append(texts, c.text)
+			if c.estimated < estimated {
+				estimated = c.estimated
+			}
+		}
+
+		text := fmt.Sprintf("AND(%s)", strings.Join(texts, ", "))
+		if q.Not != nil {
+			not, n, notText := planQuery(q.Not, df)
+			if n != 0 {
+				out.Not = not
+				text += fmt.Sprintf(" NOT(%s)", notText)
+			}
+		}
+		return out, estimated, fmt.Sprintf("%s[%d]", text, estimated)
+
+	case go_query_dsl.Query_OR, go_query_dsl.Query_DISMAX:
+		if len(q.Queries) == 0 || q.Not != nil {
+			return q, unknown, "invalid"
+		}
+
+		out := &go_query_dsl.Query{Type: q.Type, Boost: q.Boost, Tiebreaker: q.Tiebreaker}
+		estimated := uint64(0)
+		texts := []string{}
+		for _, sub := range q.Queries {
+			p, n, text := planQuery(sub, df)
+			if n == 0 {
+				continue
+			}
+			out.Queries = append(out.Queries, p)
+			texts = append(texts, text)
+			if estimated+n < estimated {
+				estimated = unknown
+			} else {
+				estimated += n
+			}
+		}
+
+		if len(out.Queries) == 0 {
+			return nil, 0, fmt.Sprintf("%s()[0]", q.Type.String())
+		}
+		return out, estimated, fmt.Sprintf("%s(%s)[%d]", q.Type.String(), strings.Join(texts, ", "), estimated)
+	}
+
+	return q, unknown, "invalid"
+}
+
+// docFrequency is the number of postings of field:value, it only stats the
+// posting files, same path as dsl.DirIndex uses
+func (s *Segment) docFrequency(field, value string) uint64 {
+	field = termCleanup(field)
+	if len(field) == 0 {
+		return 0
+	}
+
+	n := uint64(0)
+	for _, t := range dsl.DefaultAnalyzer.AnalyzeSearch(value) {
+		t = termCleanup(t)
+		if len(t) == 0 {
+			continue
+		}
+
+		info, err := os.Stat(path.Join(s.root, "inv", field, string(t[len(t)-1]), t))
+		if err == nil {
+			n += uint64(info.Size() / 4)
+		}
+	}
+	return n
+}
+
+func termCleanup(s string) string {
+	x := common.ReplaceNonAlphanumericWith(s, '_')
+	if len(x) > dsl.DirIndexMaxTermLen {
+		return x[:dsl.DirIndexMaxTermLen]
+	}
+	return x
+}
@@ -0,0 +1,129 @@
+package index
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	spec "github.com/rekki/blackrock/pkg/blackrock_io"
+	"github.com/rekki/go-query/util/go_query_dsl"
+)
+
+func term(field, value string) *go_query_dsl.Query {
+	return &go_query_dsl.Query{Type: go_query_dsl.Query_TERM, Field: field, Value: value}
+}
+
+func TestPlanQuery(t *testing.T) {
+	postings := map[string]uint64{"a:1": 100, "b:1": 10, "c:1": 1000}
+	df := func(field, value string) uint64 {
+		return postings[field+":"+value]
+	}
+
+	and := &go_query_dsl.Query{Type: go_query_dsl.Query_AND, Queries: []*go_query_dsl.Query{term("c", "1"), term("a", "1"), term("b", "1")}}
+	planned, estimated, text := planQuery(and, df)
+	if estimated != 10 || planned.Queries[0].Field != "b" || planned.Queries[1].Field != "a" || planned.Queries[2].Field != "c" {
+		t.Fatalf("unexpected plan %s", text)
+	}
+	if len(and.Queries) != 3 || and.Queries[0].Field != "c" {
+		t.Fatalf("input was modified")
+	}
+
+	and.Queries = append(and.Queries, term("missing", "1"))
+	_, estimated, text = planQuery(and, df)
+	if estimated != 0 {
+		t.Fatalf("expected 0 got %d, %s", estimated, text)
+	}
+
+	or := &go_query_dsl.Query{Type: go_query_dsl.Query_OR, Queries: []*go_query_dsl.Query{term("missing", "1"), term("a", "1"), term("b", "1")}}
+	planned, estimated, text = planQuery(or, df)
+	if estimated != 110 || len(planned.Queries) != 2 {
+		t.Fatalf("unexpected plan %s", text)
+	}
+
+	or.Queries = []*go_query_dsl.Query{term("missing", "1")}
+	_, estimated, _ = planQuery(or, df)
+	if estimated != 0 {
+		t.Fatalf("expected 0 got %d", estimated)
+	}
+
+	// not without postings is dropped, with postings is kept
+	not := &go_query_dsl.Query{Type: go_query_dsl.Query_AND, Queries: []*go_query_dsl.Query{term("a", "1")}, Not: term("missing", "1")}
+	planned, estimated, _ = planQuery(not, df)
+	if estimated != 100 || planned.Not != nil {
+		t.Fatalf("unexpected plan %+v", planned)
+	}
+	not.Not = term("b", "1")
+	planned, _, _ = planQuery(not, df)
+	if planned.Not == nil {
+		t.Fatalf("expected not")
+	}
+
+	// invalid queries are left for dsl.Parse
+	_, estimated, _ = planQuery(&go_query_dsl.Query{Type: go_query_dsl.Query_TERM}, df)
+	if estimated == 0 {
+		t.Fatalf("invalid query must not be skipped")
+	}
+}
+
+func TestExplain(t *testing.T) {
+	root, err := ioutil.TempDir("", "si")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	si := NewSearchIndex(root, 10, 3600, false, map[string]bool{})
+	defer si.Close()
+
+	for i := 0; i < 20; i++ {
+		e := RandomEnvelope(1 + int64(i%2)*3600*1e9)
+		if i%2 == 0 {
+			e.Metadata.Search = append(e.Metadata.Search, spec.KV{Key: "only", Value: "first"})
+		}
+		e.Metadata.Search = append(e.Metadata.Search, spec.KV{Key: "all", Value: "yes"})
+		err = si.Ingest(e)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	query := &spec.SearchQueryRequest{
+		FromSecond: 1,
+		ToSecond:   7200,
+		Explain:    true,
+		Query:      &go_query_dsl.Query{Type: go_query_dsl.Query_AND, Queries: []*go_query_dsl.Query{term("all", "yes"), term("only", "first")}},
+	}
+
+	matching := 0
+	explain, err := si.ForEachExplain(query, 0, func(s *Segment, did int32, score float32) error {
+		matching++
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if matching != 10 {
+		t.Fatalf("expected 10 got %d", matching)
+	}
+
+	// one segment per hour
+	if len(explain.Segments) != 2 {
+		t.Fatalf("unexpected explain %+v", explain)
+	}
+
+	first, second := explain.Segments[0], explain.Segments[1]
+	if first.Skipped || first.Matched != 10 || first.Estimated != 10 || first.Plan != "AND(all:yes[10], only:first[10])[10]" {
+		t.Fatalf("unexpected plan %+v", first)
+	}
+	if !second.Skipped || second.Matched != 0 || second.Estimated != 0 {
+		t.Fatalf("expected skipped segment %+v", second)
+	}
+
+	query.Explain = false
+	explain, err = si.ForEachExplain(query, 0, func(s *Segment, did int32, score float32) error {
+		return nil
+	})
+	if err != nil || explain != nil {
+		t.Fatalf("expected no explain, got %v %v", explain, err)
+	}
+}
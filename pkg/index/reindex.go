@@ -0,0 +1,110 @@
+package index
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	spec "github.com/rekki/blackrock/pkg/blackrock_io"
+	. "github.com/rekki/blackrock/pkg/logger"
+)
+
+var errReindexRunning = errors.New("reindex is already running")
+
+type reindexJob struct {
+	status spec.ReindexStatus
+	sync.Mutex
+}
+
+// StartReindex switches to the new whitelist and rebuilds the inverted index
+// of the segments overlapping [from, to] in the background, use
+// ReindexStatus to follow the progress
+func (m *SearchIndex) StartReindex(from, to uint32, whitelist map[string]bool) error {
+	m.reindex.Lock()
+	if m.reindex.status.Running {
+		m.reindex.Unlock()
+		return errReindexRunning
+	}
+	m.reindex.status = spec.ReindexStatus{Running: true, StartedAtNs: time.Now().UnixNano(), Whitelist: whitelistToList(whitelist)}
+	m.reindex.Unlock()
+
+	go func() {
+		err := m.Reindex(from, to, whitelist)
+		if err != nil {
+			Log.Warnf("reindex failed, err: %s", err.Error())
+		}
+	}()
+	return nil
+}
+
+func (m *SearchIndex) ReindexStatus() *spec.ReindexStatus {
+	m.reindex.Lock()
+	defer m.reindex.Unlock()
+
+	status := m.reindex.status
+	return &status
+}
+
+// Reindex is the blocking version of StartReindex, the new whitelist is used
+// for everything ingested after the call, and each segment is swapped as
+// soon as it is rebuilt
+func (m *SearchIndex) Reindex(from, to uint32, whitelist map[string]bool) error {
+	m.maintenance.Lock()
+	defer m.maintenance.Unlock()
+
+	segments := m.listSegments(from, to)
+	m.setWhitelist(whitelist)
+	m.updateReindexStatus(func(s *spec.ReindexStatus) {
+		s.Running = true
+		s.SegmentsTotal = uint32(len(segments))
+	})
+
+	var err error
+	for _, segment := range segments {
+		err = m.rebuildInverted(segment, whitelist)
+		if err != nil {
+			break
+		}
+
+		m.updateReindexStatus(func(s *spec.ReindexStatus) {
+			s.SegmentsDone++
+		})
+	}
+
+	m.updateReindexStatus(func(s *spec.ReindexStatus) {
+		s.Running = false
+		s.FinishedAtNs = time.Now().UnixNano()
+		if err != nil {
+			s.Error = err.Error()
+		}
+	})
+	return err
+}
+
+func (m *SearchIndex) updateReindexStatus(cb func(*spec.ReindexStatus)) {
+	m.reindex.Lock()
+	cb(&m.reindex.status)
+	m.reindex.Unlock()
+}
+
+func (m *SearchIndex) setWhitelist(whitelist map[string]bool) {
+	m.Lock()
+	defer m.Unlock()
+
+	m.whitelist = whitelist
+	for _, s := range m.Segments {
+		s.whitelist = whitelist
+	}
+}
+
+func whitelistToList(whitelist map[string]bool) []string {
+	out := []string{}
+	for k, v := range whitelist {
+		if v {
+			out = append(out, k)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
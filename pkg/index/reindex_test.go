@@ -0,0 +1,81 @@
+package index
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	spec "github.com/rekki/blackrock/pkg/blackrock_io"
+	"github.com/rekki/go-query/util/go_query_dsl"
+)
+
+func TestReindex(t *testing.T) {
+	root, err := ioutil.TempDir("", "si")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	si := NewSearchIndex(root, 10, 3600, false, map[string]bool{"country": true})
+	defer si.Close()
+
+	ingest := func(n int) {
+		for i := 0; i < n; i++ {
+			e := RandomEnvelope(1 + (int64(i%2) * 3600 * 1e9))
+			e.Metadata.Search = append(e.Metadata.Search, spec.KV{Key: "country", Value: "nl"}, spec.KV{Key: "city", Value: "amsterdam"})
+			err := si.Ingest(e)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	countQuery := func(field, value string) int {
+		return countMatching(t, si, &spec.SearchQueryRequest{FromSecond: 1, ToSecond: 7200, Query: &go_query_dsl.Query{Field: field, Value: value}})
+	}
+
+	inserted := 100
+	ingest(inserted)
+
+	if n := countQuery("country", "nl"); n != inserted {
+		t.Fatalf("expected %d got %d", inserted, n)
+	}
+	if n := countQuery("city", "amsterdam"); n != 0 {
+		t.Fatalf("expected 0 got %d", n)
+	}
+
+	err = si.StartReindex(0, 0, map[string]bool{"city": true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var status *spec.ReindexStatus
+	for i := 0; i < 100; i++ {
+		status = si.ReindexStatus()
+		if !status.Running {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	if status.Running || status.Error != "" || status.SegmentsTotal == 0 || status.SegmentsDone != status.SegmentsTotal {
+		t.Fatalf("unexpected status %+v", status)
+	}
+
+	if n := countQuery("city", "amsterdam"); n != inserted {
+		t.Fatalf("expected %d got %d", inserted, n)
+	}
+	if n := countQuery("country", "nl"); n != 0 {
+		t.Fatalf("expected 0 got %d", n)
+	}
+
+	// new events follow the new whitelist
+	ingest(10)
+	if n := countQuery("city", "amsterdam"); n != inserted+10 {
+		t.Fatalf("expected %d got %d", inserted+10, n)
+	}
+	if n := countQuery("country", "nl"); n != 0 {
+		t.Fatalf("expected 0 got %d", n)
+	}
+}
@@ -0,0 +1,491 @@
+package index
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/gogo/protobuf/proto"
+	spec "github.com/rekki/blackrock/pkg/blackrock_io"
+	. "github.com/rekki/blackrock/pkg/logger"
+	pen "github.com/rekki/go-pen"
+)
+
+var errReplicaDiverged = errors.New("replica forward index diverged from the leader")
+var errShipFull = errors.New("ship chunk is full")
+
+const defaultShipBytes = 4 * 1024 * 1024
+const shipChunkBytes = 1024 * 1024
+
+func forwardSize(root string) (int64, error) {
+	info, err := os.Stat(path.Join(root, "main.bin"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return info.Size(), nil
+}
+
+// padded rounds the forward size up to where the next record starts, the
+// last record is not padded on disk
+func padded(size int64) int64 {
+	pad := int64(pen.PAD)
+	return (size + pad - 1) / pad * pad
+}
+
+// ReplicationManifest lists the segments with the current size of their
+// forward index, followers compare it with what they have and Ship the rest
+func (m *SearchIndex) ReplicationManifest() (*spec.ReplicationManifest, error) {
+	m.RLock()
+	defer m.RUnlock()
+
+	out := &spec.ReplicationManifest{Whitelist: whitelistToList(m.whitelist)}
+	for _, s := range m.catalog.all() {
+		size, err := forwardSize(path.Join(m.root, s.Id))
+		if err != nil {
+			return nil, err
+		}
+		out.Segments = append(out.Segments, &spec.ReplicaSegment{Id: s.Id, ForwardBytes: size, Sealed: s.stats.Sealed})
+	}
+	return out, nil
+}
+
+// Ship sends a segment to a follower, either all of its files as they are,
+// or the forward records after offset bytes, at most maxBytes of them, so
+// the follower can index them itself
+func (m *SearchIndex) Ship(in *spec.ShipRequest, cb func(*spec.ShipChunk) error) error {
+	_, err := parseSegmentId(in.SegmentId)
+	if err != nil {
+		return err
+	}
+
+	if in.Files {
+		return m.shipFiles(in.SegmentId, cb)
+	}
+
+	maxBytes := in.MaxBytes
+	if maxBytes <= 0 {
+		maxBytes = defaultShipBytes
+	}
+	return m.shipRecords(in.SegmentId, in.Offset, maxBytes, cb)
+}
+
+// same as Snapshot, sync and stat under the lock, and then copy the first N
+// bytes of every file
+func (m *SearchIndex) shipFiles(segmentId string, cb func(*spec.ShipChunk) error) error {
+	m.Lock()
+	if m.catalog.get(segmentId) == nil {
+		m.Unlock()
+		return errSegmentNotFound
+	}
+	if s, ok := m.Segments[segmentId]; ok {
+		err := s.writer.Sync()
+		if err != nil {
+			m.Unlock()
+			return err
+		}
+	}
+	root := path.Join(m.root, segmentId)
+	files, err := listSegmentFiles(root)
+	m.Unlock()
+	if err != nil {
+		return err
+	}
+
+	buf := make([]byte, shipChunkBytes)
+	for _, file := range files {
+		err := func() error {
+			f, err := os.Open(path.Join(root, file.Name))
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+
+			offset := int64(0)
+			for {
+				n, err := io.ReadFull(io.LimitReader(f, file.SizeBytes-offset), buf)
+				if n > 0 || offset == 0 {
+					// empty files are sent too
+					serr := cb(&spec.ShipChunk{Name: file.Name, Offset: offset, Data: buf[:n]})
+					if serr != nil {
+						return serr
+					}
+					offset += int64(n)
+				}
+				if err == io.EOF || err == io.ErrUnexpectedEOF {
+					return nil
+				}
+				if err != nil {
+					return err
+				}
+			}
+		}()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// the read lock blocks the writers, so we never see a record that is being
+// written
+func (m *SearchIndex) shipRecords(segmentId string, offset int64, maxBytes int64, cb func(*spec.ShipChunk) error) error {
+	if offset%int64(pen.PAD) != 0 {
+		return errReplicaDiverged
+	}
+
+	chunk := &spec.ShipChunk{NextOffset: offset}
+	err := func() error {
+		m.RLock()
+		defer m.RUnlock()
+
+		if m.catalog.get(segmentId) == nil {
+			return errSegmentNotFound
+		}
+
+		f, err := os.Open(path.Join(m.root, segmentId, "main.bin"))
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		defer f.Close()
+
+		size := int64(0)
+		next, _, err := scanForward(f, uint32(offset/int64(pen.PAD)), func(did int32, data []byte) error {
+			if size >= maxBytes {
+				return errShipFull
+			}
+			chunk.Records = append(chunk.Records, data)
+			size += int64(len(data))
+			return nil
+		})
+		if err != nil && err != errShipFull {
+			return err
+		}
+		chunk.NextOffset = int64(next) * int64(pen.PAD)
+		return nil
+	}()
+	if err != nil {
+		return err
+	}
+	return cb(chunk)
+}
+
+// Replicate runs one round of replication from the leader: sealed segments
+// we dont have are copied as they are, the others are tailed record by
+// record and indexed here with the leader's whitelist, segments the leader
+// no longer has (merged or removed) are dropped. The forward index ends up
+// byte for byte the same as the leader's, so the sizes tell us where to
+// continue from.
+func (m *SearchIndex) Replicate(ctx context.Context, leader spec.SearchClient) error {
+	m.maintenance.Lock()
+	defer m.maintenance.Unlock()
+
+	manifest, err := leader.SayReplicationManifest(ctx, &spec.ReplicationRequest{})
+	if err != nil {
+		return err
+	}
+
+	m.RLock()
+	current := whitelistToList(m.whitelist)
+	m.RUnlock()
+	if strings.Join(current, ",") != strings.Join(manifest.Whitelist, ",") {
+		whitelist := map[string]bool{}
+		for _, v := range manifest.Whitelist {
+			whitelist[v] = true
+		}
+		m.setWhitelist(whitelist)
+	}
+
+	keep := map[string]bool{}
+	for _, segment := range manifest.Segments {
+		keep[segment.Id] = true
+	}
+
+	for _, segment := range manifest.Segments {
+		m.RLock()
+		local := m.catalog.get(segment.Id)
+		m.RUnlock()
+
+		size := int64(0)
+		if local != nil {
+			size, err = forwardSize(path.Join(m.root, segment.Id))
+			if err != nil {
+				return err
+			}
+		}
+
+		if size == segment.ForwardBytes && local != nil {
+			continue
+		}
+
+		if size < segment.ForwardBytes && (local != nil || !segment.Sealed) {
+			err = m.tailSegment(ctx, leader, segment.Id, padded(size), padded(segment.ForwardBytes), keep)
+			if err != errReplicaDiverged {
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			Log.Warnf("segment %s diverged from the leader, copying it again", segment.Id)
+		}
+
+		err = m.fetchSegment(ctx, leader, segment.Id, keep)
+		if err != nil {
+			return err
+		}
+	}
+
+	m.Lock()
+	defer m.Unlock()
+
+	return m.dropSegmentsLocked(func(s *segmentInfo) bool {
+		return !keep[s.Id]
+	})
+}
+
+func (m *SearchIndex) tailSegment(ctx context.Context, leader spec.SearchClient, segmentId string, offset, until int64, keep map[string]bool) error {
+	for offset < until {
+		stream, err := leader.SayShip(ctx, &spec.ShipRequest{SegmentId: segmentId, Offset: offset, MaxBytes: defaultShipBytes})
+		if err != nil {
+			return err
+		}
+
+		progress := false
+		for {
+			chunk, err := stream.Recv()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return err
+			}
+
+			size, err := m.applyRecords(segmentId, chunk.Records, keep)
+			if err != nil {
+				return err
+			}
+			if padded(size) != chunk.NextOffset {
+				return errReplicaDiverged
+			}
+			progress = progress || chunk.NextOffset > offset
+			offset = chunk.NextOffset
+		}
+
+		if !progress {
+			// the leader has a torn write at the end, next round
+			return nil
+		}
+	}
+	return nil
+}
+
+// applyRecords appends the records as they are, returns the size of the
+// forward index after that
+func (m *SearchIndex) applyRecords(segmentId string, records [][]byte, keep map[string]bool) (int64, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	info := m.catalog.get(segmentId)
+	isNew := info == nil
+	if isNew {
+		var err error
+		info, err = parseSegmentId(segmentId)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if isNew {
+		// the leader merged them into this one
+		err := m.dropSegmentsLocked(func(s *segmentInfo) bool {
+			return !keep[s.Id] && s.overlaps(info.StartNs, info.EndNs())
+		})
+		if err != nil {
+			return 0, err
+		}
+		m.catalog.add(info)
+	}
+
+	segment, ok := m.Segments[segmentId]
+	if !ok {
+		var err error
+		segment, err = m.loadSegmentFromDisk(segmentId, m.whitelist)
+		if err != nil {
+			return 0, err
+		}
+		m.Segments[segmentId] = segment
+	}
+
+	for _, data := range records {
+		meta := &spec.Metadata{}
+		err := proto.Unmarshal(data, meta)
+		if err != nil {
+			// keep the same layout as the leader even if we cant index it
+			_, _, err = segment.writer.Append(data)
+			if err != nil {
+				return 0, err
+			}
+			continue
+		}
+
+		err = segment.ingestEncoded(data, meta)
+		if err != nil {
+			return 0, err
+		}
+		addToStats(info.stats, meta.CreatedAtNs, meta.EventType, meta.Id)
+		m.publish(meta, segment.whitelist)
+	}
+
+	return forwardSize(path.Join(m.root, segmentId))
+}
+
+// fetchSegment copies all files of the segment into a staging directory and
+// swaps it in
+func (m *SearchIndex) fetchSegment(ctx context.Context, leader spec.SearchClient, segmentId string, keep map[string]bool) error {
+	info, err := parseSegmentId(segmentId)
+	if err != nil {
+		return err
+	}
+
+	dst := path.Join(m.root, segmentId)
+	staging := dst + ".replica"
+	old := dst + ".old"
+
+	err = os.RemoveAll(staging)
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(staging)
+
+	stream, err := leader.SayShip(ctx, &spec.ShipRequest{SegmentId: segmentId, Files: true})
+	if err != nil {
+		return err
+	}
+
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		name, err := cleanSnapshotName(chunk.Name)
+		if err != nil {
+			return err
+		}
+
+		err = writeChunk(path.Join(staging, name), chunk.Offset, chunk.Data)
+		if err != nil {
+			return err
+		}
+	}
+
+	m.Lock()
+	defer m.Unlock()
+
+	err = m.dropSegmentsLocked(func(s *segmentInfo) bool {
+		return !keep[s.Id] && s.overlaps(info.StartNs, info.EndNs())
+	})
+	if err != nil {
+		return err
+	}
+
+	m.closeSegments()
+
+	err = os.RemoveAll(old)
+	if err != nil {
+		return err
+	}
+
+	_, err = os.Stat(dst)
+	if err == nil {
+		err = os.Rename(dst, old)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = os.MkdirAll(path.Dir(dst), 0700)
+	if err != nil {
+		return err
+	}
+
+	err = os.Rename(staging, dst)
+	if err != nil {
+		return err
+	}
+
+	err = os.RemoveAll(old)
+	if err != nil {
+		return err
+	}
+
+	existing := m.catalog.get(segmentId)
+	if existing == nil {
+		m.catalog.add(info)
+	} else {
+		info = existing
+	}
+
+	info.stats = newSegmentStats(info)
+	_, err = scanStats(dst, info.stats, 0)
+	if err != nil {
+		return err
+	}
+
+	return m.saveCatalog()
+}
+
+func writeChunk(p string, offset int64, data []byte) error {
+	err := os.MkdirAll(path.Dir(p), 0700)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(p, os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+
+	_, err = f.WriteAt(data, offset)
+	if err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// dropSegmentsLocked removes the matching segments, must be called with the
+// write lock held
+func (m *SearchIndex) dropSegmentsLocked(match func(s *segmentInfo) bool) error {
+	drop := []*segmentInfo{}
+	for _, s := range m.catalog.all() {
+		if match(s) {
+			drop = append(drop, s)
+		}
+	}
+	if len(drop) == 0 {
+		return nil
+	}
+
+	m.closeSegments()
+	for _, s := range drop {
+		Log.Infof("dropping segment %s, the leader does not have it anymore", s.Id)
+		m.catalog.remove(s.Id)
+		err := removeSegmentDir(m.root, s.Id)
+		if err != nil {
+			return err
+		}
+	}
+	return m.saveCatalog()
+}
@@ -0,0 +1,202 @@
+package index
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	spec "github.com/rekki/blackrock/pkg/blackrock_io"
+	"google.golang.org/grpc"
+)
+
+// localLeader calls the leader index directly instead of going over grpc
+type localLeader struct {
+	spec.SearchClient
+	si     *SearchIndex
+	copies int
+}
+
+func (l *localLeader) SayReplicationManifest(ctx context.Context, in *spec.ReplicationRequest, opts ...grpc.CallOption) (*spec.ReplicationManifest, error) {
+	return l.si.ReplicationManifest()
+}
+
+func (l *localLeader) SayShip(ctx context.Context, in *spec.ShipRequest, opts ...grpc.CallOption) (spec.Search_SayShipClient, error) {
+	if in.Files {
+		l.copies++
+	}
+	stream := &shipStream{}
+	err := l.si.Ship(in, func(chunk *spec.ShipChunk) error {
+		// the sender reuses its buffer
+		data := append([]byte{}, chunk.Data...)
+		chunk.Data = data
+		stream.chunks = append(stream.chunks, chunk)
+		return nil
+	})
+	return stream, err
+}
+
+type shipStream struct {
+	grpc.ClientStream
+	chunks []*spec.ShipChunk
+}
+
+func (s *shipStream) Recv() (*spec.ShipChunk, error) {
+	if len(s.chunks) == 0 {
+		return nil, io.EOF
+	}
+	chunk := s.chunks[0]
+	s.chunks = s.chunks[1:]
+	return chunk, nil
+}
+
+func TestReplicate(t *testing.T) {
+	leaderRoot, err := ioutil.TempDir("", "leader")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(leaderRoot)
+
+	followerRoot, err := ioutil.TempDir("", "follower")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(followerRoot)
+
+	leader := NewSearchIndex(leaderRoot, 10, 3600, false, map[string]bool{"all": true})
+	defer leader.Close()
+	follower := NewSearchIndex(followerRoot, 10, 3600, false, map[string]bool{})
+	defer follower.Close()
+
+	offset := uint64(0)
+	ingest := func(n int, createdAt int64) {
+		for i := 0; i < n; i++ {
+			e := RandomEnvelope(createdAt + int64(i))
+			e.Metadata.Search = append(e.Metadata.Search, spec.KV{Key: "all", Value: "yes"})
+			e.Metadata.Id = 3<<56 | offset
+			offset++
+			err := leader.Ingest(e)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	count := func(si *SearchIndex) int {
+		n := 0
+		err := si.ForEach(&spec.SearchQueryRequest{FromSecond: 1, ToSecond: 7200, Query: term("all", "yes")}, 0, func(s *Segment, did int32, score float32) error {
+			n++
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+
+	shipper := &localLeader{si: leader}
+	replicate := func() {
+		err := follower.Replicate(context.Background(), shipper)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if a, b := count(leader), count(follower); a != b {
+			t.Fatalf("leader has %d follower has %d", a, b)
+		}
+
+		for _, s := range leader.catalog.all() {
+			a, err := ioutil.ReadFile(path.Join(leaderRoot, s.Id, "main.bin"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := ioutil.ReadFile(path.Join(followerRoot, s.Id, "main.bin"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(a, b) {
+				t.Fatalf("%s differs", s.Id)
+			}
+		}
+		if a, b := len(leader.catalog.all()), len(follower.catalog.all()); a != b {
+			t.Fatalf("leader has %d segments follower has %d", a, b)
+		}
+	}
+
+	// sealed segments are copied as they are
+	ingest(10, 1e9)
+	ingest(10, 3600*1e9)
+	err = leader.SaveCatalog()
+	if err != nil {
+		t.Fatal(err)
+	}
+	replicate()
+	if count(follower) != 20 {
+		t.Fatalf("expected 20 got %d", count(follower))
+	}
+	if w := whitelistToList(follower.whitelist); len(w) != 1 || w[0] != "all" {
+		t.Fatalf("expected the leader whitelist, got %v", w)
+	}
+
+	// the rest is tailed, the last record is not padded on disk
+	ingest(5, 3601*1e9)
+	replicate()
+	replicate()
+	copies := shipper.copies
+	ingest(1, 3606*1e9)
+	replicate()
+	if shipper.copies != copies {
+		t.Fatalf("expected the append to be tailed, got %d copies", shipper.copies-copies)
+	}
+	ingest(1, 3607*1e9)
+	leaderSize, _ := forwardSize(path.Join(leaderRoot, "3600/1"))
+	if leaderSize%64 == 0 {
+		t.Fatalf("expected an unpadded forward size, got %d", leaderSize)
+	}
+	replicate()
+	if shipper.copies != copies {
+		t.Fatalf("expected the append to be tailed, got %d copies", shipper.copies-copies)
+	}
+	if count(follower) != 27 {
+		t.Fatalf("expected 27 got %d", count(follower))
+	}
+
+	stats := follower.Stats(0, 0, 10)
+	if stats.Docs != 27 || stats.PartitionOffsets[3] != 26 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+
+	// merged segments replace the sources
+	err = leader.Merge([]Tier{{Step: 86400, Age: time.Hour}}, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	replicate()
+	if ids := follower.catalog.all(); len(ids) != 1 || ids[0].Id != "86400/0" {
+		t.Fatalf("unexpected segments %v", ids)
+	}
+
+	// the follower diverged, it gets a fresh copy
+	ingest(1, 2*1e9)
+	f, err := os.OpenFile(path.Join(followerRoot, "86400/0", "main.bin"), os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = f.Write(make([]byte, 1024*1024))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	copies = shipper.copies
+	replicate()
+	if count(follower) != 28 {
+		t.Fatalf("expected 28 got %d", count(follower))
+	}
+	if shipper.copies == copies {
+		t.Fatal("expected a fresh copy")
+	}
+}
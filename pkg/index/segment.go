@@ -53,21 +53,28 @@ func (s *Segment) Ingest(envelope *spec.Envelope) error {
 		return err
 	}
 
+	return s.ingestEncoded(encoded, envelope.Metadata)
+}
+
+func (s *Segment) ingestEncoded(encoded []byte, meta *spec.Metadata) error {
 	did, _, err := s.writer.Append(encoded)
 	if err != nil {
 		return err
 	}
-	meta := envelope.Metadata
 
-	x := Indexable{
+	return s.dir.Index(dsl.DocumentWithID(toIndexable(int32(did), meta, s.whitelist)))
+}
+
+func toIndexable(did int32, meta *spec.Metadata, whitelist map[string]bool) *Indexable {
+	x := &Indexable{
 		data: map[string][]string{},
-		id:   int32(did),
+		id:   did,
 	}
 	for _, kv := range meta.Search {
 		if len(kv.Key) == 0 || len(kv.Value) == 0 {
 			continue
 		}
-		if s.whitelist == nil || len(s.whitelist) == 0 || s.whitelist[kv.Key] {
+		if whitelist == nil || len(whitelist) == 0 || whitelist[kv.Key] {
 			x.data[kv.Key] = append(x.data[kv.Key], kv.Value)
 		}
 	}
@@ -76,7 +83,7 @@ func (s *Segment) Ingest(envelope *spec.Envelope) error {
 	x.data["event_type"] = []string{meta.EventType}
 	x.data["blackrock"] = []string{"match_all"}
 
-	return s.dir.Index(dsl.DocumentWithID(&x))
+	return x
 }
 
 func (s *Segment) ReadForward(did int32) ([]byte, error) {
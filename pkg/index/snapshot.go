@@ -0,0 +1,560 @@
+package index
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gogo/protobuf/jsonpb"
+	spec "github.com/rekki/blackrock/pkg/blackrock_io"
+)
+
+const snapshotManifestName = "manifest.json"
+
+var errSnapshotOverlap = errors.New("snapshot segment overlaps existing segment")
+var errSnapshotChecksum = errors.New("snapshot checksum mismatch")
+var errSnapshotBadName = errors.New("snapshot contains invalid file name")
+
+// Snapshot copies the segments that start within [from, to] into dest, from
+// and to are seconds and 0 means no bound. If dest ends with .tar, .tar.gz or
+// .tgz a tarball is written, otherwise a directory.
+//
+// main.bin and the postings are append only, so we hold the lock only to
+// sync and stat the files, copying the first N bytes of each file after that
+// gives a consistent view while new events are still being ingested.
+func (m *SearchIndex) Snapshot(dest string, from, to uint32) (*spec.SnapshotManifest, error) {
+	m.maintenance.Lock()
+	defer m.maintenance.Unlock()
+
+	segments := m.listSegments(from, to)
+	manifest := &spec.SnapshotManifest{SegmentStep: m.SegmentStep, CreatedAtNs: time.Now().UnixNano()}
+
+	m.Lock()
+	for _, segment := range segments {
+		if s, ok := m.Segments[segment.Id]; ok {
+			err := s.writer.Sync()
+			if err != nil {
+				m.Unlock()
+				return nil, err
+			}
+		}
+
+		files, err := listSegmentFiles(path.Join(m.root, segment.Id))
+		if err != nil {
+			m.Unlock()
+			return nil, err
+		}
+		manifest.Segments = append(manifest.Segments, &spec.SnapshotSegment{Id: segment.Id, StartNs: segment.StartNs, Files: files})
+	}
+	m.Unlock()
+
+	w, err := newSnapshotWriter(dest)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, segment := range manifest.Segments {
+		for _, f := range segment.Files {
+			f.Sha256, err = copySnapshotFile(w, path.Join(m.root, segment.Id, f.Name), path.Join(segment.Id, f.Name), f.SizeBytes)
+			if err != nil {
+				w.Close()
+				return nil, err
+			}
+		}
+	}
+
+	encoded, err := (&jsonpb.Marshaler{OrigName: true, Indent: "  "}).MarshalToString(manifest)
+	if err != nil {
+		w.Close()
+		return nil, err
+	}
+
+	err = w.Write(snapshotManifestName, strings.NewReader(encoded), int64(len(encoded)))
+	if err != nil {
+		w.Close()
+		return nil, err
+	}
+
+	return manifest, w.Close()
+}
+
+// Restore validates the snapshot at src (directory or tarball) and replaces
+// the segments it contains, segments that are not in the snapshot are left
+// untouched. It is safe to call on a running index, ingestion and search are
+// blocked while the segments are swapped.
+//
+// Segments that overlap an existing segment with different step (e.g. it
+// was merged in the meantime) are rejected, we would double count them.
+func (m *SearchIndex) Restore(src string) (*spec.SnapshotManifest, error) {
+	m.maintenance.Lock()
+	defer m.maintenance.Unlock()
+
+	staging, err := ioutil.TempDir(m.root, ".restore")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(staging)
+
+	if isTarball(src) {
+		err = extractSnapshotTarball(src, staging)
+	} else {
+		err = extractSnapshotDir(src, staging)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	manifest, err := verifySnapshot(staging)
+	if err != nil {
+		return nil, err
+	}
+
+	err = pruneSnapshot(staging, manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	m.Lock()
+	defer m.Unlock()
+
+	restored := []*segmentInfo{}
+	for _, segment := range manifest.Segments {
+		info, err := parseSegmentId(segment.Id)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, existing := range m.catalog.between(info.StartNs, info.EndNs()) {
+			if existing.Id != info.Id {
+				return nil, fmt.Errorf("%s overlaps %s: %s", info.Id, existing.Id, errSnapshotOverlap.Error())
+			}
+		}
+		restored = append(restored, info)
+	}
+
+	// otherwise we would keep appending to the old files
+	m.closeSegments()
+
+	for i, segment := range manifest.Segments {
+		dst := path.Join(m.root, segment.Id)
+		old := dst + ".old"
+
+		err = os.RemoveAll(old)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = os.Stat(dst)
+		if err == nil {
+			err = os.Rename(dst, old)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		err = os.MkdirAll(path.Dir(dst), 0700)
+		if err != nil {
+			return nil, err
+		}
+
+		err = os.Rename(path.Join(staging, segment.Id), dst)
+		if err != nil {
+			return nil, err
+		}
+
+		err = os.RemoveAll(old)
+		if err != nil {
+			return nil, err
+		}
+
+		info := m.catalog.get(segment.Id)
+		if info == nil {
+			info = restored[i]
+			m.catalog.add(info)
+		}
+
+		info.stats = newSegmentStats(info)
+		_, err = scanStats(dst, info.stats, 0)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return manifest, m.saveCatalog()
+}
+
+func listSegmentFiles(root string) ([]*spec.SnapshotFile, error) {
+	files := []*spec.SnapshotFile{}
+	err := filepath.Walk(root, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		name, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		files = append(files, &spec.SnapshotFile{Name: filepath.ToSlash(name), SizeBytes: info.Size()})
+		return nil
+	})
+	return files, err
+}
+
+func copySnapshotFile(w snapshotWriter, src string, name string, size int64) (string, error) {
+	f, err := os.Open(src)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	err = w.Write(name, io.TeeReader(io.LimitReader(f, size), h), size)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func verifySnapshot(root string) (*spec.SnapshotManifest, error) {
+	f, err := os.Open(path.Join(root, snapshotManifestName))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	manifest := &spec.SnapshotManifest{}
+	err = jsonpb.Unmarshal(f, manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	err = upgradeSnapshot(root, manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, segment := range manifest.Segments {
+		_, err := parseSegmentId(segment.Id)
+		if err != nil {
+			return nil, errSnapshotBadName
+		}
+
+		for _, file := range segment.Files {
+			name, err := cleanSnapshotName(path.Join(segment.Id, file.Name))
+			if err != nil {
+				return nil, err
+			}
+
+			sum, size, err := sha256File(path.Join(root, name))
+			if err != nil {
+				return nil, err
+			}
+
+			if size != file.SizeBytes || sum != file.Sha256 {
+				return nil, fmt.Errorf("%s: %s", name, errSnapshotChecksum.Error())
+			}
+		}
+	}
+
+	return manifest, nil
+}
+
+// upgradeSnapshot moves the segments of snapshots written before the segment
+// catalog, their id is only the segment number, to <step>/<number>
+func upgradeSnapshot(root string, manifest *spec.SnapshotManifest) error {
+	legacy := []*spec.SnapshotSegment{}
+	for _, segment := range manifest.Segments {
+		if _, err := strconv.ParseInt(segment.Id, 10, 64); err == nil {
+			legacy = append(legacy, segment)
+		}
+	}
+	if len(legacy) == 0 {
+		return nil
+	}
+	if manifest.SegmentStep <= 0 {
+		return errSnapshotBadName
+	}
+
+	// the old and the new ids can clash, e.g. 3600 and 3600/3600
+	moved := path.Join(root, ".legacy")
+	err := os.MkdirAll(moved, 0700)
+	if err != nil {
+		return err
+	}
+	for _, segment := range legacy {
+		err := os.Rename(path.Join(root, segment.Id), path.Join(moved, segment.Id))
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, segment := range legacy {
+		id := fmt.Sprintf("%d/%s", manifest.SegmentStep, segment.Id)
+		err := os.MkdirAll(path.Dir(path.Join(root, id)), 0700)
+		if err != nil {
+			return err
+		}
+		err = os.Rename(path.Join(moved, segment.Id), path.Join(root, id))
+		if err != nil {
+			return err
+		}
+		segment.Id = id
+	}
+	return nil
+}
+
+// pruneSnapshot removes the files in the segments of the snapshot that are
+// not in its manifest, the segment directories are moved as they are
+func pruneSnapshot(root string, manifest *spec.SnapshotManifest) error {
+	for _, segment := range manifest.Segments {
+		listed := map[string]bool{}
+		for _, file := range segment.Files {
+			listed[path.Clean(file.Name)] = true
+		}
+
+		segmentRoot := path.Join(root, segment.Id)
+		err := filepath.Walk(segmentRoot, func(p string, info os.FileInfo, err error) error {
+			if err != nil {
+				if p == segmentRoot && os.IsNotExist(err) {
+					return nil
+				}
+				return err
+			}
+			if info.IsDir() {
+				return nil
+			}
+			name, err := filepath.Rel(segmentRoot, p)
+			if err != nil {
+				return err
+			}
+			if listed[filepath.ToSlash(name)] {
+				return nil
+			}
+			return os.Remove(p)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func sha256File(fn string) (string, int64, error) {
+	f, err := os.Open(fn)
+	if err != nil {
+		return "", 0, err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	n, err := io.Copy(h, f)
+	if err != nil {
+		return "", 0, err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), n, nil
+}
+
+func cleanSnapshotName(name string) (string, error) {
+	name = path.Clean(filepath.ToSlash(name))
+	if path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+		return "", errSnapshotBadName
+	}
+	return name, nil
+}
+
+func isTarball(fn string) bool {
+	return strings.HasSuffix(fn, ".tar") || isGzip(fn)
+}
+
+func isGzip(fn string) bool {
+	return strings.HasSuffix(fn, ".tar.gz") || strings.HasSuffix(fn, ".tgz")
+}
+
+func extractSnapshotDir(src string, dst string) error {
+	return filepath.Walk(src, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		name, err := filepath.Rel(src, p)
+		if err != nil {
+			return err
+		}
+
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		return writeSnapshotFile(path.Join(dst, filepath.ToSlash(name)), f, info.Size())
+	})
+}
+
+func extractSnapshotTarball(src string, dst string) error {
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if isGzip(src) {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		name, err := cleanSnapshotName(header.Name)
+		if err != nil {
+			return err
+		}
+
+		err = writeSnapshotFile(path.Join(dst, name), tr, header.Size)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func writeSnapshotFile(fn string, r io.Reader, size int64) error {
+	err := os.MkdirAll(path.Dir(fn), 0700)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(fn, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.CopyN(f, r, size)
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	err = f.Sync()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+type snapshotWriter interface {
+	Write(name string, r io.Reader, size int64) error
+	Close() error
+}
+
+func newSnapshotWriter(dest string) (snapshotWriter, error) {
+	if !isTarball(dest) {
+		err := os.MkdirAll(dest, 0700)
+		if err != nil {
+			return nil, err
+		}
+		return &dirSnapshotWriter{root: dest}, nil
+	}
+
+	err := os.MkdirAll(path.Dir(dest), 0700)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.OpenFile(dest, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &tarSnapshotWriter{file: f}
+	if isGzip(dest) {
+		w.gz = gzip.NewWriter(f)
+		w.tw = tar.NewWriter(w.gz)
+	} else {
+		w.tw = tar.NewWriter(f)
+	}
+	return w, nil
+}
+
+type dirSnapshotWriter struct {
+	root string
+}
+
+func (w *dirSnapshotWriter) Write(name string, r io.Reader, size int64) error {
+	return writeSnapshotFile(path.Join(w.root, name), r, size)
+}
+
+func (w *dirSnapshotWriter) Close() error {
+	return nil
+}
+
+type tarSnapshotWriter struct {
+	file *os.File
+	gz   *gzip.Writer
+	tw   *tar.Writer
+}
+
+func (w *tarSnapshotWriter) Write(name string, r io.Reader, size int64) error {
+	err := w.tw.WriteHeader(&tar.Header{
+		Name:     name,
+		Mode:     0600,
+		Size:     size,
+		ModTime:  time.Now(),
+		Typeflag: tar.TypeReg,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = io.CopyN(w.tw, r, size)
+	return err
+}
+
+func (w *tarSnapshotWriter) Close() error {
+	err := w.tw.Close()
+	if w.gz != nil && err == nil {
+		err = w.gz.Close()
+	}
+	if err == nil {
+		err = w.file.Sync()
+	}
+
+	closeErr := w.file.Close()
+	if err == nil {
+		err = closeErr
+	}
+	return err
+}
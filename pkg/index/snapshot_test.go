@@ -0,0 +1,236 @@
+package index
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/gogo/protobuf/jsonpb"
+	spec "github.com/rekki/blackrock/pkg/blackrock_io"
+	"github.com/rekki/go-query/util/go_query_dsl"
+)
+
+func countMatching(t *testing.T, si *SearchIndex, query *spec.SearchQueryRequest) int {
+	matching := 0
+	err := si.ForEach(query, 0, func(s *Segment, did int32, score float32) error {
+		m := &spec.Metadata{}
+		err := s.ReadForwardDecode(did, m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		matching++
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return matching
+}
+
+func TestSnapshotRestore(t *testing.T) {
+	for _, dest := range []string{"backup", "backup.tar", "backup.tar.gz"} {
+		root, err := ioutil.TempDir("", "si")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(root)
+
+		si := NewSearchIndex(path.Join(root, "data"), 10, 3600, false, map[string]bool{})
+		query := &spec.SearchQueryRequest{FromSecond: 1, ToSecond: 7200, Query: &go_query_dsl.Query{Field: "blackrock", Value: "match_all"}}
+
+		inserted := 100
+		for i := 0; i < inserted; i++ {
+			err = si.Ingest(RandomEnvelope(1 + (int64(i%2) * 3600 * 1e9)))
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		manifest, err := si.Snapshot(path.Join(root, dest), 0, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(manifest.Segments) != 2 {
+			t.Fatalf("expected 2 segments got %d", len(manifest.Segments))
+		}
+
+		for i := 0; i < inserted; i++ {
+			err = si.Ingest(RandomEnvelope(1))
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if n := countMatching(t, si, query); n != inserted*2 {
+			t.Fatalf("expected %d got %d", inserted*2, n)
+		}
+
+		// restore into running index
+		_, err = si.Restore(path.Join(root, dest))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if n := countMatching(t, si, query); n != inserted {
+			t.Fatalf("expected %d got %d", inserted, n)
+		}
+
+		// must be still writable after the restore
+		err = si.Ingest(RandomEnvelope(1))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n := countMatching(t, si, query); n != inserted+1 {
+			t.Fatalf("expected %d got %d", inserted+1, n)
+		}
+		si.Close()
+
+		// restore into empty index
+		si = NewSearchIndex(path.Join(root, "empty"), 10, 3600, false, map[string]bool{})
+		_, err = si.Restore(path.Join(root, dest))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n := countMatching(t, si, query); n != inserted {
+			t.Fatalf("expected %d got %d", inserted, n)
+		}
+		si.Close()
+
+		// different step, but nothing overlaps
+		si = NewSearchIndex(path.Join(root, "other-step"), 10, 60, false, map[string]bool{})
+		_, err = si.Restore(path.Join(root, dest))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n := countMatching(t, si, query); n != inserted {
+			t.Fatalf("expected %d got %d", inserted, n)
+		}
+		si.Close()
+
+		si = NewSearchIndex(path.Join(root, "overlap"), 10, 60, false, map[string]bool{})
+		err = si.Ingest(RandomEnvelope(1))
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = si.Restore(path.Join(root, dest))
+		if err == nil || !strings.Contains(err.Error(), errSnapshotOverlap.Error()) {
+			t.Fatalf("expected errSnapshotOverlap, got %v", err)
+		}
+		si.Close()
+	}
+}
+
+func TestRestoreLegacy(t *testing.T) {
+	root, err := ioutil.TempDir("", "si")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	si := NewSearchIndex(path.Join(root, "data"), 10, 3600, false, map[string]bool{})
+	for i := 0; i < 10; i++ {
+		err = si.Ingest(RandomEnvelope(1 + int64(i%2)*3600*3600*1e9))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	dest := path.Join(root, "backup")
+	manifest, err := si.Snapshot(dest, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	si.Close()
+
+	// before the catalog the ids were only the segment number, 3600 clashes
+	// with the directory of the new layout
+	for _, segment := range manifest.Segments {
+		old := strings.TrimPrefix(segment.Id, "3600/")
+		err = os.Rename(path.Join(dest, segment.Id), path.Join(dest, "legacy-"+old))
+		if err != nil {
+			t.Fatal(err)
+		}
+		segment.Id = old
+	}
+	err = os.RemoveAll(path.Join(dest, "3600"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, segment := range manifest.Segments {
+		err = os.Rename(path.Join(dest, "legacy-"+segment.Id), path.Join(dest, segment.Id))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	encoded, err := (&jsonpb.Marshaler{OrigName: true}).MarshalToString(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ioutil.WriteFile(path.Join(dest, snapshotManifestName), []byte(encoded), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	si = NewSearchIndex(path.Join(root, "restored"), 10, 3600, false, map[string]bool{})
+	defer si.Close()
+	restored, err := si.Restore(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(restored.Segments) != 2 || restored.Segments[0].Id != "3600/0" || restored.Segments[1].Id != "3600/3600" {
+		t.Fatalf("unexpected segments %s %s", restored.Segments[0].Id, restored.Segments[1].Id)
+	}
+	query := &spec.SearchQueryRequest{FromSecond: 1, ToSecond: 3600*3600 + 1, Query: &go_query_dsl.Query{Field: "blackrock", Value: "match_all"}}
+	if n := countMatching(t, si, query); n != 10 {
+		t.Fatalf("expected 10 got %d", n)
+	}
+}
+
+func TestRestoreCorrupted(t *testing.T) {
+	root, err := ioutil.TempDir("", "si")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	si := NewSearchIndex(path.Join(root, "data"), 10, 3600, false, map[string]bool{})
+	defer si.Close()
+
+	for i := 0; i < 10; i++ {
+		err = si.Ingest(RandomEnvelope(1))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dest := path.Join(root, "backup")
+	manifest, err := si.Snapshot(dest, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// files that are not in the manifest are not restored
+	err = ioutil.WriteFile(path.Join(dest, manifest.Segments[0].Id, "extra.bin"), []byte("extra"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = si.Restore(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path.Join(root, "data", manifest.Segments[0].Id, "extra.bin")); !os.IsNotExist(err) {
+		t.Fatalf("expected extra.bin to be left out, got %v", err)
+	}
+
+	fn := path.Join(dest, manifest.Segments[0].Id, "main.bin")
+	err = ioutil.WriteFile(fn, []byte("broken"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = si.Restore(dest)
+	if err == nil || !strings.Contains(err.Error(), errSnapshotChecksum.Error()) {
+		t.Fatalf("expected checksum mismatch, got %v", err)
+	}
+}
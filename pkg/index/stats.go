@@ -0,0 +1,272 @@
+package index
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/gogo/protobuf/proto"
+	spec "github.com/rekki/blackrock/pkg/blackrock_io"
+	. "github.com/rekki/blackrock/pkg/logger"
+	pen "github.com/rekki/go-pen"
+)
+
+const catalogName = "catalog.json"
+
+// after that the segment is not expected to change, so we stop refreshing
+// its sizes, late events unseal it
+const sealAfter = time.Hour
+
+// event types after that are counted as otherEventTypes
+const maxEventTypes = 1000
+const otherEventTypes = "blackrock:other"
+
+func newSegmentStats(s *segmentInfo) *spec.SegmentStats {
+	return &spec.SegmentStats{Id: s.Id, Step: s.Step, StartNs: s.StartNs, EventTypes: map[string]uint64{}, PartitionOffsets: map[int32]int64{}}
+}
+
+// addToStats counts the event, id is the kafka partition<<56 | offset set by
+// the consumer, 0 means the event did not come from kafka
+func addToStats(stats *spec.SegmentStats, createdAtNs int64, eventType string, id uint64) {
+	if stats.Docs == 0 || createdAtNs < stats.MinCreatedAtNs {
+		stats.MinCreatedAtNs = createdAtNs
+	}
+	if stats.Docs == 0 || createdAtNs > stats.MaxCreatedAtNs {
+		stats.MaxCreatedAtNs = createdAtNs
+	}
+	stats.Docs++
+
+	if stats.EventTypes == nil {
+		stats.EventTypes = map[string]uint64{}
+	}
+	if _, ok := stats.EventTypes[eventType]; !ok && len(stats.EventTypes) >= maxEventTypes {
+		eventType = otherEventTypes
+	}
+	stats.EventTypes[eventType]++
+	stats.Sealed = false
+
+	if id != 0 {
+		if stats.PartitionOffsets == nil {
+			stats.PartitionOffsets = map[int32]int64{}
+		}
+		partition, offset := int32(id>>56), int64(id&(1<<56-1))
+		if current, ok := stats.PartitionOffsets[partition]; !ok || offset > current {
+			stats.PartitionOffsets[partition] = offset
+		}
+	}
+}
+
+// scanStats adds the records written after offset bytes to the stats and
+// returns how many were added, the last record is not padded on disk so
+// offset is rounded up to the next record
+func scanStats(root string, stats *spec.SegmentStats, offset int64) (int, error) {
+	f, err := os.Open(path.Join(root, "main.bin"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	defer f.Close()
+
+	added := 0
+	_, _, err = scanForward(f, uint32(padded(offset)/int64(pen.PAD)), func(did int32, data []byte) error {
+		meta := spec.BasicMetadata{}
+		err := proto.Unmarshal(data, &meta)
+		if err != nil {
+			return nil
+		}
+		addToStats(stats, meta.CreatedAtNs, meta.EventType, meta.Id)
+		added++
+		return nil
+	})
+	return added, err
+}
+
+func refreshSizes(root string, stats *spec.SegmentStats) error {
+	info, err := os.Stat(path.Join(root, "main.bin"))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	stats.ForwardBytes = 0
+	if err == nil {
+		stats.ForwardBytes = info.Size()
+	}
+
+	stats.Terms = 0
+	stats.InvertedBytes = 0
+	inv := path.Join(root, "inv")
+	return filepath.Walk(inv, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			if p == inv && os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if info.Mode().IsRegular() {
+			stats.Terms++
+			stats.InvertedBytes += info.Size()
+		}
+		return nil
+	})
+}
+
+// DiskBytes is the size of all files of the index, it does not take the
+// lock, files removed while walking (e.g. by a merge) are skipped
+func (m *SearchIndex) DiskBytes() (int64, error) {
+	size := int64(0)
+	err := filepath.Walk(m.root, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if info.Mode().IsRegular() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
+
+// loadStats reads the persisted stats and catches up with whatever was
+// written after they were saved, segments that are not in the catalog file
+// are scanned from the start
+func loadStats(root string, c *catalog) error {
+	saved := map[string]*spec.SegmentStats{}
+	f, err := os.Open(path.Join(root, catalogName))
+	if err == nil {
+		persisted := &spec.SegmentCatalog{}
+		err = jsonpb.Unmarshal(f, persisted)
+		f.Close()
+		if err != nil {
+			Log.Warnf("ignoring %s, err: %s", catalogName, err.Error())
+		}
+		for _, s := range persisted.Segments {
+			saved[s.Id] = s
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	for _, s := range c.segments {
+		segmentRoot := path.Join(root, s.Id)
+		stats, ok := saved[s.Id]
+		if ok {
+			info, err := os.Stat(path.Join(segmentRoot, "main.bin"))
+			if err == nil && info.Size() < stats.ForwardBytes {
+				// replaced behind our back
+				ok = false
+			}
+		}
+		if !ok {
+			stats = newSegmentStats(s)
+		}
+
+		added, err := scanStats(segmentRoot, stats, stats.ForwardBytes)
+		if err != nil {
+			return err
+		}
+
+		if !ok || added > 0 {
+			stats.Sealed = false
+		}
+		s.stats = stats
+	}
+	return nil
+}
+
+// SaveCatalog persists the segment stats, so we dont have to scan all
+// segments on startup
+func (m *SearchIndex) SaveCatalog() error {
+	m.Lock()
+	defer m.Unlock()
+
+	return m.saveCatalog()
+}
+
+// saveCatalog must be called with the write lock held, the sizes of the
+// segments that are not sealed are refreshed, after that ForwardBytes is the
+// offset the stats are valid for
+func (m *SearchIndex) saveCatalog() error {
+	now := time.Now().UnixNano()
+	persisted := &spec.SegmentCatalog{}
+	for _, s := range m.catalog.segments {
+		if !s.stats.Sealed {
+			err := refreshSizes(path.Join(m.root, s.Id), s.stats)
+			if err != nil {
+				return err
+			}
+			s.stats.Sealed = s.EndNs()+int64(sealAfter) < now
+		}
+		persisted.Segments = append(persisted.Segments, s.stats)
+	}
+
+	encoded, err := (&jsonpb.Marshaler{OrigName: true}).MarshalToString(persisted)
+	if err != nil {
+		return err
+	}
+
+	tmp := path.Join(m.root, catalogName+".tmp")
+	err = ioutil.WriteFile(tmp, []byte(encoded), 0600)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, path.Join(m.root, catalogName))
+}
+
+// Stats returns the stats of the segments overlapping [from, to] seconds, 0
+// means no bound, only the top event types of each segment are returned.
+// PartitionOffsets is the highest kafka offset ingested per partition.
+// Docs and created_at are always up to date, the sizes and the term count
+// of the segments that are not sealed are from the last SaveCatalog
+func (m *SearchIndex) Stats(from, to uint32, topEventTypes int) *spec.StatsResponse {
+	out := &spec.StatsResponse{PartitionOffsets: map[int32]int64{}}
+	m.RLock()
+	defer m.RUnlock()
+
+	for _, s := range m.listSegmentsLocked(from, to) {
+		stats := *s.stats
+		stats.EventTypes = topCounts(s.stats.EventTypes, topEventTypes)
+		stats.PartitionOffsets = map[int32]int64{}
+		for partition, offset := range s.stats.PartitionOffsets {
+			stats.PartitionOffsets[partition] = offset
+			if current, ok := out.PartitionOffsets[partition]; !ok || offset > current {
+				out.PartitionOffsets[partition] = offset
+			}
+		}
+		out.Segments = append(out.Segments, &stats)
+		out.Docs += stats.Docs
+		out.ForwardBytes += stats.ForwardBytes
+		out.InvertedBytes += stats.InvertedBytes
+	}
+	return out
+}
+
+func topCounts(counts map[string]uint64, n int) map[string]uint64 {
+	keys := []string{}
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] == counts[keys[j]] {
+			return keys[i] < keys[j]
+		}
+		return counts[keys[i]] > counts[keys[j]]
+	})
+
+	if len(keys) > n {
+		keys = keys[:n]
+	}
+
+	out := map[string]uint64{}
+	for _, k := range keys {
+		out[k] = counts[k]
+	}
+	return out
+}
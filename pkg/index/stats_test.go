@@ -0,0 +1,181 @@
+package index
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	spec "github.com/rekki/blackrock/pkg/blackrock_io"
+	pen "github.com/rekki/go-pen"
+)
+
+func TestStats(t *testing.T) {
+	root, err := ioutil.TempDir("", "si")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	si := NewSearchIndex(root, 10, 3600, false, map[string]bool{})
+
+	ingest := func(n int, createdAt int64, eventType string) {
+		for i := 0; i < n; i++ {
+			e := RandomEnvelope(createdAt + int64(i))
+			e.Metadata.EventType = eventType
+			err := si.Ingest(e)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	ingest(10, 100, "click")
+	ingest(5, 200, "view")
+	ingest(1, 300, "buy")
+	ingest(7, 3600*1e9, "click")
+
+	check := func(si *SearchIndex) {
+		stats := si.Stats(0, 0, 2)
+		if stats.Docs != 23 || len(stats.Segments) != 2 {
+			t.Fatalf("unexpected stats %+v", stats)
+		}
+
+		s := stats.Segments[0]
+		if s.Id != "3600/0" || s.Docs != 16 || s.MinCreatedAtNs != 100 || s.MaxCreatedAtNs != 300 {
+			t.Fatalf("unexpected stats %+v", s)
+		}
+		if len(s.EventTypes) != 2 || s.EventTypes["click"] != 10 || s.EventTypes["view"] != 5 {
+			t.Fatalf("unexpected event types %+v", s.EventTypes)
+		}
+
+		s = stats.Segments[1]
+		if s.Id != "3600/1" || s.Docs != 7 || s.MinCreatedAtNs != 3600*1e9 || s.EventTypes["click"] != 7 {
+			t.Fatalf("unexpected stats %+v", s)
+		}
+
+		if n := len(si.Stats(3600, 0, 10).Segments); n != 1 {
+			t.Fatalf("expected 1 segment got %d", n)
+		}
+	}
+	check(si)
+
+	err = si.SaveCatalog()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stats := si.Stats(0, 0, 10)
+	for _, s := range stats.Segments {
+		if !s.Sealed || s.ForwardBytes == 0 || s.InvertedBytes == 0 || s.Terms == 0 {
+			t.Fatalf("expected sizes and sealed %+v", s)
+		}
+	}
+	size, err := si.DiskBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size < stats.ForwardBytes+stats.InvertedBytes {
+		t.Fatalf("expected at least %d bytes on disk got %d", stats.ForwardBytes+stats.InvertedBytes, size)
+	}
+
+	// write more after the catalog was saved and pretend we crashed
+	old, err := ioutil.ReadFile(path.Join(root, catalogName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ingest(3, 301, "buy")
+	si.Close()
+
+	err = ioutil.WriteFile(path.Join(root, catalogName), old, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	si = NewSearchIndex(root, 10, 3600, false, map[string]bool{})
+	stats = si.Stats(0, 0, 10)
+	s := stats.Segments[0]
+	if stats.Docs != 26 || s.Docs != 19 || s.EventTypes["buy"] != 4 || s.MaxCreatedAtNs != 303 || s.Sealed {
+		t.Fatalf("unexpected stats %+v", s)
+	}
+	si.Close()
+
+	err = os.Remove(path.Join(root, catalogName))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	si = NewSearchIndex(root, 10, 3600, false, map[string]bool{})
+	defer si.Close()
+	if n := si.Stats(0, 0, 10).Docs; n != 26 {
+		t.Fatalf("expected 26 got %d", n)
+	}
+}
+
+func TestStatsRestart(t *testing.T) {
+	root, err := ioutil.TempDir("", "si")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	si := NewSearchIndex(root, 10, 3600, false, map[string]bool{})
+	err = si.Ingest(&spec.Envelope{Metadata: &spec.Metadata{CreatedAtNs: 100, EventType: "a", ForeignType: "u", ForeignId: "1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	si.Close()
+
+	// the last record is not padded on disk, reopening must not scan it
+	// again
+	for i := 0; i < 3; i++ {
+		si = NewSearchIndex(root, 10, 3600, false, map[string]bool{})
+		stats := si.Stats(0, 0, 10)
+		if stats.Docs != 1 || stats.Segments[0].EventTypes["a"] != 1 {
+			t.Fatalf("unexpected stats after %d restarts %+v", i+1, stats)
+		}
+		err = si.SaveCatalog()
+		if err != nil {
+			t.Fatal(err)
+		}
+		si.Close()
+	}
+
+	// a record shorter than PAD ends before the next PAD boundary
+	seg := path.Join(root, "short")
+	err = os.MkdirAll(seg, 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := pen.NewWriter(path.Join(seg, "main.bin"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	encoded, err := proto.Marshal(&spec.BasicMetadata{CreatedAtNs: 1, EventType: "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, err = w.Append(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+
+	stats := &spec.SegmentStats{}
+	added, err := scanStats(seg, stats, 0)
+	if err != nil || added != 1 {
+		t.Fatalf("expected 1 got %d, err: %v", added, err)
+	}
+	info, err := os.Stat(path.Join(seg, "main.bin"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size() >= int64(pen.PAD) {
+		t.Fatalf("expected a short record got %d bytes", info.Size())
+	}
+	added, err = scanStats(seg, stats, info.Size())
+	if err != nil || added != 0 || stats.Docs != 1 {
+		t.Fatalf("expected nothing new got %d, docs: %d, err: %v", added, stats.Docs, err)
+	}
+}
@@ -0,0 +1,161 @@
+package index
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/gogo/protobuf/proto"
+	spec "github.com/rekki/blackrock/pkg/blackrock_io"
+	iq "github.com/rekki/go-query"
+	"github.com/rekki/go-query/util/go_query_dsl"
+	dsl "github.com/rekki/go-query/util/index"
+)
+
+var ErrTailTooSlow = errors.New("tail dropped, too many events were not read in time")
+
+type tailEvent struct {
+	meta    *spec.Metadata
+	dropped uint64
+}
+
+// Tail is a subscription to the newly ingested events that match a query,
+// the events are matched in memory, the same way the segments would match
+// them
+type Tail struct {
+	query      *go_query_dsl.Query
+	events     chan tailEvent
+	gone       chan struct{}
+	dropped    uint64
+	maxDropped uint64
+	m          *SearchIndex
+}
+
+type tails struct {
+	subscribed map[*Tail]bool
+	sync.Mutex
+}
+
+// Tail subscribes to the events that match the query, Ingest never waits
+// for the subscribers, buffer events are kept for a slow reader and after
+// that the events are dropped, after maxDropped dropped in a row the
+// subscription is closed, 0 means never
+func (m *SearchIndex) Tail(query *go_query_dsl.Query, buffer int, maxDropped uint64) (*Tail, error) {
+	if query == nil {
+		return nil, errBadRequest
+	}
+	_, err := dsl.Parse(query, func(k, v string) iq.Query {
+		return iq.Term(1, k+":"+v, []int32{})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	t := &Tail{query: query, events: make(chan tailEvent, buffer), gone: make(chan struct{}), maxDropped: maxDropped, m: m}
+	m.tails.Lock()
+	if m.tails.subscribed == nil {
+		m.tails.subscribed = map[*Tail]bool{}
+	}
+	m.tails.subscribed[t] = true
+	m.tails.Unlock()
+	return t, nil
+}
+
+// Close unsubscribes
+func (t *Tail) Close() {
+	t.m.tails.Lock()
+	delete(t.m.tails.subscribed, t)
+	t.m.tails.Unlock()
+}
+
+// Next waits for the next event, dropped is the number of events skipped
+// before it
+func (t *Tail) Next(ctx context.Context) (*spec.Metadata, uint64, error) {
+	select {
+	case e := <-t.events:
+		return e.meta, e.dropped, nil
+	default:
+	}
+
+	select {
+	case e := <-t.events:
+		return e.meta, e.dropped, nil
+	case <-t.gone:
+		return nil, 0, ErrTailTooSlow
+	case <-ctx.Done():
+		return nil, 0, ctx.Err()
+	}
+}
+
+// tailTerms are the field/term the segment would index for the event
+func tailTerms(meta *spec.Metadata, whitelist map[string]bool) map[string]bool {
+	terms := map[string]bool{}
+	for field, values := range toIndexable(0, meta, whitelist).data {
+		field = termCleanup(field)
+		if len(field) == 0 {
+			continue
+		}
+		for _, v := range values {
+			for _, t := range dsl.DefaultAnalyzer.AnalyzeIndex(v) {
+				t = termCleanup(t)
+				if len(t) > 0 {
+					terms[field+"/"+t] = true
+				}
+			}
+		}
+	}
+	return terms
+}
+
+func tailMatch(query *go_query_dsl.Query, terms map[string]bool) bool {
+	q, err := dsl.Parse(query, func(k, v string) iq.Query {
+		queries := []iq.Query{}
+		for _, t := range dsl.DefaultAnalyzer.AnalyzeSearch(v) {
+			postings := []int32{}
+			if terms[termCleanup(k)+"/"+termCleanup(t)] {
+				postings = []int32{0}
+			}
+			queries = append(queries, iq.Term(1, k+":"+t, postings))
+		}
+		if len(queries) == 1 {
+			return queries[0]
+		}
+		return iq.Or(queries...)
+	})
+	if err != nil {
+		return false
+	}
+	return q.Next() == 0
+}
+
+// publish sends the event to the matching subscribers without waiting
+func (m *SearchIndex) publish(meta *spec.Metadata, whitelist map[string]bool) {
+	m.tails.Lock()
+	defer m.tails.Unlock()
+	if len(m.tails.subscribed) == 0 {
+		return
+	}
+
+	terms := tailTerms(meta, whitelist)
+	var copied *spec.Metadata
+	for t := range m.tails.subscribed {
+		if !tailMatch(t.query, terms) {
+			continue
+		}
+		if copied == nil {
+			// the caller can reuse the metadata after Ingest
+			copied = proto.Clone(meta).(*spec.Metadata)
+		}
+
+		select {
+		case t.events <- tailEvent{meta: copied, dropped: t.dropped}:
+			t.dropped = 0
+		default:
+			t.dropped++
+			if t.maxDropped > 0 && t.dropped >= t.maxDropped {
+				delete(m.tails.subscribed, t)
+				close(t.gone)
+			}
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package index
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	spec "github.com/rekki/blackrock/pkg/blackrock_io"
+	"github.com/rekki/go-query/util/go_query_dsl"
+)
+
+func TestTail(t *testing.T) {
+	root, err := ioutil.TempDir("", "tail")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	si := NewSearchIndex(root, 100, 3600, false, map[string]bool{})
+	defer si.Close()
+
+	queries := []*go_query_dsl.Query{
+		term("event_type", "click"),
+		term("user", "5"),
+		term("campaign", "summer_sale_1"),
+		term("campaign", "summer-sale 0"),
+		{Type: go_query_dsl.Query_AND, Queries: []*go_query_dsl.Query{term("event_type", "click"), term("country", "nl")}},
+		{Type: go_query_dsl.Query_AND, Queries: []*go_query_dsl.Query{term("blackrock", "match_all")}, Not: term("event_type", "click")},
+		{Type: go_query_dsl.Query_OR, Queries: []*go_query_dsl.Query{term("country", "bg"), term("user", "1")}},
+	}
+	subs := []*Tail{}
+	for _, q := range queries {
+		sub, err := si.Tail(q, 100, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer sub.Close()
+		subs = append(subs, sub)
+	}
+	// the values are not tokenized
+	partial, err := si.Tail(term("campaign", "sale"), 100, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer partial.Close()
+	if _, err := si.Tail(&go_query_dsl.Query{Type: go_query_dsl.Query_TERM}, 1, 0); err == nil {
+		t.Fatal("expected error for a term without field")
+	}
+
+	now := time.Now()
+	for i := 0; i < 20; i++ {
+		eventType := "click"
+		if i%3 == 0 {
+			eventType = "view"
+		}
+		country := "nl"
+		if i%2 == 0 {
+			country = "bg"
+		}
+		err := si.Ingest(&spec.Envelope{Metadata: &spec.Metadata{
+			CreatedAtNs: now.UnixNano() + int64(i),
+			EventType:   eventType,
+			ForeignType: "user",
+			ForeignId:   fmt.Sprintf("%d", i%7),
+			Search:      []spec.KV{{Key: "country", Value: country}, {Key: "campaign", Value: fmt.Sprintf("summer-sale %d", i%2)}},
+		}})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the tail matches what the search finds
+	for i, q := range queries {
+		expected := []int64{}
+		err := si.ForEach(&spec.SearchQueryRequest{FromSecond: uint32(now.Unix()), ToSecond: uint32(now.Unix()), Query: q}, 0, func(segment *Segment, did int32, score float32) error {
+			m := spec.BasicMetadata{}
+			err := segment.ReadForwardDecode(did, &m)
+			expected = append(expected, m.CreatedAtNs)
+			return err
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(expected) == 0 {
+			t.Fatalf("query %d: expected some matches", i)
+		}
+
+		got := []int64{}
+		for len(got) < len(expected) {
+			m, dropped, err := subs[i].Next(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if dropped != 0 {
+				t.Fatalf("query %d: unexpected dropped %d", i, dropped)
+			}
+			got = append(got, m.CreatedAtNs)
+		}
+		if fmt.Sprintf("%v", got) != fmt.Sprintf("%v", expected) {
+			t.Fatalf("query %d: expected %v got %v", i, expected, got)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		if m, _, err := subs[i].Next(ctx); err != context.DeadlineExceeded {
+			t.Fatalf("query %d: expected no more events got %v %v", i, m, err)
+		}
+		cancel()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if m, _, err := partial.Next(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("expected no match got %v %v", m, err)
+	}
+
+	// slow subscribers lose events and then the subscription
+	slow, err := si.Tail(term("event_type", "click"), 2, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer slow.Close()
+	ingest := func(n int) {
+		for i := 0; i < n; i++ {
+			err := si.Ingest(&spec.Envelope{Metadata: &spec.Metadata{EventType: "click", ForeignType: "user", ForeignId: "1"}})
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	ingest(4)
+	for _, expected := range []uint64{0, 0} {
+		_, dropped, err := slow.Next(context.Background())
+		if err != nil || dropped != expected {
+			t.Fatalf("expected %d dropped got %d %v", expected, dropped, err)
+		}
+	}
+	ingest(1)
+	if _, dropped, err := slow.Next(context.Background()); err != nil || dropped != 2 {
+		t.Fatalf("expected 2 dropped got %d %v", dropped, err)
+	}
+	ingest(2 + 5)
+	for i := 0; i < 2; i++ {
+		if _, _, err := slow.Next(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, _, err := slow.Next(context.Background()); err != ErrTailTooSlow {
+		t.Fatalf("expected too slow got %v", err)
+	}
+}
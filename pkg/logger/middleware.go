@@ -19,21 +19,39 @@ func codeToLevel(code codes.Code) zapcore.Level {
 	}
 	return grpc_zap.DefaultCodeToLevel(code)
 }
-func AddLogging(opts []grpc.ServerOption) []grpc.ServerOption {
+
+// Interceptor runs after the logging ones, e.g. auth, grpc allows only one
+// chain so they have to be added here
+type Interceptor struct {
+	Unary  grpc.UnaryServerInterceptor
+	Stream grpc.StreamServerInterceptor
+}
+
+func AddLogging(opts []grpc.ServerOption, extra ...Interceptor) []grpc.ServerOption {
 	o := []grpc_zap.Option{
 		grpc_zap.WithLevels(codeToLevel),
 	}
 	grpc_zap.ReplaceGrpcLogger(log)
 
-	opts = append(opts, grpc_middleware.WithUnaryServerChain(
+	unary := []grpc.UnaryServerInterceptor{
 		grpc_ctxtags.UnaryServerInterceptor(grpc_ctxtags.WithFieldExtractor(grpc_ctxtags.CodeGenRequestFieldExtractor)),
 		grpc_zap.UnaryServerInterceptor(log, o...),
-	))
-
-	opts = append(opts, grpc_middleware.WithStreamServerChain(
+	}
+	stream := []grpc.StreamServerInterceptor{
 		grpc_ctxtags.StreamServerInterceptor(grpc_ctxtags.WithFieldExtractor(grpc_ctxtags.CodeGenRequestFieldExtractor)),
 		grpc_zap.StreamServerInterceptor(log, o...),
-	))
+	}
+	for _, i := range extra {
+		if i.Unary != nil {
+			unary = append(unary, i.Unary)
+		}
+		if i.Stream != nil {
+			stream = append(stream, i.Stream)
+		}
+	}
+
+	opts = append(opts, grpc_middleware.WithUnaryServerChain(unary...))
+	opts = append(opts, grpc_middleware.WithStreamServerChain(stream...))
 
 	return opts
 }
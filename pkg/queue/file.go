@@ -0,0 +1,140 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path"
+	"sync"
+	"sync/atomic"
+)
+
+var errNoSuchPartition = errors.New("the file queue has only partition 0")
+
+// FileQueue is a single partition queue backed by a FileLog in root/topic,
+// the producer and the consumer can be different processes on the same
+// machine
+type FileQueue struct {
+	root   string
+	topic  string
+	writer *FileLog
+	sync.Mutex
+}
+
+func NewFileQueue(root string, topic string) *FileQueue {
+	return &FileQueue{root: path.Join(root, topic), topic: topic}
+}
+
+func (q *FileQueue) Publish(ctx context.Context, values ...[]byte) error {
+	_, err := q.PublishSync(ctx, values...)
+	return err
+}
+
+// PublishSync is the same as Publish, every append is synced
+func (q *FileQueue) PublishSync(ctx context.Context, values ...[]byte) ([]Receipt, error) {
+	q.Lock()
+	defer q.Unlock()
+
+	if q.writer == nil {
+		l, err := OpenFileLog(q.root, 64*1024*1024)
+		if err != nil {
+			return nil, err
+		}
+		q.writer = l
+	}
+
+	offsets, err := q.writer.Append(values...)
+	if err != nil {
+		return nil, err
+	}
+
+	receipts := make([]Receipt, len(offsets))
+	for i, offset := range offsets {
+		receipts[i] = Receipt{Offset: offset}
+	}
+	return receipts, nil
+}
+
+func (q *FileQueue) Partitions() ([]int, error) {
+	return []int{0}, nil
+}
+
+func (q *FileQueue) Subscribe(partition int, last int64) (Subscription, error) {
+	if partition != 0 {
+		return nil, errNoSuchPartition
+	}
+
+	l, err := OpenFileLogReader(q.root)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &fileSubscription{log: l, topic: q.topic}
+	if last != FirstOffset {
+		// skip the one we already have
+		s.offset, err = l.Read(last, 1, func(int64, []byte) error {
+			return nil
+		})
+		if err != nil {
+			l.Close()
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+func (q *FileQueue) Health() error {
+	_, err := os.Stat(q.root)
+	if os.IsNotExist(err) {
+		// nothing was published yet
+		return os.MkdirAll(q.root, 0700)
+	}
+	return err
+}
+
+func (q *FileQueue) Close() error {
+	q.Lock()
+	defer q.Unlock()
+
+	if q.writer == nil {
+		return nil
+	}
+	return q.writer.Close()
+}
+
+type fileSubscription struct {
+	log     *FileLog
+	topic   string
+	offset  int64
+	pending []Message
+}
+
+func (s *fileSubscription) FetchMessage(ctx context.Context) (Message, error) {
+	for len(s.pending) == 0 {
+		err := s.log.Wait(ctx, s.offset)
+		if err != nil {
+			return Message{}, err
+		}
+
+		next, err := s.log.Read(s.offset, 1000, func(offset int64, data []byte) error {
+			s.pending = append(s.pending, Message{Topic: s.topic, Offset: offset, Value: data})
+			return nil
+		})
+		if err != nil {
+			return Message{}, err
+		}
+		atomic.StoreInt64(&s.offset, next)
+	}
+
+	m := s.pending[0]
+	s.pending = s.pending[1:]
+	return m, nil
+}
+
+func (s *fileSubscription) Lag() int64 {
+	return s.log.Behind(atomic.LoadInt64(&s.offset))
+}
+
+func (s *fileSubscription) Close() error {
+	return s.log.Close()
+}
@@ -0,0 +1,99 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestFileQueue(t *testing.T) {
+	dir, err := ioutil.TempDir("", "filequeue")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	q, err := Open("file:"+dir, "", "events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer q.Close()
+
+	err = q.Health()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the consumer is a different process, it only sees what is synced
+	consumer := NewFileQueue(dir, "events")
+	sub, err := consumer.Subscribe(0, FirstOffset)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 10; i++ {
+		err = q.Publish(context.Background(), []byte(fmt.Sprintf("event %d", i)))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if sub.Lag() == 0 {
+		t.Fatalf("expected lag")
+	}
+
+	messages := []Message{}
+	for i := 0; i < 10; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		m, err := sub.FetchMessage(ctx)
+		cancel()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(m.Value) != fmt.Sprintf("event %d", i) || m.Topic != "events" || m.Partition != 0 {
+			t.Fatalf("unexpected message %+v", m)
+		}
+		if i > 0 && m.Offset <= messages[i-1].Offset {
+			t.Fatalf("offsets must grow")
+		}
+		messages = append(messages, m)
+	}
+	if sub.Lag() != 0 {
+		t.Fatalf("expected no lag got %d", sub.Lag())
+	}
+
+	// waits for the next publish
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		_ = q.Publish(context.Background(), []byte("event 10"))
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	m, err := sub.FetchMessage(ctx)
+	cancel()
+	if err != nil || string(m.Value) != "event 10" {
+		t.Fatalf("unexpected message %+v, err: %v", m, err)
+	}
+	sub.Close()
+
+	// resuming after a message
+	sub, err = consumer.Subscribe(0, messages[4].Offset)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Close()
+	m, err = sub.FetchMessage(context.Background())
+	if err != nil || string(m.Value) != "event 5" {
+		t.Fatalf("unexpected message %+v, err: %v", m, err)
+	}
+
+	_, err = consumer.Subscribe(1, FirstOffset)
+	if err != errNoSuchPartition {
+		t.Fatalf("expected error")
+	}
+	_, err = Open("redis", "", "events")
+	if err != errUnknownQueue {
+		t.Fatalf("expected error")
+	}
+}
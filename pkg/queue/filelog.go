@@ -0,0 +1,385 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	pen "github.com/rekki/go-pen"
+)
+
+var errLogClosed = errors.New("log is closed")
+var errLogReadOnly = errors.New("log is opened for reading")
+
+const logSuffix = ".log"
+
+// FileLog is an append only log on disk, split in files of about
+// maxFileBytes so the consumed part can be removed. The offset of a record
+// is file<<32 | position in the file in pen.PAD units, offsets only grow.
+//
+// Only one process writes, the end of what is synced is stored next to the
+// files so other processes can read the log while it is written.
+type FileLog struct {
+	root         string
+	maxFileBytes int64
+
+	writer    *pen.Writer
+	endWriter *pen.OffsetWriter
+	readOnly  bool
+	file      int64
+	end       int64
+	changed   chan struct{}
+	closed    bool
+	sync.Mutex
+}
+
+func logFileName(root string, file int64) string {
+	return path.Join(root, fmt.Sprintf("%010d%s", file, logSuffix))
+}
+
+func listLogFiles(root string) ([]int64, error) {
+	entries, err := ioutil.ReadDir(root)
+	if err != nil {
+		return nil, err
+	}
+
+	out := []int64{}
+	for _, e := range entries {
+		if !strings.HasSuffix(e.Name(), logSuffix) {
+			continue
+		}
+		n, err := strconv.ParseInt(strings.TrimSuffix(e.Name(), logSuffix), 10, 64)
+		if err != nil {
+			continue
+		}
+		out = append(out, n)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i] < out[j]
+	})
+	return out, nil
+}
+
+// OpenFileLog opens or creates the log in root, new records are appended
+// to the last file
+func OpenFileLog(root string, maxFileBytes int64) (*FileLog, error) {
+	err := os.MkdirAll(root, 0700)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := listLogFiles(root)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &FileLog{root: root, maxFileBytes: maxFileBytes, changed: make(chan struct{})}
+	if len(files) > 0 {
+		l.file = files[len(files)-1]
+	}
+
+	l.endWriter, err = pen.NewOffsetWriter(path.Join(root, "end.offset"))
+	if err != nil {
+		return nil, err
+	}
+
+	err = l.openWriter()
+	if err == nil {
+		err = l.endWriter.SetOffset(l.end)
+	}
+	if err != nil {
+		l.endWriter.Close()
+		return nil, err
+	}
+	return l, nil
+}
+
+// OpenFileLogReader opens the log for reading only, it follows what the
+// writer, possibly in another process, appends
+func OpenFileLogReader(root string) (*FileLog, error) {
+	err := os.MkdirAll(root, 0700)
+	if err != nil {
+		return nil, err
+	}
+
+	endWriter, err := pen.NewOffsetWriter(path.Join(root, "end.offset"))
+	if err != nil {
+		return nil, err
+	}
+	return &FileLog{root: root, endWriter: endWriter, readOnly: true, changed: make(chan struct{})}, nil
+}
+
+func (l *FileLog) openWriter() error {
+	fn := logFileName(l.root, l.file)
+	w, err := pen.NewWriter(fn)
+	if err != nil {
+		return err
+	}
+
+	info, err := os.Stat(fn)
+	if err != nil {
+		w.Close()
+		return err
+	}
+
+	l.writer = w
+	l.end = l.file<<32 | (info.Size()+int64(pen.PAD)-1)/int64(pen.PAD)
+	return nil
+}
+
+// Append writes the records and syncs the file, it returns the offsets of
+// the records
+func (l *FileLog) Append(records ...[]byte) ([]int64, error) {
+	l.Lock()
+	defer l.Unlock()
+
+	if l.closed {
+		return nil, errLogClosed
+	}
+	if l.readOnly {
+		return nil, errLogReadOnly
+	}
+
+	if (l.end&0xFFFFFFFF)*int64(pen.PAD) >= l.maxFileBytes {
+		err := l.writer.Close()
+		if err != nil {
+			return nil, err
+		}
+		l.file++
+		err = l.openWriter()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	offsets := make([]int64, len(records))
+	next := uint32(0)
+	for i, data := range records {
+		offset, n, err := l.writer.Append(data)
+		if err != nil {
+			return nil, err
+		}
+		offsets[i] = l.file<<32 | int64(offset)
+		next = n
+	}
+	if len(records) == 0 {
+		return offsets, nil
+	}
+
+	err := l.writer.Sync()
+	if err != nil {
+		return nil, err
+	}
+
+	l.end = l.file<<32 | int64(next)
+	err = l.endWriter.SetOffset(l.end)
+	if err != nil {
+		return nil, err
+	}
+	close(l.changed)
+	l.changed = make(chan struct{})
+	return offsets, nil
+}
+
+// End is the offset after the last record
+func (l *FileLog) End() int64 {
+	if l.readOnly {
+		return l.endWriter.ReadOrDefault(0)
+	}
+
+	l.Lock()
+	defer l.Unlock()
+	return l.end
+}
+
+// Behind is how many bytes there are after offset
+func (l *FileLog) Behind(offset int64) int64 {
+	end := l.End()
+	behind := int64(0)
+	for file := offset >> 32; file <= end>>32; file++ {
+		info, err := os.Stat(logFileName(l.root, file))
+		if err != nil {
+			continue
+		}
+
+		size := info.Size()
+		if file == end>>32 {
+			size = (end & 0xFFFFFFFF) * int64(pen.PAD)
+		}
+		if file == offset>>32 {
+			size -= (offset & 0xFFFFFFFF) * int64(pen.PAD)
+		}
+		if size > 0 {
+			behind += size
+		}
+	}
+	return behind
+}
+
+// Read calls cb with at most max records starting at offset from, and
+// returns the offset to continue from. Corrupted records are skipped.
+func (l *FileLog) Read(from int64, max int, cb func(offset int64, data []byte) error) (int64, error) {
+	end := l.End()
+	read := 0
+
+	for from < end && read < max {
+		file := from >> 32
+		f, err := os.Open(logFileName(l.root, file))
+		if err != nil {
+			if os.IsNotExist(err) {
+				// removed by Truncate
+				from = (file + 1) << 32
+				continue
+			}
+			return from, err
+		}
+
+		from, err = func() (int64, error) {
+			defer f.Close()
+
+			info, err := f.Stat()
+			if err != nil {
+				return from, err
+			}
+			limit := (info.Size() + int64(pen.PAD) - 1) / int64(pen.PAD)
+			if file == end>>32 && end&0xFFFFFFFF < limit {
+				limit = end & 0xFFFFFFFF
+			}
+
+			pos := from & 0xFFFFFFFF
+			for read < max && pos < limit {
+				data, next, err := pen.ReadFromReader(f, uint32(pos), 16)
+				if err == pen.EBADSLT || err == io.EOF {
+					// corrupted or torn write, the next record starts
+					// at one of the following slots
+					pos++
+					continue
+				}
+				if err != nil {
+					return file<<32 | pos, err
+				}
+
+				err = cb(file<<32|pos, data)
+				if err != nil {
+					return file<<32 | pos, err
+				}
+				read++
+				pos = int64(next)
+			}
+
+			if pos >= limit && file < end>>32 {
+				// the rest is in the next file
+				return (file + 1) << 32, nil
+			}
+			return file<<32 | pos, nil
+		}()
+		if err != nil {
+			return from, err
+		}
+	}
+	return from, nil
+}
+
+// Wait blocks until there are records after offset
+func (l *FileLog) Wait(ctx context.Context, offset int64) error {
+	if l.readOnly {
+		return l.poll(ctx, offset)
+	}
+
+	l.Lock()
+	if l.closed {
+		l.Unlock()
+		return errLogClosed
+	}
+	if l.end > offset {
+		l.Unlock()
+		return nil
+	}
+	changed := l.changed
+	l.Unlock()
+
+	select {
+	case <-changed:
+		if l.isClosed() {
+			return errLogClosed
+		}
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (l *FileLog) poll(ctx context.Context, offset int64) error {
+	for {
+		if l.isClosed() {
+			return errLogClosed
+		}
+		if l.End() > offset {
+			return nil
+		}
+
+		select {
+		case <-l.changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
+
+func (l *FileLog) isClosed() bool {
+	l.Lock()
+	defer l.Unlock()
+	return l.closed
+}
+
+// Truncate removes the files that have only records before offset
+func (l *FileLog) Truncate(offset int64) error {
+	l.Lock()
+	current := l.file
+	l.Unlock()
+	if l.readOnly {
+		current = l.End() >> 32
+	}
+
+	files, err := listLogFiles(l.root)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		if file >= offset>>32 || file >= current {
+			break
+		}
+		err = os.Remove(logFileName(l.root, file))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (l *FileLog) Close() error {
+	l.Lock()
+	defer l.Unlock()
+
+	if l.closed {
+		return nil
+	}
+	l.closed = true
+	close(l.changed)
+	l.endWriter.Close()
+	if l.writer == nil {
+		return nil
+	}
+	return l.writer.Close()
+}
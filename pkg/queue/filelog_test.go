@@ -0,0 +1,176 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+)
+
+func readAll(t *testing.T, l *FileLog, from int64) ([]string, int64) {
+	out := []string{}
+	next, err := l.Read(from, 1000000, func(offset int64, data []byte) error {
+		out = append(out, string(data))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out, next
+}
+
+func TestFileLog(t *testing.T) {
+	dir, err := ioutil.TempDir("", "filelog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	l, err := OpenFileLog(dir, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 100; i += 2 {
+		_, err = l.Append([]byte(fmt.Sprintf("record %d", i)), []byte(fmt.Sprintf("record %d", i+1)))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	files, err := listLogFiles(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) < 3 {
+		t.Fatalf("expected rotated files got %v", files)
+	}
+
+	records, end := readAll(t, l, 0)
+	if len(records) != 100 || end != l.End() {
+		t.Fatalf("expected 100 records got %d", len(records))
+	}
+	for i, r := range records {
+		if r != fmt.Sprintf("record %d", i) {
+			t.Fatalf("unexpected record %d: %s", i, r)
+		}
+	}
+
+	// read in small steps across files
+	from := int64(0)
+	n := 0
+	for from < end {
+		from, err = l.Read(from, 3, func(offset int64, data []byte) error {
+			if string(data) != fmt.Sprintf("record %d", n) {
+				t.Fatalf("unexpected record %d: %s", n, data)
+			}
+			n++
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n != 100 {
+		t.Fatalf("expected 100 records got %d", n)
+	}
+
+	// reopening appends after the existing records
+	l.Close()
+	l, err = OpenFileLog(dir, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	if l.End() != end {
+		t.Fatalf("expected end %d got %d", end, l.End())
+	}
+
+	done := make(chan error)
+	go func() {
+		done <- l.Wait(context.Background(), end)
+	}()
+	time.Sleep(10 * time.Millisecond)
+	_, err = l.Append([]byte("record 100"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = <-done
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	records, _ = readAll(t, l, end)
+	if len(records) != 1 || records[0] != "record 100" {
+		t.Fatalf("unexpected records %v", records)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if l.Wait(ctx, l.End()) != context.DeadlineExceeded {
+		t.Fatalf("expected timeout")
+	}
+
+	// the files before the offset are removed, reading from the start
+	// continues from the first remaining file
+	err = l.Truncate(end)
+	if err != nil {
+		t.Fatal(err)
+	}
+	left, err := listLogFiles(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(left) != 1 {
+		t.Fatalf("expected one file left got %v", left)
+	}
+	records, _ = readAll(t, l, 0)
+	if records[len(records)-1] != "record 100" || len(records) > 20 {
+		t.Fatalf("unexpected records after truncate %d", len(records))
+	}
+}
+
+func TestFileLogTornWrite(t *testing.T) {
+	dir, err := ioutil.TempDir("", "filelog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	l, err := OpenFileLog(dir, 1<<20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = l.Append([]byte("a"), []byte("b"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Close()
+
+	f, err := os.OpenFile(logFileName(dir, 0), os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = f.Write([]byte("garbage from a crash"))
+	f.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l, err = OpenFileLog(dir, 1<<20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	_, err = l.Append([]byte("c"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	records, _ := readAll(t, l, 0)
+	if fmt.Sprintf("%v", records) != "[a b c]" {
+		t.Fatalf("unexpected records %v", records)
+	}
+}
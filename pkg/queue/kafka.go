@@ -0,0 +1,269 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rekki/blackrock/pkg/depths"
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/snappy"
+)
+
+var errInvalidAcks = errors.New("required acks must be -1 (all replicas) or 1 (leader only)")
+
+// Kafka publishes to and reads from one topic, Publish is asynchronous and
+// errors are only logged by the writer, PublishSync waits for the acks
+type Kafka struct {
+	servers string
+	topic   string
+	writer  *kafka.Writer
+
+	requiredAcks int
+	partitions   []int
+	leaders      map[int]*kafka.Conn
+	next         int
+	sync.Mutex
+}
+
+func NewKafka(servers string, topic string) *Kafka {
+	return &Kafka{
+		servers:      servers,
+		topic:        topic,
+		requiredAcks: -1,
+		leaders:      map[int]*kafka.Conn{},
+		writer: kafka.NewWriter(kafka.WriterConfig{
+			Brokers:          strings.Split(servers, ","),
+			Topic:            topic,
+			Balancer:         &kafka.LeastBytes{},
+			BatchTimeout:     1 * time.Second,
+			CompressionCodec: snappy.NewCompressionCodec(),
+			Async:            true,
+		}),
+	}
+}
+
+func (k *Kafka) Publish(ctx context.Context, values ...[]byte) error {
+	messages := make([]kafka.Message, len(values))
+	for i, v := range values {
+		messages[i] = kafka.Message{Value: v}
+	}
+	return k.writer.WriteMessages(ctx, messages...)
+}
+
+// SetRequiredAcks sets how many replicas must have the values before
+// PublishSync returns
+func (k *Kafka) SetRequiredAcks(n int) error {
+	if n != -1 && n != 1 {
+		return errInvalidAcks
+	}
+	k.Lock()
+	defer k.Unlock()
+	k.requiredAcks = n
+	return nil
+}
+
+// PublishSync writes all values to the same partition, the partitions are
+// used round robin
+func (k *Kafka) PublishSync(ctx context.Context, values ...[]byte) ([]Receipt, error) {
+	if len(values) == 0 {
+		return []Receipt{}, nil
+	}
+
+	partition, conn, err := k.leader()
+	if err != nil {
+		return nil, err
+	}
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(10 * time.Second)
+	}
+	err = conn.SetWriteDeadline(deadline)
+	if err != nil {
+		k.dropLeader(partition, conn)
+		return nil, err
+	}
+
+	messages := make([]kafka.Message, len(values))
+	for i, v := range values {
+		messages[i] = kafka.Message{Value: v}
+	}
+	_, _, offset, _, err := conn.WriteCompressedMessagesAt(snappy.NewCompressionCodec(), messages...)
+	if err != nil {
+		// most likely the leader moved, dial again next time
+		k.dropLeader(partition, conn)
+		return nil, err
+	}
+
+	receipts := make([]Receipt, len(values))
+	for i := range values {
+		receipts[i] = Receipt{Partition: partition, Offset: offset + int64(i)}
+	}
+	return receipts, nil
+}
+
+func (k *Kafka) leader() (int, *kafka.Conn, error) {
+	k.Lock()
+	defer k.Unlock()
+
+	if len(k.partitions) == 0 {
+		p, err := k.Partitions()
+		if err != nil {
+			return 0, nil, err
+		}
+		k.partitions = p
+	}
+
+	partition := k.partitions[k.next%len(k.partitions)]
+	k.next++
+	if conn, ok := k.leaders[partition]; ok {
+		return partition, conn, nil
+	}
+
+	var err error
+	for _, b := range depths.ShuffledStrings(strings.Split(k.servers, ",")) {
+		var conn *kafka.Conn
+		conn, err = kafka.DialLeader(context.Background(), "tcp", b, k.topic, partition)
+		if err != nil {
+			continue
+		}
+		err = conn.SetRequiredAcks(k.requiredAcks)
+		if err != nil {
+			conn.Close()
+			return 0, nil, err
+		}
+		k.leaders[partition] = conn
+		return partition, conn, nil
+	}
+	return 0, nil, err
+}
+
+func (k *Kafka) dropLeader(partition int, conn *kafka.Conn) {
+	k.Lock()
+	defer k.Unlock()
+	if k.leaders[partition] == conn {
+		delete(k.leaders, partition)
+		// the partitions could have changed too
+		k.partitions = nil
+	}
+	conn.Close()
+}
+
+func (k *Kafka) Partitions() ([]int, error) {
+	for _, b := range depths.ShuffledStrings(strings.Split(k.servers, ",")) {
+		conn, err := kafka.Dial("tcp", b)
+		if err == nil {
+			p, err := conn.ReadPartitions(k.topic)
+			conn.Close()
+			if err == nil {
+				out := []int{}
+				for _, partition := range p {
+					out = append(out, partition.ID)
+				}
+				return out, nil
+			}
+		}
+	}
+	return nil, errors.New("failed to get partitions, assuming we cant reach kafka")
+}
+
+func (k *Kafka) Subscribe(partition int, last int64) (Subscription, error) {
+	r := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:   strings.Split(k.servers, ","),
+		Topic:     k.topic,
+		MaxWait:   1 * time.Second,
+		Partition: partition,
+	})
+
+	offset := last
+	if offset != FirstOffset {
+		offset++ // start from the next one, as we already have the current one
+	}
+	err := r.SetOffset(offset)
+	if err != nil {
+		r.Close()
+		return nil, err
+	}
+	return &kafkaSubscription{r}, nil
+}
+
+func (k *Kafka) Health() error {
+	return depths.HealthCheckKafka(k.servers, k.topic)
+}
+
+func (k *Kafka) Stats() kafka.WriterStats {
+	return k.writer.Stats()
+}
+
+func (k *Kafka) Close() error {
+	k.Lock()
+	for partition, conn := range k.leaders {
+		conn.Close()
+		delete(k.leaders, partition)
+	}
+	k.Unlock()
+	return k.writer.Close()
+}
+
+type kafkaSubscription struct {
+	reader *kafka.Reader
+}
+
+func (s *kafkaSubscription) FetchMessage(ctx context.Context) (Message, error) {
+	m, err := s.reader.FetchMessage(ctx)
+	if err != nil {
+		return Message{}, err
+	}
+	return Message{Topic: m.Topic, Partition: m.Partition, Offset: m.Offset, Value: m.Value}, nil
+}
+
+func (s *kafkaSubscription) Lag() int64 {
+	return s.reader.Lag()
+}
+
+func (s *kafkaSubscription) Close() error {
+	return s.reader.Close()
+}
+
+// Group is a consumer group subscription, kafka assigns the partitions and
+// keeps the committed offsets
+type Group struct {
+	reader *kafka.Reader
+}
+
+func (k *Kafka) SubscribeGroup(group string) *Group {
+	return &Group{
+		reader: kafka.NewReader(kafka.ReaderConfig{
+			Brokers:               strings.Split(k.servers, ","),
+			Topic:                 k.topic,
+			GroupID:               group,
+			MaxWait:               1 * time.Second,
+			WatchPartitionChanges: true,
+			StartOffset:           kafka.FirstOffset,
+		}),
+	}
+}
+
+func (g *Group) FetchMessage(ctx context.Context) (Message, error) {
+	return (&kafkaSubscription{g.reader}).FetchMessage(ctx)
+}
+
+func (g *Group) Commit(ctx context.Context, messages ...Message) error {
+	committed := make([]kafka.Message, len(messages))
+	for i, m := range messages {
+		committed[i] = kafka.Message{Topic: m.Topic, Partition: m.Partition, Offset: m.Offset}
+	}
+	return g.reader.CommitMessages(ctx, committed...)
+}
+
+// Lag is the total lag of the assigned partitions
+func (g *Group) Lag() int64 {
+	return g.reader.Stats().Lag
+}
+
+func (g *Group) Close() error {
+	return g.reader.Close()
+}
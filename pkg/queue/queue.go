@@ -0,0 +1,64 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+var errUnknownQueue = errors.New("queue must be kafka or file:<dir>")
+
+// FirstOffset subscribes from the oldest message, same as kafka.FirstOffset
+const FirstOffset = -2
+
+// Message is one record of a partition, offsets grow within a partition
+// but are not necessarily consecutive
+type Message struct {
+	Topic     string
+	Partition int
+	Offset    int64
+	Value     []byte
+}
+
+// Queue is what sits between the producer and the consumer
+type Queue interface {
+	// Publish returns once the queue accepted the values
+	Publish(ctx context.Context, values ...[]byte) error
+	Partitions() ([]int, error)
+	// Subscribe reads the partition starting after the message at offset
+	// last, or from the beginning with FirstOffset
+	Subscribe(partition int, last int64) (Subscription, error)
+	Health() error
+	Close() error
+}
+
+// Receipt is where a published value was stored
+type Receipt struct {
+	Partition int
+	Offset    int64
+}
+
+// SyncPublisher waits until the values are stored and says where
+type SyncPublisher interface {
+	PublishSync(ctx context.Context, values ...[]byte) ([]Receipt, error)
+}
+
+// Subscription reads one partition
+type Subscription interface {
+	FetchMessage(ctx context.Context) (Message, error)
+	// Lag is how far behind we are, in messages for kafka and in bytes for
+	// the file log
+	Lag() int64
+	Close() error
+}
+
+// Open parses kafka or file:<dir>, kafka uses topic on kafkaServers
+func Open(s string, kafkaServers string, topic string) (Queue, error) {
+	switch {
+	case s == "kafka":
+		return NewKafka(kafkaServers, topic), nil
+	case strings.HasPrefix(s, "file:"):
+		return NewFileQueue(strings.TrimPrefix(s, "file:"), topic), nil
+	}
+	return nil, errUnknownQueue
+}
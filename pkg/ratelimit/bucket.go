@@ -0,0 +1,67 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Bucket is a token bucket, it starts full with burst tokens and gets rate
+// tokens per second, rate must be more than 0
+type Bucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+	sync.Mutex
+}
+
+func NewBucket(rate float64, burst int) *Bucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &Bucket{rate: rate, burst: float64(burst), tokens: float64(burst), last: time.Now()}
+}
+
+func (b *Bucket) refill(now time.Time) {
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+}
+
+// Allow takes a token if there is one
+func (b *Bucket) Allow() bool {
+	b.Lock()
+	defer b.Unlock()
+	b.refill(time.Now())
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// Wait takes a token, waiting for it if needed
+func (b *Bucket) Wait(ctx context.Context) error {
+	b.Lock()
+	b.refill(time.Now())
+	b.tokens--
+	missing := -b.tokens
+	b.Unlock()
+	if missing <= 0 {
+		return nil
+	}
+
+	select {
+	case <-time.After(time.Duration(missing / b.rate * float64(time.Second))):
+		return nil
+	case <-ctx.Done():
+		// give it back
+		b.Lock()
+		b.tokens++
+		b.Unlock()
+		return ctx.Err()
+	}
+}
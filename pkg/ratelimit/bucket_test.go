@@ -0,0 +1,76 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestBucket(t *testing.T) {
+	b := NewBucket(100, 2)
+	if !b.Allow() || !b.Allow() {
+		t.Fatal("expected the burst to be allowed")
+	}
+	if b.Allow() {
+		t.Fatal("expected empty bucket")
+	}
+	time.Sleep(30 * time.Millisecond)
+	if !b.Allow() {
+		t.Fatal("expected refill")
+	}
+
+	started := time.Now()
+	for i := 0; i < 5; i++ {
+		err := b.Wait(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if took := time.Since(started); took < 30*time.Millisecond {
+		t.Fatalf("expected to wait, took %s", took)
+	}
+
+	slow := NewBucket(0.1, 1)
+	slow.Allow()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := slow.Wait(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("expected deadline got %v", err)
+	}
+}
+
+func TestLimiter(t *testing.T) {
+	l := NewLimiter(0.001, 2, 3)
+	for _, k := range []string{"a", "a", "b"} {
+		if !l.Allow(k) {
+			t.Fatalf("expected %s to be allowed", k)
+		}
+	}
+	if l.Allow("a") {
+		t.Fatal("expected a to be limited")
+	}
+	if !l.Allow("b") {
+		t.Fatal("expected b to have its own bucket")
+	}
+
+	l.Allow("c")
+	l.Allow("d")
+	if n := l.Len(); n != 3 {
+		t.Fatalf("expected 3 keys got %d", n)
+	}
+
+	// the least recently seen key is forgotten, not a random one
+	for i := 0; i < 100; i++ {
+		lru := NewLimiter(0.001, 1, 2)
+		lru.Allow("old")
+		lru.Allow("b")
+		lru.Allow("b")
+		lru.Allow("c")
+		if lru.Allow("b") {
+			t.Fatal("expected b to be remembered and limited")
+		}
+		if !lru.Allow("old") {
+			t.Fatal("expected old to be forgotten")
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package ratelimit
+
+import (
+	"container/list"
+	"sync"
+)
+
+type keyed struct {
+	key    string
+	bucket *Bucket
+}
+
+// Limiter keeps a Bucket per key, e.g. per ip, at most maxKeys of them, when
+// there are more the least recently seen key is forgotten and starts full
+// again
+type Limiter struct {
+	rate    float64
+	burst   int
+	maxKeys int
+	buckets map[string]*list.Element
+	lru     *list.List
+	sync.Mutex
+}
+
+func NewLimiter(rate float64, burst int, maxKeys int) *Limiter {
+	if maxKeys < 1 {
+		maxKeys = 1
+	}
+	return &Limiter{rate: rate, burst: burst, maxKeys: maxKeys, buckets: map[string]*list.Element{}, lru: list.New()}
+}
+
+func (l *Limiter) Allow(key string) bool {
+	l.Lock()
+	e, ok := l.buckets[key]
+	if ok {
+		l.lru.MoveToFront(e)
+	} else {
+		if l.lru.Len() >= l.maxKeys {
+			oldest := l.lru.Back()
+			l.lru.Remove(oldest)
+			delete(l.buckets, oldest.Value.(*keyed).key)
+		}
+		e = l.lru.PushFront(&keyed{key: key, bucket: NewBucket(l.rate, l.burst)})
+		l.buckets[key] = e
+	}
+	b := e.Value.(*keyed).bucket
+	l.Unlock()
+
+	return b.Allow()
+}
+
+func (l *Limiter) Len() int {
+	l.Lock()
+	defer l.Unlock()
+	return l.lru.Len()
+}